@@ -32,15 +32,14 @@ func main() {
 		cfg.SMTP.Pass,
 		cfg.SMTP.Host,
 		cfg.StoragePath,
-		cfg.App.AppID,
-		cfg.App.AppName,
-		cfg.App.AppSecret,
 		cfg.TokenTTL,
 		cfg.SMTP.VerificationCodeLength,
 		cfg.Redis,
 		cfg.Redis.VerTokenTTL)
 
 	go application.GRPCSrv.MustRun()
+	go application.HTTPSrv.MustRun()
+	go application.Purger.Run()
 
 	//Graceful shutdown
 	stop := make(chan os.Signal, 1)
@@ -50,6 +49,8 @@ func main() {
 	log.Info("stopping application", slog.String("signal", sign.String()))
 
 	application.GRPCSrv.Stop()
+	application.HTTPSrv.Stop()
+	application.Purger.Stop()
 	log.Info("application stopped")
 }
 
@@ -3,21 +3,35 @@ package app
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	grpcapp "sso/internal/app/grpc"
+	httpapp "sso/internal/app/http"
 	"sso/internal/bootstrap"
 	"sso/internal/config"
+	federationhttp "sso/internal/http/federation"
 	"sso/internal/lib/logger/sl"
 	"sso/internal/otp"
 	"sso/internal/repository/redis"
 	"sso/internal/repository/sqlite"
 	"sso/internal/services"
+	"sso/internal/services/account"
+	"sso/internal/services/admin"
+	"sso/internal/services/attributes"
 	"sso/internal/services/auth"
+	"sso/internal/services/directory"
+	"sso/internal/services/directory/ldap"
 	"sso/internal/services/email/smtp"
+	"sso/internal/services/invitation"
+	"sso/internal/services/org"
+	"sso/internal/services/registration"
+	"sso/internal/services/sms/stub"
 	"time"
 )
 
 type App struct {
 	GRPCSrv *grpcapp.App
+	HTTPSrv *httpapp.App
+	Purger  *account.Purger
 }
 
 func New(
@@ -29,9 +43,6 @@ func New(
 	pass string,
 	host string,
 	storagePath string,
-	appID int,
-	appName string,
-	appSecret string,
 	tokenTTL time.Duration,
 	verificationCodeLength int,
 	redisHost config.RedisConfig,
@@ -42,15 +53,17 @@ func New(
 		log.Error("sqlite unavailable")
 	}
 
-	if err := bootstrap.InitApp(
+	if err := bootstrap.InitApps(
 		context.Background(),
 		log,
 		storage,
-		appID,
-		appName,
-		appSecret,
+		config.Bootstrap,
 	); err != nil {
-		log.Error("failed to init app", sl.Err(err))
+		log.Error("failed to init apps", sl.Err(err))
+	}
+
+	if err := bootstrap.NormalizePhones(context.Background(), log, storage, config.Phone); err != nil {
+		log.Error("failed to normalize phones", sl.Err(err))
 	}
 
 	smtpService, err := smtp.NewSMTPService(from, pass, host, smtpPort)
@@ -63,6 +76,9 @@ func New(
 		log.Error("emails unavailable")
 	}
 
+	// no sms provider is integrated yet, the stub only logs messages
+	smsService := services.NewSMSService(log, stub.NewStubService(log))
+
 	otpGenerator := otp.NewGOTPGenerator()
 
 	redisRepo, err := redis.New(redisHost)
@@ -70,21 +86,102 @@ func New(
 		log.Error("redis unavailable")
 	}
 
-	authService := auth.New(
-		log,
-		storage,
-		storage,
-		storage,
-		tokenTTL,
-		emails,
-		otpGenerator,
-		verificationCodeLength,
-		redisRepo,
-		verificationCodeTTL)
+	// logins are checked against local passwords only unless a directory is configured
+	var userDirectory directory.Directory
+	if config.LDAP.Enabled {
+		ldapDirectory, err := ldap.New(config.LDAP)
+		if err != nil {
+			log.Error("ldap unavailable", sl.Err(err))
+		} else {
+			userDirectory = ldapDirectory
+		}
+	}
+
+	if config.Admin.AppID == 0 {
+		log.Warn("admin.app_id is not set, nobody can administer the sso")
+	}
+
+	authService := auth.New(log, auth.Deps{
+		UserSaver:         storage,
+		UserProvider:      storage,
+		AppProvider:       storage,
+		LockoutProvider:   storage,
+		PermProvider:      storage,
+		OrgProvider:       storage,
+		GroupProvider:     storage,
+		PolicyProvider:    storage,
+		APIKeyRepo:        storage,
+		SAProvider:        storage,
+		AuditLog:          storage,
+		InvitationRepo:    storage,
+		EmailChangeRepo:   storage,
+		PhoneChangeRepo:   storage,
+		AttributeProvider: storage,
+		ConsentRepo:       storage,
+		GrantProvider:     storage,
+		IdentityRepo:      storage,
+		DirectoryRepo:     storage,
+		Directory:         userDirectory,
+		EmailService:      emails,
+		SMSService:        smsService,
+		OTPGenerator:      otpGenerator,
+		Redis:             redisRepo,
+	}, auth.Config{
+		TokenTTL:               tokenTTL,
+		VerificationCodeLength: verificationCodeLength,
+		VerificationCodeTTL:    verificationCodeTTL,
+		Admin:                  config.Admin,
+		Lockout:                config.Lockout,
+		Codes:                  config.Codes,
+		GroupsClaim:            config.GroupsClaim,
+		APIKeys:                config.APIKeys,
+		Impersonation:          config.Impersonation,
+		EmailChange:            config.EmailChange,
+		Phone:                  config.Phone,
+		Consent:                config.Consent,
+		Federation:             config.Federation,
+		LDAP:                   config.LDAP,
+		EnumerationProtection:  config.EnumerationProtection,
+	})
+
+	adminService := admin.New(log, storage, storage, storage, storage, storage, storage, storage, storage, storage, storage, storage, emails, config.APIKeys, config.Phone, config.ClientRegistration)
+
+	orgService := org.New(log, storage, storage)
+
+	invitationService := invitation.New(log, storage, storage, emails, config.Invitations)
+
+	accountService := account.New(log, storage, storage, storage, storage, storage, emails, config.AccountDeletion)
+
+	attributesService := attributes.New(log, storage, storage, storage)
+
+	registrationService := registration.New(log, storage)
+
+	purger := account.NewPurger(log, storage, storage, config.AccountDeletion)
+
+	grpcApp := grpcapp.New(log, grpcPort, grpcapp.Services{
+		Auth:            authService,
+		Admin:           adminService,
+		Org:             orgService,
+		APIKeys:         authService,
+		ServiceAccounts: adminService,
+		Impersonation:   authService,
+		Invitations:     invitationService,
+		Account:         accountService,
+		EmailChange:     authService,
+		PhoneChange:     authService,
+		Attributes:      attributesService,
+		Registration:    registrationService,
+		Authorizer:      authService,
+	})
+
+	mux := http.NewServeMux()
+	federationhttp.Register(mux, log, authService)
 
-	grpcApp := grpcapp.New(log, grpcPort, authService)
+	httpApp := httpapp.New(log, config.HTTP.Port, config.HTTP.Timeout, mux)
 
 	return &App{
 		GRPCSrv: grpcApp,
+		HTTPSrv: httpApp,
+		Purger:  purger,
 	}
 }
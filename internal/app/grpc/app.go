@@ -4,8 +4,20 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"sso/internal/domain/models"
+	accountgrpc "sso/internal/grpc/account"
+	admingrpc "sso/internal/grpc/admin"
+	apikeysgrpc "sso/internal/grpc/apikeys"
+	attributesgrpc "sso/internal/grpc/attributes"
 	authgrpc "sso/internal/grpc/auth"
+	impersonationgrpc "sso/internal/grpc/impersonation"
+	"sso/internal/grpc/interceptors"
+	invitationsgrpc "sso/internal/grpc/invitations"
+	orggrpc "sso/internal/grpc/org"
+	registrationgrpc "sso/internal/grpc/registration"
+	serviceaccountsgrpc "sso/internal/grpc/serviceaccounts"
 
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
 	"google.golang.org/grpc"
 )
 
@@ -15,11 +27,28 @@ type App struct {
 	port       int
 }
 
-func New(
-	log *slog.Logger,
-	port int,
-	authService authgrpc.Auth,
-) *App {
+// Services are the implementations of the gRPC services the server registers.
+type Services struct {
+	Auth            authgrpc.Auth
+	Admin           admingrpc.Admin
+	Org             orggrpc.Org
+	APIKeys         apikeysgrpc.APIKeys
+	ServiceAccounts serviceaccountsgrpc.ServiceAccounts
+	Impersonation   impersonationgrpc.Impersonation
+	Invitations     invitationsgrpc.Invitations
+	Account         accountgrpc.Account
+	EmailChange     accountgrpc.EmailChange
+	PhoneChange     accountgrpc.PhoneChange
+	Attributes      attributesgrpc.Attributes
+	Registration    registrationgrpc.Registration
+	// Authorizer checks the tokens of the services guarded by the interceptors.
+	Authorizer interface {
+		interceptors.Authorizer
+		interceptors.AdminAuthorizer
+	}
+}
+
+func New(log *slog.Logger, port int, services Services) *App {
 	//creds, err := credentials.NewServerTLSFromFile(
 	//	"certs/server.crt",
 	//	"certs/server.key")
@@ -28,9 +57,32 @@ func New(
 	//	log.Info(err.Error())
 	//}
 	gRPCServer := grpc.NewServer(
-	//grpc.Creds(creds),
+		//grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(
+			// policy decisions and their explanations are for the backends of the app only
+			interceptors.RequireMethodPermission(services.Authorizer, ssov1.Auth_Check_FullMethodName, models.PermissionCheck),
+			interceptors.RequireMethodPermission(services.Authorizer, ssov1.Auth_BatchCheck_FullMethodName, models.PermissionCheck),
+			interceptors.RequireAdmin(services.Authorizer, ssov1.Admin_ServiceDesc.ServiceName),
+			interceptors.RequirePermission(services.Authorizer, ssov1.Organizations_ServiceDesc.ServiceName, ""),
+			interceptors.RequirePermission(services.Authorizer, ssov1.APIKeys_ServiceDesc.ServiceName, ""),
+			interceptors.RequireAdmin(services.Authorizer, ssov1.ServiceAccounts_ServiceDesc.ServiceName),
+			interceptors.RequirePermission(services.Authorizer, ssov1.Impersonation_ServiceDesc.ServiceName, models.PermissionImpersonate),
+			interceptors.RequirePermission(services.Authorizer, ssov1.Invitations_ServiceDesc.ServiceName, ""),
+			interceptors.RequirePermission(services.Authorizer, ssov1.Account_ServiceDesc.ServiceName, ""),
+			interceptors.RequirePermission(services.Authorizer, ssov1.Attributes_ServiceDesc.ServiceName, ""),
+		),
 	)
-	authgrpc.Register(gRPCServer, authService)
+	authgrpc.Register(gRPCServer, services.Auth)
+	admingrpc.Register(gRPCServer, services.Admin)
+	orggrpc.Register(gRPCServer, services.Org)
+	apikeysgrpc.Register(gRPCServer, services.APIKeys)
+	serviceaccountsgrpc.Register(gRPCServer, services.ServiceAccounts)
+	impersonationgrpc.Register(gRPCServer, services.Impersonation)
+	invitationsgrpc.Register(gRPCServer, services.Invitations)
+	accountgrpc.Register(gRPCServer, services.Account, services.EmailChange, services.PhoneChange)
+	attributesgrpc.Register(gRPCServer, services.Attributes)
+	// client registration checks its own tokens, they aren't user tokens
+	registrationgrpc.Register(gRPCServer, services.Registration)
 
 	return &App{
 		log:        log,
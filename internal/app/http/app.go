@@ -0,0 +1,73 @@
+package httpapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sso/internal/lib/logger/sl"
+	"time"
+)
+
+type App struct {
+	log        *slog.Logger
+	httpServer *http.Server
+	port       int
+}
+
+// New returns the HTTP server browsers are redirected to, e.g. by upstream identity providers.
+// Requests are served within timeout.
+func New(log *slog.Logger, port int, timeout time.Duration, handler http.Handler) *App {
+	return &App{
+		log: log,
+		httpServer: &http.Server{
+			Handler:           http.TimeoutHandler(handler, timeout, "request timed out"),
+			ReadHeaderTimeout: timeout,
+			ReadTimeout:       timeout,
+			// the handler's timeout plus time to write its response
+			WriteTimeout: 2 * timeout,
+		},
+		port: port,
+	}
+}
+
+// MustRun runs HTTP server and panics if any error occurs
+func (a *App) MustRun() {
+	if err := a.Run(); err != nil {
+		panic(err)
+	}
+}
+
+func (a *App) Run() error {
+	const op = "httpapp.Run"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("port", a.port),
+	)
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", a.port))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("HTTP server is running", slog.String("addr", l.Addr().String()))
+
+	if err := a.httpServer.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a *App) Stop() {
+	const op = "httpapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping HTTP server", slog.Int("port", a.port))
+
+	if err := a.httpServer.Shutdown(context.Background()); err != nil {
+		a.log.Error("failed to stop HTTP server", slog.String("op", op), sl.Err(err))
+	}
+}
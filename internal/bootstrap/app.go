@@ -2,75 +2,261 @@ package bootstrap
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"sso/internal/config"
 	"sso/internal/domain/models"
 	"sso/internal/lib/logger/sl"
-	"sso/internal/repository"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AppBootstrapRepository interface {
-	CreateApp(ctx context.Context, name string, secret []byte) (int, error)
-	UpdateApp(ctx context.Context, id int, name string, secret []byte) error
-	App(ctx context.Context, id int) (models.App, error)
+	CreateApp(ctx context.Context, app models.App) (int, error)
+	UpdateApp(ctx context.Context, app models.App) error
+	Apps(ctx context.Context) ([]models.App, error)
+	RolePermissions(ctx context.Context, appID int) (map[string][]string, error)
+	EnsureRole(ctx context.Context, appID int, role string, perms []string) error
 }
 
-func InitApp(
+// appChange is a change needed to bring a stored app in line with the config.
+type appChange struct {
+	action string
+	app    models.App
+	// diff lists changed fields of the app
+	diff []string
+	// roles lists roles to create or grant missing permissions to
+	roles map[string][]string
+}
+
+// InitApps reconciles stored apps with the declared ones, matching them by name.
+// Missing apps are created, changed ones are updated and, if cfg.DisableMissingApps is set,
+// apps created by admins that are not declared are disabled. Declared apps are only disabled or enabled
+// if their config sets disabled. Roles are only added, never revoked.
+// Every change is logged before it is applied; with cfg.DryRun nothing is applied.
+func InitApps(
 	ctx context.Context,
 	log *slog.Logger,
 	repo AppBootstrapRepository,
-	id int,
-	name string,
-	secret string,
+	cfg config.BootstrapConfig,
 ) error {
-	const op = "bootstrap.initApp"
+	const op = "bootstrap.InitApps"
+
+	log = log.With(
+		slog.String("op", op),
+		slog.Bool("dry_run", cfg.DryRun),
+	)
+
+	changes, err := planApps(ctx, repo, cfg)
+	if err != nil {
+		log.Error("failed to plan app changes", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(changes) == 0 {
+		log.Info("apps are up to date")
+
+		return nil
+	}
 
-	app, err := repo.App(ctx, id)
-	if err == nil {
-		nameChanged := app.Name != name
+	for _, change := range changes {
+		log.Info("app change",
+			slog.String("action", change.action),
+			slog.String("name", change.app.Name),
+			slog.Any("diff", change.diff),
+			slog.Any("roles", change.roles),
+		)
+	}
+
+	if cfg.DryRun {
+		log.Info("dry run, app changes are not applied", slog.Int("changes", len(changes)))
+
+		return nil
+	}
+
+	for _, change := range changes {
+		if err := applyAppChange(ctx, repo, change); err != nil {
+			log.Error("failed to apply app change", slog.String("name", change.app.Name), sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
 
-		secretChanged := bcrypt.CompareHashAndPassword(app.SecretHash, []byte(secret)) != nil
-		if nameChanged || secretChanged {
+	log.Info("apps reconciled", slog.Int("changes", len(changes)))
+
+	return nil
+}
+
+func planApps(ctx context.Context, repo AppBootstrapRepository, cfg config.BootstrapConfig) ([]appChange, error) {
+	stored, err := repo.Apps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]models.App, len(stored))
+	for _, app := range stored {
+		byName[app.Name] = app
+	}
+
+	var changes []appChange
+	declared := make(map[string]bool, len(cfg.Apps))
+
+	for _, appCfg := range cfg.Apps {
+		if err := validateAppConfig(appCfg); err != nil {
+			return nil, err
+		}
+		if declared[appCfg.Name] {
+			return nil, fmt.Errorf("app %q is declared twice", appCfg.Name)
+		}
+		declared[appCfg.Name] = true
+
+		secret := os.Getenv(appCfg.SecretEnv)
+		if secret == "" {
+			return nil, fmt.Errorf("app %q: secret env %q is empty", appCfg.Name, appCfg.SecretEnv)
+		}
+
+		app, exists := byName[appCfg.Name]
+		if !exists {
 			secretHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
 			if err != nil {
-				log.Error("failed to generate app secret hash", sl.Err(err))
+				return nil, err
+			}
 
-				return fmt.Errorf("%s: %w", op, err)
+			changes = append(changes, appChange{
+				action: "create",
+				app: models.App{
+					Name:         appCfg.Name,
+					SecretHash:   secretHash,
+					RedirectURIs: appCfg.RedirectURIs,
+					GrantTypes:   appCfg.GrantTypes,
+					Disabled:     appCfg.Disabled != nil && *appCfg.Disabled,
+				},
+				roles: appCfg.Roles,
+			})
+			continue
+		}
+
+		change := appChange{action: "update", app: app}
+
+		if !slices.Equal(app.RedirectURIs, appCfg.RedirectURIs) {
+			change.diff = append(change.diff, "redirect_uris")
+			change.app.RedirectURIs = appCfg.RedirectURIs
+		}
+		if !slices.Equal(app.GrantTypes, appCfg.GrantTypes) {
+			change.diff = append(change.diff, "grant_types")
+			change.app.GrantTypes = appCfg.GrantTypes
+		}
+		// apps may be disabled at runtime, only the config saying otherwise enables them
+		if appCfg.Disabled != nil && app.Disabled != *appCfg.Disabled {
+			change.diff = append(change.diff, "disabled")
+			change.app.Disabled = *appCfg.Disabled
+		}
+		if bcrypt.CompareHashAndPassword(app.SecretHash, []byte(secret)) != nil {
+			secretHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, err
 			}
 
-			if err := repo.UpdateApp(ctx, id, name, secretHash); err != nil {
-				log.Error("failed to update app secret hash", sl.Err(err))
+			change.diff = append(change.diff, "secret")
+			change.app.SecretHash = secretHash
+		}
+
+		change.roles, err = missingRoles(ctx, repo, app.ID, appCfg.Roles)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(change.diff) > 0 || len(change.roles) > 0 {
+			changes = append(changes, change)
+		}
+	}
 
-				return fmt.Errorf("%s: %w", op, err)
+	if cfg.DisableMissingApps {
+		for _, app := range stored {
+			// apps that registered themselves are managed by their clients, never declared in the config
+			if declared[app.Name] || app.Disabled || app.ThirdParty() {
+				continue
 			}
 
-			log.Info("app secret updated", slog.String("name", name))
+			app.Disabled = true
+			changes = append(changes, appChange{action: "disable", app: app, diff: []string{"disabled"}})
 		}
-		return nil
 	}
 
-	if errors.Is(err, repository.ErrAppNotFound) {
-		secretHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
-		if err != nil {
-			log.Error("failed to generate password hash", sl.Err(err))
+	return changes, nil
+}
 
-			return fmt.Errorf("%s: %w", op, err)
+// missingRoles returns declared roles and permissions the stored app lacks.
+func missingRoles(ctx context.Context, repo AppBootstrapRepository, appID int, declared map[string][]string) (map[string][]string, error) {
+	stored, err := repo.RolePermissions(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make(map[string][]string)
+	for _, role := range slices.Sorted(maps.Keys(declared)) {
+		storedPerms, exists := stored[role]
+
+		var perms []string
+		for _, perm := range declared[role] {
+			if !slices.Contains(storedPerms, perm) {
+				perms = append(perms, perm)
+			}
+		}
+
+		if !exists || len(perms) > 0 {
+			missing[role] = perms
 		}
+	}
 
-		if _, err := repo.CreateApp(ctx, name, secretHash); err != nil {
-			log.Error("failed to create app", sl.Err(err))
+	return missing, nil
+}
 
-			return fmt.Errorf("%s: %w", op, err)
+func applyAppChange(ctx context.Context, repo AppBootstrapRepository, change appChange) error {
+	app := change.app
+
+	switch change.action {
+	case "create":
+		id, err := repo.CreateApp(ctx, app)
+		if err != nil {
+			return err
+		}
+		app.ID = id
+	default:
+		if len(change.diff) > 0 {
+			if err := repo.UpdateApp(ctx, app); err != nil {
+				return err
+			}
 		}
+	}
 
-		log.Info("app created", slog.String("name", name))
-		return nil
+	for _, role := range slices.Sorted(maps.Keys(change.roles)) {
+		if err := repo.EnsureRole(ctx, app.ID, role, change.roles[role]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateAppConfig(appCfg config.AppConfig) error {
+	if appCfg.Name == "" {
+		return fmt.Errorf("app name is required")
 	}
 
-	log.Info("failed to get app", slog.String("name", name))
+	if appCfg.SecretEnv == "" {
+		return fmt.Errorf("app %q: secret_env is required", appCfg.Name)
+	}
+
+	for _, grantType := range appCfg.GrantTypes {
+		if !slices.Contains(models.GrantTypes, grantType) {
+			return fmt.Errorf("app %q: invalid grant type %q", appCfg.Name, grantType)
+		}
+	}
 
-	return fmt.Errorf("%s: %w", op, err)
+	return nil
 }
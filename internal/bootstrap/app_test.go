@@ -0,0 +1,125 @@
+package bootstrap
+
+import (
+	"context"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/handlers/slogdiscard"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// appRepo keeps apps in memory, by ID.
+type appRepo struct {
+	apps map[int]models.App
+}
+
+func (r *appRepo) CreateApp(_ context.Context, app models.App) (int, error) {
+	app.ID = len(r.apps) + 1
+	r.apps[app.ID] = app
+
+	return app.ID, nil
+}
+
+func (r *appRepo) UpdateApp(_ context.Context, app models.App) error {
+	r.apps[app.ID] = app
+
+	return nil
+}
+
+func (r *appRepo) Apps(context.Context) ([]models.App, error) {
+	apps := make([]models.App, 0, len(r.apps))
+	for id := 1; id <= len(r.apps); id++ {
+		apps = append(apps, r.apps[id])
+	}
+
+	return apps, nil
+}
+
+func (r *appRepo) RolePermissions(context.Context, int) (map[string][]string, error) {
+	return nil, nil
+}
+
+func (r *appRepo) EnsureRole(context.Context, int, string, []string) error {
+	return nil
+}
+
+func TestInitAppsDisableMissingApps(t *testing.T) {
+	t.Setenv("TEST_APP_SECRET", "secret")
+
+	secretHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &appRepo{apps: map[int]models.App{
+		1: {ID: 1, Name: "declared", SecretHash: secretHash, GrantTypes: []string{models.GrantTypePassword}},
+		2: {ID: 2, Name: "undeclared", SecretHash: secretHash},
+		3: {
+			ID:                    3,
+			Name:                  "registered",
+			SecretHash:            secretHash,
+			GrantTypes:            []string{models.GrantTypeAuthorizationCode},
+			RegistrationTokenHash: []byte("token hash"),
+			RegisteredAt:          time.Now(),
+		},
+	}}
+
+	err = InitApps(context.Background(), slogdiscard.NewDiscardLogger(), repo, config.BootstrapConfig{
+		DisableMissingApps: true,
+		Apps: []config.AppConfig{{
+			Name:       "declared",
+			SecretEnv:  "TEST_APP_SECRET",
+			GrantTypes: []string{models.GrantTypePassword},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("InitApps() error = %v", err)
+	}
+
+	for id, wantDisabled := range map[int]bool{1: false, 2: true, 3: false} {
+		if app := repo.apps[id]; app.Disabled != wantDisabled {
+			t.Errorf("app %q disabled = %v, want %v", app.Name, app.Disabled, wantDisabled)
+		}
+	}
+}
+
+func TestInitAppsDisabled(t *testing.T) {
+	t.Setenv("TEST_APP_SECRET", "secret")
+
+	secretHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disabled, enabled := true, false
+
+	repo := &appRepo{apps: map[int]models.App{
+		1: {ID: 1, Name: "disabled at runtime", SecretHash: secretHash, Disabled: true},
+		2: {ID: 2, Name: "enabled by config", SecretHash: secretHash, Disabled: true},
+		3: {ID: 3, Name: "disabled by config", SecretHash: secretHash},
+	}}
+
+	apps := []config.AppConfig{
+		{Name: "disabled at runtime"},
+		{Name: "enabled by config", Disabled: &enabled},
+		{Name: "disabled by config", Disabled: &disabled},
+		{Name: "created disabled", Disabled: &disabled},
+	}
+	for i := range apps {
+		apps[i].SecretEnv = "TEST_APP_SECRET"
+	}
+
+	err = InitApps(context.Background(), slogdiscard.NewDiscardLogger(), repo, config.BootstrapConfig{Apps: apps})
+	if err != nil {
+		t.Fatalf("InitApps() error = %v", err)
+	}
+
+	for id, wantDisabled := range map[int]bool{1: true, 2: false, 3: true, 4: true} {
+		if app := repo.apps[id]; app.Disabled != wantDisabled {
+			t.Errorf("app %q disabled = %v, want %v", app.Name, app.Disabled, wantDisabled)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/phone"
+	"sso/internal/repository"
+)
+
+type PhoneBootstrapRepository interface {
+	UsersWithNationalPhones(ctx context.Context) ([]models.User, error)
+	User(ctx context.Context, email string, phone string) (models.User, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	ReplacePhone(ctx context.Context, userID int64, oldPhone string, phone string) error
+	PhoneConflicts(ctx context.Context) ([]models.PhoneConflict, error)
+	DeletePhoneConflict(ctx context.Context, userID int64) error
+}
+
+// NormalizePhones converts phones stored before phones were normalized to E.164, treating those
+// without the international prefix as national numbers of cfg.DefaultCountryCode.
+// Phones that can't be converted or belong to another user once converted are left as they are
+// and reported. Phones the migration set aside because another user had them are reported too
+// and given back once they are free, unless the user has a new phone by then.
+func NormalizePhones(
+	ctx context.Context,
+	log *slog.Logger,
+	repo PhoneBootstrapRepository,
+	cfg config.PhoneConfig,
+) error {
+	const op = "bootstrap.NormalizePhones"
+
+	log = log.With(slog.String("op", op))
+
+	phones := phone.NewNormalizer(cfg.DefaultCountryCode, cfg.TrunkPrefix)
+
+	users, err := repo.UsersWithNationalPhones(ctx)
+	if err != nil {
+		log.Error("failed to get users with national phones", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var converted, unresolved int
+	for _, user := range users {
+		log := log.With(slog.Int64("userID", user.ID), slog.String("phone", user.Phone))
+
+		normalized, err := phones.Normalize(user.Phone)
+		if err != nil {
+			log.Warn("phone can't be converted to E.164, is phone.default_country_code set?")
+			unresolved++
+
+			continue
+		}
+
+		if err := repo.ReplacePhone(ctx, user.ID, user.Phone, normalized); err != nil {
+			if errors.Is(err, repository.ErrUserExists) {
+				log.Warn("phone belongs to another user once converted, it's left as it is",
+					slog.String("normalized", normalized),
+					slog.Int64("ownerID", phoneOwner(ctx, repo, normalized)),
+				)
+				unresolved++
+
+				continue
+			}
+			// the user changed the phone meanwhile
+			if errors.Is(err, repository.ErrUserNotFound) {
+				continue
+			}
+
+			log.Error("failed to replace phone", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		converted++
+	}
+
+	conflicts, err := repo.PhoneConflicts(ctx)
+	if err != nil {
+		log.Error("failed to get phone conflicts", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var restored int
+	for _, conflict := range conflicts {
+		ok, err := restorePhone(ctx, log, repo, phones, conflict)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if ok {
+			restored++
+		} else {
+			unresolved++
+		}
+	}
+
+	if converted+restored+unresolved > 0 {
+		log.Info("phones normalized",
+			slog.Int("converted", converted),
+			slog.Int("restored", restored),
+			slog.Int("unresolved", unresolved),
+		)
+	}
+
+	return nil
+}
+
+// restorePhone gives the phone set aside back to the user if it's free and tells whether
+// the conflict is resolved, it is if the user has a new phone as well.
+func restorePhone(
+	ctx context.Context,
+	log *slog.Logger,
+	repo PhoneBootstrapRepository,
+	phones *phone.Normalizer,
+	conflict models.PhoneConflict,
+) (bool, error) {
+	log = log.With(slog.Int64("userID", conflict.UserID), slog.String("phone", conflict.Phone))
+
+	user, err := repo.UserByID(ctx, conflict.UserID)
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+
+		return false, err
+	}
+
+	if user.Phone == "" {
+		normalized, err := phones.Normalize(conflict.Phone)
+		if err != nil {
+			log.Warn("phone set aside can't be converted to E.164, is phone.default_country_code set?")
+
+			return false, nil
+		}
+
+		if err := repo.ReplacePhone(ctx, user.ID, "", normalized); err != nil {
+			if errors.Is(err, repository.ErrUserExists) {
+				log.Warn("phone set aside still belongs to another user",
+					slog.String("normalized", normalized),
+					slog.Int64("ownerID", phoneOwner(ctx, repo, normalized)),
+				)
+
+				return false, nil
+			}
+			// the user set a new phone meanwhile
+			if !errors.Is(err, repository.ErrUserNotFound) {
+				log.Error("failed to restore phone", sl.Err(err))
+
+				return false, err
+			}
+		} else {
+			log.Info("phone set aside is given back", slog.String("normalized", normalized))
+		}
+	}
+
+	if err := repo.DeletePhoneConflict(ctx, conflict.UserID); err != nil {
+		log.Error("failed to delete phone conflict", sl.Err(err))
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// phoneOwner returns the ID of the user with the phone for reports, zero if it can't be found.
+func phoneOwner(ctx context.Context, repo PhoneBootstrapRepository, phone string) int64 {
+	owner, err := repo.User(ctx, "", phone)
+	if err != nil {
+		return 0
+	}
+
+	return owner.ID
+}
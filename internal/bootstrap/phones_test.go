@@ -0,0 +1,138 @@
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/handlers/slogdiscard"
+	"sso/internal/repository/sqlite"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// newLegacyPhonesRepo returns the storage of users with the phones typed before phones were normalized,
+// by user ID, migrated to the latest version.
+func newLegacyPhonesRepo(t *testing.T, phones map[int64]string) *sqlite.Repository {
+	t.Helper()
+
+	storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://../../migrations", "sqlite3://"+storagePath)
+	if err != nil {
+		t.Fatalf("open migrations: %v", err)
+	}
+	defer m.Close()
+
+	// the last version before phones were normalized
+	if err := m.Migrate(19); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", storagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, phone := range phones {
+		_, err := db.Exec(
+			"INSERT INTO users (id, name, last_name, email, pass_hash, phone) VALUES (?, 'Test', 'User', ?, '', ?)",
+			id, phone+"@example.com", phone,
+		)
+		if err != nil {
+			t.Fatalf("save user: %v", err)
+		}
+	}
+	db.Close()
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	repo, err := sqlite.New(storagePath)
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	t.Cleanup(func() { repo.Stop() })
+
+	return repo
+}
+
+func userPhones(t *testing.T, repo *sqlite.Repository, ids ...int64) map[int64]string {
+	t.Helper()
+
+	phones := make(map[int64]string, len(ids))
+	for _, id := range ids {
+		user, err := repo.UserByID(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		phones[id] = user.Phone
+	}
+
+	return phones
+}
+
+func TestNormalizePhones(t *testing.T) {
+	repo := newLegacyPhonesRepo(t, map[int64]string{
+		1: "+7 (999) 111-11-11",
+		2: "8 999 111 11 11",
+		3: "8-999-222-22-22",
+		4: "+7 999 333 33 33",
+		5: "+79993333333",
+		6: "8 999 444 44 44",
+		7: "89994444444",
+		8: "abc",
+	})
+	ctx := context.Background()
+	log := slogdiscard.NewDiscardLogger()
+	cfg := config.PhoneConfig{DefaultCountryCode: "7", TrunkPrefix: "8"}
+
+	if err := NormalizePhones(ctx, log, repo, cfg); err != nil {
+		t.Fatalf("NormalizePhones() error = %v", err)
+	}
+
+	want := map[int64]string{
+		1: "+79991111111",
+		// the converted phone is user 1's, so it's kept as it was
+		2: "89991111111",
+		3: "+79992222222",
+		4: "+79993333333",
+		// the migration set aside the phone user 4 has
+		5: "",
+		6: "+79994444444",
+		7: "",
+		8: "abc",
+	}
+	if got := userPhones(t, repo, 1, 2, 3, 4, 5, 6, 7, 8); !reflect.DeepEqual(got, want) {
+		t.Errorf("phones = %v, want %v", got, want)
+	}
+
+	wantConflicts := []models.PhoneConflict{{UserID: 5, Phone: "+79993333333"}, {UserID: 7, Phone: "89994444444"}}
+	if got, _ := repo.PhoneConflicts(ctx); !reflect.DeepEqual(got, wantConflicts) {
+		t.Errorf("PhoneConflicts() = %v, want %v", got, wantConflicts)
+	}
+
+	// user 4 gives up the phone and user 7 gets a new one
+	if err := repo.ReplacePhone(ctx, 4, "+79993333333", "+79995555555"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.ReplacePhone(ctx, 7, "", "+79996666666"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NormalizePhones(ctx, log, repo, cfg); err != nil {
+		t.Fatalf("NormalizePhones() error = %v", err)
+	}
+
+	if got := userPhones(t, repo, 5, 7); got[5] != "+79993333333" || got[7] != "+79996666666" {
+		t.Errorf("phones = %v, want the set aside phone given back to user 5 and user 7's new phone kept", got)
+	}
+	if got, _ := repo.PhoneConflicts(ctx); len(got) != 0 {
+		t.Errorf("PhoneConflicts() = %v, want none", got)
+	}
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -10,15 +13,31 @@ import (
 )
 
 type Config struct {
-	Env            string        `yaml:"env" env-default:"local"`
-	StoragePath    string        `yaml:"storage_path" env-required:"true"`
-	TokenTTL       time.Duration `yaml:"token_ttl" env-default:"1h"`
-	GRPC           GRPCConfig    `yaml:"grpc"`
-	App            AppConfig     `yaml:"app"`
-	SMTP           SMTPConfig    `yaml:"smtp"`
-	Email          EmailConfig   `yaml:"email"`
-	Redis          RedisConfig   `yaml:"redis"`
-	MigrationsPath string
+	Env                   string                   `yaml:"env" env-default:"local"`
+	StoragePath           string                   `yaml:"storage_path" env-required:"true"`
+	TokenTTL              time.Duration            `yaml:"token_ttl" env-default:"1h"`
+	GRPC                  GRPCConfig               `yaml:"grpc"`
+	HTTP                  HTTPConfig               `yaml:"http"`
+	Bootstrap             BootstrapConfig          `yaml:"bootstrap"`
+	SMTP                  SMTPConfig               `yaml:"smtp"`
+	Email                 EmailConfig              `yaml:"email"`
+	Redis                 RedisConfig              `yaml:"redis"`
+	Admin                 AdminConfig              `yaml:"admin"`
+	Lockout               LockoutConfig            `yaml:"lockout"`
+	Codes                 CodesConfig              `yaml:"codes"`
+	GroupsClaim           GroupsClaimConfig        `yaml:"groups_claim"`
+	APIKeys               APIKeysConfig            `yaml:"api_keys"`
+	Impersonation         ImpersonationConfig      `yaml:"impersonation"`
+	Invitations           InvitationsConfig        `yaml:"invitations"`
+	EmailChange           EmailChangeConfig        `yaml:"email_change"`
+	Phone                 PhoneConfig              `yaml:"phone"`
+	AccountDeletion       AccountDeletionConfig    `yaml:"account_deletion"`
+	Consent               ConsentConfig            `yaml:"consent"`
+	ClientRegistration    ClientRegistrationConfig `yaml:"client_registration"`
+	Federation            FederationConfig         `yaml:"federation"`
+	LDAP                  LDAPConfig               `yaml:"ldap"`
+	EnumerationProtection bool                     `yaml:"enumeration_protection" env-default:"false"`
+	MigrationsPath        string
 }
 
 type GRPCConfig struct {
@@ -26,10 +45,33 @@ type GRPCConfig struct {
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+// HTTPConfig controls the HTTP server browsers are redirected to, e.g. by identity providers.
+type HTTPConfig struct {
+	Port    int           `yaml:"port" env-default:"8080"`
+	Timeout time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
+// BootstrapConfig declares apps reconciled with the storage at startup.
+type BootstrapConfig struct {
+	// DryRun only logs changes needed to match the config without applying them.
+	DryRun bool `yaml:"dry_run" env-default:"false"`
+	// DisableMissingApps disables stored apps that are not declared in Apps, except the ones
+	// clients registered dynamically.
+	DisableMissingApps bool        `yaml:"disable_missing_apps" env-default:"false"`
+	Apps               []AppConfig `yaml:"apps"`
+}
+
 type AppConfig struct {
-	AppID     int    `yaml:"id"`
-	AppName   string `yaml:"name"`
-	AppSecret string `env:"secret"`
+	Name string `yaml:"name"`
+	// SecretEnv is the environment variable holding the app secret.
+	SecretEnv    string   `yaml:"secret_env"`
+	RedirectURIs []string `yaml:"redirect_uris"`
+	GrantTypes   []string `yaml:"grant_types"`
+	// Roles maps role names to permissions they grant.
+	Roles map[string][]string `yaml:"roles"`
+	// Disabled disables or enables the app on every start, nil leaves the stored state alone,
+	// so that apps disabled at runtime stay disabled.
+	Disabled *bool `yaml:"disabled"`
 }
 
 type SMTPConfig struct {
@@ -49,6 +91,10 @@ type EmailConfig struct {
 type EmailTemplate struct {
 	VerificationCode string `yaml:"verification_email"`
 	VerificationName string `yaml:"verification_info"`
+	AccountLocked    string `yaml:"account_locked"`
+	Invitation       string `yaml:"invitation"`
+	EmailChanged     string `yaml:"email_changed"`
+	AccountStatus    string `yaml:"account_status"`
 }
 
 type RedisConfig struct {
@@ -57,6 +103,173 @@ type RedisConfig struct {
 	VerTokenTTL time.Duration `yaml:"token_ttl" env-default:"5m"`
 }
 
+// AdminConfig controls administration of the SSO itself. Users holding the admin permission
+// in the app with AppID manage every user and app, admins of other apps manage only their app.
+// The SSO can't be administered while AppID is zero.
+type AdminConfig struct {
+	AppID int `yaml:"app_id" env:"admin_app_id"`
+}
+
+// LockoutConfig controls progressive delays and temporary lockout after failed logins.
+type LockoutConfig struct {
+	MaxAttempts int           `yaml:"max_attempts" env-default:"5"`
+	BaseDelay   time.Duration `yaml:"base_delay" env-default:"1s"`
+	Duration    time.Duration `yaml:"duration" env-default:"15m"`
+	// Window is how long failed attempts count towards a lockout after the first one, zero for ever.
+	Window time.Duration `yaml:"window" env-default:"1h"`
+}
+
+// CodesConfig controls verification codes sent to users.
+// Codes are stored as HMAC digests keyed with Secret, which is required.
+type CodesConfig struct {
+	Secret         string        `env:"code_secret"`
+	MaxAttempts    int           `yaml:"max_attempts" env-default:"5"`
+	ResendCooldown time.Duration `yaml:"resend_cooldown" env-default:"1m"`
+}
+
+// GroupsClaimConfig controls the groups claim of access tokens.
+// Tokens of users in more than MaxGroups groups get the groups_overage claim instead,
+// so that their size stays bounded.
+type GroupsClaimConfig struct {
+	Enabled   bool `yaml:"enabled" env-default:"false"`
+	MaxGroups int  `yaml:"max_groups" env-default:"50"`
+}
+
+// APIKeysConfig controls lifetime of API keys users create.
+type APIKeysConfig struct {
+	DefaultTTL time.Duration `yaml:"default_ttl" env-default:"2160h"`
+	MaxTTL     time.Duration `yaml:"max_ttl" env-default:"8760h"`
+}
+
+// ImpersonationConfig controls tokens support staff get to act as other users.
+// They are kept short, since the impersonated user isn't aware of them.
+type ImpersonationConfig struct {
+	TokenTTL time.Duration `yaml:"token_ttl" env-default:"15m"`
+}
+
+// InvitationsConfig controls invitations sent by email.
+// The emailed link is URL with the invitation token in the token query parameter.
+type InvitationsConfig struct {
+	TTL time.Duration `yaml:"ttl" env-default:"168h"`
+	URL string        `yaml:"url"`
+}
+
+// PhoneConfig controls normalization of phones to E.164. Phones typed without the international
+// prefix are national numbers of the country with DefaultCountryCode, with TrunkPrefix dropped.
+// Phones stored before they were normalized are converted at startup.
+type PhoneConfig struct {
+	DefaultCountryCode string `yaml:"default_country_code"`
+	TrunkPrefix        string `yaml:"trunk_prefix"`
+}
+
+// EmailChangeConfig controls the notice sent to the old address once the email is changed.
+// The notice links to RevertURL with a token in the token query parameter, valid for RevertTTL.
+type EmailChangeConfig struct {
+	RevertTTL time.Duration `yaml:"revert_ttl" env-default:"168h"`
+	RevertURL string        `yaml:"revert_url"`
+}
+
+// AccountDeletionConfig controls deletion of accounts requested by their users. Accounts are purged
+// GracePeriod after the request, unless the user logs in before. The purger looks for accounts
+// to purge every PurgeInterval. Anonymize keeps user rows with personal data erased instead of deleting them.
+type AccountDeletionConfig struct {
+	GracePeriod   time.Duration `yaml:"grace_period" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+	Anonymize     bool          `yaml:"anonymize" env-default:"false"`
+}
+
+// ConsentConfig controls consent challenges users get on logging in while they haven't accepted
+// the current legal documents. A challenge lets the user finish the login within ChallengeTTL.
+type ConsentConfig struct {
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" env-default:"10m"`
+}
+
+// ClientRegistrationConfig controls dynamic client registration. Initial access tokens admins issue
+// to partners are valid for InitialTokenTTL unless the admin asks for another lifetime, up to InitialTokenMaxTTL.
+type ClientRegistrationConfig struct {
+	InitialTokenTTL    time.Duration `yaml:"initial_token_ttl" env-default:"24h"`
+	InitialTokenMaxTTL time.Duration `yaml:"initial_token_max_ttl" env-default:"720h"`
+}
+
+// FederationConfig declares upstream OpenID Connect providers users can log in with.
+// The user must come back from the provider within LoginTTL, the app then exchanges
+// the login code for the token within CodeTTL.
+type FederationConfig struct {
+	LoginTTL  time.Duration        `yaml:"login_ttl" env-default:"10m"`
+	CodeTTL   time.Duration        `yaml:"code_ttl" env-default:"1m"`
+	Providers []OIDCProviderConfig `yaml:"providers"`
+}
+
+// OIDCProviderConfig describes the client registered at an upstream provider. Logins are linked
+// to existing users by email and users are created for new emails only if the provider verified
+// the email. TrustEmail treats every email of the provider as verified, e.g. for Azure AD tenants
+// that don't send the email_verified claim.
+type OIDCProviderConfig struct {
+	Name     string `yaml:"name"`
+	Issuer   string `yaml:"issuer"`
+	ClientID string `yaml:"client_id"`
+	// ClientSecretEnv is the environment variable holding the client secret.
+	ClientSecretEnv string `yaml:"client_secret_env"`
+	// RedirectURL is the callback of the HTTP server registered at the provider,
+	// e.g. https://sso.example.com/federation/callback.
+	RedirectURL string `yaml:"redirect_url"`
+	// Scopes are requested along with openid, email and profile if empty.
+	Scopes     []string `yaml:"scopes"`
+	TrustEmail bool     `yaml:"trust_email"`
+}
+
+// LDAPConfig lets users log in with passwords kept in an LDAP directory, e.g. Active Directory.
+// Login finds the entry of the email with the service account and binds as the entry with the password,
+// logins not found in the directory are checked against local passwords. Directory users get a local
+// shadow user, its profile and the roles of GroupRoles are synced with the directory on every login.
+// Entries need an email to get a shadow user, local users are linked to entries only by a verified email.
+type LDAPConfig struct {
+	Enabled bool `yaml:"enabled" env-default:"false"`
+	// URL is ldap://host:389 or ldaps://host:636.
+	URL string `yaml:"url"`
+	// StartTLS upgrades ldap:// connections to TLS before binding.
+	StartTLS bool `yaml:"start_tls" env-default:"false"`
+	// CAFile is a PEM file of the CAs the server certificate is verified with instead of the system ones.
+	CAFile  string        `yaml:"ca_file"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+	// BindDN is the service account searching the directory.
+	BindDN string `yaml:"bind_dn"`
+	// BindPasswordEnv is the environment variable holding the password of the service account.
+	BindPasswordEnv string `yaml:"bind_password_env"`
+	BaseDN          string `yaml:"base_dn"`
+	// UserFilter finds the entry of the login, {login} stands for the escaped login.
+	UserFilter string `yaml:"user_filter" env-default:"(&(objectClass=person)(mail={login}))"`
+	// GroupBaseDN is searched for groups of the user, BaseDN if empty.
+	GroupBaseDN string `yaml:"group_base_dn"`
+	// GroupFilter finds groups of the user, {dn} stands for the escaped DN of the user's entry.
+	// Active Directory finds nested groups as well with (member:1.2.840.113556.1.4.1941:={dn}).
+	GroupFilter string                `yaml:"group_filter" env-default:"(&(objectClass=groupOfNames)(member={dn}))"`
+	Attributes  LDAPAttributesConfig  `yaml:"attributes"`
+	GroupRoles  []LDAPGroupRoleConfig `yaml:"group_roles"`
+}
+
+// LDAPAttributesConfig maps attributes of directory entries to user fields.
+type LDAPAttributesConfig struct {
+	// ID identifies entries across renames, e.g. entryUUID or objectGUID. Entries are identified
+	// by their DN if empty.
+	ID       string `yaml:"id"`
+	Email    string `yaml:"email" env-default:"mail"`
+	Name     string `yaml:"name" env-default:"givenName"`
+	LastName string `yaml:"last_name" env-default:"sn"`
+	Phone    string `yaml:"phone" env-default:"telephoneNumber"`
+	// MemberOf lists groups of the entry along with the ones GroupFilter finds, e.g. memberOf.
+	MemberOf string `yaml:"member_of"`
+}
+
+// LDAPGroupRoleConfig grants the role of the app, both referenced by name, to members of the group,
+// referenced by DN. Roles of the mappings are granted and revoked on every login, other roles
+// of directory users are left to admins.
+type LDAPGroupRoleConfig struct {
+	Group string `yaml:"group"`
+	App   string `yaml:"app"`
+	Role  string `yaml:"role"`
+}
+
 func MustLoad() *Config {
 	configPath := fetchConfigPath()
 	if configPath == "" {
@@ -79,9 +292,38 @@ func MustLoadByPath(configPath string) *Config {
 		panic("failed to read config: " + err.Error())
 	}
 
+	if err := cfg.validate(); err != nil {
+		panic("invalid config: " + err.Error())
+	}
+
 	return &cfg
 }
 
+// validate rejects configs the service must not run with.
+func (c *Config) validate() error {
+	// the secret is left as the ${code_secret} placeholder if the variable isn't set
+	if c.Codes.Secret == "" || strings.Contains(c.Codes.Secret, "${") {
+		return errors.New("codes.secret is not set, set the code_secret env variable")
+	}
+
+	return nil
+}
+
+// LogValue logs the config with the secrets it holds redacted.
+func (c Config) LogValue() slog.Value {
+	// the conversion drops the method, so that the copy is logged as it is
+	type config Config
+	redacted := config(c)
+	if redacted.Codes.Secret != "" {
+		redacted.Codes.Secret = "REDACTED"
+	}
+	if redacted.SMTP.Pass != "" {
+		redacted.SMTP.Pass = "REDACTED"
+	}
+
+	return slog.AnyValue(redacted)
+}
+
 // fetches config path from command line flag or env variable.
 func fetchConfigPath() string {
 	var res string
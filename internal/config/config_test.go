@@ -0,0 +1,79 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMustLoadByPathCodeSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "storage_path: ./sso.db\ncodes:\n  secret: \"${code_secret}\"\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		wantPanic bool
+	}{
+		{name: "unset", wantPanic: true},
+		{name: "placeholder", secret: "${code_secret}", wantPanic: true},
+		{name: "set", secret: "secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.secret != "" {
+				t.Setenv("code_secret", tt.secret)
+			} else {
+				// the variable may be set in the environment the tests run in
+				t.Setenv("code_secret", "")
+				os.Unsetenv("code_secret")
+			}
+
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("MustLoadByPath() panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+
+			cfg := MustLoadByPath(path)
+			if cfg.Codes.Secret != tt.secret {
+				t.Errorf("MustLoadByPath() secret = %q, want %q", cfg.Codes.Secret, tt.secret)
+			}
+		})
+	}
+}
+
+func TestConfigLogValueRedactsSecrets(t *testing.T) {
+	cfg := &Config{
+		Codes: CodesConfig{Secret: "code-secret"},
+		SMTP:  SMTPConfig{Pass: "smtp-pass", Host: "smtp.example.com"},
+	}
+
+	for name, handler := range map[string]func(w io.Writer) slog.Handler{
+		"text": func(w io.Writer) slog.Handler { return slog.NewTextHandler(w, nil) },
+		"json": func(w io.Writer) slog.Handler { return slog.NewJSONHandler(w, nil) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(handler(&buf)).Info("starting app", slog.Any("config", cfg))
+
+			out := buf.String()
+			for _, secret := range []string{"code-secret", "smtp-pass"} {
+				if strings.Contains(out, secret) {
+					t.Errorf("log %q contains secret %q", out, secret)
+				}
+			}
+			if !strings.Contains(out, "smtp.example.com") {
+				t.Errorf("log %q doesn't contain the rest of the config", out)
+			}
+		})
+	}
+}
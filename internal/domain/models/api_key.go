@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// APIKey is a long-lived credential of a user or a service account in an app. Only the hash of the key is stored,
+// the prefix identifies the key without revealing it.
+type APIKey struct {
+	ID int64
+	// UserID is the user owning the key, zero if a service account owns it.
+	UserID int64
+	// ServiceAccountID is the service account owning the key, zero if a user owns it.
+	ServiceAccountID int64
+	AppID            int
+	Name             string
+	Prefix           string
+	KeyHash          []byte
+	Scopes           []string
+	CreatedAt        time.Time
+	ExpiresAt        time.Time
+	LastUsedAt       time.Time
+}
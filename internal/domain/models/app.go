@@ -1,7 +1,51 @@
 package models
 
+import "time"
+
+const (
+	GrantTypePassword          = "password"
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// GrantTypes lists grant types an app can be allowed to use.
+var GrantTypes = []string{
+	GrantTypePassword,
+	GrantTypeAuthorizationCode,
+	GrantTypeRefreshToken,
+	GrantTypeClientCredentials,
+}
+
 type App struct {
-	ID         int
-	Name       string
-	SecretHash []byte
+	ID           int
+	Name         string
+	SecretHash   []byte
+	RedirectURIs []string
+	GrantTypes   []string
+	// OwnerID is the user responsible for the app, zero if nobody is.
+	OwnerID int64
+	// Disabled apps can't be logged in to and their tokens are rejected.
+	Disabled bool
+	// OrgID is the organization the app belongs to, zero if the app is open to everyone.
+	OrgID int64
+	// AttributesSchema is the JSON schema attributes of users in the app must match, empty if they are schemaless.
+	AttributesSchema string
+	// ClaimAttributes are keys of user attributes put into tokens issued for the app.
+	ClaimAttributes []string
+	// LogoURI is the logo shown on the consent screen, empty if the app has none.
+	LogoURI string
+	// Contacts are emails of people responsible for the app.
+	Contacts []string
+	// RegistrationTokenHash is the hash of the registration access token managing the app,
+	// nil unless the app registered itself with dynamic client registration.
+	RegistrationTokenHash []byte
+	// RegisteredAt is the time the app registered itself at, zero if an admin created it.
+	RegisteredAt time.Time
+}
+
+// ThirdParty tells whether the app registered itself. Users must grant such apps access
+// on the consent screen before they get tokens for them.
+func (a App) ThirdParty() bool {
+	return !a.RegisteredAt.IsZero()
 }
@@ -0,0 +1,7 @@
+package models
+
+import "encoding/json"
+
+// Attributes are custom data an app attaches to a user, keyed by attribute name.
+// Values are JSON documents.
+type Attributes map[string]json.RawMessage
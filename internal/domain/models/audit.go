@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	AuditActionImpersonate     = "impersonate"
+	AuditActionRequestDeletion = "request_deletion"
+	AuditActionCancelDeletion  = "cancel_deletion"
+	AuditActionPurge           = "purge"
+	AuditActionSuspend         = "suspend"
+	AuditActionReactivate      = "reactivate"
+	AuditActionSetAttributes   = "set_attributes"
+	AuditActionGrantAppAccess  = "grant_app_access"
+	AuditActionRevokeAppAccess = "revoke_app_access"
+	AuditActionLinkIdentity    = "link_identity"
+)
+
+// AuditEntry records a sensitive action of a user. Entries outlive the users they mention.
+type AuditEntry struct {
+	ID           int64
+	ActorID      int64
+	Action       string
+	TargetUserID int64
+	AppID        int
+	// Details explain the action, e.g. the reason given for it.
+	Details   string
+	CreatedAt time.Time
+}
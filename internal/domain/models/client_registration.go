@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// InitialAccessToken lets a partner register one OAuth client with dynamic client registration.
+// Only the hash of the token is stored.
+type InitialAccessToken struct {
+	ID          int64
+	TokenHash   []byte
+	Description string
+	// CreatedBy is the admin who issued the token, zero if the admin was deleted.
+	CreatedBy int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// UsedAt is the time a client was registered with the token at, zero if it is unused.
+	UsedAt time.Time
+	// AppID is the app registered with the token, zero if it is unused or the app was deleted.
+	AppID int
+}
+
+// Usable tells whether a client can be registered with the token at the given time.
+func (t InitialAccessToken) Usable(now time.Time) bool {
+	return t.UsedAt.IsZero() && now.Before(t.ExpiresAt)
+}
@@ -1,6 +1,19 @@
 package models
 
+// CodePurpose binds a verification code to the flow it was issued for,
+// so that a code sent for one flow can't be used to confirm another.
+type CodePurpose string
+
+const (
+	CodePurposePasswordChange CodePurpose = "password_change"
+	CodePurposeEmailVerify    CodePurpose = "email_verify"
+	CodePurposeLogin          CodePurpose = "login"
+	CodePurposePhoneVerify    CodePurpose = "phone_verify"
+)
+
 type Code struct {
-	UserID int64
-	Code   string
+	UserID   int64
+	Purpose  CodePurpose
+	Digest   string
+	Attempts int
 }
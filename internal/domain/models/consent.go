@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+const (
+	LegalDocumentTerms   = "terms"
+	LegalDocumentPrivacy = "privacy"
+)
+
+// LegalDocumentTypes lists kinds of documents users accept.
+var LegalDocumentTypes = []string{
+	LegalDocumentTerms,
+	LegalDocumentPrivacy,
+}
+
+// LegalDocument is a version of a document users accept, like the terms of service.
+// The text itself is published at URL.
+type LegalDocument struct {
+	ID      int64
+	Type    string
+	Version string
+	URL     string
+	// Mandatory versions must be accepted before logging in, unless a later version of the document is.
+	Mandatory bool
+	// PublishedAt is the time the version takes effect at.
+	PublishedAt time.Time
+	CreatedAt   time.Time
+}
+
+// Published tells whether the version is in effect at the given time.
+func (d LegalDocument) Published(now time.Time) bool {
+	return !d.PublishedAt.After(now)
+}
+
+// Consent records the user accepting a version of a legal document.
+type Consent struct {
+	UserID     int64
+	Document   LegalDocument
+	AcceptedAt time.Time
+	// IP is the address the user accepted the document from, empty if unknown.
+	IP string
+}
+
+// ConsentChallenge lets the user who logged in with the right credentials finish the login
+// once the required legal documents are accepted.
+type ConsentChallenge struct {
+	TokenHash []byte
+	UserID    int64
+	AppID     int
+	// OrgID is the organization the user logs in to, zero if none.
+	OrgID int64
+	// GrantType is the grant the user logs in with, the app must still allow it once the challenge is accepted.
+	GrantType string
+	// Scopes are the scopes the app requested, the token is issued for them.
+	Scopes    []string
+	ExpiresAt time.Time
+}
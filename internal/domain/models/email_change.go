@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// EmailChange is a change of the user's email. It is pending until the new address is confirmed,
+// then the old address can revert it until RevertExpiresAt.
+type EmailChange struct {
+	ID              int64
+	UserID          int64
+	OldEmail        string
+	NewEmail        string
+	RevertTokenHash []byte
+	CreatedAt       time.Time
+	ConfirmedAt     time.Time
+	RevertExpiresAt time.Time
+	RevertedAt      time.Time
+}
+
+// Revertible tells if the change was confirmed and can still be reverted at the given time.
+func (c EmailChange) Revertible(now time.Time) bool {
+	return !c.ConfirmedAt.IsZero() && c.RevertedAt.IsZero() && now.Before(c.RevertExpiresAt)
+}
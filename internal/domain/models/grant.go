@@ -0,0 +1,45 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Grant records the scopes a user allowed an app to access on the consent screen
+// of the authorization flow, so that returning users aren't asked again.
+type Grant struct {
+	UserID    int64
+	AppID     int
+	AppName   string
+	Scopes    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MissingScopes returns the scopes the grant doesn't include.
+func (g Grant) MissingScopes(scopes []string) []string {
+	var missing []string
+	for _, scope := range scopes {
+		if !slices.Contains(g.Scopes, scope) && !slices.Contains(missing, scope) {
+			missing = append(missing, scope)
+		}
+	}
+
+	return missing
+}
+
+// ValidScope tells whether the scope is a scope token of RFC 6749: printable ASCII
+// except space, '"' and '\'.
+func ValidScope(scope string) bool {
+	if scope == "" || len(scope) > 128 {
+		return false
+	}
+
+	for _, c := range []byte(scope) {
+		if c < 0x21 || c > 0x7e || c == '"' || c == '\\' {
+			return false
+		}
+	}
+
+	return true
+}
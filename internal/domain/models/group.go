@@ -0,0 +1,7 @@
+package models
+
+type Group struct {
+	ID          int64
+	Name        string
+	Description string
+}
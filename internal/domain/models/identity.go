@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Identity links a user to their account at an upstream identity provider.
+type Identity struct {
+	// Provider is the name of the provider in the config.
+	Provider string
+	// Subject identifies the account at the provider, the sub claim of its ID tokens.
+	Subject string
+	UserID  int64
+	// Email is the email the provider asserted at the last login.
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+}
+
+// FederatedLogin is a login through an upstream identity provider, from sending the user
+// to the provider until the app exchanges the login code for the token.
+type FederatedLogin struct {
+	ID int64
+	// StateHash identifies the login when the provider sends the user back, it's used once.
+	StateHash    []byte
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	AppID        int
+	// OrgID is the organization the user logs in to, zero if none.
+	OrgID int64
+	// RedirectURI is the URI of the app the user is sent back to with the login code.
+	RedirectURI string
+	// ClientState is the state the app passed, it's sent back along with the login code.
+	ClientState string
+	// Scopes are the scopes the app requested, the token is issued for them.
+	Scopes []string
+	// AppCodeChallenge is the S256 PKCE challenge of the app, the login code is exchanged
+	// only with its verifier, so that nobody else can use the code.
+	AppCodeChallenge string
+	ExpiresAt        time.Time
+	// UserID is the user who logged in at the provider, zero until then.
+	UserID int64
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Invitation lets the invited person register with the email it was sent to.
+// The user created on acceptance gets the preset app role and organization membership.
+type Invitation struct {
+	ID        int64
+	Email     string
+	TokenHash []byte
+	// InviterID is the user who sent the invitation, zero if the user was deleted.
+	InviterID int64
+	AppID     int
+	// Role is the role in the app the user gets, none if empty.
+	Role string
+	// OrgID is the organization the user joins with OrgRole, zero if none.
+	OrgID      int64
+	OrgRole    string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	AcceptedAt time.Time
+	RevokedAt  time.Time
+}
+
+// Pending tells whether the invitation can still be accepted.
+func (i Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt.IsZero() && i.RevokedAt.IsZero() && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type LockoutState struct {
+	UserID         int64
+	FailedAttempts int
+	// FirstFailedAt starts the window failed attempts are counted in.
+	FirstFailedAt time.Time
+	LastFailedAt  time.Time
+	LockedUntil   time.Time
+}
+
+// Locked reports whether the user can't log in at the given moment.
+func (s LockoutState) Locked(now time.Time) bool {
+	return now.Before(s.LockedUntil)
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// OrgRoles lists roles a user can have in an organization.
+var OrgRoles = []string{OrgRoleOwner, OrgRoleAdmin, OrgRoleMember}
+
+type Organization struct {
+	ID   int64
+	Name string
+	// AllowedEmailDomains restricts logging in to members with emails in these domains, any if empty.
+	AllowedEmailDomains []string
+	RequireMFA          bool
+	CreatedAt           time.Time
+}
+
+type OrgMember struct {
+	OrgID  int64
+	UserID int64
+	Email  string
+	Role   string
+}
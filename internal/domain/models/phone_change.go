@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// PhoneChange is a change of the user's phone, pending until the new phone is confirmed by SMS.
+type PhoneChange struct {
+	ID          int64
+	UserID      int64
+	OldPhone    string
+	NewPhone    string
+	CreatedAt   time.Time
+	ConfirmedAt time.Time
+}
+
+// PhoneConflict is a phone set aside because another user has it, the phone is given back
+// to the user once it's free.
+type PhoneConflict struct {
+	UserID int64
+	Phone  string
+}
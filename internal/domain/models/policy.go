@@ -0,0 +1,77 @@
+package models
+
+import "strings"
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+const (
+	ConditionEq       = "eq"
+	ConditionNe       = "ne"
+	ConditionIn       = "in"
+	ConditionContains = "contains"
+)
+
+// PolicyRule allows or denies actions on resources of a type to subjects meeting all its conditions.
+type PolicyRule struct {
+	ID     int64
+	AppID  int
+	Name   string
+	Effect string
+	// Actions are action names, "*" or a trailing "*" matches any suffix.
+	Actions []string
+	// ResourceType is the type of resources the rule applies to, "*" or empty for any.
+	ResourceType string
+	Conditions   []PolicyCondition
+}
+
+// Matches tells if the rule applies to the action on a resource of the type.
+func (r PolicyRule) Matches(action string, resourceType string) bool {
+	if r.ResourceType != "" && r.ResourceType != "*" && r.ResourceType != resourceType {
+		return false
+	}
+
+	for _, pattern := range r.Actions {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(action, prefix) {
+				return true
+			}
+		} else if pattern == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PolicyCondition compares an attribute with Value, Values or the Ref attribute, whichever is set first.
+type PolicyCondition struct {
+	Attribute string   `json:"attribute"`
+	Op        string   `json:"op"`
+	Value     string   `json:"value,omitempty"`
+	Values    []string `json:"values,omitempty"`
+	Ref       string   `json:"ref,omitempty"`
+}
+
+// AccessRequest asks whether the subject may perform the action on the resource.
+type AccessRequest struct {
+	SubjectID     int64
+	AppID         int
+	OrgID         int64
+	Action        string
+	ResourceType  string
+	ResourceID    string
+	ResourceAttrs map[string]string
+}
+
+// Decision is the answer to an access request along with the rule it is based on.
+type Decision struct {
+	Allowed bool
+	// RuleID is the matched rule, zero if no rule matched.
+	RuleID   int64
+	RuleName string
+	Effect   string
+	Reason   string
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Profile fields users can change themselves, the email only by confirming the new address.
+const (
+	ProfileFieldTitle     = "title"
+	ProfileFieldBirthDate = "birth_date"
+	ProfileFieldName      = "name"
+	ProfileFieldLastName  = "last_name"
+	ProfileFieldPhone     = "phone"
+	ProfileFieldEmail     = "email"
+)
+
+var Titles = []string{"Mr", "Mrs", "Ms", "Miss", "Mx", "Dr"}
+
+// ProfileChange records a change of one profile field. ChangedBy is the user themselves
+// or the support staff impersonating them.
+type ProfileChange struct {
+	ID        int64
+	UserID    int64
+	ChangedBy int64
+	Field     string
+	OldValue  string
+	NewValue  string
+	ChangedAt time.Time
+}
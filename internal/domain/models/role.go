@@ -0,0 +1,24 @@
+package models
+
+const (
+	// PermissionAdmin grants full access to the app it belongs to.
+	PermissionAdmin = "admin"
+	// PermissionImpersonate lets support staff get tokens of other users of the app.
+	PermissionImpersonate = "impersonate"
+	// PermissionAttributes lets app backends read and write attributes of any user of the app.
+	PermissionAttributes = "attributes"
+	// PermissionCheck lets app backends check access of users of the app against its policy rules.
+	PermissionCheck = "check"
+)
+
+type Role struct {
+	ID    int64
+	AppID int
+	Name  string
+}
+
+type Permission struct {
+	ID    int64
+	AppID int
+	Name  string
+}
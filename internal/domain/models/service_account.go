@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ServiceAccount is a non-human principal owned by either an app or an organization.
+// It authenticates with client credentials or API keys, never with a password.
+type ServiceAccount struct {
+	ID          int64
+	Name        string
+	Description string
+	// AppID is the app owning the account, zero if an organization owns it.
+	AppID int
+	// OrgID is the organization owning the account, zero if an app owns it.
+	OrgID      int64
+	ClientID   string
+	SecretHash []byte
+	Disabled   bool
+	CreatedAt  time.Time
+}
+
+// CanAccess tells whether the account may get tokens for the app.
+// Accounts owned by an app are limited to it, accounts owned by an organization to the apps of the organization.
+func (sa ServiceAccount) CanAccess(app App) bool {
+	if sa.AppID != 0 {
+		return sa.AppID == app.ID
+	}
+
+	return sa.OrgID == app.OrgID
+}
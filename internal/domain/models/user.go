@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type User struct {
 	ID        int64
 	Title     string
@@ -7,6 +9,99 @@ type User struct {
 	Name      string
 	LastName  string
 	Email     string
-	PassHash  []byte
-	Phone     string
+	// EmailVerified tells that the user proved owning the email.
+	EmailVerified bool
+	PassHash      []byte
+	Phone         string
+	CreatedAt     time.Time
+	Status        string
+	// StatusReason explains the status, e.g. why the user was suspended.
+	StatusReason    string
+	StatusChangedAt time.Time
+	// TokensValidAfter revokes tokens issued before it, zero if none were revoked.
+	TokensValidAfter time.Time
+	// DeleteAfter is when the account requested to be deleted gets purged, zero if deletion wasn't requested.
+	DeleteAfter time.Time
+}
+
+const (
+	// UserStatusPending users await activation by an administrator.
+	UserStatusPending = "pending"
+	UserStatusActive  = "active"
+	// UserStatusSuspended users were suspended by an administrator.
+	UserStatusSuspended = "suspended"
+	// UserStatusLocked users are locked out after too many failed logins.
+	UserStatusLocked = "locked"
+	// UserStatusDeleted users requested deletion of the account or were purged but kept anonymized.
+	UserStatusDeleted = "deleted"
+)
+
+// UserStatuses lists statuses a user can have.
+var UserStatuses = []string{
+	UserStatusPending,
+	UserStatusActive,
+	UserStatusSuspended,
+	UserStatusLocked,
+	UserStatusDeleted,
+}
+
+// Blocked tells if the user can't log in nor use tokens and API keys. Locked users keep using
+// the tokens they have, the lockout only stops logging in.
+func (u User) Blocked() bool {
+	return u.Status == UserStatusPending || u.Status == UserStatusSuspended || u.Status == UserStatusDeleted
+}
+
+// DeletionPending tells if the user requested deletion of the account and can still cancel it.
+func (u User) DeletionPending(now time.Time) bool {
+	return !u.DeleteAfter.IsZero() && now.Before(u.DeleteAfter)
+}
+
+// Deleted tells if the account is gone: it was anonymized or its deletion grace period is over,
+// even if it wasn't purged yet.
+func (u User) Deleted(now time.Time) bool {
+	return u.Status == UserStatusDeleted && !u.DeletionPending(now)
+}
+
+// UserFilter selects a page of users. Zero fields don't filter.
+type UserFilter struct {
+	Email         string
+	Phone         string
+	Status        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Offset        int
+	Limit         int
+}
+
+const (
+	PrincipalUser           = "user"
+	PrincipalServiceAccount = "service_account"
+)
+
+// Principal is the caller authenticated by an access token or API key.
+type Principal struct {
+	// Type is PrincipalUser or PrincipalServiceAccount.
+	Type string
+	// UserID is the ID of the user or of the service account, depending on Type.
+	UserID int64
+	Email  string
+	AppID  int
+	Roles  []string
+	// OrgID is the organization the token was issued for, zero if none.
+	OrgID   int64
+	OrgRole string
+	// Groups are names of groups the user belongs to, nil if they weren't requested.
+	Groups []string
+	// GroupsOverage tells that the user is in too many groups to list them in the token.
+	GroupsOverage bool
+	// Attributes are attributes of the user in the app the app maps to token claims, nil if none are.
+	Attributes Attributes
+	// Scopes limit what the principal may do: scopes of the API key or those the app requested
+	// when the access token was issued, empty if it requested none.
+	Scopes []string
+	// ActorID is the user impersonating the principal, zero if the principal isn't impersonated.
+	ActorID    int64
+	ActorEmail string
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
 }
@@ -0,0 +1,36 @@
+package account
+
+import (
+	"sso/internal/domain/models"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+)
+
+func ToProtoProfile(u models.User) *ssov1.Profile {
+	p := &ssov1.Profile{
+		UserId:        u.ID,
+		Title:         u.Title,
+		Name:          u.Name,
+		LastName:      u.LastName,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		Phone:         u.Phone,
+		BirthDate:     u.BirthDate,
+	}
+	if !u.CreatedAt.IsZero() {
+		p.CreatedAt = u.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	return p
+}
+
+func ToProtoAppGrant(g models.Grant) *ssov1.AppGrant {
+	return &ssov1.AppGrant{
+		AppId:     int32(g.AppID),
+		AppName:   g.AppName,
+		Scopes:    g.Scopes,
+		CreatedAt: g.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: g.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,408 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/lib/profile"
+	"sso/internal/services/account"
+	"sso/internal/services/auth"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Account interface {
+	Profile(ctx context.Context, caller models.Principal) (models.User, error)
+	UpdateProfile(ctx context.Context, caller models.Principal, upd models.User) (models.User, error)
+	RequestAccountDeletion(ctx context.Context, caller models.Principal, password string) (time.Time, error)
+	ExportMyData(ctx context.Context, caller models.Principal) ([]byte, error)
+	AuthorizeApp(
+		ctx context.Context,
+		caller models.Principal,
+		appID int,
+		redirectURI string,
+		scopes []string,
+		approve bool,
+	) (models.Grant, error)
+	AuthorizedApps(ctx context.Context, caller models.Principal) ([]models.Grant, error)
+	RevokeAppAccess(ctx context.Context, caller models.Principal, appID int) error
+}
+
+type EmailChange interface {
+	ChangeEmailInit(ctx context.Context, caller models.Principal, newEmail string) (time.Time, error)
+	ChangeEmailConfirm(ctx context.Context, caller models.Principal, code string) error
+}
+
+type PhoneChange interface {
+	ChangePhoneInit(ctx context.Context, caller models.Principal, newPhone string) (time.Time, error)
+	ChangePhoneConfirm(ctx context.Context, caller models.Principal, code string) error
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedAccountServer
+	account     Account
+	emailChange EmailChange
+	phoneChange PhoneChange
+}
+
+func Register(gRPC *grpc.Server, account Account, emailChange EmailChange, phoneChange PhoneChange) {
+	ssov1.RegisterAccountServer(gRPC, &serverAPI{
+		account:     account,
+		emailChange: emailChange,
+		phoneChange: phoneChange,
+	})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) GetProfile(
+	ctx context.Context,
+	req *ssov1.GetProfileRequest,
+) (*ssov1.GetProfileResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.account.Profile(ctx, caller)
+	if err != nil {
+		return nil, accountError(err, "failed to get profile")
+	}
+
+	return &ssov1.GetProfileResponse{Profile: ToProtoProfile(user)}, nil
+}
+
+func (s *serverAPI) UpdateProfile(
+	ctx context.Context,
+	req *ssov1.UpdateProfileRequest,
+) (*ssov1.UpdateProfileResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upd := models.User{
+		Title:     req.GetTitle(),
+		BirthDate: req.GetBirthDate(),
+		Name:      req.GetName(),
+		LastName:  req.GetLastName(),
+	}
+	if err := profile.Validate(upd); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	user, err := s.account.UpdateProfile(ctx, caller, upd)
+	if err != nil {
+		return nil, accountError(err, "failed to update profile")
+	}
+
+	return &ssov1.UpdateProfileResponse{Profile: ToProtoProfile(user)}, nil
+}
+
+func (s *serverAPI) ChangeEmailInit(
+	ctx context.Context,
+	req *ssov1.ChangeEmailInitRequest,
+) (*ssov1.ChangeEmailInitResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetNewEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_email is required")
+	}
+
+	expiresAt, err := s.emailChange.ChangeEmailInit(ctx, caller, req.GetNewEmail())
+	if err != nil {
+		return nil, emailChangeError(err, "failed to change email")
+	}
+
+	return &ssov1.ChangeEmailInitResponse{ExpiresAt: expiresAt.UTC().Format(time.RFC3339)}, nil
+}
+
+func (s *serverAPI) ChangeEmailConfirm(
+	ctx context.Context,
+	req *ssov1.ChangeEmailConfirmRequest,
+) (*ssov1.ChangeEmailConfirmResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	if err := s.emailChange.ChangeEmailConfirm(ctx, caller, req.GetCode()); err != nil {
+		return nil, emailChangeError(err, "failed to change email")
+	}
+
+	return &ssov1.ChangeEmailConfirmResponse{Success: true}, nil
+}
+
+func (s *serverAPI) ChangePhoneInit(
+	ctx context.Context,
+	req *ssov1.ChangePhoneInitRequest,
+) (*ssov1.ChangePhoneInitResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetNewPhone() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_phone is required")
+	}
+
+	expiresAt, err := s.phoneChange.ChangePhoneInit(ctx, caller, req.GetNewPhone())
+	if err != nil {
+		return nil, phoneChangeError(err, "failed to change phone")
+	}
+
+	return &ssov1.ChangePhoneInitResponse{ExpiresAt: expiresAt.UTC().Format(time.RFC3339)}, nil
+}
+
+func (s *serverAPI) ChangePhoneConfirm(
+	ctx context.Context,
+	req *ssov1.ChangePhoneConfirmRequest,
+) (*ssov1.ChangePhoneConfirmResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	if err := s.phoneChange.ChangePhoneConfirm(ctx, caller, req.GetCode()); err != nil {
+		return nil, phoneChangeError(err, "failed to change phone")
+	}
+
+	return &ssov1.ChangePhoneConfirmResponse{Success: true}, nil
+}
+
+func (s *serverAPI) RequestAccountDeletion(
+	ctx context.Context,
+	req *ssov1.RequestAccountDeletionRequest,
+) (*ssov1.RequestAccountDeletionResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	deleteAfter, err := s.account.RequestAccountDeletion(ctx, caller, req.GetPassword())
+	if err != nil {
+		return nil, accountError(err, "failed to request account deletion")
+	}
+
+	return &ssov1.RequestAccountDeletionResponse{DeleteAfter: deleteAfter.UTC().Format(time.RFC3339)}, nil
+}
+
+func (s *serverAPI) ExportMyData(
+	ctx context.Context,
+	req *ssov1.ExportMyDataRequest,
+) (*ssov1.ExportMyDataResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.account.ExportMyData(ctx, caller)
+	if err != nil {
+		return nil, accountError(err, "failed to export data")
+	}
+
+	return &ssov1.ExportMyDataResponse{Data: data, ContentType: "application/json"}, nil
+}
+
+func (s *serverAPI) AuthorizeApp(
+	ctx context.Context,
+	req *ssov1.AuthorizeAppRequest,
+) (*ssov1.AuthorizeAppResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+	if req.GetRedirectUri() == "" {
+		return nil, status.Error(codes.InvalidArgument, "redirect_uri is required")
+	}
+
+	grant, err := s.account.AuthorizeApp(
+		ctx,
+		caller,
+		int(req.GetAppId()),
+		req.GetRedirectUri(),
+		req.GetScopes(),
+		req.GetApprove(),
+	)
+	if err != nil {
+		var consentErr *account.ConsentRequiredError
+		if errors.As(err, &consentErr) {
+			return &ssov1.AuthorizeAppResponse{
+				ConsentRequired: true,
+				AppName:         consentErr.App.Name,
+				Scopes:          consentErr.Scopes,
+				NewScopes:       consentErr.Missing,
+			}, nil
+		}
+		return nil, grantError(err, "failed to authorize app")
+	}
+
+	return &ssov1.AuthorizeAppResponse{
+		AppName: grant.AppName,
+		Scopes:  req.GetScopes(),
+		Grant:   ToProtoAppGrant(grant),
+	}, nil
+}
+
+func (s *serverAPI) ListAuthorizedApps(
+	ctx context.Context,
+	req *ssov1.ListAuthorizedAppsRequest,
+) (*ssov1.ListAuthorizedAppsResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := s.account.AuthorizedApps(ctx, caller)
+	if err != nil {
+		return nil, grantError(err, "failed to list authorized apps")
+	}
+
+	resp := &ssov1.ListAuthorizedAppsResponse{}
+	for _, grant := range grants {
+		resp.Grants = append(resp.Grants, ToProtoAppGrant(grant))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeAppAccess(
+	ctx context.Context,
+	req *ssov1.RevokeAppAccessRequest,
+) (*ssov1.RevokeAppAccessResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.account.RevokeAppAccess(ctx, caller, int(req.GetAppId())); err != nil {
+		return nil, grantError(err, "failed to revoke app access")
+	}
+
+	return &ssov1.RevokeAppAccessResponse{Success: true}, nil
+}
+
+// callerFromContext returns the user authenticated by the interceptor.
+func callerFromContext(ctx context.Context) (models.Principal, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+	if caller.Type != models.PrincipalUser {
+		return models.Principal{}, status.Error(codes.PermissionDenied, "service accounts have no profile")
+	}
+
+	return caller, nil
+}
+
+// accountError maps errors of calls on the caller's account to gRPC statuses.
+func accountError(err error, msg string) error {
+	switch {
+	case errors.Is(err, account.ErrInvalidProfile):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, account.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, account.ErrInvalidCredentials):
+		return status.Error(codes.Unauthenticated, "invalid password")
+	case errors.Is(err, account.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "not allowed while impersonating")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+// grantError maps errors of calls on apps the caller granted access to to gRPC statuses.
+func grantError(err error, msg string) error {
+	switch {
+	case errors.Is(err, account.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, account.ErrInvalidRedirectURI):
+		return status.Error(codes.InvalidArgument, "redirect_uri isn't registered for the app")
+	case errors.Is(err, account.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, "invalid scope")
+	case errors.Is(err, account.ErrGrantNotFound):
+		return status.Error(codes.NotFound, "app has no access")
+	case errors.Is(err, account.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "not allowed while impersonating")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+// emailChangeError maps errors of email changes to gRPC statuses.
+func emailChangeError(err error, msg string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidEmail):
+		return status.Error(codes.InvalidArgument, "invalid email")
+	case errors.Is(err, auth.ErrUserExists):
+		return status.Error(codes.AlreadyExists, "email is already taken")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "email can't be changed while impersonating")
+	case errors.Is(err, auth.ErrInvalidCode):
+		return status.Error(codes.InvalidArgument, "invalid verification code")
+	case errors.Is(err, auth.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, "too many verification attempts")
+	case errors.Is(err, auth.ErrCodeResendCooldown):
+		return status.Error(codes.ResourceExhausted, "verification code was sent recently")
+	case errors.Is(err, auth.ErrInvalidEmailChange):
+		return status.Error(codes.FailedPrecondition, "email was changed meanwhile")
+	case errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+// phoneChangeError maps errors of phone changes to gRPC statuses.
+func phoneChangeError(err error, msg string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidPhone):
+		return status.Error(codes.InvalidArgument, "phone must be in E.164 format, e.g. +14155552671")
+	case errors.Is(err, auth.ErrUserExists):
+		return status.Error(codes.AlreadyExists, "phone is already taken")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "phone can't be changed while impersonating")
+	case errors.Is(err, auth.ErrInvalidCode):
+		return status.Error(codes.InvalidArgument, "invalid verification code")
+	case errors.Is(err, auth.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, "too many verification attempts")
+	case errors.Is(err, auth.ErrCodeResendCooldown):
+		return status.Error(codes.ResourceExhausted, "verification code was sent recently")
+	case errors.Is(err, auth.ErrInvalidPhoneChange):
+		return status.Error(codes.FailedPrecondition, "phone was changed meanwhile")
+	case errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
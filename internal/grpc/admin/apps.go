@@ -0,0 +1,217 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/lib/jsonschema"
+	"sso/internal/services/admin"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *serverAPI) CreateApp(
+	ctx context.Context,
+	req *ssov1.CreateAppRequest,
+) (*ssov1.CreateAppResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if err := validateAttributesSchema(req.GetAttributesSchema()); err != nil {
+		return nil, err
+	}
+
+	app, secret, err := s.admin.CreateApp(ctx, models.App{
+		Name:             req.GetName(),
+		RedirectURIs:     req.GetRedirectUris(),
+		GrantTypes:       req.GetGrantTypes(),
+		OwnerID:          req.GetOwnerId(),
+		OrgID:            req.GetOrgId(),
+		AttributesSchema: req.GetAttributesSchema(),
+		ClaimAttributes:  req.GetClaimAttributes(),
+	})
+	if err != nil {
+		return nil, appError(err, "failed to create app")
+	}
+
+	return &ssov1.CreateAppResponse{
+		App:    ToProtoAppDetails(app),
+		Secret: secret,
+	}, nil
+}
+
+func (s *serverAPI) ListApps(
+	ctx context.Context,
+	req *ssov1.ListAppsRequest,
+) (*ssov1.ListAppsResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	apps, err := s.admin.ListApps(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list apps")
+	}
+
+	resp := &ssov1.ListAppsResponse{}
+	for _, app := range apps {
+		resp.Apps = append(resp.Apps, ToProtoAppDetails(app))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetApp(
+	ctx context.Context,
+	req *ssov1.GetAppRequest,
+) (*ssov1.GetAppResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateAppID(req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	app, err := s.admin.App(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, appError(err, "failed to get app")
+	}
+
+	return &ssov1.GetAppResponse{App: ToProtoAppDetails(app)}, nil
+}
+
+func (s *serverAPI) UpdateApp(
+	ctx context.Context,
+	req *ssov1.UpdateAppRequest,
+) (*ssov1.UpdateAppResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateAppID(req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if err := validateAttributesSchema(req.GetAttributesSchema()); err != nil {
+		return nil, err
+	}
+
+	app, err := s.admin.UpdateApp(ctx, models.App{
+		ID:               int(req.GetAppId()),
+		Name:             req.GetName(),
+		RedirectURIs:     req.GetRedirectUris(),
+		GrantTypes:       req.GetGrantTypes(),
+		OwnerID:          req.GetOwnerId(),
+		Disabled:         req.GetDisabled(),
+		OrgID:            req.GetOrgId(),
+		AttributesSchema: req.GetAttributesSchema(),
+		ClaimAttributes:  req.GetClaimAttributes(),
+	})
+	if err != nil {
+		return nil, appError(err, "failed to update app")
+	}
+
+	return &ssov1.UpdateAppResponse{App: ToProtoAppDetails(app)}, nil
+}
+
+func (s *serverAPI) RotateAppSecret(
+	ctx context.Context,
+	req *ssov1.RotateAppSecretRequest,
+) (*ssov1.RotateAppSecretResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateAppID(req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.admin.RotateAppSecret(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, appError(err, "failed to rotate app secret")
+	}
+
+	return &ssov1.RotateAppSecretResponse{Secret: secret}, nil
+}
+
+func (s *serverAPI) DeleteApp(
+	ctx context.Context,
+	req *ssov1.DeleteAppRequest,
+) (*ssov1.DeleteAppResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateAppID(req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	// deleting the app the caller is admin of would lock them out
+	if caller.AppID == int(req.GetAppId()) {
+		return nil, status.Error(codes.FailedPrecondition, "can't delete the app of the current token")
+	}
+
+	if err := s.admin.DeleteApp(ctx, int(req.GetAppId())); err != nil {
+		return nil, appError(err, "failed to delete app")
+	}
+
+	return &ssov1.DeleteAppResponse{Success: true}, nil
+}
+
+// appError maps errors of calls on apps to gRPC statuses.
+func appError(err error, msg string) error {
+	switch {
+	case errors.Is(err, admin.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, admin.ErrAppExists):
+		return status.Error(codes.AlreadyExists, "app with this name already exists")
+	case errors.Is(err, admin.ErrInvalidGrantType):
+		return status.Error(codes.InvalidArgument, "invalid grant type")
+	case errors.Is(err, admin.ErrInvalidRedirectURI):
+		return status.Error(codes.InvalidArgument, "redirect uri must be an absolute url without fragment")
+	case errors.Is(err, admin.ErrUserNotFound):
+		return status.Error(codes.InvalidArgument, "owner not found")
+	case errors.Is(err, admin.ErrOrgNotFound):
+		return status.Error(codes.InvalidArgument, "organization not found")
+	case errors.Is(err, admin.ErrInvalidSchema):
+		return status.Error(codes.InvalidArgument, "invalid attributes schema")
+	case errors.Is(err, admin.ErrInvalidClaim):
+		return status.Error(codes.InvalidArgument, "claim attributes must be unique non-empty keys")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func validateAppID(appID int32) error {
+	if appID == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	return nil
+}
+
+// validateAttributesSchema tells what is wrong with the schema, the service only rejects it.
+func validateAttributesSchema(schema string) error {
+	if schema == "" {
+		return nil
+	}
+
+	if _, err := jsonschema.Compile([]byte(schema)); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"context"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *serverAPI) ListAuditLog(
+	ctx context.Context,
+	req *ssov1.ListAuditLogRequest,
+) (*ssov1.ListAuditLogResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit == emptyValue {
+		limit = defaultPageSize
+	}
+	if limit < 0 || limit > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxPageSize)
+	}
+
+	entries, err := s.admin.AuditLog(ctx, req.GetUserId(), limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list audit log")
+	}
+
+	resp := &ssov1.ListAuditLogResponse{}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, ToProtoAuditEntry(entry))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) ListProfileChanges(
+	ctx context.Context,
+	req *ssov1.ListProfileChangesRequest,
+) (*ssov1.ListProfileChangesResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit == emptyValue {
+		limit = defaultPageSize
+	}
+	if limit < 0 || limit > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxPageSize)
+	}
+
+	changes, err := s.admin.ProfileChanges(ctx, req.GetUserId(), limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list profile changes")
+	}
+
+	resp := &ssov1.ListProfileChangesResponse{}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, ToProtoProfileChange(change))
+	}
+
+	return resp, nil
+}
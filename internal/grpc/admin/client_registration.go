@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"sso/internal/services/admin"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *serverAPI) CreateInitialAccessToken(
+	ctx context.Context,
+	req *ssov1.CreateInitialAccessTokenRequest,
+) (*ssov1.CreateInitialAccessTokenResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetExpiresIn() < emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "expires_in must not be negative")
+	}
+
+	token, plain, err := s.admin.CreateInitialAccessToken(
+		ctx,
+		caller,
+		req.GetDescription(),
+		time.Duration(req.GetExpiresIn())*time.Second,
+	)
+	if err != nil {
+		if errors.Is(err, admin.ErrInvalidTokenTTL) {
+			return nil, status.Error(codes.InvalidArgument, "expires_in exceeds the maximum lifetime")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create initial access token")
+	}
+
+	return &ssov1.CreateInitialAccessTokenResponse{
+		Id:        token.ID,
+		Token:     plain,
+		ExpiresAt: token.ExpiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
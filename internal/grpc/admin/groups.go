@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"sso/internal/services/admin"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *serverAPI) CreateGroup(
+	ctx context.Context,
+	req *ssov1.CreateGroupRequest,
+) (*ssov1.CreateGroupResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	group, err := s.admin.CreateGroup(ctx, req.GetName(), req.GetDescription())
+	if err != nil {
+		return nil, groupError(err, "failed to create group")
+	}
+
+	return &ssov1.CreateGroupResponse{Group: ToProtoGroup(group)}, nil
+}
+
+func (s *serverAPI) ListGroups(
+	ctx context.Context,
+	req *ssov1.ListGroupsRequest,
+) (*ssov1.ListGroupsResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.admin.ListGroups(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list groups")
+	}
+
+	resp := &ssov1.ListGroupsResponse{}
+	for _, group := range groups {
+		resp.Groups = append(resp.Groups, ToProtoGroup(group))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DeleteGroup(
+	ctx context.Context,
+	req *ssov1.DeleteGroupRequest,
+) (*ssov1.DeleteGroupResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateGroupID(req.GetGroupId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.DeleteGroup(ctx, req.GetGroupId()); err != nil {
+		return nil, groupError(err, "failed to delete group")
+	}
+
+	return &ssov1.DeleteGroupResponse{Success: true}, nil
+}
+
+func (s *serverAPI) AddMember(
+	ctx context.Context,
+	req *ssov1.AddMemberRequest,
+) (*ssov1.AddMemberResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateGroupMember(req.GetGroupId(), req.GetUserId(), req.GetMemberGroupId()); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if req.GetUserId() != emptyValue {
+		err = s.admin.AddGroupUser(ctx, req.GetGroupId(), req.GetUserId())
+	} else {
+		err = s.admin.AddSubgroup(ctx, req.GetGroupId(), req.GetMemberGroupId())
+	}
+	if err != nil {
+		return nil, groupError(err, "failed to add group member")
+	}
+
+	return &ssov1.AddMemberResponse{Success: true}, nil
+}
+
+func (s *serverAPI) RemoveMember(
+	ctx context.Context,
+	req *ssov1.RemoveMemberRequest,
+) (*ssov1.RemoveMemberResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateGroupMember(req.GetGroupId(), req.GetUserId(), req.GetMemberGroupId()); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if req.GetUserId() != emptyValue {
+		err = s.admin.RemoveGroupUser(ctx, req.GetGroupId(), req.GetUserId())
+	} else {
+		err = s.admin.RemoveSubgroup(ctx, req.GetGroupId(), req.GetMemberGroupId())
+	}
+	if err != nil {
+		return nil, groupError(err, "failed to remove group member")
+	}
+
+	return &ssov1.RemoveMemberResponse{Success: true}, nil
+}
+
+func (s *serverAPI) GetUserGroups(
+	ctx context.Context,
+	req *ssov1.GetUserGroupsRequest,
+) (*ssov1.GetUserGroupsResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.admin.UserGroups(ctx, req.GetUserId())
+	if err != nil {
+		return nil, userError(err, "failed to get user groups")
+	}
+
+	resp := &ssov1.GetUserGroupsResponse{}
+	for _, group := range groups {
+		resp.Groups = append(resp.Groups, ToProtoGroup(group))
+	}
+
+	return resp, nil
+}
+
+// groupError maps errors of calls on groups to gRPC statuses.
+func groupError(err error, msg string) error {
+	switch {
+	case errors.Is(err, admin.ErrGroupNotFound):
+		return status.Error(codes.NotFound, "group not found")
+	case errors.Is(err, admin.ErrGroupExists):
+		return status.Error(codes.AlreadyExists, "group with this name already exists")
+	case errors.Is(err, admin.ErrGroupCycle):
+		return status.Error(codes.FailedPrecondition, "group can't contain itself")
+	case errors.Is(err, admin.ErrNotGroupMember):
+		return status.Error(codes.NotFound, "not a member of the group")
+	case errors.Is(err, admin.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func validateGroupID(groupID int64) error {
+	if groupID == emptyValue {
+		return status.Error(codes.InvalidArgument, "group_id is required")
+	}
+
+	return nil
+}
+
+func validateGroupMember(groupID int64, userID int64, memberGroupID int64) error {
+	if err := validateGroupID(groupID); err != nil {
+		return err
+	}
+
+	if (userID == emptyValue) == (memberGroupID == emptyValue) {
+		return status.Error(codes.InvalidArgument, "exactly one of user_id and member_group_id is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/services/admin"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *serverAPI) CreateLegalDocument(
+	ctx context.Context,
+	req *ssov1.CreateLegalDocumentRequest,
+) (*ssov1.CreateLegalDocumentResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateLegalDocument(req); err != nil {
+		return nil, err
+	}
+
+	doc := models.LegalDocument{
+		Type:      req.GetType(),
+		Version:   req.GetVersion(),
+		URL:       req.GetUrl(),
+		Mandatory: req.GetMandatory(),
+	}
+
+	if req.GetPublishedAt() != "" {
+		var err error
+		if doc.PublishedAt, err = time.Parse(time.RFC3339, req.GetPublishedAt()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "published_at must be RFC3339 time")
+		}
+	}
+
+	doc, err := s.admin.CreateLegalDocument(ctx, doc)
+	if err != nil {
+		return nil, legalDocumentError(err, "failed to create legal document")
+	}
+
+	return &ssov1.CreateLegalDocumentResponse{Document: ToProtoLegalDocument(doc)}, nil
+}
+
+func (s *serverAPI) ListLegalDocuments(
+	ctx context.Context,
+	req *ssov1.ListLegalDocumentsRequest,
+) (*ssov1.ListLegalDocumentsResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	docs, err := s.admin.LegalDocuments(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list legal documents")
+	}
+
+	resp := &ssov1.ListLegalDocumentsResponse{}
+	for _, doc := range docs {
+		resp.Documents = append(resp.Documents, ToProtoLegalDocument(doc))
+	}
+
+	return resp, nil
+}
+
+// legalDocumentError maps errors of calls on legal documents to gRPC statuses.
+func legalDocumentError(err error, msg string) error {
+	switch {
+	case errors.Is(err, admin.ErrInvalidLegalDocument):
+		return status.Error(codes.InvalidArgument, "invalid legal document")
+	case errors.Is(err, admin.ErrLegalDocumentExists):
+		return status.Error(codes.AlreadyExists, "legal document with this type and version already exists")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func validateLegalDocument(req *ssov1.CreateLegalDocumentRequest) error {
+	if !slices.Contains(models.LegalDocumentTypes, req.GetType()) {
+		return status.Errorf(codes.InvalidArgument, "type must be one of %v", models.LegalDocumentTypes)
+	}
+	if req.GetVersion() == "" {
+		return status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	u, err := url.Parse(req.GetUrl())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return status.Error(codes.InvalidArgument, "url must be an absolute http(s) URL")
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"sso/internal/domain/models"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+)
+
+func ToProtoUserDetails(u models.User) *ssov1.UserDetails {
+	details := &ssov1.UserDetails{
+		Id:            u.ID,
+		Title:         u.Title,
+		Name:          u.Name,
+		LastName:      u.LastName,
+		Email:         u.Email,
+		Phone:         u.Phone,
+		BirthDate:     u.BirthDate,
+		Disabled:      u.Blocked(),
+		EmailVerified: u.EmailVerified,
+		Status:        u.Status,
+		StatusReason:  u.StatusReason,
+	}
+	if !u.CreatedAt.IsZero() {
+		details.CreatedAt = u.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if !u.StatusChangedAt.IsZero() {
+		details.StatusChangedAt = u.StatusChangedAt.UTC().Format(time.RFC3339)
+	}
+
+	return details
+}
+
+func ToProtoAppDetails(a models.App) *ssov1.AppDetails {
+	return &ssov1.AppDetails{
+		Id:               int32(a.ID),
+		Name:             a.Name,
+		RedirectUris:     a.RedirectURIs,
+		GrantTypes:       a.GrantTypes,
+		OwnerId:          a.OwnerID,
+		Disabled:         a.Disabled,
+		OrgId:            a.OrgID,
+		AttributesSchema: a.AttributesSchema,
+		ClaimAttributes:  a.ClaimAttributes,
+		LogoUri:          a.LogoURI,
+		Contacts:         a.Contacts,
+		SelfRegistered:   a.RegistrationTokenHash != nil,
+	}
+}
+
+func ToProtoGroup(g models.Group) *ssov1.Group {
+	return &ssov1.Group{
+		Id:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+	}
+}
+
+func ToProtoPolicyRule(r models.PolicyRule) *ssov1.PolicyRule {
+	rule := &ssov1.PolicyRule{
+		Id:           r.ID,
+		AppId:        int32(r.AppID),
+		Name:         r.Name,
+		Effect:       r.Effect,
+		Actions:      r.Actions,
+		ResourceType: r.ResourceType,
+	}
+	for _, c := range r.Conditions {
+		rule.Conditions = append(rule.Conditions, &ssov1.PolicyCondition{
+			Attribute: c.Attribute,
+			Op:        c.Op,
+			Value:     c.Value,
+			Values:    c.Values,
+			Ref:       c.Ref,
+		})
+	}
+
+	return rule
+}
+
+func FromProtoPolicyRule(r *ssov1.PolicyRule) models.PolicyRule {
+	rule := models.PolicyRule{
+		AppID:        int(r.GetAppId()),
+		Name:         r.GetName(),
+		Effect:       r.GetEffect(),
+		Actions:      r.GetActions(),
+		ResourceType: r.GetResourceType(),
+	}
+	for _, c := range r.GetConditions() {
+		rule.Conditions = append(rule.Conditions, models.PolicyCondition{
+			Attribute: c.GetAttribute(),
+			Op:        c.GetOp(),
+			Value:     c.GetValue(),
+			Values:    c.GetValues(),
+			Ref:       c.GetRef(),
+		})
+	}
+
+	return rule
+}
+
+func ToProtoLockoutState(s models.LockoutState) *ssov1.GetLockoutStateResponse {
+	resp := &ssov1.GetLockoutStateResponse{
+		FailedAttempts: int32(s.FailedAttempts),
+		Locked:         s.Locked(time.Now()),
+	}
+	if resp.Locked {
+		resp.LockedUntil = s.LockedUntil.UTC().Format(time.RFC3339)
+	}
+	if !s.LastFailedAt.IsZero() {
+		resp.LastFailedAt = s.LastFailedAt.UTC().Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func ToProtoAuditEntry(e models.AuditEntry) *ssov1.AuditEntry {
+	return &ssov1.AuditEntry{
+		Id:           e.ID,
+		ActorId:      e.ActorID,
+		Action:       e.Action,
+		TargetUserId: e.TargetUserID,
+		AppId:        int32(e.AppID),
+		Details:      e.Details,
+		CreatedAt:    e.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func ToProtoProfileChange(c models.ProfileChange) *ssov1.ProfileChange {
+	return &ssov1.ProfileChange{
+		Id:        c.ID,
+		ChangedBy: c.ChangedBy,
+		Field:     c.Field,
+		OldValue:  c.OldValue,
+		NewValue:  c.NewValue,
+		ChangedAt: c.ChangedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func ToProtoLegalDocument(d models.LegalDocument) *ssov1.LegalDocument {
+	return &ssov1.LegalDocument{
+		Id:          d.ID,
+		Type:        d.Type,
+		Version:     d.Version,
+		Url:         d.URL,
+		Mandatory:   d.Mandatory,
+		PublishedAt: d.PublishedAt.UTC().Format(time.RFC3339),
+	}
+}
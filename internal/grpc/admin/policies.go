@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"sso/internal/lib/policy"
+	"sso/internal/services/admin"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *serverAPI) CreatePolicyRule(
+	ctx context.Context,
+	req *ssov1.CreatePolicyRuleRequest,
+) (*ssov1.CreatePolicyRuleResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.GetRule() == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	if err := validateAppID(req.GetRule().GetAppId()); err != nil {
+		return nil, err
+	}
+
+	rule := FromProtoPolicyRule(req.GetRule())
+	if err := policy.Validate(rule); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	rule, err := s.admin.CreatePolicyRule(ctx, rule)
+	if err != nil {
+		return nil, policyError(err, "failed to create policy rule")
+	}
+
+	return &ssov1.CreatePolicyRuleResponse{Rule: ToProtoPolicyRule(rule)}, nil
+}
+
+func (s *serverAPI) ListPolicyRules(
+	ctx context.Context,
+	req *ssov1.ListPolicyRulesRequest,
+) (*ssov1.ListPolicyRulesResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateAppID(req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.admin.PolicyRules(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, policyError(err, "failed to list policy rules")
+	}
+
+	resp := &ssov1.ListPolicyRulesResponse{}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, ToProtoPolicyRule(rule))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DeletePolicyRule(
+	ctx context.Context,
+	req *ssov1.DeletePolicyRuleRequest,
+) (*ssov1.DeletePolicyRuleResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.GetRuleId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "rule_id is required")
+	}
+
+	if err := s.admin.DeletePolicyRule(ctx, req.GetRuleId()); err != nil {
+		return nil, policyError(err, "failed to delete policy rule")
+	}
+
+	return &ssov1.DeletePolicyRuleResponse{Success: true}, nil
+}
+
+// policyError maps errors of calls on policy rules to gRPC statuses.
+func policyError(err error, msg string) error {
+	switch {
+	case errors.Is(err, admin.ErrInvalidRule):
+		return status.Error(codes.InvalidArgument, "invalid policy rule")
+	case errors.Is(err, admin.ErrRuleExists):
+		return status.Error(codes.AlreadyExists, "policy rule with this name already exists in the app")
+	case errors.Is(err, admin.ErrRuleNotFound):
+		return status.Error(codes.NotFound, "policy rule not found")
+	case errors.Is(err, admin.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
@@ -0,0 +1,372 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/services/admin"
+	"strings"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Admin interface {
+	ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, int64, error)
+	User(ctx context.Context, userID int64) (models.User, error)
+	UpdateUser(ctx context.Context, upd models.User) (models.User, error)
+	SuspendUser(ctx context.Context, caller models.Principal, userID int64, reason string) (models.User, error)
+	ReactivateUser(ctx context.Context, caller models.Principal, userID int64, reason string) (models.User, error)
+	DeleteUser(ctx context.Context, userID int64) error
+	SetRoles(ctx context.Context, userID int64, appID int, roles []string) ([]string, error)
+	UnlockUser(ctx context.Context, userID int64) error
+	LockoutState(ctx context.Context, userID int64) (models.LockoutState, error)
+	CreateApp(ctx context.Context, app models.App) (models.App, string, error)
+	ListApps(ctx context.Context) ([]models.App, error)
+	App(ctx context.Context, appID int) (models.App, error)
+	UpdateApp(ctx context.Context, upd models.App) (models.App, error)
+	RotateAppSecret(ctx context.Context, appID int) (string, error)
+	DeleteApp(ctx context.Context, appID int) error
+	CreateGroup(ctx context.Context, name string, description string) (models.Group, error)
+	ListGroups(ctx context.Context) ([]models.Group, error)
+	DeleteGroup(ctx context.Context, groupID int64) error
+	AddGroupUser(ctx context.Context, groupID int64, userID int64) error
+	RemoveGroupUser(ctx context.Context, groupID int64, userID int64) error
+	AddSubgroup(ctx context.Context, parentID int64, childID int64) error
+	RemoveSubgroup(ctx context.Context, parentID int64, childID int64) error
+	UserGroups(ctx context.Context, userID int64) ([]models.Group, error)
+	CreatePolicyRule(ctx context.Context, rule models.PolicyRule) (models.PolicyRule, error)
+	PolicyRules(ctx context.Context, appID int) ([]models.PolicyRule, error)
+	DeletePolicyRule(ctx context.Context, ruleID int64) error
+	AuditLog(ctx context.Context, userID int64, limit int) ([]models.AuditEntry, error)
+	ProfileChanges(ctx context.Context, userID int64, limit int) ([]models.ProfileChange, error)
+	CreateLegalDocument(ctx context.Context, doc models.LegalDocument) (models.LegalDocument, error)
+	LegalDocuments(ctx context.Context) ([]models.LegalDocument, error)
+	CreateInitialAccessToken(
+		ctx context.Context,
+		caller models.Principal,
+		description string,
+		ttl time.Duration,
+	) (models.InitialAccessToken, string, error)
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedAdminServer
+	admin Admin
+}
+
+func Register(gRPC *grpc.Server, admin Admin) {
+	ssov1.RegisterAdminServer(gRPC, &serverAPI{admin: admin})
+}
+
+const (
+	emptyValue      = 0
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+func (s *serverAPI) ListUsers(
+	ctx context.Context,
+	req *ssov1.ListUsersRequest,
+) (*ssov1.ListUsersResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	filter, err := listUsersFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	users, total, err := s.admin.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	resp := &ssov1.ListUsersResponse{Total: total}
+	for _, user := range users {
+		resp.Users = append(resp.Users, ToProtoUserDetails(user))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetUser(
+	ctx context.Context,
+	req *ssov1.GetUserRequest,
+) (*ssov1.GetUserResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.User(ctx, req.GetUserId())
+	if err != nil {
+		return nil, userError(err, "failed to get user")
+	}
+
+	return &ssov1.GetUserResponse{User: ToProtoUserDetails(user)}, nil
+}
+
+func (s *serverAPI) UpdateUser(
+	ctx context.Context,
+	req *ssov1.UpdateUserRequest,
+) (*ssov1.UpdateUserResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.UpdateUser(ctx, models.User{
+		ID:        req.GetUserId(),
+		Title:     req.GetTitle(),
+		BirthDate: req.GetBirthDate(),
+		Name:      req.GetName(),
+		LastName:  req.GetLastName(),
+		Email:     req.GetEmail(),
+		Phone:     req.GetPhone(),
+	})
+	if err != nil {
+		if errors.Is(err, admin.ErrUserExists) {
+			return nil, status.Error(codes.AlreadyExists, "email or phone is already taken")
+		}
+		if errors.Is(err, admin.ErrInvalidPhone) {
+			return nil, status.Error(codes.InvalidArgument, "phone must be in E.164 format, e.g. +14155552671")
+		}
+		return nil, userError(err, "failed to update user")
+	}
+
+	return &ssov1.UpdateUserResponse{User: ToProtoUserDetails(user)}, nil
+}
+
+func (s *serverAPI) DisableUser(
+	ctx context.Context,
+	req *ssov1.DisableUserRequest,
+) (*ssov1.DisableUserResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.admin.SuspendUser(ctx, caller, req.GetUserId(), ""); err != nil {
+		return nil, userError(err, "failed to disable user")
+	}
+
+	return &ssov1.DisableUserResponse{Success: true}, nil
+}
+
+func (s *serverAPI) SuspendUser(
+	ctx context.Context,
+	req *ssov1.SuspendUserRequest,
+) (*ssov1.SuspendUserResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	if req.GetReason() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	user, err := s.admin.SuspendUser(ctx, caller, req.GetUserId(), req.GetReason())
+	if err != nil {
+		return nil, userError(err, "failed to suspend user")
+	}
+
+	return &ssov1.SuspendUserResponse{User: ToProtoUserDetails(user)}, nil
+}
+
+func (s *serverAPI) ReactivateUser(
+	ctx context.Context,
+	req *ssov1.ReactivateUserRequest,
+) (*ssov1.ReactivateUserResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.ReactivateUser(ctx, caller, req.GetUserId(), req.GetReason())
+	if err != nil {
+		return nil, userError(err, "failed to reactivate user")
+	}
+
+	return &ssov1.ReactivateUserResponse{User: ToProtoUserDetails(user)}, nil
+}
+
+func (s *serverAPI) DeleteUser(
+	ctx context.Context,
+	req *ssov1.DeleteUserRequest,
+) (*ssov1.DeleteUserResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.DeleteUser(ctx, req.GetUserId()); err != nil {
+		return nil, userError(err, "failed to delete user")
+	}
+
+	return &ssov1.DeleteUserResponse{Success: true}, nil
+}
+
+func (s *serverAPI) SetRoles(
+	ctx context.Context,
+	req *ssov1.SetRolesRequest,
+) (*ssov1.SetRolesResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	roles, err := s.admin.SetRoles(ctx, req.GetUserId(), int(req.GetAppId()), req.GetRoles())
+	if err != nil {
+		if errors.Is(err, admin.ErrRoleNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "unknown role")
+		}
+		return nil, userError(err, "failed to set roles")
+	}
+
+	return &ssov1.SetRolesResponse{Roles: roles}, nil
+}
+
+func (s *serverAPI) UnlockUser(
+	ctx context.Context,
+	req *ssov1.UnlockUserRequest,
+) (*ssov1.UnlockUserResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.UnlockUser(ctx, req.GetUserId()); err != nil {
+		return nil, status.Error(codes.Internal, "failed to unlock user")
+	}
+
+	return &ssov1.UnlockUserResponse{Success: true}, nil
+}
+
+func (s *serverAPI) GetLockoutState(
+	ctx context.Context,
+	req *ssov1.GetLockoutStateRequest,
+) (*ssov1.GetLockoutStateResponse, error) {
+	if _, err := callerFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateUserID(req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	state, err := s.admin.LockoutState(ctx, req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get lockout state")
+	}
+
+	return ToProtoLockoutState(state), nil
+}
+
+// callerFromContext returns the admin the interceptor authorized. Every call checks it,
+// so that the service fails closed on a server registering it without the admin interceptor.
+func callerFromContext(ctx context.Context) (models.Principal, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return caller, nil
+}
+
+// userError maps errors of calls on a single user to gRPC statuses.
+func userError(err error, msg string) error {
+	if errors.Is(err, admin.ErrUserNotFound) {
+		return status.Error(codes.NotFound, "user not found")
+	}
+	if errors.Is(err, admin.ErrInvalidStatus) {
+		return status.Error(codes.FailedPrecondition, "user status doesn't allow the change")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func validateUserID(userID int64) error {
+	if userID == emptyValue {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	return nil
+}
+
+func listUsersFilter(req *ssov1.ListUsersRequest) (models.UserFilter, error) {
+	page := max(int(req.GetPage()), 1)
+
+	pageSize := int(req.GetPageSize())
+	if pageSize == emptyValue {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize {
+		return models.UserFilter{}, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageSize)
+	}
+
+	if req.GetStatus() != "" && !slices.Contains(models.UserStatuses, req.GetStatus()) {
+		return models.UserFilter{}, status.Errorf(
+			codes.InvalidArgument, "status must be one of %s", strings.Join(models.UserStatuses, ", "),
+		)
+	}
+
+	filter := models.UserFilter{
+		Email:  req.GetEmail(),
+		Phone:  req.GetPhone(),
+		Status: req.GetStatus(),
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	}
+
+	var err error
+	if req.GetCreatedAfter() != "" {
+		if filter.CreatedAfter, err = time.Parse(time.RFC3339, req.GetCreatedAfter()); err != nil {
+			return models.UserFilter{}, status.Error(codes.InvalidArgument, "created_after must be RFC3339 time")
+		}
+	}
+	if req.GetCreatedBefore() != "" {
+		if filter.CreatedBefore, err = time.Parse(time.RFC3339, req.GetCreatedBefore()); err != nil {
+			return models.UserFilter{}, status.Error(codes.InvalidArgument, "created_before must be RFC3339 time")
+		}
+	}
+
+	return filter, nil
+}
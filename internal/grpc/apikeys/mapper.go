@@ -0,0 +1,25 @@
+package apikeys
+
+import (
+	"sso/internal/domain/models"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+)
+
+func ToProtoAPIKey(k models.APIKey) *ssov1.APIKey {
+	key := &ssov1.APIKey{
+		Id:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		AppId:     int32(k.AppID),
+		Scopes:    k.Scopes,
+		CreatedAt: k.CreatedAt.UTC().Format(time.RFC3339),
+		ExpiresAt: k.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+	if !k.LastUsedAt.IsZero() {
+		key.LastUsedAt = k.LastUsedAt.UTC().Format(time.RFC3339)
+	}
+
+	return key
+}
@@ -0,0 +1,155 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/services/auth"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type APIKeys interface {
+	CreateAPIKey(ctx context.Context, caller models.Principal, name string, scopes []string, ttl time.Duration) (models.APIKey, string, error)
+	APIKeys(ctx context.Context, caller models.Principal) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, caller models.Principal, keyID int64) error
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedAPIKeysServer
+	apiKeys APIKeys
+}
+
+func Register(gRPC *grpc.Server, apiKeys APIKeys) {
+	ssov1.RegisterAPIKeysServer(gRPC, &serverAPI{apiKeys: apiKeys})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) CreateAPIKey(
+	ctx context.Context,
+	req *ssov1.CreateAPIKeyRequest,
+) (*ssov1.CreateAPIKeyResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCreateAPIKey(req); err != nil {
+		return nil, err
+	}
+
+	key, plain, err := s.apiKeys.CreateAPIKey(
+		ctx,
+		caller,
+		req.GetName(),
+		req.GetScopes(),
+		time.Duration(req.GetExpiresIn())*time.Second,
+	)
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyExists) {
+			return nil, status.Error(codes.AlreadyExists, "api key with this name already exists")
+		}
+		if errors.Is(err, auth.ErrInvalidTTL) {
+			return nil, status.Error(codes.InvalidArgument, "expires_in exceeds the maximum api key lifetime")
+		}
+		if errors.Is(err, auth.ErrUnknownScope) {
+			return nil, status.Error(codes.InvalidArgument, "scopes must be permissions of the app")
+		}
+		if errors.Is(err, auth.ErrScopeNotHeld) {
+			return nil, status.Error(codes.PermissionDenied, "scopes must be permissions the user holds")
+		}
+		return nil, status.Error(codes.Internal, "failed to create api key")
+	}
+
+	return &ssov1.CreateAPIKeyResponse{
+		ApiKey: ToProtoAPIKey(key),
+		Key:    plain,
+	}, nil
+}
+
+func (s *serverAPI) ListAPIKeys(
+	ctx context.Context,
+	req *ssov1.ListAPIKeysRequest,
+) (*ssov1.ListAPIKeysResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeys.APIKeys(ctx, caller)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list api keys")
+	}
+
+	resp := &ssov1.ListAPIKeysResponse{}
+	for _, key := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, ToProtoAPIKey(key))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeAPIKey(
+	ctx context.Context,
+	req *ssov1.RevokeAPIKeyRequest,
+) (*ssov1.RevokeAPIKeyResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetApiKeyId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "api_key_id is required")
+	}
+
+	if err := s.apiKeys.RevokeAPIKey(ctx, caller, req.GetApiKeyId()); err != nil {
+		if errors.Is(err, auth.ErrAPIKeyNotFound) {
+			return nil, status.Error(codes.NotFound, "api key not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to revoke api key")
+	}
+
+	return &ssov1.RevokeAPIKeyResponse{Success: true}, nil
+}
+
+// callerFromContext returns the user authenticated by the interceptor.
+func callerFromContext(ctx context.Context) (models.Principal, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+	if caller.Type != models.PrincipalUser {
+		return models.Principal{}, status.Error(codes.PermissionDenied, "service accounts get api keys from admins")
+	}
+	if caller.ActorID != 0 {
+		return models.Principal{}, status.Error(codes.PermissionDenied, "api keys can't be managed while impersonating")
+	}
+
+	return caller, nil
+}
+
+func validateCreateAPIKey(req *ssov1.CreateAPIKeyRequest) error {
+	if req.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	for _, scope := range req.GetScopes() {
+		if scope == "" {
+			return status.Error(codes.InvalidArgument, "scopes must be non-empty")
+		}
+	}
+
+	if req.GetExpiresIn() < emptyValue {
+		return status.Error(codes.InvalidArgument, "expires_in must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package attributes
+
+import (
+	"encoding/json"
+	"sso/internal/domain/models"
+)
+
+func ToProtoAttributes(attrs models.Attributes) map[string]string {
+	values := make(map[string]string, len(attrs))
+	for key, value := range attrs {
+		values[key] = string(value)
+	}
+
+	return values
+}
+
+// FromProtoAttributes keeps values as they are, they are validated as JSON by the service.
+func FromProtoAttributes(values map[string]string) models.Attributes {
+	attrs := make(models.Attributes, len(values))
+	for key, value := range values {
+		attrs[key] = json.RawMessage(value)
+	}
+
+	return attrs
+}
@@ -0,0 +1,106 @@
+package attributes
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/services/attributes"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Attributes interface {
+	GetAttributes(ctx context.Context, caller models.Principal, userID int64) (models.Attributes, error)
+	SetAttributes(ctx context.Context, caller models.Principal, userID int64, patch models.Attributes) (models.Attributes, error)
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedAttributesServer
+	attributes Attributes
+}
+
+func Register(gRPC *grpc.Server, attributes Attributes) {
+	ssov1.RegisterAttributesServer(gRPC, &serverAPI{attributes: attributes})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) GetAttributes(
+	ctx context.Context,
+	req *ssov1.GetAttributesRequest,
+) (*ssov1.GetAttributesResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetUserId() < emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be positive")
+	}
+
+	attrs, err := s.attributes.GetAttributes(ctx, caller, req.GetUserId())
+	if err != nil {
+		return nil, attributesError(err, "failed to get attributes")
+	}
+
+	return &ssov1.GetAttributesResponse{Attributes: ToProtoAttributes(attrs)}, nil
+}
+
+func (s *serverAPI) SetAttributes(
+	ctx context.Context,
+	req *ssov1.SetAttributesRequest,
+) (*ssov1.SetAttributesResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetUserId() < emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be positive")
+	}
+	if len(req.GetAttributes()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "attributes are required")
+	}
+
+	attrs, err := s.attributes.SetAttributes(ctx, caller, req.GetUserId(), FromProtoAttributes(req.GetAttributes()))
+	if err != nil {
+		return nil, attributesError(err, "failed to set attributes")
+	}
+
+	return &ssov1.SetAttributesResponse{Attributes: ToProtoAttributes(attrs)}, nil
+}
+
+func callerFromContext(ctx context.Context) (models.Principal, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return caller, nil
+}
+
+// attributesError maps errors of calls on attributes to gRPC statuses.
+func attributesError(err error, msg string) error {
+	switch {
+	case errors.Is(err, attributes.ErrInvalidKey),
+		errors.Is(err, attributes.ErrInvalidValue),
+		errors.Is(err, attributes.ErrSchemaMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, attributes.ErrTooManyAttributes):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, attributes.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, attributes.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, attributes.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxBatchChecks = 100
+
+func (s *serverAPI) Check(
+	ctx context.Context,
+	req *ssov1.CheckRequest,
+) (*ssov1.CheckResponse, error) {
+	caller, err := checkerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCheck(caller, req); err != nil {
+		return nil, err
+	}
+
+	decision, err := s.auth.Check(ctx, ToAccessRequest(req))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to check access")
+	}
+
+	return ToProtoCheckResponse(decision, req.GetExplain()), nil
+}
+
+func (s *serverAPI) BatchCheck(
+	ctx context.Context,
+	req *ssov1.BatchCheckRequest,
+) (*ssov1.BatchCheckResponse, error) {
+	caller, err := checkerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetChecks()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "checks are required")
+	}
+
+	if len(req.GetChecks()) > maxBatchChecks {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d checks are allowed", maxBatchChecks)
+	}
+
+	reqs := make([]models.AccessRequest, 0, len(req.GetChecks()))
+	for i, check := range req.GetChecks() {
+		if err := validateCheck(caller, check); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "check %d: %s", i, status.Convert(err).Message())
+		}
+		reqs = append(reqs, ToAccessRequest(check))
+	}
+
+	decisions, err := s.auth.BatchCheck(ctx, reqs)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to check access")
+	}
+
+	resp := &ssov1.BatchCheckResponse{}
+	for i, decision := range decisions {
+		resp.Results = append(resp.Results, ToProtoCheckResponse(decision, req.GetChecks()[i].GetExplain()))
+	}
+
+	return resp, nil
+}
+
+// checkerFromContext returns the app backend the interceptor authorized to check access.
+// Checks verify it themselves, so that they fail closed on a server registering the service
+// without the interceptor.
+func checkerFromContext(ctx context.Context) (models.Principal, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return caller, nil
+}
+
+func validateCheck(caller models.Principal, req *ssov1.CheckRequest) error {
+	if req.GetSubjectId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "subject_id is required")
+	}
+
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	// the check permission is held in the app of the token, other apps' rules aren't the caller's to see
+	if int(req.GetAppId()) != caller.AppID {
+		return status.Error(codes.PermissionDenied, "app_id must be the app of the token")
+	}
+
+	if req.GetAction() == "" {
+		return status.Error(codes.InvalidArgument, "action is required")
+	}
+
+	if req.GetResource().GetType() == "" {
+		return status.Error(codes.InvalidArgument, "resource type is required")
+	}
+
+	// conditions refer to the type and the ID as resource.type and resource.id too
+	for _, name := range []string{"type", "id"} {
+		if _, ok := req.GetResource().GetAttributes()[name]; ok {
+			return status.Errorf(codes.InvalidArgument, "resource attribute %q is reserved, set resource.%s instead", name, name)
+		}
+	}
+
+	return nil
+}
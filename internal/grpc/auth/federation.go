@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/services/auth"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codeChallengeLen is the length of base64url encoded SHA-256 digests, S256 PKCE challenges.
+const codeChallengeLen = 43
+
+func (s *serverAPI) ListIdentityProviders(
+	ctx context.Context,
+	req *ssov1.ListIdentityProvidersRequest,
+) (*ssov1.ListIdentityProvidersResponse, error) {
+	return &ssov1.ListIdentityProvidersResponse{Providers: s.auth.IdentityProviders()}, nil
+}
+
+func (s *serverAPI) StartFederatedLogin(
+	ctx context.Context,
+	req *ssov1.StartFederatedLoginRequest,
+) (*ssov1.StartFederatedLoginResponse, error) {
+	if req.GetProvider() == "" {
+		return nil, status.Error(codes.InvalidArgument, "provider is required")
+	}
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+	if req.GetOrgId() < emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "org_id must be positive")
+	}
+	if req.GetRedirectUri() == "" {
+		return nil, status.Error(codes.InvalidArgument, "redirect_uri is required")
+	}
+	if len(req.GetCodeChallenge()) != codeChallengeLen {
+		return nil, status.Error(codes.InvalidArgument, "code_challenge must be an S256 challenge")
+	}
+
+	authURL, err := s.auth.StartFederatedLogin(
+		ctx,
+		req.GetProvider(),
+		int(req.GetAppId()),
+		req.GetOrgId(),
+		req.GetRedirectUri(),
+		req.GetState(),
+		req.GetCodeChallenge(),
+		req.GetScopes(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrUnknownProvider):
+			return nil, status.Error(codes.NotFound, "identity provider not found")
+		case errors.Is(err, auth.ErrInvalidAppID):
+			return nil, status.Error(codes.InvalidArgument, "invalid app id")
+		case errors.Is(err, auth.ErrGrantNotAllowed):
+			return nil, status.Error(codes.PermissionDenied, "app doesn't allow the authorization_code grant")
+		case errors.Is(err, auth.ErrInvalidRedirectURI):
+			return nil, status.Error(codes.InvalidArgument, "redirect uri isn't registered for the app")
+		case errors.Is(err, auth.ErrInvalidScope):
+			return nil, status.Error(codes.InvalidArgument, "invalid scope")
+		case errors.Is(err, auth.ErrFederatedLoginFailed):
+			return nil, status.Error(codes.Unavailable, "identity provider is unavailable")
+		}
+		return nil, status.Error(codes.Internal, "failed to start login")
+	}
+
+	return &ssov1.StartFederatedLoginResponse{AuthorizationUrl: authURL}, nil
+}
+
+func (s *serverAPI) FederatedLogin(
+	ctx context.Context,
+	req *ssov1.FederatedLoginRequest,
+) (*ssov1.LoginResponse, error) {
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	if req.GetCodeVerifier() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code_verifier is required")
+	}
+
+	user, token, err := s.auth.FederatedLogin(ctx, req.GetCode(), req.GetCodeVerifier())
+	if err != nil {
+		var consentErr *auth.ConsentRequiredError
+		if errors.As(err, &consentErr) {
+			return &ssov1.LoginResponse{ConsentRequired: ToProtoConsentChallenge(consentErr)}, nil
+		}
+		if errors.Is(err, auth.ErrInvalidFederatedLogin) {
+			return nil, status.Error(codes.NotFound, "login code is invalid or expired")
+		}
+		if errors.Is(err, auth.ErrUserDisabled) {
+			return nil, status.Error(codes.PermissionDenied, "account is disabled")
+		}
+		if errors.Is(err, auth.ErrUserPending) {
+			return nil, status.Error(codes.PermissionDenied, "account is not activated yet")
+		}
+		return nil, loginError(err)
+	}
+
+	return &ssov1.LoginResponse{
+		User:  ToProtoUser(user),
+		Token: token,
+	}, nil
+}
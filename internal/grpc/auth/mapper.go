@@ -2,6 +2,8 @@ package auth
 
 import (
 	"sso/internal/domain/models"
+	"sso/internal/services/auth"
+	"time"
 
 	ssov1 "github.com/Abazin97/protos/gen/go/sso"
 )
@@ -16,3 +18,82 @@ func ToProtoUser(u models.User) *ssov1.User {
 		BirthDate: u.BirthDate,
 	}
 }
+
+func ToProtoConsentChallenge(e *auth.ConsentRequiredError) *ssov1.ConsentChallenge {
+	challenge := &ssov1.ConsentChallenge{
+		ConsentToken: e.Token,
+		ExpiresAt:    e.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+	for _, doc := range e.Documents {
+		challenge.Documents = append(challenge.Documents, ToProtoLegalDocument(doc))
+	}
+
+	return challenge
+}
+
+func ToProtoLegalDocument(d models.LegalDocument) *ssov1.LegalDocument {
+	return &ssov1.LegalDocument{
+		Id:          d.ID,
+		Type:        d.Type,
+		Version:     d.Version,
+		Url:         d.URL,
+		Mandatory:   d.Mandatory,
+		PublishedAt: d.PublishedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func ToAccessRequest(req *ssov1.CheckRequest) models.AccessRequest {
+	return models.AccessRequest{
+		SubjectID:     req.GetSubjectId(),
+		AppID:         int(req.GetAppId()),
+		OrgID:         req.GetOrgId(),
+		Action:        req.GetAction(),
+		ResourceType:  req.GetResource().GetType(),
+		ResourceID:    req.GetResource().GetId(),
+		ResourceAttrs: req.GetResource().GetAttributes(),
+	}
+}
+
+// ToProtoCheckResponse maps the decision, leaving out its explanation unless explain is set.
+func ToProtoCheckResponse(d models.Decision, explain bool) *ssov1.CheckResponse {
+	resp := &ssov1.CheckResponse{Allowed: d.Allowed}
+	if explain {
+		resp.Explanation = &ssov1.Explanation{
+			RuleId:   d.RuleID,
+			RuleName: d.RuleName,
+			Effect:   d.Effect,
+			Reason:   d.Reason,
+		}
+	}
+
+	return resp
+}
+
+func ToProtoPrincipal(p models.Principal) *ssov1.Principal {
+	principal := &ssov1.Principal{
+		UserId:        p.UserID,
+		Email:         p.Email,
+		AppId:         int32(p.AppID),
+		Roles:         p.Roles,
+		OrgId:         p.OrgID,
+		OrgRole:       p.OrgRole,
+		Groups:        p.Groups,
+		Scopes:        p.Scopes,
+		ExpiresAt:     p.ExpiresAt.UTC().Format(time.RFC3339),
+		PrincipalType: p.Type,
+	}
+	if p.Attributes != nil {
+		principal.Attributes = make(map[string]string, len(p.Attributes))
+		for key, value := range p.Attributes {
+			principal.Attributes[key] = string(value)
+		}
+	}
+	if p.ActorID != 0 {
+		principal.Actor = &ssov1.Actor{
+			UserId: p.ActorID,
+			Email:  p.ActorEmail,
+		}
+	}
+
+	return principal
+}
@@ -3,13 +3,14 @@ package auth
 import (
 	"context"
 	"errors"
+	"net"
 	"sso/internal/domain/models"
-	"sso/internal/repository"
 	"sso/internal/services/auth"
 
 	ssov1 "github.com/Abazin97/protos/gen/go/sso"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -19,6 +20,7 @@ type Auth interface {
 		password string,
 		phone string,
 		appID int,
+		orgID int64,
 	) (models.User, string, error)
 	RegisterNewUser(ctx context.Context,
 		title string,
@@ -30,6 +32,27 @@ type Auth interface {
 		phone string,
 	) (userID int64, err error)
 	IsAdmin(ctx context.Context, userID int64) (bool, error)
+	HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error)
+	Check(ctx context.Context, req models.AccessRequest) (models.Decision, error)
+	BatchCheck(ctx context.Context, reqs []models.AccessRequest) ([]models.Decision, error)
+	Introspect(ctx context.Context, token string) (models.Principal, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (models.Principal, error)
+	ServiceAccountToken(ctx context.Context, clientID string, clientSecret string, appID int) (string, error)
+	AcceptInvitation(ctx context.Context, token string, password string, profile models.User) (int64, error)
+	RevertEmailChange(ctx context.Context, token string) error
+	AcceptTerms(ctx context.Context, consentToken string, documentIDs []int64, ip string) (models.User, string, error)
+	IdentityProviders() []string
+	StartFederatedLogin(
+		ctx context.Context,
+		provider string,
+		appID int,
+		orgID int64,
+		redirectURI string,
+		clientState string,
+		codeChallenge string,
+		scopes []string,
+	) (string, error)
+	FederatedLogin(ctx context.Context, loginCode string, codeVerifier string) (models.User, string, error)
 	ChangePasswordInit(
 		ctx context.Context,
 		email string,
@@ -39,7 +62,6 @@ type Auth interface {
 		ctx context.Context,
 		code string,
 		verificationID int64,
-		email string,
 		newPassword string,
 	) (bool, error)
 }
@@ -66,15 +88,25 @@ func (s *serverAPI) Login(
 		return nil, err
 	}
 
-	user, token, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), req.GetPhone(), int(req.GetAppId()))
+	user, token, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), req.GetPhone(), int(req.GetAppId()), req.GetOrgId())
 	if err != nil {
+		var consentErr *auth.ConsentRequiredError
+		if errors.As(err, &consentErr) {
+			return &ssov1.LoginResponse{ConsentRequired: ToProtoConsentChallenge(consentErr)}, nil
+		}
 		if errors.Is(err, auth.ErrInvalidCredentials) {
 			return nil, status.Error(codes.InvalidArgument, "invalid email or password")
 		}
-		if errors.Is(err, repository.ErrAppNotFound) {
-			return nil, status.Error(codes.InvalidArgument, "invalid app id")
+		if errors.Is(err, auth.ErrAccountLocked) {
+			return nil, status.Error(codes.PermissionDenied, "account is temporarily locked")
 		}
-		return nil, status.Error(codes.Internal, "failed to login")
+		if errors.Is(err, auth.ErrUserDisabled) {
+			return nil, status.Error(codes.PermissionDenied, "account is disabled")
+		}
+		if errors.Is(err, auth.ErrUserPending) {
+			return nil, status.Error(codes.PermissionDenied, "account is not activated yet")
+		}
+		return nil, loginError(err)
 	}
 
 	return &ssov1.LoginResponse{
@@ -83,6 +115,79 @@ func (s *serverAPI) Login(
 	}, nil
 }
 
+func (s *serverAPI) AcceptTerms(
+	ctx context.Context,
+	req *ssov1.AcceptTermsRequest,
+) (*ssov1.AcceptTermsResponse, error) {
+	if req.GetConsentToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "consent_token is required")
+	}
+
+	user, token, err := s.auth.AcceptTerms(ctx, req.GetConsentToken(), req.GetDocumentIds(), clientIP(ctx))
+	if err != nil {
+		var consentErr *auth.ConsentRequiredError
+		if errors.As(err, &consentErr) {
+			return &ssov1.AcceptTermsResponse{ConsentRequired: ToProtoConsentChallenge(consentErr)}, nil
+		}
+		if errors.Is(err, auth.ErrInvalidConsentToken) {
+			return nil, status.Error(codes.NotFound, "consent token is invalid or expired")
+		}
+		if errors.Is(err, auth.ErrLegalDocumentNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "legal document not found")
+		}
+		return nil, loginError(err)
+	}
+
+	return &ssov1.AcceptTermsResponse{
+		User:  ToProtoUser(user),
+		Token: token,
+	}, nil
+}
+
+// loginError maps errors of issuing the token to the app and organization the user logs in to.
+func loginError(err error) error {
+	if errors.Is(err, auth.ErrInvalidAppID) {
+		return status.Error(codes.InvalidArgument, "invalid app id")
+	}
+	if errors.Is(err, auth.ErrInvalidOrgID) {
+		return status.Error(codes.InvalidArgument, "invalid organization id")
+	}
+	if errors.Is(err, auth.ErrGrantNotAllowed) {
+		return status.Error(codes.PermissionDenied, "app doesn't allow the grant type")
+	}
+	if errors.Is(err, auth.ErrAppNotAuthorized) {
+		return status.Error(codes.FailedPrecondition, "user hasn't granted the app access to the scopes")
+	}
+	if errors.Is(err, auth.ErrNotOrgMember) || errors.Is(err, auth.ErrEmailDomain) {
+		return status.Error(codes.PermissionDenied, "user is not allowed in the organization")
+	}
+	if errors.Is(err, auth.ErrMFARequired) {
+		return status.Error(codes.FailedPrecondition, "organization requires multi-factor authentication")
+	}
+	if errors.Is(err, auth.ErrDirectoryUnavailable) {
+		return status.Error(codes.Unavailable, "user directory is unavailable")
+	}
+	if errors.Is(err, auth.ErrDirectoryUserNotLinkable) {
+		return status.Error(codes.FailedPrecondition, "directory account can't be linked to a local account")
+	}
+	return status.Error(codes.Internal, "failed to login")
+}
+
+// clientIP returns the address of the peer that sent the request, empty if unknown.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
 func (s *serverAPI) Register(
 	ctx context.Context,
 	req *ssov1.RegisterRequest,
@@ -107,6 +212,9 @@ func (s *serverAPI) Register(
 		if errors.Is(err, auth.ErrUserExists) {
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
 		}
+		if errors.Is(err, auth.ErrInvalidPhone) {
+			return nil, status.Error(codes.InvalidArgument, "phone must be in E.164 format, e.g. +14155552671")
+		}
 
 		return nil, status.Error(codes.Internal, "internal error")
 	}
@@ -136,6 +244,159 @@ func (s *serverAPI) IsAdmin(
 	}, nil
 }
 
+func (s *serverAPI) HasPermission(
+	ctx context.Context,
+	req *ssov1.HasPermissionRequest,
+) (*ssov1.HasPermissionResponse, error) {
+	if err := validateHasPermission(req); err != nil {
+		return nil, err
+	}
+
+	has, err := s.auth.HasPermission(ctx, req.GetUserId(), int(req.GetAppId()), req.GetPermission())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &ssov1.HasPermissionResponse{
+		HasPermission: has,
+	}, nil
+}
+
+func (s *serverAPI) IntrospectToken(
+	ctx context.Context,
+	req *ssov1.IntrospectTokenRequest,
+) (*ssov1.IntrospectTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	principal, err := s.auth.Introspect(ctx, req.GetToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return &ssov1.IntrospectTokenResponse{Active: false}, nil
+		}
+		return nil, status.Error(codes.Internal, "failed to introspect token")
+	}
+
+	return &ssov1.IntrospectTokenResponse{
+		Active:    true,
+		Principal: ToProtoPrincipal(principal),
+	}, nil
+}
+
+func (s *serverAPI) AuthenticateAPIKey(
+	ctx context.Context,
+	req *ssov1.AuthenticateAPIKeyRequest,
+) (*ssov1.AuthenticateAPIKeyResponse, error) {
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	principal, err := s.auth.AuthenticateAPIKey(ctx, req.GetKey())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+		return nil, status.Error(codes.Internal, "failed to authenticate api key")
+	}
+
+	return &ssov1.AuthenticateAPIKeyResponse{
+		Principal: ToProtoPrincipal(principal),
+	}, nil
+}
+
+func (s *serverAPI) ServiceAccountToken(
+	ctx context.Context,
+	req *ssov1.ServiceAccountTokenRequest,
+) (*ssov1.ServiceAccountTokenResponse, error) {
+	if req.GetClientId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "client_id is required")
+	}
+	if req.GetClientSecret() == "" {
+		return nil, status.Error(codes.InvalidArgument, "client_secret is required")
+	}
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	token, err := s.auth.ServiceAccountToken(ctx, req.GetClientId(), req.GetClientSecret(), int(req.GetAppId()))
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			return nil, status.Error(codes.Unauthenticated, "invalid client credentials")
+		}
+		if errors.Is(err, auth.ErrAccountLocked) {
+			return nil, status.Error(codes.PermissionDenied, "service account is temporarily locked")
+		}
+		if errors.Is(err, auth.ErrUserDisabled) {
+			return nil, status.Error(codes.PermissionDenied, "service account is disabled")
+		}
+		if errors.Is(err, auth.ErrInvalidAppID) {
+			return nil, status.Error(codes.InvalidArgument, "invalid app id")
+		}
+		if errors.Is(err, auth.ErrGrantNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "app doesn't allow the client_credentials grant")
+		}
+		return nil, status.Error(codes.Internal, "failed to issue token")
+	}
+
+	return &ssov1.ServiceAccountTokenResponse{Token: token}, nil
+}
+
+func (s *serverAPI) AcceptInvitation(
+	ctx context.Context,
+	req *ssov1.AcceptInvitationRequest,
+) (*ssov1.AcceptInvitationResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	userID, err := s.auth.AcceptInvitation(ctx, req.GetToken(), req.GetPassword(), models.User{
+		Title:     req.GetTitle(),
+		Name:      req.GetName(),
+		LastName:  req.GetLastName(),
+		Phone:     req.GetPhone(),
+		BirthDate: req.GetBirthDate(),
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidInvitation) {
+			return nil, status.Error(codes.NotFound, "invitation is invalid or expired")
+		}
+		if errors.Is(err, auth.ErrInvalidPhone) {
+			return nil, status.Error(codes.InvalidArgument, "phone must be in E.164 format, e.g. +14155552671")
+		}
+		if errors.Is(err, auth.ErrUserExists) {
+			return nil, status.Error(codes.AlreadyExists, "user already exists")
+		}
+		return nil, status.Error(codes.Internal, "failed to accept invitation")
+	}
+
+	return &ssov1.AcceptInvitationResponse{UserId: userID}, nil
+}
+
+func (s *serverAPI) RevertEmailChange(
+	ctx context.Context,
+	req *ssov1.RevertEmailChangeRequest,
+) (*ssov1.RevertEmailChangeResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.auth.RevertEmailChange(ctx, req.GetToken()); err != nil {
+		if errors.Is(err, auth.ErrInvalidEmailChange) {
+			return nil, status.Error(codes.NotFound, "link is invalid or expired")
+		}
+		if errors.Is(err, auth.ErrUserExists) {
+			return nil, status.Error(codes.AlreadyExists, "email is already taken")
+		}
+		return nil, status.Error(codes.Internal, "failed to revert email change")
+	}
+
+	return &ssov1.RevertEmailChangeResponse{Success: true}, nil
+}
+
 func (s *serverAPI) ChangePasswordInit(
 	ctx context.Context,
 	req *ssov1.ChangePassInitRequest) (*ssov1.ChangePassInitResponse, error) {
@@ -146,6 +407,21 @@ func (s *serverAPI) ChangePasswordInit(
 		if errors.Is(err, auth.ErrUserNotFound) {
 			return nil, status.Error(codes.NotFound, "user not found")
 		}
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			return nil, status.Error(codes.InvalidArgument, "invalid email or password")
+		}
+		if errors.Is(err, auth.ErrAccountLocked) {
+			return nil, status.Error(codes.PermissionDenied, "account is temporarily locked")
+		}
+		if errors.Is(err, auth.ErrUserDisabled) {
+			return nil, status.Error(codes.PermissionDenied, "account is disabled")
+		}
+		if errors.Is(err, auth.ErrUserPending) {
+			return nil, status.Error(codes.PermissionDenied, "account is not activated yet")
+		}
+		if errors.Is(err, auth.ErrCodeResendCooldown) {
+			return nil, status.Error(codes.ResourceExhausted, "verification code was sent recently")
+		}
 		return nil, status.Error(codes.Internal, "failed to change password")
 	}
 
@@ -156,9 +432,15 @@ func (s *serverAPI) ChangePasswordConfirm(
 	ctx context.Context,
 	req *ssov1.ChangePassConfirmRequest) (*ssov1.ChangePassConfirmResponse, error) {
 
-	success, err := s.auth.ChangePasswordConfirm(ctx, req.GetCode(), req.GetUid(), req.GetEmail(), req.GetNewPassword())
+	success, err := s.auth.ChangePasswordConfirm(ctx, req.GetCode(), req.GetUid(), req.GetNewPassword())
 
 	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCode) {
+			return nil, status.Error(codes.InvalidArgument, "invalid verification code")
+		}
+		if errors.Is(err, auth.ErrTooManyAttempts) {
+			return nil, status.Error(codes.ResourceExhausted, "too many verification attempts")
+		}
 		return nil, status.Error(codes.Internal, "failed to change password")
 	}
 
@@ -179,6 +461,10 @@ func validateLogin(req *ssov1.LoginRequest) error {
 		return status.Error(codes.InvalidArgument, "app_id is required")
 	}
 
+	if req.GetOrgId() < emptyValue {
+		return status.Error(codes.InvalidArgument, "org_id must be positive")
+	}
+
 	return nil
 }
 
@@ -205,3 +491,19 @@ func validateIsAdmin(req *ssov1.IsAdminRequest) error {
 
 	return nil
 }
+
+func validateHasPermission(req *ssov1.HasPermissionRequest) error {
+	if req.GetUserId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if req.GetPermission() == "" {
+		return status.Error(codes.InvalidArgument, "permission is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package impersonation
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/services/auth"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Impersonation interface {
+	Impersonate(ctx context.Context, caller models.Principal, targetUserID int64, reason string) (string, time.Time, error)
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedImpersonationServer
+	impersonation Impersonation
+}
+
+func Register(gRPC *grpc.Server, impersonation Impersonation) {
+	ssov1.RegisterImpersonationServer(gRPC, &serverAPI{impersonation: impersonation})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) Impersonate(
+	ctx context.Context,
+	req *ssov1.ImpersonateRequest,
+) (*ssov1.ImpersonateResponse, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	if req.GetTargetUserId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "target_user_id is required")
+	}
+	if req.GetReason() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	token, expiresAt, err := s.impersonation.Impersonate(ctx, caller, req.GetTargetUserId(), req.GetReason())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidImpersonation) {
+			return nil, status.Error(codes.FailedPrecondition, "can't impersonate this user")
+		}
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		if errors.Is(err, auth.ErrUserDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "user is disabled")
+		}
+		if errors.Is(err, auth.ErrNotOrgMember) {
+			return nil, status.Error(codes.FailedPrecondition, "user is not a member of the app's organization")
+		}
+		return nil, status.Error(codes.Internal, "failed to impersonate")
+	}
+
+	return &ssov1.ImpersonateResponse{
+		Token:     token,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
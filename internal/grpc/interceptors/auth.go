@@ -0,0 +1,113 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/services/auth"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type Authorizer interface {
+	Authorize(ctx context.Context, token string, permission string) (models.Principal, error)
+}
+
+type AdminAuthorizer interface {
+	AuthorizeAdmin(ctx context.Context, token string) (models.Principal, error)
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the caller authenticated by the interceptor.
+func PrincipalFromContext(ctx context.Context) (models.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(models.Principal)
+
+	return principal, ok
+}
+
+// RequirePermission guards every method of the service: the caller must pass
+// "authorization: Bearer <token>" metadata whose user holds the permission.
+// Empty permission lets in any caller with a valid token.
+func RequirePermission(authorizer Authorizer, service string, permission string) grpc.UnaryServerInterceptor {
+	return guard(inService(service), func(ctx context.Context, token string) (models.Principal, error) {
+		return authorizer.Authorize(ctx, token, permission)
+	})
+}
+
+// RequireAdmin guards every method of the service administering the SSO itself:
+// the caller's token must be issued for the SSO admin app to one of its admins.
+func RequireAdmin(authorizer AdminAuthorizer, service string) grpc.UnaryServerInterceptor {
+	return guard(inService(service), authorizer.AuthorizeAdmin)
+}
+
+// RequireMethodPermission guards a single method of a service whose other methods are public,
+// fullMethod is e.g. "/auth.Auth/Check". See RequirePermission.
+func RequireMethodPermission(authorizer Authorizer, fullMethod string, permission string) grpc.UnaryServerInterceptor {
+	return guard(
+		func(method string) bool { return method == fullMethod },
+		func(ctx context.Context, token string) (models.Principal, error) {
+			return authorizer.Authorize(ctx, token, permission)
+		},
+	)
+}
+
+// inService tells whether the full method belongs to the service.
+func inService(service string) func(fullMethod string) bool {
+	prefix := "/" + service + "/"
+
+	return func(fullMethod string) bool {
+		return strings.HasPrefix(fullMethod, prefix)
+	}
+}
+
+// guard authorizes the bearer token of calls to the methods it guards and passes the caller to the handler.
+func guard(
+	guards func(fullMethod string) bool,
+	authorize func(ctx context.Context, token string) (models.Principal, error),
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !guards(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		token, err := BearerToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		principal, err := authorize(ctx, token)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			if errors.Is(err, auth.ErrPermissionDenied) {
+				return nil, status.Error(codes.PermissionDenied, "permission denied")
+			}
+			return nil, status.Error(codes.Internal, "failed to authorize")
+		}
+
+		return handler(context.WithValue(ctx, principalKey{}, principal), req)
+	}
+}
+
+// BearerToken returns the token of "authorization: Bearer <token>" metadata.
+func BearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return "", status.Error(codes.Unauthenticated, "authorization token must be a bearer token")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,22 @@
+package invitations
+
+import (
+	"sso/internal/domain/models"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+)
+
+func ToProtoInvitation(inv models.Invitation) *ssov1.Invitation {
+	return &ssov1.Invitation{
+		Id:        inv.ID,
+		Email:     inv.Email,
+		InviterId: inv.InviterID,
+		AppId:     int32(inv.AppID),
+		Role:      inv.Role,
+		OrgId:     inv.OrgID,
+		OrgRole:   inv.OrgRole,
+		CreatedAt: inv.CreatedAt.UTC().Format(time.RFC3339),
+		ExpiresAt: inv.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,142 @@
+package invitations
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/services/invitation"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Invitations interface {
+	CreateInvitation(ctx context.Context, caller models.Principal, inv models.Invitation) (models.Invitation, error)
+	PendingInvitations(ctx context.Context, caller models.Principal, orgID int64) ([]models.Invitation, error)
+	RevokeInvitation(ctx context.Context, caller models.Principal, id int64) error
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedInvitationsServer
+	invitations Invitations
+}
+
+func Register(gRPC *grpc.Server, invitations Invitations) {
+	ssov1.RegisterInvitationsServer(gRPC, &serverAPI{invitations: invitations})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) CreateInvitation(
+	ctx context.Context,
+	req *ssov1.CreateInvitationRequest,
+) (*ssov1.CreateInvitationResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	if req.GetOrgId() < emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "org_id must be positive")
+	}
+
+	inv, err := s.invitations.CreateInvitation(ctx, caller, models.Invitation{
+		Email:   req.GetEmail(),
+		Role:    req.GetRole(),
+		OrgID:   req.GetOrgId(),
+		OrgRole: req.GetOrgRole(),
+	})
+	if err != nil {
+		return nil, invitationError(err, "failed to create invitation")
+	}
+
+	return &ssov1.CreateInvitationResponse{Invitation: ToProtoInvitation(inv)}, nil
+}
+
+func (s *serverAPI) ListInvitations(
+	ctx context.Context,
+	req *ssov1.ListInvitationsRequest,
+) (*ssov1.ListInvitationsResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitations.PendingInvitations(ctx, caller, req.GetOrgId())
+	if err != nil {
+		return nil, invitationError(err, "failed to list invitations")
+	}
+
+	resp := &ssov1.ListInvitationsResponse{}
+	for _, inv := range invitations {
+		resp.Invitations = append(resp.Invitations, ToProtoInvitation(inv))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeInvitation(
+	ctx context.Context,
+	req *ssov1.RevokeInvitationRequest,
+) (*ssov1.RevokeInvitationResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetInvitationId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "invitation_id is required")
+	}
+
+	if err := s.invitations.RevokeInvitation(ctx, caller, req.GetInvitationId()); err != nil {
+		return nil, invitationError(err, "failed to revoke invitation")
+	}
+
+	return &ssov1.RevokeInvitationResponse{Success: true}, nil
+}
+
+// callerFromContext returns the user authenticated by the interceptor.
+func callerFromContext(ctx context.Context) (models.Principal, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+	if caller.Type != models.PrincipalUser {
+		return models.Principal{}, status.Error(codes.PermissionDenied, "only users can invite")
+	}
+	if caller.ActorID != 0 {
+		return models.Principal{}, status.Error(codes.PermissionDenied, "invitations can't be managed while impersonating")
+	}
+
+	return caller, nil
+}
+
+// invitationError maps errors of calls on invitations to gRPC statuses.
+func invitationError(err error, msg string) error {
+	switch {
+	case errors.Is(err, invitation.ErrInvalidEmail):
+		return status.Error(codes.InvalidArgument, "invalid email")
+	case errors.Is(err, invitation.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, "role must be one of owner, admin, member")
+	case errors.Is(err, invitation.ErrRoleNotFound):
+		return status.Error(codes.InvalidArgument, "role not found")
+	case errors.Is(err, invitation.ErrOrgNotFound):
+		return status.Error(codes.NotFound, "organization not found")
+	case errors.Is(err, invitation.ErrInvitationNotFound):
+		return status.Error(codes.NotFound, "invitation not found")
+	case errors.Is(err, invitation.ErrEmailDomain):
+		return status.Error(codes.FailedPrecondition, "email domain is not allowed in the organization")
+	case errors.Is(err, invitation.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
@@ -0,0 +1,26 @@
+package org
+
+import (
+	"sso/internal/domain/models"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+)
+
+func ToProtoOrganization(o models.Organization) *ssov1.Organization {
+	return &ssov1.Organization{
+		Id:                  o.ID,
+		Name:                o.Name,
+		AllowedEmailDomains: o.AllowedEmailDomains,
+		RequireMfa:          o.RequireMFA,
+		CreatedAt:           o.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func ToProtoOrganizationMember(m models.OrgMember) *ssov1.OrganizationMember {
+	return &ssov1.OrganizationMember{
+		UserId: m.UserID,
+		Email:  m.Email,
+		Role:   m.Role,
+	}
+}
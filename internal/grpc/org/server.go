@@ -0,0 +1,291 @@
+package org
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/services/org"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Org interface {
+	CreateOrganization(ctx context.Context, caller models.Principal, org models.Organization, ownerID int64) (models.Organization, error)
+	Organizations(ctx context.Context, caller models.Principal) ([]models.Organization, error)
+	Organization(ctx context.Context, caller models.Principal, orgID int64) (models.Organization, error)
+	UpdateOrganization(ctx context.Context, caller models.Principal, upd models.Organization) (models.Organization, error)
+	DeleteOrganization(ctx context.Context, caller models.Principal, orgID int64) error
+	Members(ctx context.Context, caller models.Principal, orgID int64) ([]models.OrgMember, error)
+	SetMember(ctx context.Context, caller models.Principal, orgID int64, userID int64, role string) error
+	RemoveMember(ctx context.Context, caller models.Principal, orgID int64, userID int64) error
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedOrganizationsServer
+	org Org
+}
+
+func Register(gRPC *grpc.Server, org Org) {
+	ssov1.RegisterOrganizationsServer(gRPC, &serverAPI{org: org})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) CreateOrganization(
+	ctx context.Context,
+	req *ssov1.CreateOrganizationRequest,
+) (*ssov1.CreateOrganizationResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if req.GetOwnerId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "owner_id is required")
+	}
+
+	created, err := s.org.CreateOrganization(ctx, caller, models.Organization{
+		Name:                req.GetName(),
+		AllowedEmailDomains: req.GetAllowedEmailDomains(),
+		RequireMFA:          req.GetRequireMfa(),
+	}, req.GetOwnerId())
+	if err != nil {
+		return nil, orgError(err, "failed to create organization")
+	}
+
+	return &ssov1.CreateOrganizationResponse{Organization: ToProtoOrganization(created)}, nil
+}
+
+func (s *serverAPI) ListOrganizations(
+	ctx context.Context,
+	req *ssov1.ListOrganizationsRequest,
+) (*ssov1.ListOrganizationsResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, err := s.org.Organizations(ctx, caller)
+	if err != nil {
+		return nil, orgError(err, "failed to list organizations")
+	}
+
+	resp := &ssov1.ListOrganizationsResponse{}
+	for _, o := range orgs {
+		resp.Organizations = append(resp.Organizations, ToProtoOrganization(o))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetOrganization(
+	ctx context.Context,
+	req *ssov1.GetOrganizationRequest,
+) (*ssov1.GetOrganizationResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOrgID(req.GetOrgId()); err != nil {
+		return nil, err
+	}
+
+	o, err := s.org.Organization(ctx, caller, req.GetOrgId())
+	if err != nil {
+		return nil, orgError(err, "failed to get organization")
+	}
+
+	return &ssov1.GetOrganizationResponse{Organization: ToProtoOrganization(o)}, nil
+}
+
+func (s *serverAPI) UpdateOrganization(
+	ctx context.Context,
+	req *ssov1.UpdateOrganizationRequest,
+) (*ssov1.UpdateOrganizationResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOrgID(req.GetOrgId()); err != nil {
+		return nil, err
+	}
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	o, err := s.org.UpdateOrganization(ctx, caller, models.Organization{
+		ID:                  req.GetOrgId(),
+		Name:                req.GetName(),
+		AllowedEmailDomains: req.GetAllowedEmailDomains(),
+		RequireMFA:          req.GetRequireMfa(),
+	})
+	if err != nil {
+		return nil, orgError(err, "failed to update organization")
+	}
+
+	return &ssov1.UpdateOrganizationResponse{Organization: ToProtoOrganization(o)}, nil
+}
+
+func (s *serverAPI) DeleteOrganization(
+	ctx context.Context,
+	req *ssov1.DeleteOrganizationRequest,
+) (*ssov1.DeleteOrganizationResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOrgID(req.GetOrgId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.org.DeleteOrganization(ctx, caller, req.GetOrgId()); err != nil {
+		return nil, orgError(err, "failed to delete organization")
+	}
+
+	return &ssov1.DeleteOrganizationResponse{Success: true}, nil
+}
+
+func (s *serverAPI) ListOrganizationMembers(
+	ctx context.Context,
+	req *ssov1.ListOrganizationMembersRequest,
+) (*ssov1.ListOrganizationMembersResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOrgID(req.GetOrgId()); err != nil {
+		return nil, err
+	}
+
+	members, err := s.org.Members(ctx, caller, req.GetOrgId())
+	if err != nil {
+		return nil, orgError(err, "failed to list organization members")
+	}
+
+	resp := &ssov1.ListOrganizationMembersResponse{}
+	for _, member := range members {
+		resp.Members = append(resp.Members, ToProtoOrganizationMember(member))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) SetOrganizationMember(
+	ctx context.Context,
+	req *ssov1.SetOrganizationMemberRequest,
+) (*ssov1.SetOrganizationMemberResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMember(req.GetOrgId(), req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if req.GetRole() == "" {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	if err := s.org.SetMember(ctx, caller, req.GetOrgId(), req.GetUserId(), req.GetRole()); err != nil {
+		return nil, orgError(err, "failed to set organization member")
+	}
+
+	return &ssov1.SetOrganizationMemberResponse{Success: true}, nil
+}
+
+func (s *serverAPI) RemoveOrganizationMember(
+	ctx context.Context,
+	req *ssov1.RemoveOrganizationMemberRequest,
+) (*ssov1.RemoveOrganizationMemberResponse, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMember(req.GetOrgId(), req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.org.RemoveMember(ctx, caller, req.GetOrgId(), req.GetUserId()); err != nil {
+		return nil, orgError(err, "failed to remove organization member")
+	}
+
+	return &ssov1.RemoveOrganizationMemberResponse{Success: true}, nil
+}
+
+// callerFromContext returns the user authenticated by the interceptor.
+func callerFromContext(ctx context.Context) (models.Principal, error) {
+	caller, ok := interceptors.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+	if caller.Type != models.PrincipalUser {
+		return models.Principal{}, status.Error(codes.PermissionDenied, "service accounts can't manage organizations")
+	}
+	// the impersonated user's admin permission must not pass to the support engineer
+	if caller.ActorID != 0 {
+		return models.Principal{}, status.Error(codes.PermissionDenied, "organizations can't be managed while impersonating")
+	}
+
+	return caller, nil
+}
+
+// orgError maps errors of calls on organizations to gRPC statuses.
+func orgError(err error, msg string) error {
+	switch {
+	case errors.Is(err, org.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	case errors.Is(err, org.ErrOrgNotFound):
+		return status.Error(codes.NotFound, "organization not found")
+	case errors.Is(err, org.ErrOrgExists):
+		return status.Error(codes.AlreadyExists, "organization with this name already exists")
+	case errors.Is(err, org.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, org.ErrNotMember):
+		return status.Error(codes.NotFound, "user is not a member of the organization")
+	case errors.Is(err, org.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, "role must be one of owner, admin, member")
+	case errors.Is(err, org.ErrInvalidDomain):
+		return status.Error(codes.InvalidArgument, "invalid email domain")
+	case errors.Is(err, org.ErrLastOwner):
+		return status.Error(codes.FailedPrecondition, "organization must have an owner")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
+
+func validateOrgID(orgID int64) error {
+	if orgID == emptyValue {
+		return status.Error(codes.InvalidArgument, "org_id is required")
+	}
+
+	return nil
+}
+
+func validateMember(orgID int64, userID int64) error {
+	if err := validateOrgID(orgID); err != nil {
+		return err
+	}
+
+	if userID == emptyValue {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package registration
+
+import (
+	"sso/internal/domain/models"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+)
+
+func FromProtoClientMetadata(m *ssov1.ClientMetadata) models.App {
+	return models.App{
+		Name:         m.GetClientName(),
+		RedirectURIs: m.GetRedirectUris(),
+		GrantTypes:   m.GetGrantTypes(),
+		LogoURI:      m.GetLogoUri(),
+		Contacts:     m.GetContacts(),
+	}
+}
+
+func ToProtoClientInformation(a models.App) *ssov1.ClientInformation {
+	return &ssov1.ClientInformation{
+		ClientId:         int32(a.ID),
+		ClientIdIssuedAt: a.RegisteredAt.Unix(),
+		Metadata: &ssov1.ClientMetadata{
+			ClientName:   a.Name,
+			RedirectUris: a.RedirectURIs,
+			GrantTypes:   a.GrantTypes,
+			LogoUri:      a.LogoURI,
+			Contacts:     a.Contacts,
+		},
+	}
+}
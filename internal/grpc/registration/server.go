@@ -0,0 +1,139 @@
+package registration
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/interceptors"
+	"sso/internal/services/registration"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Registration interface {
+	RegisterClient(ctx context.Context, initialToken string, meta models.App) (models.App, string, string, error)
+	Client(ctx context.Context, clientID int, registrationToken string) (models.App, error)
+	UpdateClient(ctx context.Context, clientID int, registrationToken string, meta models.App) (models.App, error)
+	DeleteClient(ctx context.Context, clientID int, registrationToken string) error
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedClientRegistrationServer
+	registration Registration
+}
+
+func Register(gRPC *grpc.Server, registration Registration) {
+	ssov1.RegisterClientRegistrationServer(gRPC, &serverAPI{registration: registration})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) RegisterClient(
+	ctx context.Context,
+	req *ssov1.RegisterClientRequest,
+) (*ssov1.ClientInformation, error) {
+	token, err := interceptors.BearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetMetadata() == nil {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+
+	app, secret, registrationToken, err := s.registration.RegisterClient(ctx, token, FromProtoClientMetadata(req.GetMetadata()))
+	if err != nil {
+		return nil, registrationError(err, "failed to register client")
+	}
+
+	info := ToProtoClientInformation(app)
+	info.ClientSecret = secret
+	info.RegistrationAccessToken = registrationToken
+
+	return info, nil
+}
+
+func (s *serverAPI) GetClient(
+	ctx context.Context,
+	req *ssov1.GetClientRequest,
+) (*ssov1.ClientInformation, error) {
+	token, err := interceptors.BearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetClientId() <= emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "client_id is required")
+	}
+
+	app, err := s.registration.Client(ctx, int(req.GetClientId()), token)
+	if err != nil {
+		return nil, registrationError(err, "failed to get client")
+	}
+
+	return ToProtoClientInformation(app), nil
+}
+
+func (s *serverAPI) UpdateClient(
+	ctx context.Context,
+	req *ssov1.UpdateClientRequest,
+) (*ssov1.ClientInformation, error) {
+	token, err := interceptors.BearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetClientId() <= emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "client_id is required")
+	}
+	if req.GetMetadata() == nil {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+
+	app, err := s.registration.UpdateClient(ctx, int(req.GetClientId()), token, FromProtoClientMetadata(req.GetMetadata()))
+	if err != nil {
+		return nil, registrationError(err, "failed to update client")
+	}
+
+	return ToProtoClientInformation(app), nil
+}
+
+func (s *serverAPI) DeleteClient(
+	ctx context.Context,
+	req *ssov1.DeleteClientRequest,
+) (*ssov1.DeleteClientResponse, error) {
+	token, err := interceptors.BearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetClientId() <= emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "client_id is required")
+	}
+
+	if err := s.registration.DeleteClient(ctx, int(req.GetClientId()), token); err != nil {
+		return nil, registrationError(err, "failed to delete client")
+	}
+
+	return &ssov1.DeleteClientResponse{}, nil
+}
+
+// registrationError maps errors of the registration service to gRPC statuses. Metadata errors
+// keep their message, it names the invalid field like error_description of RFC 7591 does.
+func registrationError(err error, msg string) error {
+	switch {
+	case errors.Is(err, registration.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	case errors.Is(err, registration.ErrInvalidClientMetadata), errors.Is(err, registration.ErrInvalidRedirectURI):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, registration.ErrClientExists):
+		return status.Error(codes.AlreadyExists, "client with this name already exists")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
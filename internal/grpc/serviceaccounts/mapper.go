@@ -0,0 +1,21 @@
+package serviceaccounts
+
+import (
+	"sso/internal/domain/models"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+)
+
+func ToProtoServiceAccount(sa models.ServiceAccount) *ssov1.ServiceAccount {
+	return &ssov1.ServiceAccount{
+		Id:          sa.ID,
+		Name:        sa.Name,
+		Description: sa.Description,
+		AppId:       int32(sa.AppID),
+		OrgId:       sa.OrgID,
+		ClientId:    sa.ClientID,
+		Disabled:    sa.Disabled,
+		CreatedAt:   sa.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,304 @@
+package serviceaccounts
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/grpc/apikeys"
+	"sso/internal/services/admin"
+	"time"
+
+	ssov1 "github.com/Abazin97/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ServiceAccounts interface {
+	CreateServiceAccount(ctx context.Context, sa models.ServiceAccount) (models.ServiceAccount, string, error)
+	ListServiceAccounts(ctx context.Context) ([]models.ServiceAccount, error)
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
+	UpdateServiceAccount(ctx context.Context, upd models.ServiceAccount) (models.ServiceAccount, error)
+	DeleteServiceAccount(ctx context.Context, id int64) error
+	RotateServiceAccountSecret(ctx context.Context, id int64) (string, error)
+	SetServiceAccountRoles(ctx context.Context, id int64, appID int, roles []string) ([]string, error)
+	CreateServiceAccountAPIKey(
+		ctx context.Context,
+		id int64,
+		appID int,
+		name string,
+		scopes []string,
+		ttl time.Duration,
+	) (models.APIKey, string, error)
+	ServiceAccountAPIKeys(ctx context.Context, id int64) ([]models.APIKey, error)
+	RevokeServiceAccountAPIKey(ctx context.Context, id int64, keyID int64) error
+}
+
+type serverAPI struct {
+	ssov1.UnimplementedServiceAccountsServer
+	serviceAccounts ServiceAccounts
+}
+
+func Register(gRPC *grpc.Server, serviceAccounts ServiceAccounts) {
+	ssov1.RegisterServiceAccountsServer(gRPC, &serverAPI{serviceAccounts: serviceAccounts})
+}
+
+const (
+	emptyValue = 0
+)
+
+func (s *serverAPI) CreateServiceAccount(
+	ctx context.Context,
+	req *ssov1.CreateServiceAccountRequest,
+) (*ssov1.CreateServiceAccountResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if (req.GetAppId() == emptyValue) == (req.GetOrgId() == emptyValue) {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of app_id and org_id is required")
+	}
+
+	sa, secret, err := s.serviceAccounts.CreateServiceAccount(ctx, models.ServiceAccount{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		AppID:       int(req.GetAppId()),
+		OrgID:       req.GetOrgId(),
+	})
+	if err != nil {
+		return nil, serviceAccountError(err, "failed to create service account")
+	}
+
+	return &ssov1.CreateServiceAccountResponse{
+		ServiceAccount: ToProtoServiceAccount(sa),
+		ClientSecret:   secret,
+	}, nil
+}
+
+func (s *serverAPI) ListServiceAccounts(
+	ctx context.Context,
+	req *ssov1.ListServiceAccountsRequest,
+) (*ssov1.ListServiceAccountsResponse, error) {
+	accounts, err := s.serviceAccounts.ListServiceAccounts(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list service accounts")
+	}
+
+	resp := &ssov1.ListServiceAccountsResponse{}
+	for _, sa := range accounts {
+		resp.ServiceAccounts = append(resp.ServiceAccounts, ToProtoServiceAccount(sa))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetServiceAccount(
+	ctx context.Context,
+	req *ssov1.GetServiceAccountRequest,
+) (*ssov1.GetServiceAccountResponse, error) {
+	if req.GetServiceAccountId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+
+	sa, err := s.serviceAccounts.ServiceAccount(ctx, req.GetServiceAccountId())
+	if err != nil {
+		return nil, serviceAccountError(err, "failed to get service account")
+	}
+
+	return &ssov1.GetServiceAccountResponse{ServiceAccount: ToProtoServiceAccount(sa)}, nil
+}
+
+func (s *serverAPI) UpdateServiceAccount(
+	ctx context.Context,
+	req *ssov1.UpdateServiceAccountRequest,
+) (*ssov1.UpdateServiceAccountResponse, error) {
+	if req.GetServiceAccountId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	sa, err := s.serviceAccounts.UpdateServiceAccount(ctx, models.ServiceAccount{
+		ID:          req.GetServiceAccountId(),
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Disabled:    req.GetDisabled(),
+	})
+	if err != nil {
+		return nil, serviceAccountError(err, "failed to update service account")
+	}
+
+	return &ssov1.UpdateServiceAccountResponse{ServiceAccount: ToProtoServiceAccount(sa)}, nil
+}
+
+func (s *serverAPI) DeleteServiceAccount(
+	ctx context.Context,
+	req *ssov1.DeleteServiceAccountRequest,
+) (*ssov1.DeleteServiceAccountResponse, error) {
+	if req.GetServiceAccountId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+
+	if err := s.serviceAccounts.DeleteServiceAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, serviceAccountError(err, "failed to delete service account")
+	}
+
+	return &ssov1.DeleteServiceAccountResponse{Success: true}, nil
+}
+
+func (s *serverAPI) RotateServiceAccountSecret(
+	ctx context.Context,
+	req *ssov1.RotateServiceAccountSecretRequest,
+) (*ssov1.RotateServiceAccountSecretResponse, error) {
+	if req.GetServiceAccountId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+
+	secret, err := s.serviceAccounts.RotateServiceAccountSecret(ctx, req.GetServiceAccountId())
+	if err != nil {
+		return nil, serviceAccountError(err, "failed to rotate service account secret")
+	}
+
+	return &ssov1.RotateServiceAccountSecretResponse{ClientSecret: secret}, nil
+}
+
+func (s *serverAPI) SetServiceAccountRoles(
+	ctx context.Context,
+	req *ssov1.SetServiceAccountRolesRequest,
+) (*ssov1.SetServiceAccountRolesResponse, error) {
+	if req.GetServiceAccountId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	roles, err := s.serviceAccounts.SetServiceAccountRoles(
+		ctx,
+		req.GetServiceAccountId(),
+		int(req.GetAppId()),
+		req.GetRoles(),
+	)
+	if err != nil {
+		return nil, serviceAccountError(err, "failed to set service account roles")
+	}
+
+	return &ssov1.SetServiceAccountRolesResponse{Roles: roles}, nil
+}
+
+func (s *serverAPI) CreateServiceAccountAPIKey(
+	ctx context.Context,
+	req *ssov1.CreateServiceAccountAPIKeyRequest,
+) (*ssov1.CreateServiceAccountAPIKeyResponse, error) {
+	if err := validateCreateAPIKey(req); err != nil {
+		return nil, err
+	}
+
+	key, plain, err := s.serviceAccounts.CreateServiceAccountAPIKey(
+		ctx,
+		req.GetServiceAccountId(),
+		int(req.GetAppId()),
+		req.GetName(),
+		req.GetScopes(),
+		time.Duration(req.GetExpiresIn())*time.Second,
+	)
+	if err != nil {
+		return nil, serviceAccountError(err, "failed to create api key")
+	}
+
+	return &ssov1.CreateServiceAccountAPIKeyResponse{
+		ApiKey: apikeys.ToProtoAPIKey(key),
+		Key:    plain,
+	}, nil
+}
+
+func (s *serverAPI) ListServiceAccountAPIKeys(
+	ctx context.Context,
+	req *ssov1.ListServiceAccountAPIKeysRequest,
+) (*ssov1.ListServiceAccountAPIKeysResponse, error) {
+	if req.GetServiceAccountId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+
+	keys, err := s.serviceAccounts.ServiceAccountAPIKeys(ctx, req.GetServiceAccountId())
+	if err != nil {
+		return nil, serviceAccountError(err, "failed to list api keys")
+	}
+
+	resp := &ssov1.ListServiceAccountAPIKeysResponse{}
+	for _, key := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, apikeys.ToProtoAPIKey(key))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeServiceAccountAPIKey(
+	ctx context.Context,
+	req *ssov1.RevokeServiceAccountAPIKeyRequest,
+) (*ssov1.RevokeServiceAccountAPIKeyResponse, error) {
+	if req.GetServiceAccountId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+	if req.GetApiKeyId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "api_key_id is required")
+	}
+
+	if err := s.serviceAccounts.RevokeServiceAccountAPIKey(ctx, req.GetServiceAccountId(), req.GetApiKeyId()); err != nil {
+		return nil, serviceAccountError(err, "failed to revoke api key")
+	}
+
+	return &ssov1.RevokeServiceAccountAPIKeyResponse{Success: true}, nil
+}
+
+func validateCreateAPIKey(req *ssov1.CreateServiceAccountAPIKeyRequest) error {
+	if req.GetServiceAccountId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+	if req.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	for _, scope := range req.GetScopes() {
+		if scope == "" {
+			return status.Error(codes.InvalidArgument, "scopes must be non-empty")
+		}
+	}
+
+	if req.GetExpiresIn() < emptyValue {
+		return status.Error(codes.InvalidArgument, "expires_in must be positive")
+	}
+
+	return nil
+}
+
+// serviceAccountError maps errors of calls on service accounts to gRPC statuses.
+func serviceAccountError(err error, msg string) error {
+	switch {
+	case errors.Is(err, admin.ErrServiceAccountNotFound):
+		return status.Error(codes.NotFound, "service account not found")
+	case errors.Is(err, admin.ErrServiceAccountExists):
+		return status.Error(codes.AlreadyExists, "service account with this name already exists")
+	case errors.Is(err, admin.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, admin.ErrOrgNotFound):
+		return status.Error(codes.NotFound, "organization not found")
+	case errors.Is(err, admin.ErrInvalidOwner):
+		return status.Error(codes.InvalidArgument, "exactly one of app_id and org_id is required")
+	case errors.Is(err, admin.ErrAppNotAccessible):
+		return status.Error(codes.FailedPrecondition, "service account can't access the app")
+	case errors.Is(err, admin.ErrRoleNotFound):
+		return status.Error(codes.InvalidArgument, "role not found")
+	case errors.Is(err, admin.ErrAPIKeyExists):
+		return status.Error(codes.AlreadyExists, "api key with this name already exists")
+	case errors.Is(err, admin.ErrAPIKeyNotFound):
+		return status.Error(codes.NotFound, "api key not found")
+	case errors.Is(err, admin.ErrInvalidTTL):
+		return status.Error(codes.InvalidArgument, "expires_in exceeds the maximum api key lifetime")
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
@@ -0,0 +1,81 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sso/internal/domain/models"
+	"sso/internal/services/auth"
+)
+
+// CallbackPath is the path of the callback registered at upstream identity providers.
+const CallbackPath = "/federation/callback"
+
+type Federation interface {
+	FederatedCallback(ctx context.Context, state string, code string, providerError string) (models.FederatedLogin, string, error)
+}
+
+type handler struct {
+	log        *slog.Logger
+	federation Federation
+}
+
+// Register serves the callback upstream identity providers send users back to.
+func Register(mux *http.ServeMux, log *slog.Logger, federation Federation) {
+	h := &handler{log: log, federation: federation}
+
+	mux.HandleFunc("GET "+CallbackPath, h.callback)
+}
+
+// callback finishes the login at the provider and sends the user back to the app: with the login
+// code on success, with the error code of RFC 6749 otherwise. Both carry the state the app passed.
+func (h *handler) callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if q.Get("state") == "" {
+		http.Error(w, "state is required", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code") == "" && q.Get("error") == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	login, code, err := h.federation.FederatedCallback(r.Context(), q.Get("state"), q.Get("code"), q.Get("error"))
+	if errors.Is(err, auth.ErrInvalidFederatedLogin) {
+		http.Error(w, "login is invalid or expired, please start over", http.StatusBadRequest)
+		return
+	}
+	// the app is unknown until the login is found
+	if login.RedirectURI == "" {
+		http.Error(w, "failed to log in", http.StatusInternalServerError)
+		return
+	}
+
+	redirect, parseErr := url.Parse(login.RedirectURI)
+	if parseErr != nil {
+		h.log.Error("invalid redirect uri", slog.String("redirectURI", login.RedirectURI))
+		http.Error(w, "failed to log in", http.StatusInternalServerError)
+		return
+	}
+
+	params := redirect.Query()
+	switch {
+	case err == nil:
+		params.Set("code", code)
+	case errors.Is(err, auth.ErrFederatedLoginDenied), errors.Is(err, auth.ErrEmailNotVerified):
+		params.Set("error", "access_denied")
+	case errors.Is(err, auth.ErrFederatedLoginFailed):
+		params.Set("error", "temporarily_unavailable")
+	default:
+		params.Set("error", "server_error")
+	}
+	if login.ClientState != "" {
+		params.Set("state", login.ClientState)
+	}
+	redirect.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
@@ -0,0 +1,51 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// API keys look like sso_<prefix>_<secret>, the part up to the second underscore is the stored prefix.
+	tag         = "sso_"
+	prefixBytes = 6
+	secretBytes = 32
+	prefixLen   = len(tag) + 2*prefixBytes
+)
+
+// Generate returns a random key and its prefix.
+func Generate() (string, string, error) {
+	b := make([]byte, prefixBytes+secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	prefix := tag + hex.EncodeToString(b[:prefixBytes])
+
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(b[prefixBytes:]), prefix, nil
+}
+
+// Prefix returns the prefix identifying the key, false if the key is malformed.
+func Prefix(key string) (string, bool) {
+	if !strings.HasPrefix(key, tag) || len(key) <= prefixLen+1 || key[prefixLen] != '_' {
+		return "", false
+	}
+
+	return key[:prefixLen], true
+}
+
+// Hash returns SHA-256 of the key. Keys are random, so unlike passwords they need no slow hash.
+func Hash(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+
+	return sum[:]
+}
+
+// Matches compares the key with the hash in constant time.
+func Matches(key string, hash []byte) bool {
+	return subtle.ConstantTimeCompare(Hash(key), hash) == 1
+}
@@ -0,0 +1,28 @@
+package appsecret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const secretBytes = 32
+
+// Generate returns a random secret of an app or a service account and its bcrypt hash,
+// only the hash is stored.
+func Generate() (string, []byte, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	secretHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return secret, secretHash, nil
+}
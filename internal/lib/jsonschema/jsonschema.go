@@ -0,0 +1,465 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidSchema = errors.New("invalid json schema")
+	ErrInvalid       = errors.New("value doesn't match the schema")
+)
+
+// unsupported are keywords of the specification Compile rejects, since ignoring them
+// would accept values the schema author meant to reject.
+var unsupported = []string{
+	"$ref", "$defs", "definitions", "allOf", "anyOf", "oneOf", "not",
+	"if", "then", "else", "dependentRequired", "dependentSchemas",
+	"patternProperties", "propertyNames", "unevaluatedProperties", "contains", "prefixItems",
+}
+
+var types = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// Schema is a compiled JSON schema. Only a subset of the specification is supported:
+// type, enum, const, properties, required, additionalProperties, minProperties, maxProperties,
+// items, minItems, maxItems, uniqueItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum and exclusiveMaximum. Annotations like title and format are ignored.
+type Schema struct {
+	types      []string
+	enum       []any
+	constant   *any
+	properties map[string]*Schema
+	required   []string
+	// additional is nil if any additional property is allowed
+	additional    *Schema
+	noAdditional  bool
+	minProperties *int
+	maxProperties *int
+	items         *Schema
+	minItems      *int
+	maxItems      *int
+	uniqueItems   bool
+	minLength     *int
+	maxLength     *int
+	pattern       *regexp.Regexp
+	minimum       *float64
+	maximum       *float64
+	exclusiveMin  *float64
+	exclusiveMax  *float64
+	// never rejects everything, as the false schema
+	never bool
+}
+
+// Compile parses the JSON schema document.
+func Compile(schema []byte) (*Schema, error) {
+	var doc any
+	if err := unmarshal(schema, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+
+	s, err := compile(doc, "#")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+
+	return s, nil
+}
+
+func compile(doc any, path string) (*Schema, error) {
+	switch doc := doc.(type) {
+	case bool:
+		return &Schema{never: !doc}, nil
+	case map[string]any:
+		return compileObject(doc, path)
+	}
+
+	return nil, fmt.Errorf("%s: schema must be an object or a boolean", path)
+}
+
+func compileObject(doc map[string]any, path string) (*Schema, error) {
+	for _, keyword := range unsupported {
+		if _, ok := doc[keyword]; ok {
+			return nil, fmt.Errorf("%s: keyword %q is not supported", path, keyword)
+		}
+	}
+
+	s := &Schema{}
+	var err error
+
+	switch t := doc["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []any:
+		for _, v := range t {
+			name, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/type: must be a string or an array of strings", path)
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return nil, fmt.Errorf("%s/type: must be a string or an array of strings", path)
+	}
+	for _, t := range s.types {
+		if !slices.Contains(types, t) {
+			return nil, fmt.Errorf("%s/type: unknown type %q", path, t)
+		}
+	}
+
+	if v, ok := doc["enum"]; ok {
+		if s.enum, ok = v.([]any); !ok {
+			return nil, fmt.Errorf("%s/enum: must be an array", path)
+		}
+	}
+	if v, ok := doc["const"]; ok {
+		s.constant = &v
+	}
+
+	if v, ok := doc["properties"]; ok {
+		props, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/properties: must be an object", path)
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, prop := range props {
+			if s.properties[name], err = compile(prop, path+"/properties/"+name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if v, ok := doc["required"]; ok {
+		required, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/required: must be an array of strings", path)
+		}
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/required: must be an array of strings", path)
+			}
+			s.required = append(s.required, name)
+		}
+	}
+
+	if v, ok := doc["additionalProperties"]; ok {
+		if allowed, ok := v.(bool); ok {
+			s.noAdditional = !allowed
+		} else if s.additional, err = compile(v, path+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := doc["items"]; ok {
+		if s.items, err = compile(v, path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := doc["uniqueItems"]; ok {
+		if s.uniqueItems, ok = v.(bool); !ok {
+			return nil, fmt.Errorf("%s/uniqueItems: must be a boolean", path)
+		}
+	}
+
+	for keyword, dst := range map[string]**int{
+		"minProperties": &s.minProperties,
+		"maxProperties": &s.maxProperties,
+		"minItems":      &s.minItems,
+		"maxItems":      &s.maxItems,
+		"minLength":     &s.minLength,
+		"maxLength":     &s.maxLength,
+	} {
+		if *dst, err = count(doc, keyword, path); err != nil {
+			return nil, err
+		}
+	}
+
+	for keyword, dst := range map[string]**float64{
+		"minimum":          &s.minimum,
+		"maximum":          &s.maximum,
+		"exclusiveMinimum": &s.exclusiveMin,
+		"exclusiveMaximum": &s.exclusiveMax,
+	} {
+		if *dst, err = number(doc, keyword, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := doc["pattern"]; ok {
+		pattern, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/pattern: must be a string", path)
+		}
+		if s.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%s/pattern: %w", path, err)
+		}
+	}
+
+	return s, nil
+}
+
+func count(doc map[string]any, keyword string, path string) (*int, error) {
+	v, ok := doc[keyword]
+	if !ok {
+		return nil, nil
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: must be a non-negative integer", path, keyword)
+	}
+	i, err := n.Int64()
+	if err != nil || i < 0 || i > math.MaxInt32 {
+		return nil, fmt.Errorf("%s/%s: must be a non-negative integer", path, keyword)
+	}
+
+	c := int(i)
+
+	return &c, nil
+}
+
+func number(doc map[string]any, keyword string, path string) (*float64, error) {
+	v, ok := doc[keyword]
+	if !ok {
+		return nil, nil
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: must be a number", path, keyword)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: must be a number", path, keyword)
+	}
+
+	return &f, nil
+}
+
+// Validate checks the JSON document against the schema.
+// The error tells the location of the first mismatch found.
+func (s *Schema) Validate(document []byte) error {
+	var v any
+	if err := unmarshal(document, &v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	if err := s.validate(v, "#"); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	return nil
+}
+
+func (s *Schema) validate(v any, path string) error {
+	if s.never {
+		return fmt.Errorf("%s: no value is allowed", path)
+	}
+
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return hasType(v, t) }) {
+		return fmt.Errorf("%s: must be of type %s", path, strings.Join(s.types, " or "))
+	}
+
+	if s.enum != nil && !slices.ContainsFunc(s.enum, func(e any) bool { return equal(v, e) }) {
+		return fmt.Errorf("%s: must be one of the enum values", path)
+	}
+	if s.constant != nil && !equal(v, *s.constant) {
+		return fmt.Errorf("%s: must be equal to the const value", path)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		return s.validateObject(v, path)
+	case []any:
+		return s.validateArray(v, path)
+	case string:
+		return s.validateString(v, path)
+	case json.Number:
+		return s.validateNumber(v, path)
+	}
+
+	return nil
+}
+
+func (s *Schema) validateObject(v map[string]any, path string) error {
+	for _, name := range s.required {
+		if _, ok := v[name]; !ok {
+			return fmt.Errorf("%s: property %q is required", path, name)
+		}
+	}
+
+	if s.minProperties != nil && len(v) < *s.minProperties {
+		return fmt.Errorf("%s: must have at least %d properties", path, *s.minProperties)
+	}
+	if s.maxProperties != nil && len(v) > *s.maxProperties {
+		return fmt.Errorf("%s: must have at most %d properties", path, *s.maxProperties)
+	}
+
+	// sorted, so that the same document always fails with the same error
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		propPath := path + "/" + name
+
+		if prop, ok := s.properties[name]; ok {
+			if err := prop.validate(v[name], propPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if s.noAdditional {
+			return fmt.Errorf("%s: property is not allowed", propPath)
+		}
+		if s.additional != nil {
+			if err := s.additional.validate(v[name], propPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateArray(v []any, path string) error {
+	if s.minItems != nil && len(v) < *s.minItems {
+		return fmt.Errorf("%s: must have at least %d items", path, *s.minItems)
+	}
+	if s.maxItems != nil && len(v) > *s.maxItems {
+		return fmt.Errorf("%s: must have at most %d items", path, *s.maxItems)
+	}
+
+	for i, item := range v {
+		if s.uniqueItems && slices.ContainsFunc(v[:i], func(prev any) bool { return equal(prev, item) }) {
+			return fmt.Errorf("%s/%d: items must be unique", path, i)
+		}
+		if s.items != nil {
+			if err := s.items.validate(item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateString(v string, path string) error {
+	length := utf8.RuneCountInString(v)
+
+	if s.minLength != nil && length < *s.minLength {
+		return fmt.Errorf("%s: must be at least %d characters long", path, *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		return fmt.Errorf("%s: must be at most %d characters long", path, *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		return fmt.Errorf("%s: must match pattern %q", path, s.pattern.String())
+	}
+
+	return nil
+}
+
+func (s *Schema) validateNumber(v json.Number, path string) error {
+	f, err := v.Float64()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if s.minimum != nil && f < *s.minimum {
+		return fmt.Errorf("%s: must be at least %v", path, *s.minimum)
+	}
+	if s.maximum != nil && f > *s.maximum {
+		return fmt.Errorf("%s: must be at most %v", path, *s.maximum)
+	}
+	if s.exclusiveMin != nil && f <= *s.exclusiveMin {
+		return fmt.Errorf("%s: must be greater than %v", path, *s.exclusiveMin)
+	}
+	if s.exclusiveMax != nil && f >= *s.exclusiveMax {
+		return fmt.Errorf("%s: must be less than %v", path, *s.exclusiveMax)
+	}
+
+	return nil
+}
+
+func hasType(v any, t string) bool {
+	switch v := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case map[string]any:
+		return t == "object"
+	case []any:
+		return t == "array"
+	case string:
+		return t == "string"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		// 1.0 is an integer too
+		f, err := v.Float64()
+		return t == "integer" && err == nil && f == math.Trunc(f)
+	}
+
+	return false
+}
+
+// equal compares JSON values, numbers are equal if their values are, e.g. 1 and 1.0.
+func equal(a any, b any) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, equal)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// unmarshal decodes a single JSON value keeping numbers precise.
+func unmarshal(data []byte, v *any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after the json value")
+	}
+
+	return nil
+}
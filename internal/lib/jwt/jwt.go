@@ -1,20 +1,64 @@
 package jwt
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"sso/internal/domain/models"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func NewToken(user models.User, app models.App, duration time.Duration) (string, error) {
+var ErrInvalidToken = errors.New("invalid token")
+
+// NewToken issues a token for the principal signed with the app secret.
+// Organization claims are only set if the principal logged in to an organization,
+// the groups claim is only set if the principal has groups listed,
+// the attributes claim is only set if the app maps attributes to claims,
+// the scope claim is only set if the app requested scopes
+// and the act claim is only set if the principal is impersonated.
+func NewToken(principal models.Principal, app models.App, duration time.Duration) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 
+	principalType := principal.Type
+	if principalType == "" {
+		principalType = models.PrincipalUser
+	}
+
 	claims := token.Claims.(jwt.MapClaims)
-	claims["uid"] = user.ID
-	claims["email"] = user.Email
+	claims["principal_type"] = principalType
+	claims["uid"] = principal.UserID
+	claims["email"] = principal.Email
+	claims["iat"] = time.Now().Unix()
 	claims["exp"] = time.Now().Add(duration).Unix()
 	claims["app_id"] = app.ID
+	claims["roles"] = principal.Roles
+	if principal.OrgID != 0 {
+		claims["org_id"] = principal.OrgID
+		claims["org_role"] = principal.OrgRole
+	}
+	if principal.Groups != nil {
+		claims["groups"] = principal.Groups
+	}
+	if principal.GroupsOverage {
+		claims["groups_overage"] = true
+	}
+	if principal.Attributes != nil {
+		claims["attributes"] = principal.Attributes
+	}
+	// scope is space-delimited, as in RFC 9068
+	if len(principal.Scopes) > 0 {
+		claims["scope"] = strings.Join(principal.Scopes, " ")
+	}
+	// act identifies the user impersonating the principal, as in RFC 8693
+	if principal.ActorID != 0 {
+		claims["act"] = map[string]any{
+			"uid":   principal.ActorID,
+			"email": principal.ActorEmail,
+		}
+	}
 
 	tokenString, err := token.SignedString(app.SecretHash)
 	if err != nil {
@@ -24,6 +68,97 @@ func NewToken(user models.User, app models.App, duration time.Duration) (string,
 	return tokenString, nil
 }
 
+// ParseToken verifies the token issued by NewToken and returns the principal it was issued to.
+// appSecret returns the signing key of the app the token claims to be issued for.
+func ParseToken(tokenString string, appSecret func(appID int) ([]byte, error)) (models.Principal, error) {
+	token, err := jwt.Parse(
+		tokenString,
+		func(token *jwt.Token) (any, error) {
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return nil, ErrInvalidToken
+			}
+
+			appID, ok := claims["app_id"].(float64)
+			if !ok {
+				return nil, ErrInvalidToken
+			}
+
+			return appSecret(int(appID))
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	uid, _ := claims["uid"].(float64)
+	appID, _ := claims["app_id"].(float64)
+	email, _ := claims["email"].(string)
+	orgID, _ := claims["org_id"].(float64)
+	orgRole, _ := claims["org_role"].(string)
+	iat, _ := claims["iat"].(float64)
+	exp, _ := claims["exp"].(float64)
+
+	// tokens issued before service accounts existed have no principal type
+	principalType, _ := claims["principal_type"].(string)
+	if principalType == "" {
+		principalType = models.PrincipalUser
+	}
+
+	principal := models.Principal{
+		Type:      principalType,
+		UserID:    int64(uid),
+		Email:     email,
+		AppID:     int(appID),
+		OrgID:     int64(orgID),
+		OrgRole:   orgRole,
+		IssuedAt:  time.Unix(int64(iat), 0),
+		ExpiresAt: time.Unix(int64(exp), 0),
+	}
+
+	roles, _ := claims["roles"].([]any)
+	for _, role := range roles {
+		if name, ok := role.(string); ok {
+			principal.Roles = append(principal.Roles, name)
+		}
+	}
+
+	groups, _ := claims["groups"].([]any)
+	for _, group := range groups {
+		if name, ok := group.(string); ok {
+			principal.Groups = append(principal.Groups, name)
+		}
+	}
+	principal.GroupsOverage, _ = claims["groups_overage"].(bool)
+
+	if attrs, ok := claims["attributes"].(map[string]any); ok {
+		principal.Attributes = make(models.Attributes, len(attrs))
+		for key, value := range attrs {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return models.Principal{}, fmt.Errorf("%w: attribute %q: %w", ErrInvalidToken, key, err)
+			}
+			principal.Attributes[key] = raw
+		}
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		principal.Scopes = strings.Fields(scope)
+	}
+
+	if act, ok := claims["act"].(map[string]any); ok {
+		actorID, _ := act["uid"].(float64)
+		principal.ActorID = int64(actorID)
+		principal.ActorEmail, _ = act["email"].(string)
+	}
+
+	return principal, nil
+}
+
 //func PendingToken(user models.User, app models.App, duration time.Duration) (string, error) {
 //	claims := jwt.MapClaims{
 //		"uid": user.ID,
@@ -0,0 +1,418 @@
+// Package oidc is a relying party of OpenID Connect providers: it sends users to log in
+// at the provider with the authorization code flow and verifies the ID tokens it returns.
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrDiscovery    = errors.New("failed to discover provider")
+	ErrExchange     = errors.New("failed to exchange authorization code")
+	ErrInvalidToken = errors.New("invalid id token")
+)
+
+// maxResponseSize bounds responses read from providers.
+const maxResponseSize = 1 << 20
+
+// signingMethods are algorithms ID tokens may be signed with, HMAC and "none" are never accepted.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Config describes the client registered at the provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback URL registered at the provider.
+	RedirectURL string
+	// Scopes are requested along with openid.
+	Scopes []string
+}
+
+// Claims are claims of a verified ID token identifying the user.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	GivenName     string
+	FamilyName    string
+}
+
+// Provider is an OpenID Connect provider. Its metadata and keys are fetched on first use
+// and the keys are fetched again when a token is signed with an unknown one.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]any
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider returns the provider described by cfg. Nil client stands for http.DefaultClient.
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Provider{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// CodeChallenge returns the S256 PKCE challenge of the code verifier, as in RFC 7636.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL of the provider's login page. The provider sends the user
+// back to the redirect URL with the state and an authorization code for Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	const op = "oidc.AuthCodeURL"
+
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %w", op, ErrDiscovery, err)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(append([]string{"openid"}, p.cfg.Scopes...), " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallenge(codeVerifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange redeems the authorization code at the token endpoint and returns the raw ID token.
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (string, error) {
+	const op = "oidc.Exchange"
+
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client_secret_basic, the authentication every provider supports
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	var resp struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.do(req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %w", op, ErrExchange, err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("%s: %w: status %d: %s %s", op, ErrExchange, status, resp.Error, resp.ErrorDescription)
+	}
+	if resp.IDToken == "" {
+		return "", fmt.Errorf("%s: %w: no id_token in response", op, ErrExchange)
+	}
+
+	return resp.IDToken, nil
+}
+
+// Verify checks the signature, issuer, audience, expiry and nonce of the ID token
+// and returns its claims.
+func (p *Provider) Verify(ctx context.Context, rawIDToken string, nonce string) (Claims, error) {
+	const op = "oidc.Verify"
+
+	md, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var claims struct {
+		jwt.RegisteredClaims
+		Nonce         string `json:"nonce"`
+		AZP           string `json:"azp"`
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		Name          string `json:"name"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+	}
+
+	_, err = jwt.ParseWithClaims(
+		rawIDToken,
+		&claims,
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+
+			return p.key(ctx, md.JWKSURI, kid)
+		},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(md.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidToken, err)
+	}
+
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%s: %w: no subject", op, ErrInvalidToken)
+	}
+	if claims.Nonce != nonce {
+		return Claims{}, fmt.Errorf("%s: %w: nonce mismatch", op, ErrInvalidToken)
+	}
+	// tokens issued to several audiences must name the party they were issued to
+	if len(claims.Audience) > 1 && claims.AZP != p.cfg.ClientID {
+		return Claims{}, fmt.Errorf("%s: %w: authorized party mismatch", op, ErrInvalidToken)
+	}
+
+	return Claims{
+		Subject: claims.Subject,
+		Email:   claims.Email,
+		// some providers send the flag as a string
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:          claims.Name,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
+
+// discover fetches the provider metadata from its well-known location, see OpenID Connect Discovery.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	var md metadata
+	status, err := p.do(req, &md)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrDiscovery, status)
+	}
+	// the issuer must be the one configured, or tokens of another issuer would be accepted
+	if md.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q doesn't match %q", ErrDiscovery, md.Issuer, p.cfg.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete metadata", ErrDiscovery)
+	}
+
+	p.metadata = &md
+
+	return p.metadata, nil
+}
+
+// key returns the public key the ID token was signed with. Keys are fetched again
+// on unknown key IDs, since providers rotate them.
+func (p *Provider) key(ctx context.Context, jwksURI string, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	keys, err := p.fetchKeys(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// lookupKey finds the key by ID. Tokens without a key ID can only be verified
+// if the provider publishes a single key.
+func (p *Provider) lookupKey(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+
+	return key, ok
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys fetches the signing keys of the JWK set, keys of unsupported types are skipped.
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	status, err := p.do(req, &set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch keys: status %d", status)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid exponent")
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{
+			"P-256": elliptic.P256(),
+			"P-384": elliptic.P384(),
+			"P-521": elliptic.P521(),
+		}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// do sends the request and decodes the JSON response into v whatever the status is,
+// error responses of the token endpoint are JSON as well.
+func (p *Provider) do(req *http.Request, v any) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return resp.StatusCode, nil
+}
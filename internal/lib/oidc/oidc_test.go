@@ -0,0 +1,206 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"sso/internal/lib/oidc"
+	"sso/internal/lib/oidc/oidctest"
+)
+
+func TestAuthCodeURL(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	p := oidc.NewProvider(op.Config(), op.Client())
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := u.Scheme+"://"+u.Host+u.Path, op.URL+"/authorize"; got != want {
+		t.Errorf("AuthCodeURL() endpoint = %s, want %s", got, want)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             oidctest.ClientID,
+		"redirect_uri":          op.Config().RedirectURL,
+		"scope":                 "openid",
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"code_challenge":        oidc.CodeChallenge("verifier"),
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := u.Query().Get(name); got != value {
+			t.Errorf("AuthCodeURL() %s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	op.Issuer = "https://attacker.example.com"
+	p := oidc.NewProvider(op.Config(), op.Client())
+
+	_, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	if !errors.Is(err, oidc.ErrDiscovery) {
+		t.Errorf("AuthCodeURL() error = %v, want %v", err, oidc.ErrDiscovery)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	p := oidc.NewProvider(op.Config(), op.Client())
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, code := op.Authorize(t, authURL, jwt.MapClaims{"sub": "user"})
+	if _, err := p.Exchange(ctx, code, "another verifier"); !errors.Is(err, oidc.ErrExchange) {
+		t.Errorf("Exchange() with another code verifier error = %v, want %v", err, oidc.ErrExchange)
+	}
+
+	_, code = op.Authorize(t, authURL, jwt.MapClaims{"sub": "user"})
+	idToken, err := p.Exchange(ctx, code, "verifier")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	claims, err := p.Verify(ctx, idToken, "nonce")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.Subject != "user" {
+		t.Errorf("Verify() subject = %q, want %q", claims.Subject, "user")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	p := oidc.NewProvider(op.Config(), op.Client())
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		idToken string
+		want    oidc.Claims
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			idToken: op.IDToken(t, "nonce", jwt.MapClaims{
+				"sub":            "user",
+				"email":          "user@example.com",
+				"email_verified": true,
+				"given_name":     "Test",
+			}),
+			want: oidc.Claims{Subject: "user", Email: "user@example.com", EmailVerified: true, GivenName: "Test"},
+		},
+		{
+			name:    "email verified as string",
+			idToken: op.IDToken(t, "nonce", jwt.MapClaims{"sub": "user", "email_verified": "true"}),
+			want:    oidc.Claims{Subject: "user", EmailVerified: true},
+		},
+		{
+			name: "signed with another key",
+			idToken: oidctest.Sign(t, otherKey, jwt.MapClaims{
+				"iss":   op.Issuer,
+				"aud":   oidctest.ClientID,
+				"sub":   "user",
+				"iat":   time.Now().Unix(),
+				"exp":   time.Now().Add(time.Minute).Unix(),
+				"nonce": "nonce",
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "nonce of another login",
+			idToken: op.IDToken(t, "another nonce", jwt.MapClaims{"sub": "user"}),
+			wantErr: true,
+		},
+		{
+			name:    "another audience",
+			idToken: op.IDToken(t, "nonce", jwt.MapClaims{"sub": "user", "aud": "another-client"}),
+			wantErr: true,
+		},
+		{
+			name:    "several audiences without authorized party",
+			idToken: op.IDToken(t, "nonce", jwt.MapClaims{"sub": "user", "aud": []string{oidctest.ClientID, "another-client"}}),
+			wantErr: true,
+		},
+		{
+			name:    "another issuer",
+			idToken: op.IDToken(t, "nonce", jwt.MapClaims{"sub": "user", "iss": "https://attacker.example.com"}),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			idToken: op.IDToken(t, "nonce", jwt.MapClaims{"sub": "user", "exp": time.Now().Add(-time.Hour).Unix()}),
+			wantErr: true,
+		},
+		{
+			name:    "without expiry",
+			idToken: op.IDToken(t, "nonce", jwt.MapClaims{"sub": "user", "exp": nil}),
+			wantErr: true,
+		},
+		{
+			name:    "without subject",
+			idToken: op.IDToken(t, "nonce", nil),
+			wantErr: true,
+		},
+		{
+			name:    "unsigned",
+			idToken: unsigned(t, jwt.MapClaims{"iss": op.Issuer, "aud": oidctest.ClientID, "sub": "user", "nonce": "nonce"}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Verify(context.Background(), tt.idToken, "nonce")
+			if tt.wantErr {
+				if !errors.Is(err, oidc.ErrInvalidToken) {
+					t.Errorf("Verify() error = %v, want %v", err, oidc.ErrInvalidToken)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func unsigned(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
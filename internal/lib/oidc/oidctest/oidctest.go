@@ -0,0 +1,217 @@
+// Package oidctest runs a local OpenID Connect provider for tests of relying parties.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"sso/internal/lib/oidc"
+)
+
+const (
+	ClientID     = "sso"
+	ClientSecret = "client-secret"
+	// KeyID is the ID of the key the provider signs ID tokens with.
+	KeyID = "test-key"
+)
+
+// Provider serves discovery, the JWK set and the token endpoint. Users log in at it
+// with Authorize instead of a login page.
+type Provider struct {
+	*httptest.Server
+	// Issuer is the issuer announced by discovery and put into ID tokens, the server URL by default.
+	Issuer string
+
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// grant is an authorization code waiting to be exchanged.
+type grant struct {
+	codeChallenge string
+	nonce         string
+	claims        jwt.MapClaims
+}
+
+// NewProvider starts the provider, it's stopped when the test ends.
+func NewProvider(t testing.TB) *Provider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provider{
+		key:    key,
+		grants: make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	mux.HandleFunc("POST /token", p.token)
+
+	p.Server = httptest.NewServer(mux)
+	p.Issuer = p.URL
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// Config returns the config of the client registered at the provider.
+func (p *Provider) Config() oidc.Config {
+	return oidc.Config{
+		Issuer:       p.URL,
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURL:  "https://sso.example.com/federation/callback",
+	}
+}
+
+// Authorize logs the user with the claims in at the provider's login page the URL of AuthCodeURL
+// points to. It returns the state and the authorization code the provider sends back with the user.
+func (p *Provider) Authorize(t testing.TB, authURL string, claims jwt.MapClaims) (string, string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	q := u.Query()
+
+	code := rand.Text()
+
+	p.mu.Lock()
+	p.grants[code] = grant{
+		codeChallenge: q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		claims:        claims,
+	}
+	p.mu.Unlock()
+
+	return q.Get("state"), code
+}
+
+// IDToken returns an ID token for the client with the nonce, the claims are added
+// to the issuer, audience and times of a valid token or replace them.
+func (p *Provider) IDToken(t testing.TB, nonce string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := p.idToken(nonce, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func (p *Provider) idToken(nonce string, claims jwt.MapClaims) (string, error) {
+	now := time.Now()
+	all := jwt.MapClaims{
+		"iss":   p.Issuer,
+		"aud":   ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": nonce,
+	}
+	for k, v := range claims {
+		all[k] = v
+	}
+
+	return sign(p.key, all)
+}
+
+// Sign signs the claims with the key as the provider does, e.g. to forge tokens with another key.
+func Sign(t testing.TB, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := sign(key, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func sign(key *rsa.PrivateKey, claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = KeyID
+
+	return token.SignedString(key)
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.Issuer,
+		"authorization_endpoint": p.URL + "/authorize",
+		"token_endpoint":         p.URL + "/token",
+		"jwks_uri":               p.URL + "/jwks",
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	if clientID != ClientID || clientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+
+		return
+	}
+
+	code := r.PostFormValue("code")
+
+	p.mu.Lock()
+	g, ok := p.grants[code]
+	delete(p.grants, code)
+	p.mu.Unlock()
+
+	if !ok || oidc.CodeChallenge(r.PostFormValue("code_verifier")) != g.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+
+		return
+	}
+
+	idToken, err := p.idToken(g.nonce, g.claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,56 @@
+package phone
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone")
+
+var (
+	e164       = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	formatting = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+)
+
+// Normalizer converts phones as users type them to E.164.
+type Normalizer struct {
+	countryCode string
+	trunkPrefix string
+}
+
+// NewNormalizer returns a normalizer treating phones without the international prefix
+// as national numbers of the country with the given calling code, with the trunk prefix
+// dropped. Empty countryCode rejects national numbers.
+func NewNormalizer(countryCode string, trunkPrefix string) *Normalizer {
+	return &Normalizer{
+		countryCode: strings.TrimPrefix(countryCode, "+"),
+		trunkPrefix: trunkPrefix,
+	}
+}
+
+// Normalize returns the phone in E.164 format, e.g. "8 (999) 123-45-67" is +79991234567 in Russia.
+func (n *Normalizer) Normalize(phone string) (string, error) {
+	p := formatting.Replace(strings.TrimSpace(phone))
+
+	switch {
+	case strings.HasPrefix(p, "+"):
+	case strings.HasPrefix(p, "00"):
+		p = "+" + p[2:]
+	case n.countryCode != "":
+		p = "+" + n.countryCode + strings.TrimPrefix(p, n.trunkPrefix)
+	default:
+		return "", ErrInvalidPhone
+	}
+
+	if !Valid(p) {
+		return "", ErrInvalidPhone
+	}
+
+	return p, nil
+}
+
+// Valid tells if the phone is in E.164 format.
+func Valid(phone string) bool {
+	return e164.MatchString(phone)
+}
@@ -0,0 +1,149 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sso/internal/domain/models"
+	"strings"
+)
+
+var ErrInvalidRule = errors.New("invalid policy rule")
+
+// Attributes are values of attributes conditions refer to, e.g. "subject.roles".
+// Single-valued attributes hold one value.
+type Attributes map[string][]string
+
+// Evaluate decides the action on a resource of the type by the rules.
+// Deny rules take precedence over allow rules, and nothing is allowed unless a rule allows it.
+func Evaluate(rules []models.PolicyRule, attrs Attributes, action string, resourceType string) models.Decision {
+	var allow *models.PolicyRule
+
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Matches(action, resourceType) || !conditionsHold(rule.Conditions, attrs) {
+			continue
+		}
+
+		if rule.Effect == models.EffectDeny {
+			return decision(rule, false, "denied by rule")
+		}
+		if allow == nil {
+			allow = rule
+		}
+	}
+
+	if allow != nil {
+		return decision(allow, true, "allowed by rule")
+	}
+
+	return models.Decision{Reason: "no rule matched"}
+}
+
+func decision(rule *models.PolicyRule, allowed bool, reason string) models.Decision {
+	return models.Decision{
+		Allowed:  allowed,
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Effect:   rule.Effect,
+		Reason:   fmt.Sprintf("%s %q", reason, rule.Name),
+	}
+}
+
+func conditionsHold(conditions []models.PolicyCondition, attrs Attributes) bool {
+	for _, cond := range conditions {
+		if !conditionHolds(cond, attrs) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// conditionHolds compares the attribute with the condition operand. Missing attributes fail any condition,
+// so that rules never match because of data the caller forgot to pass.
+func conditionHolds(cond models.PolicyCondition, attrs Attributes) bool {
+	values, ok := attrs[cond.Attribute]
+	if !ok || len(values) == 0 {
+		return false
+	}
+
+	var operand []string
+	switch {
+	case cond.Value != "":
+		operand = []string{cond.Value}
+	case len(cond.Values) > 0:
+		operand = cond.Values
+	case cond.Ref != "":
+		if operand, ok = attrs[cond.Ref]; !ok || len(operand) == 0 {
+			return false
+		}
+	}
+
+	switch cond.Op {
+	case models.ConditionEq:
+		return len(values) == 1 && len(operand) == 1 && values[0] == operand[0]
+	case models.ConditionNe:
+		return len(values) == 1 && !slices.Contains(operand, values[0])
+	case models.ConditionIn:
+		return len(values) == 1 && slices.Contains(operand, values[0])
+	case models.ConditionContains:
+		return slices.ContainsFunc(operand, func(v string) bool { return slices.Contains(values, v) })
+	}
+
+	return false
+}
+
+// Validate checks that the rule can be evaluated.
+func Validate(rule models.PolicyRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRule)
+	}
+
+	if rule.Effect != models.EffectAllow && rule.Effect != models.EffectDeny {
+		return fmt.Errorf("%w: effect must be allow or deny", ErrInvalidRule)
+	}
+
+	if len(rule.Actions) == 0 || slices.Contains(rule.Actions, "") {
+		return fmt.Errorf("%w: actions must be non-empty", ErrInvalidRule)
+	}
+
+	for _, cond := range rule.Conditions {
+		if !validAttribute(cond.Attribute) {
+			return fmt.Errorf("%w: unknown attribute %q", ErrInvalidRule, cond.Attribute)
+		}
+
+		if !slices.Contains([]string{models.ConditionEq, models.ConditionNe, models.ConditionIn, models.ConditionContains}, cond.Op) {
+			return fmt.Errorf("%w: unknown op %q", ErrInvalidRule, cond.Op)
+		}
+
+		if cond.Value == "" && len(cond.Values) == 0 && cond.Ref == "" {
+			return fmt.Errorf("%w: condition on %q has nothing to compare with", ErrInvalidRule, cond.Attribute)
+		}
+
+		if cond.Ref != "" && !validAttribute(cond.Ref) {
+			return fmt.Errorf("%w: unknown attribute %q", ErrInvalidRule, cond.Ref)
+		}
+	}
+
+	return nil
+}
+
+var subjectAttributes = []string{
+	"subject.id",
+	"subject.email",
+	"subject.roles",
+	"subject.groups",
+	"subject.org_id",
+	"subject.org_role",
+}
+
+func validAttribute(attr string) bool {
+	if attr == "action" || slices.Contains(subjectAttributes, attr) {
+		return true
+	}
+
+	name, ok := strings.CutPrefix(attr, "resource.")
+
+	return ok && name != ""
+}
@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/phone"
+	"time"
+)
+
+var ErrInvalidProfile = errors.New("invalid profile")
+
+const (
+	BirthDateLayout = "2006-01-02"
+	maxNameLen      = 100
+)
+
+var minBirthDate = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Validate checks non-empty profile fields of the user, empty fields are left unchanged by updates.
+func Validate(u models.User) error {
+	if u.Title != "" && !slices.Contains(models.Titles, u.Title) {
+		return fmt.Errorf("%w: title must be one of %v", ErrInvalidProfile, models.Titles)
+	}
+
+	if u.BirthDate != "" {
+		date, err := time.Parse(BirthDateLayout, u.BirthDate)
+		if err != nil {
+			return fmt.Errorf("%w: birth_date must be formatted as YYYY-MM-DD", ErrInvalidProfile)
+		}
+		if date.Before(minBirthDate) || date.After(time.Now()) {
+			return fmt.Errorf("%w: birth_date is out of range", ErrInvalidProfile)
+		}
+	}
+
+	if len(u.Name) > maxNameLen || len(u.LastName) > maxNameLen {
+		return fmt.Errorf("%w: names must be at most %d bytes", ErrInvalidProfile, maxNameLen)
+	}
+
+	if u.Phone != "" && !phone.Valid(u.Phone) {
+		return fmt.Errorf("%w: phone must be in E.164 format, e.g. +14155552671", ErrInvalidProfile)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package randtoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+const tokenBytes = 32
+
+// Generate returns a random URL-safe token for links sent by email.
+func Generate() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns SHA-256 of the token to be stored instead of it. Tokens are random, so no slow hash is needed.
+func Hash(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+
+	return sum[:]
+}
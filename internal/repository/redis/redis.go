@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sso/internal/config"
 	"sso/internal/domain/models"
+	"sso/internal/repository"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -32,38 +33,122 @@ func New(Redis config.RedisConfig) (*Repository, error) {
 	}, nil
 }
 
-func (s *Repository) SaveCode(ctx context.Context, code string, uid int64) error {
+func codeKey(purpose models.CodePurpose, uid int64) string {
+	return fmt.Sprintf("code:%s:%d", purpose, uid)
+}
+
+func cooldownKey(purpose models.CodePurpose, uid int64) string {
+	return fmt.Sprintf("code:%s:%d:cooldown", purpose, uid)
+}
+
+// SaveCode stores the code digest for the user and purpose, replacing the previous one
+// and resetting its attempts.
+func (s *Repository) SaveCode(ctx context.Context, code models.Code) error {
 	const op = "repository.redis.SaveCode"
 
-	//id, err := s.db.Incr(ctx, "code:id").Result()
-	//if err != nil {
-	//	return fmt.Errorf("%w: %s", err, op)
-	//}
+	key := codeKey(code.Purpose, code.UserID)
 
-	key := fmt.Sprintf("code:%d", uid)
+	_, err := s.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "digest", code.Digest, "attempts", 0)
+		pipe.Expire(ctx, key, s.verCodeTTL)
 
-	if err := s.db.Set(ctx, key, code, s.verCodeTTL).Err(); err != nil {
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("%w: %s", err, op)
 	}
 
 	return nil
 }
 
-func (s *Repository) Code(ctx context.Context, id int64) (models.Code, error) {
-	const op = "repository.redis.Code"
+// useCodeScript counts the attempt, the count doesn't recreate the code if it has expired meanwhile.
+// It returns one of the useCode results.
+var useCodeScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+local max = tonumber(ARGV[2])
+if max > 0 and attempts > max then
+	redis.call("DEL", KEYS[1])
+	return 3
+end
+if redis.call("HGET", KEYS[1], "digest") == ARGV[1] then
+	redis.call("DEL", KEYS[1])
+	return 1
+end
+return 2
+`)
+
+const (
+	useCodeNotFound  = 0
+	useCodeMatched   = 1
+	useCodeMismatch  = 2
+	useCodeExhausted = 3
+)
 
-	key := fmt.Sprintf("code:%d", id)
+// UseCode counts an attempt to use the code and deletes the code if the digest matches
+// or the attempt is over maxAttempts, zero for no limit.
+func (s *Repository) UseCode(
+	ctx context.Context,
+	purpose models.CodePurpose,
+	uid int64,
+	digest string,
+	maxAttempts int,
+) error {
+	const op = "repository.redis.UseCode"
+
+	res, err := useCodeScript.Run(ctx, s.db, []string{codeKey(purpose, uid)}, digest, maxAttempts).Int()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, op)
+	}
 
-	code, err := s.db.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return models.Code{}, fmt.Errorf("%w: %s", err, op)
+	switch res {
+	case useCodeMatched:
+		return nil
+	case useCodeNotFound:
+		return fmt.Errorf("%w: %s", repository.ErrCodeNotFound, op)
+	case useCodeMismatch:
+		return fmt.Errorf("%w: %s", repository.ErrCodeMismatch, op)
+	case useCodeExhausted:
+		return fmt.Errorf("%w: %s", repository.ErrCodeExhausted, op)
+	default:
+		return fmt.Errorf("unexpected result %d: %s", res, op)
+	}
+}
+
+func (s *Repository) DeleteCode(ctx context.Context, purpose models.CodePurpose, uid int64) error {
+	const op = "repository.redis.DeleteCode"
+
+	if err := s.db.Del(ctx, codeKey(purpose, uid)).Err(); err != nil {
+		return fmt.Errorf("%w: %s", err, op)
 	}
+
+	return nil
+}
+
+func (s *Repository) CodeCooldown(ctx context.Context, purpose models.CodePurpose, uid int64) (bool, error) {
+	const op = "repository.redis.CodeCooldown"
+
+	n, err := s.db.Exists(ctx, cooldownKey(purpose, uid)).Result()
 	if err != nil {
-		return models.Code{}, err
+		return false, fmt.Errorf("%w: %s", err, op)
 	}
 
-	return models.Code{
-		UserID: id,
-		Code:   code,
-	}, nil
+	return n > 0, nil
+}
+
+func (s *Repository) SetCodeCooldown(ctx context.Context, purpose models.CodePurpose, uid int64, cooldown time.Duration) error {
+	const op = "repository.redis.SetCodeCooldown"
+
+	if cooldown <= 0 {
+		return nil
+	}
+
+	if err := s.db.Set(ctx, cooldownKey(purpose, uid), 1, cooldown).Err(); err != nil {
+		return fmt.Errorf("%w: %s", err, op)
+	}
+
+	return nil
 }
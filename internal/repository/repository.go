@@ -4,16 +4,61 @@ import (
 	"context"
 	"errors"
 	"sso/internal/domain/models"
+	"time"
 )
 
 var (
-	ErrUserExists   = errors.New("user already exists")
-	ErrUserNotFound = errors.New("user not found")
-	ErrCodeNotFound = errors.New("code not found")
-	ErrAppNotFound  = errors.New("app not found")
+	ErrUserExists     = errors.New("user already exists")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrCodeNotFound   = errors.New("code not found")
+	ErrCodeMismatch   = errors.New("code doesn't match")
+	ErrCodeExhausted  = errors.New("code ran out of attempts")
+	ErrAppNotFound    = errors.New("app not found")
+	ErrAppExists      = errors.New("app already exists")
+	ErrRoleExists     = errors.New("role already exists")
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrPermExists     = errors.New("permission already exists")
+	ErrOrgNotFound    = errors.New("organization not found")
+	ErrOrgExists      = errors.New("organization already exists")
+	ErrNotOrgMember   = errors.New("user is not a member of the organization")
+	ErrGroupExists    = errors.New("group already exists")
+	ErrGroupNotFound  = errors.New("group not found")
+	ErrGroupCycle     = errors.New("group would contain itself")
+	ErrNotGroupMember = errors.New("not a member of the group")
+	ErrRuleExists     = errors.New("policy rule already exists")
+	ErrRuleNotFound   = errors.New("policy rule not found")
+	ErrAPIKeyExists   = errors.New("api key already exists")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	ErrServiceAccountExists   = errors.New("service account already exists")
+	ErrServiceAccountNotFound = errors.New("service account not found")
+
+	ErrInvitationNotFound = errors.New("invitation not found")
+
+	ErrEmailChangeNotFound = errors.New("email change not found")
+	ErrPhoneChangeNotFound = errors.New("phone change not found")
+
+	ErrLegalDocumentExists      = errors.New("legal document version already exists")
+	ErrLegalDocumentNotFound    = errors.New("legal document not found")
+	ErrConsentChallengeNotFound = errors.New("consent challenge not found")
+
+	ErrGrantNotFound = errors.New("grant not found")
+
+	ErrInitialAccessTokenNotFound = errors.New("initial access token not found")
+
+	ErrIdentityNotFound       = errors.New("identity not found")
+	ErrFederatedLoginNotFound = errors.New("federated login not found")
 )
 
 type Redis interface {
-	SaveCode(ctx context.Context, code string, uid int64) error
-	Code(ctx context.Context, uid int64) (models.Code, error)
+	SaveCode(ctx context.Context, code models.Code) error
+	// UseCode counts an attempt to use the code and deletes the code if the digest matches
+	// or the attempt is over maxAttempts, zero for no limit. Both are done atomically,
+	// so that a code is used at most once.
+	UseCode(ctx context.Context, purpose models.CodePurpose, uid int64, digest string, maxAttempts int) error
+	DeleteCode(ctx context.Context, purpose models.CodePurpose, uid int64) error
+	// CodeCooldown tells whether resend cooldown for the code is running.
+	CodeCooldown(ctx context.Context, purpose models.CodePurpose, uid int64) (bool, error)
+	// SetCodeCooldown starts resend cooldown for the code.
+	SetCodeCooldown(ctx context.Context, purpose models.CodePurpose, uid int64, cooldown time.Duration) error
 }
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const apiKeyColumns = "id, user_id, service_account_id, app_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at"
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var (
+		key              models.APIKey
+		userID           sql.NullInt64
+		serviceAccountID sql.NullInt64
+		scopes           string
+		lastUsedAt       sql.NullTime
+	)
+
+	err := row.Scan(
+		&key.ID,
+		&userID,
+		&serviceAccountID,
+		&key.AppID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&scopes,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&lastUsedAt,
+	)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return models.APIKey{}, err
+	}
+	key.UserID = userID.Int64
+	key.ServiceAccountID = serviceAccountID.Int64
+	key.LastUsedAt = lastUsedAt.Time
+
+	return key, nil
+}
+
+func (s *Repository) CreateAPIKey(ctx context.Context, key models.APIKey) (int64, error) {
+	const op = "repository.sqlite.CreateAPIKey"
+
+	scopes, err := json.Marshal(nonNil(key.Scopes))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID := sql.NullInt64{Int64: key.UserID, Valid: key.UserID != 0}
+	serviceAccountID := sql.NullInt64{Int64: key.ServiceAccountID, Valid: key.ServiceAccountID != 0}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO api_keys (user_id, service_account_id, app_id, name, prefix, key_hash, scopes, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		userID, serviceAccountID, key.AppID, key.Name, key.Prefix, key.KeyHash, string(scopes), key.CreatedAt.UTC(), key.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrAPIKeyExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) APIKeyByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	const op = "repository.sqlite.APIKeyByPrefix"
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = ?", prefix)
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, fmt.Errorf("%s: %w", op, repository.ErrAPIKeyNotFound)
+		}
+
+		return models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (s *Repository) APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	const op = "repository.sqlite.APIKeys"
+
+	keys, err := s.queryAPIKeys(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE user_id = ? ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (s *Repository) ServiceAccountAPIKeys(ctx context.Context, serviceAccountID int64) ([]models.APIKey, error) {
+	const op = "repository.sqlite.ServiceAccountAPIKeys"
+
+	keys, err := s.queryAPIKeys(
+		ctx,
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE service_account_id = ? ORDER BY id",
+		serviceAccountID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (s *Repository) queryAPIKeys(ctx context.Context, query string, args ...any) ([]models.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// DeleteAPIKey deletes the key if it belongs to the user.
+func (s *Repository) DeleteAPIKey(ctx context.Context, id int64, userID int64) error {
+	const op = "repository.sqlite.DeleteAPIKey"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM api_keys WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrAPIKeyNotFound)
+}
+
+// DeleteServiceAccountAPIKey deletes the key if it belongs to the service account.
+func (s *Repository) DeleteServiceAccountAPIKey(ctx context.Context, id int64, serviceAccountID int64) error {
+	const op = "repository.sqlite.DeleteServiceAccountAPIKey"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"DELETE FROM api_keys WHERE id = ? AND service_account_id = ?",
+		id,
+		serviceAccountID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrAPIKeyNotFound)
+}
+
+func (s *Repository) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	const op = "repository.sqlite.TouchAPIKey"
+
+	if _, err := s.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = ? WHERE id = ?", usedAt.UTC(), id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const appColumns = `id, name, secret, redirect_uris, grant_types, owner_id, disabled, org_id, attributes_schema, claim_attributes,
+	logo_uri, contacts, registration_token_hash, registered_at`
+
+func scanApp(row rowScanner) (models.App, error) {
+	var (
+		app             models.App
+		redirectURIs    string
+		grantTypes      string
+		ownerID         sql.NullInt64
+		orgID           sql.NullInt64
+		claimAttributes string
+		contacts        string
+		registeredAt    sql.NullTime
+	)
+
+	if err := row.Scan(
+		&app.ID, &app.Name, &app.SecretHash, &redirectURIs, &grantTypes, &ownerID, &app.Disabled, &orgID,
+		&app.AttributesSchema, &claimAttributes, &app.LogoURI, &contacts, &app.RegistrationTokenHash, &registeredAt,
+	); err != nil {
+		return models.App{}, err
+	}
+
+	if err := json.Unmarshal([]byte(redirectURIs), &app.RedirectURIs); err != nil {
+		return models.App{}, err
+	}
+	if err := json.Unmarshal([]byte(grantTypes), &app.GrantTypes); err != nil {
+		return models.App{}, err
+	}
+	if err := json.Unmarshal([]byte(claimAttributes), &app.ClaimAttributes); err != nil {
+		return models.App{}, err
+	}
+	if err := json.Unmarshal([]byte(contacts), &app.Contacts); err != nil {
+		return models.App{}, err
+	}
+	app.OwnerID = ownerID.Int64
+	app.OrgID = orgID.Int64
+	app.RegisteredAt = registeredAt.Time
+
+	return app, nil
+}
+
+// appArgs returns values of the app columns except id in the order of appColumns.
+func appArgs(app models.App) ([]any, error) {
+	redirectURIs, err := json.Marshal(nonNil(app.RedirectURIs))
+	if err != nil {
+		return nil, err
+	}
+
+	grantTypes, err := json.Marshal(nonNil(app.GrantTypes))
+	if err != nil {
+		return nil, err
+	}
+
+	claimAttributes, err := json.Marshal(nonNil(app.ClaimAttributes))
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := json.Marshal(nonNil(app.Contacts))
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID := sql.NullInt64{Int64: app.OwnerID, Valid: app.OwnerID != 0}
+	orgID := sql.NullInt64{Int64: app.OrgID, Valid: app.OrgID != 0}
+	registeredAt := sql.NullTime{Time: app.RegisteredAt.UTC(), Valid: !app.RegisteredAt.IsZero()}
+
+	return []any{
+		app.Name, app.SecretHash, string(redirectURIs), string(grantTypes), ownerID, app.Disabled, orgID,
+		app.AttributesSchema, string(claimAttributes), app.LogoURI, string(contacts), app.RegistrationTokenHash, registeredAt,
+	}, nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
+
+// CreateApp saves the app along with its admin role, so that app admins can be appointed right away.
+func (s *Repository) CreateApp(ctx context.Context, app models.App) (int, error) {
+	const op = "repository.sqlite.CreateApp"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	id, err := createApp(ctx, tx, app)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func createApp(ctx context.Context, tx *sql.Tx, app models.App) (int, error) {
+	args, err := appArgs(app)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO apps (name, secret, redirect_uris, grant_types, owner_id, disabled, org_id, attributes_schema, claim_attributes,
+		logo_uri, contacts, registration_token_hash, registered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		args...,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, repository.ErrAppExists
+		}
+
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, query := range []string{
+		"INSERT INTO roles (app_id, name) VALUES (?1, ?2)",
+		"INSERT INTO permissions (app_id, name) VALUES (?1, ?2)",
+		`INSERT INTO role_permissions (role_id, permission_id)
+		SELECT r.id, p.id FROM roles r JOIN permissions p ON p.app_id = r.app_id AND p.name = ?2
+		WHERE r.app_id = ?1 AND r.name = ?2`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, id, models.PermissionAdmin); err != nil {
+			return 0, err
+		}
+	}
+
+	return int(id), nil
+}
+
+func (s *Repository) UpdateApp(ctx context.Context, app models.App) error {
+	const op = "repository.sqlite.UpdateApp"
+
+	args, err := appArgs(app)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE apps SET name = ?, secret = ?, redirect_uris = ?, grant_types = ?, owner_id = ?, disabled = ?, org_id = ?,
+		attributes_schema = ?, claim_attributes = ?, logo_uri = ?, contacts = ?, registration_token_hash = ?, registered_at = ?
+		WHERE id = ?`,
+		append(args, app.ID)...,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, repository.ErrAppExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrAppNotFound)
+}
+
+func (s *Repository) App(ctx context.Context, id int) (models.App, error) {
+	const op = "repository.sqlite.App"
+
+	stmt, err := s.db.Prepare("SELECT " + appColumns + " FROM apps WHERE id = ?")
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, id)
+
+	app, err := scanApp(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.App{}, fmt.Errorf("%s: %w", op, repository.ErrAppNotFound)
+		}
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return app, nil
+}
+
+func (s *Repository) Apps(ctx context.Context) ([]models.App, error) {
+	const op = "repository.sqlite.Apps"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+appColumns+" FROM apps ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var apps []models.App
+	for rows.Next() {
+		app, err := scanApp(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		apps = append(apps, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+// DeleteApp deletes the app, its roles and permissions are deleted by cascade.
+func (s *Repository) DeleteApp(ctx context.Context, id int) error {
+	const op = "repository.sqlite.DeleteApp"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM apps WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrAppNotFound)
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sso/internal/domain/models"
+	"time"
+)
+
+// UserAttributes returns attributes of the user in the app, empty if the user has none.
+func (s *Repository) UserAttributes(ctx context.Context, userID int64, appID int) (models.Attributes, error) {
+	const op = "repository.sqlite.UserAttributes"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		"SELECT key, value FROM user_attributes WHERE user_id = ? AND app_id = ?",
+		userID, appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	attrs := models.Attributes{}
+	for rows.Next() {
+		var (
+			key   string
+			value string
+		)
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		attrs[key] = json.RawMessage(value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attrs, nil
+}
+
+// UpdateUserAttributes saves the set attributes of the user in the app and deletes the removed ones,
+// other attributes are kept.
+func (s *Repository) UpdateUserAttributes(
+	ctx context.Context,
+	userID int64,
+	appID int,
+	set models.Attributes,
+	remove []string,
+	updatedAt time.Time,
+) error {
+	const op = "repository.sqlite.UpdateUserAttributes"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for key, value := range set {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO user_attributes (user_id, app_id, key, value, updated_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (user_id, app_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+			userID, appID, key, string(value), updatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, key := range remove {
+		_, err := tx.ExecContext(
+			ctx,
+			"DELETE FROM user_attributes WHERE user_id = ? AND app_id = ? AND key = ?",
+			userID, appID, key,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sso/internal/domain/models"
+)
+
+func (s *Repository) SaveAuditEntry(ctx context.Context, entry models.AuditEntry) (int64, error) {
+	const op = "repository.sqlite.SaveAuditEntry"
+
+	targetUserID := sql.NullInt64{Int64: entry.TargetUserID, Valid: entry.TargetUserID != 0}
+	appID := sql.NullInt64{Int64: int64(entry.AppID), Valid: entry.AppID != 0}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO audit_log (actor_id, action, target_user_id, app_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.ActorID, entry.Action, targetUserID, appID, entry.Details, entry.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// AuditEntries returns the latest entries the user is the actor or the target of, newest first.
+// Zero userID returns entries of all users.
+func (s *Repository) AuditEntries(ctx context.Context, userID int64, limit int) ([]models.AuditEntry, error) {
+	const op = "repository.sqlite.AuditEntries"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, actor_id, action, target_user_id, app_id, details, created_at FROM audit_log
+		WHERE ? = 0 OR actor_id = ? OR target_user_id = ?
+		ORDER BY id DESC LIMIT ?`,
+		userID, userID, userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var (
+			entry        models.AuditEntry
+			targetUserID sql.NullInt64
+			appID        sql.NullInt64
+		)
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.Action,
+			&targetUserID,
+			&appID,
+			&entry.Details,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		entry.TargetUserID = targetUserID.Int64
+		entry.AppID = int(appID.Int64)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+)
+
+func (s *Repository) CreateInitialAccessToken(ctx context.Context, token models.InitialAccessToken) (int64, error) {
+	const op = "repository.sqlite.CreateInitialAccessToken"
+
+	createdBy := sql.NullInt64{Int64: token.CreatedBy, Valid: token.CreatedBy != 0}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO initial_access_tokens (token_hash, description, created_by, created_at, expires_at) VALUES (?, ?, ?, ?, ?)",
+		token.TokenHash, token.Description, createdBy, token.CreatedAt.UTC(), token.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) InitialAccessToken(ctx context.Context, tokenHash []byte) (models.InitialAccessToken, error) {
+	const op = "repository.sqlite.InitialAccessToken"
+
+	var (
+		token     = models.InitialAccessToken{TokenHash: tokenHash}
+		createdBy sql.NullInt64
+		usedAt    sql.NullTime
+		appID     sql.NullInt64
+	)
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, description, created_by, created_at, expires_at, used_at, app_id
+		FROM initial_access_tokens WHERE token_hash = ?`,
+		tokenHash,
+	).Scan(&token.ID, &token.Description, &createdBy, &token.CreatedAt, &token.ExpiresAt, &usedAt, &appID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.InitialAccessToken{}, fmt.Errorf("%s: %w", op, repository.ErrInitialAccessTokenNotFound)
+		}
+
+		return models.InitialAccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+	token.CreatedBy = createdBy.Int64
+	token.UsedAt = usedAt.Time
+	token.AppID = int(appID.Int64)
+
+	return token, nil
+}
+
+// RegisterApp saves the app registered with the initial access token and marks the token used,
+// unless it was used or expired at now meanwhile.
+func (s *Repository) RegisterApp(ctx context.Context, app models.App, tokenID int64, now time.Time) (int, error) {
+	const op = "repository.sqlite.RegisterApp"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	id, err := createApp(ctx, tx, app)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE initial_access_tokens SET used_at = ?, app_id = ? WHERE id = ? AND used_at IS NULL AND expires_at > ?",
+		now.UTC(), id, tokenID, now.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrInitialAccessTokenNotFound); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,282 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const legalDocumentColumns = "id, type, version, url, mandatory, published_at, created_at"
+
+func scanLegalDocument(row rowScanner) (models.LegalDocument, error) {
+	var doc models.LegalDocument
+
+	err := row.Scan(&doc.ID, &doc.Type, &doc.Version, &doc.URL, &doc.Mandatory, &doc.PublishedAt, &doc.CreatedAt)
+	if err != nil {
+		return models.LegalDocument{}, err
+	}
+
+	return doc, nil
+}
+
+func (s *Repository) CreateLegalDocument(ctx context.Context, doc models.LegalDocument) (int64, error) {
+	const op = "repository.sqlite.CreateLegalDocument"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO legal_documents (type, version, url, mandatory, published_at, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		doc.Type, doc.Version, doc.URL, doc.Mandatory, doc.PublishedAt.UTC(), doc.CreatedAt.UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrLegalDocumentExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) LegalDocument(ctx context.Context, id int64) (models.LegalDocument, error) {
+	const op = "repository.sqlite.LegalDocument"
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+legalDocumentColumns+" FROM legal_documents WHERE id = ?", id)
+
+	doc, err := scanLegalDocument(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.LegalDocument{}, fmt.Errorf("%s: %w", op, repository.ErrLegalDocumentNotFound)
+		}
+
+		return models.LegalDocument{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return doc, nil
+}
+
+// LegalDocuments returns all versions of legal documents, the latest published first.
+func (s *Repository) LegalDocuments(ctx context.Context) ([]models.LegalDocument, error) {
+	const op = "repository.sqlite.LegalDocuments"
+
+	docs, err := s.queryLegalDocuments(
+		ctx,
+		"SELECT "+legalDocumentColumns+" FROM legal_documents ORDER BY published_at DESC, id DESC",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return docs, nil
+}
+
+// PendingConsents returns the latest mandatory version of every legal document published at now,
+// unless the user accepted it or a later version.
+func (s *Repository) PendingConsents(ctx context.Context, userID int64, now time.Time) ([]models.LegalDocument, error) {
+	const op = "repository.sqlite.PendingConsents"
+
+	docs, err := s.queryLegalDocuments(
+		ctx,
+		`SELECT `+legalDocumentColumns+` FROM legal_documents d
+		WHERE d.mandatory AND d.published_at <= ?1
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_documents l
+				WHERE l.type = d.type AND l.mandatory AND l.published_at <= ?1
+					AND (l.published_at > d.published_at OR (l.published_at = d.published_at AND l.id > d.id))
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM consents c JOIN legal_documents a ON a.id = c.document_id
+				WHERE c.user_id = ?2 AND a.type = d.type AND (a.id = d.id OR a.published_at > d.published_at)
+			)
+		ORDER BY d.type`,
+		now.UTC(), userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return docs, nil
+}
+
+func (s *Repository) queryLegalDocuments(ctx context.Context, query string, args ...any) ([]models.LegalDocument, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []models.LegalDocument
+	for rows.Next() {
+		doc, err := scanLegalDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// SaveConsents records the user accepting the documents. Documents accepted before keep
+// the time and address of the first acceptance.
+func (s *Repository) SaveConsents(ctx context.Context, userID int64, documentIDs []int64, acceptedAt time.Time, ip string) error {
+	const op = "repository.sqlite.SaveConsents"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range documentIDs {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO consents (user_id, document_id, accepted_at, ip) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING",
+			userID, id, acceptedAt.UTC(), ip,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Consents returns legal documents the user accepted, the latest accepted first.
+func (s *Repository) Consents(ctx context.Context, userID int64) ([]models.Consent, error) {
+	const op = "repository.sqlite.Consents"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT d.id, d.type, d.version, d.url, d.mandatory, d.published_at, d.created_at, c.accepted_at, c.ip
+		FROM consents c JOIN legal_documents d ON d.id = c.document_id
+		WHERE c.user_id = ?
+		ORDER BY c.accepted_at DESC, d.id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var consents []models.Consent
+	for rows.Next() {
+		consent := models.Consent{UserID: userID}
+
+		err := rows.Scan(
+			&consent.Document.ID,
+			&consent.Document.Type,
+			&consent.Document.Version,
+			&consent.Document.URL,
+			&consent.Document.Mandatory,
+			&consent.Document.PublishedAt,
+			&consent.Document.CreatedAt,
+			&consent.AcceptedAt,
+			&consent.IP,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		consents = append(consents, consent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return consents, nil
+}
+
+// SaveConsentChallenge saves the challenge and deletes expired challenges of the user.
+func (s *Repository) SaveConsentChallenge(ctx context.Context, challenge models.ConsentChallenge, now time.Time) error {
+	const op = "repository.sqlite.SaveConsentChallenge"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM consent_challenges WHERE user_id = ? AND expires_at <= ?",
+		challenge.UserID, now.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	orgID := sql.NullInt64{Int64: challenge.OrgID, Valid: challenge.OrgID != 0}
+
+	scopes, err := json.Marshal(nonNil(challenge.Scopes))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO consent_challenges (token_hash, user_id, app_id, org_id, grant_type, scopes, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		challenge.TokenHash, challenge.UserID, challenge.AppID, orgID, challenge.GrantType, scopes, challenge.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// TakeConsentChallenge deletes the challenge unexpired at now and returns it,
+// so that every challenge is used once.
+func (s *Repository) TakeConsentChallenge(ctx context.Context, tokenHash []byte, now time.Time) (models.ConsentChallenge, error) {
+	const op = "repository.sqlite.TakeConsentChallenge"
+
+	challenge := models.ConsentChallenge{TokenHash: tokenHash}
+	var (
+		orgID  sql.NullInt64
+		scopes string
+	)
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`DELETE FROM consent_challenges WHERE token_hash = ? AND expires_at > ?
+		RETURNING user_id, app_id, org_id, grant_type, scopes, expires_at`,
+		tokenHash, now.UTC(),
+	).Scan(&challenge.UserID, &challenge.AppID, &orgID, &challenge.GrantType, &scopes, &challenge.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ConsentChallenge{}, fmt.Errorf("%s: %w", op, repository.ErrConsentChallengeNotFound)
+		}
+
+		return models.ConsentChallenge{}, fmt.Errorf("%s: %w", op, err)
+	}
+	challenge.OrgID = orgID.Int64
+
+	if err := json.Unmarshal([]byte(scopes), &challenge.Scopes); err != nil {
+		return models.ConsentChallenge{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+)
+
+// ScheduleUserDeletion marks the user deleted, to be purged after deleteAfter, and revokes the user's tokens.
+func (s *Repository) ScheduleUserDeletion(ctx context.Context, userID int64, deleteAfter time.Time, requestedAt time.Time) error {
+	const op = "repository.sqlite.ScheduleUserDeletion"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE users SET status = ?, status_reason = ?, status_changed_at = ?, delete_after = ?, tokens_valid_after = ?
+		WHERE id = ?`,
+		models.UserStatusDeleted, "deletion requested by the user", requestedAt.UTC(), deleteAfter.UTC(),
+		// token iat has second precision, tokens issued later in the same second must stay valid
+		requestedAt.UTC().Truncate(time.Second),
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrUserNotFound)
+}
+
+// CancelUserDeletion unmarks the user marked to be purged and makes the user active again.
+func (s *Repository) CancelUserDeletion(ctx context.Context, userID int64, cancelledAt time.Time) error {
+	const op = "repository.sqlite.CancelUserDeletion"
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE users SET status = ?, status_reason = '', status_changed_at = ?, delete_after = NULL
+		WHERE id = ? AND delete_after IS NOT NULL`,
+		models.UserStatusActive, cancelledAt.UTC(), userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UsersDueForDeletion returns IDs of users whose deletion grace period is over at now,
+// the longest overdue first.
+func (s *Repository) UsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	const op = "repository.sqlite.UsersDueForDeletion"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		"SELECT id FROM users WHERE delete_after IS NOT NULL AND delete_after <= ? ORDER BY delete_after LIMIT ?",
+		now.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+// PurgeUser deletes the user whose deletion grace period is over at now, rows referencing the user
+// are deleted by cascade. Invitations sent to the user's email are deleted too.
+// It fails with ErrUserNotFound if the user is gone or cancelled the deletion meanwhile.
+func (s *Repository) PurgeUser(ctx context.Context, userID int64, now time.Time) error {
+	const op = "repository.sqlite.PurgeUser"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	email, err := dueUserEmail(ctx, tx, userID, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM invitations WHERE email = ?", email); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = ?", userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AnonymizeUser erases personal data of the user whose deletion grace period is over at now,
+// but keeps the deleted user row, so that organizations, invitations and audit entries
+// still refer to an existing user. Credentials, memberships and history of the user are deleted.
+// It fails with ErrUserNotFound if the user is gone or cancelled the deletion meanwhile.
+func (s *Repository) AnonymizeUser(ctx context.Context, userID int64, now time.Time) error {
+	const op = "repository.sqlite.AnonymizeUser"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	email, err := dueUserEmail(ctx, tx, userID, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM invitations WHERE email = ?", email); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE users SET title = '', birth_date = '', name = '', last_name = '',
+			email = 'deleted-' || id || '@invalid', email_verified = FALSE, pass_hash = X'', phone = '',
+			status = ?, status_reason = '', status_changed_at = ?, delete_after = NULL
+		WHERE id = ?`,
+		models.UserStatusDeleted, now.UTC(), userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, table := range []string{
+		"user_roles",
+		"group_users",
+		"organization_members",
+		"api_keys",
+		"login_attempts",
+		"profile_changes",
+		"email_changes",
+		"phone_changes",
+		"user_attributes",
+		"consents",
+		"consent_challenges",
+		"oauth_grants",
+		"app_token_revocations",
+		"user_identities",
+		"federated_logins",
+	} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE user_id = ?", userID); err != nil {
+			return fmt.Errorf("%s: %s: %w", op, table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// dueUserEmail returns the email of the user due for deletion at now,
+// or ErrUserNotFound if the user isn't due for deletion.
+func dueUserEmail(ctx context.Context, tx *sql.Tx, userID int64, now time.Time) (string, error) {
+	var email string
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT email FROM users WHERE id = ? AND delete_after IS NOT NULL AND delete_after <= ?",
+		userID, now.UTC(),
+	).Scan(&email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", repository.ErrUserNotFound
+		}
+
+		return "", err
+	}
+
+	return email, nil
+}
@@ -0,0 +1,224 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const emailChangeColumns = `id, user_id, old_email, new_email, revert_token_hash, created_at,
+	confirmed_at, revert_expires_at, reverted_at`
+
+func scanEmailChange(row rowScanner) (models.EmailChange, error) {
+	var (
+		change          models.EmailChange
+		confirmedAt     sql.NullTime
+		revertExpiresAt sql.NullTime
+		revertedAt      sql.NullTime
+	)
+
+	err := row.Scan(
+		&change.ID,
+		&change.UserID,
+		&change.OldEmail,
+		&change.NewEmail,
+		&change.RevertTokenHash,
+		&change.CreatedAt,
+		&confirmedAt,
+		&revertExpiresAt,
+		&revertedAt,
+	)
+	if err != nil {
+		return models.EmailChange{}, err
+	}
+
+	change.ConfirmedAt = confirmedAt.Time
+	change.RevertExpiresAt = revertExpiresAt.Time
+	change.RevertedAt = revertedAt.Time
+
+	return change, nil
+}
+
+func (s *Repository) CreateEmailChange(ctx context.Context, change models.EmailChange) (int64, error) {
+	const op = "repository.sqlite.CreateEmailChange"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO email_changes (user_id, old_email, new_email, created_at) VALUES (?, ?, ?, ?)",
+		change.UserID, change.OldEmail, change.NewEmail, change.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// PendingEmailChange returns the latest unconfirmed email change of the user.
+func (s *Repository) PendingEmailChange(ctx context.Context, userID int64) (models.EmailChange, error) {
+	const op = "repository.sqlite.PendingEmailChange"
+
+	change, err := s.queryEmailChange(
+		ctx,
+		"SELECT "+emailChangeColumns+" FROM email_changes WHERE user_id = ? AND confirmed_at IS NULL ORDER BY id DESC LIMIT 1",
+		userID,
+	)
+	if err != nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return change, nil
+}
+
+func (s *Repository) EmailChangeByRevertTokenHash(ctx context.Context, tokenHash []byte) (models.EmailChange, error) {
+	const op = "repository.sqlite.EmailChangeByRevertTokenHash"
+
+	change, err := s.queryEmailChange(
+		ctx,
+		"SELECT "+emailChangeColumns+" FROM email_changes WHERE revert_token_hash = ?",
+		tokenHash,
+	)
+	if err != nil {
+		return models.EmailChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return change, nil
+}
+
+func (s *Repository) queryEmailChange(ctx context.Context, query string, args ...any) (models.EmailChange, error) {
+	change, err := scanEmailChange(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EmailChange{}, repository.ErrEmailChangeNotFound
+		}
+
+		return models.EmailChange{}, err
+	}
+
+	return change, nil
+}
+
+// ConfirmEmailChange replaces the user's email with the new one, revokes the user's tokens
+// and records the change, all at once. It fails with ErrEmailChangeNotFound if the change
+// was confirmed or the email was changed meanwhile, and with ErrUserExists if the new email is taken.
+func (s *Repository) ConfirmEmailChange(ctx context.Context, change models.EmailChange) error {
+	const op = "repository.sqlite.ConfirmEmailChange"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		`UPDATE email_changes SET revert_token_hash = ?, confirmed_at = ?, revert_expires_at = ?
+		WHERE id = ? AND confirmed_at IS NULL`,
+		change.RevertTokenHash, change.ConfirmedAt.UTC(), change.RevertExpiresAt.UTC(), change.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrEmailChangeNotFound); err != nil {
+		return err
+	}
+
+	err = setEmail(ctx, tx, change.UserID, change.OldEmail, change.NewEmail, change.ConfirmedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevertEmailChange restores the old email of the confirmed change, revokes the user's tokens
+// and records the change, all at once. It fails with ErrEmailChangeNotFound if the change
+// was reverted meanwhile, and with ErrUserExists if the old email was taken by someone else.
+func (s *Repository) RevertEmailChange(ctx context.Context, change models.EmailChange, revertedAt time.Time) error {
+	const op = "repository.sqlite.RevertEmailChange"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE email_changes SET reverted_at = ? WHERE id = ? AND confirmed_at IS NOT NULL AND reverted_at IS NULL",
+		revertedAt.UTC(), change.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrEmailChangeNotFound); err != nil {
+		return err
+	}
+
+	// the email may have been changed again since, the old address wins anyway
+	if err := setEmail(ctx, tx, change.UserID, "", change.OldEmail, revertedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// setEmail sets the verified email of the user, revokes the user's tokens and records the change.
+// Non-empty current only updates the user whose email is still current.
+func setEmail(ctx context.Context, tx *sql.Tx, userID int64, current string, email string, at time.Time) error {
+	var old string
+	err := tx.QueryRowContext(ctx, "SELECT email FROM users WHERE id = ?", userID).Scan(&old)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrUserNotFound
+		}
+
+		return err
+	}
+	if current != "" && old != current {
+		return repository.ErrEmailChangeNotFound
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE users SET email = ?, email_verified = TRUE, tokens_valid_after = ? WHERE id = ?",
+		// token iat has second precision, tokens issued later in the same second must stay valid
+		email, at.UTC().Truncate(time.Second), userID,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return repository.ErrUserExists
+		}
+
+		return err
+	}
+
+	return insertProfileChange(ctx, tx, models.ProfileChange{
+		UserID:    userID,
+		ChangedBy: userID,
+		Field:     models.ProfileFieldEmail,
+		OldValue:  old,
+		NewValue:  email,
+		ChangedAt: at,
+	})
+}
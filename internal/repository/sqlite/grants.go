@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+)
+
+const grantColumns = "g.user_id, g.app_id, a.name, g.scopes, g.created_at, g.updated_at"
+
+func scanGrant(row rowScanner) (models.Grant, error) {
+	var (
+		grant  models.Grant
+		scopes string
+	)
+
+	if err := row.Scan(&grant.UserID, &grant.AppID, &grant.AppName, &scopes, &grant.CreatedAt, &grant.UpdatedAt); err != nil {
+		return models.Grant{}, err
+	}
+
+	if err := json.Unmarshal([]byte(scopes), &grant.Scopes); err != nil {
+		return models.Grant{}, err
+	}
+
+	return grant, nil
+}
+
+func (s *Repository) Grant(ctx context.Context, userID int64, appID int) (models.Grant, error) {
+	const op = "repository.sqlite.Grant"
+
+	row := s.db.QueryRowContext(
+		ctx,
+		"SELECT "+grantColumns+" FROM oauth_grants g JOIN apps a ON a.id = g.app_id WHERE g.user_id = ? AND g.app_id = ?",
+		userID, appID,
+	)
+
+	grant, err := scanGrant(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Grant{}, fmt.Errorf("%s: %w", op, repository.ErrGrantNotFound)
+		}
+
+		return models.Grant{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return grant, nil
+}
+
+// Grants returns apps the user granted access to, the latest granted first.
+func (s *Repository) Grants(ctx context.Context, userID int64) ([]models.Grant, error) {
+	const op = "repository.sqlite.Grants"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		"SELECT "+grantColumns+" FROM oauth_grants g JOIN apps a ON a.id = g.app_id WHERE g.user_id = ? ORDER BY g.updated_at DESC, g.app_id",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var grants []models.Grant
+	for rows.Next() {
+		grant, err := scanGrant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return grants, nil
+}
+
+// SaveGrant saves the scopes of the grant, replacing the scopes granted before.
+// The time of the first grant is kept.
+func (s *Repository) SaveGrant(ctx context.Context, grant models.Grant) error {
+	const op = "repository.sqlite.SaveGrant"
+
+	scopes, err := json.Marshal(nonNil(grant.Scopes))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = s.db.ExecContext(
+		ctx,
+		`INSERT INTO oauth_grants (user_id, app_id, scopes, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, app_id) DO UPDATE SET scopes = excluded.scopes, updated_at = excluded.updated_at`,
+		grant.UserID, grant.AppID, string(scopes), grant.CreatedAt.UTC(), grant.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeGrant deletes the grant and revokes tokens the app got for the user before revokedAt.
+func (s *Repository) RevokeGrant(ctx context.Context, userID int64, appID int, revokedAt time.Time) error {
+	const op = "repository.sqlite.RevokeGrant"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM oauth_grants WHERE user_id = ? AND app_id = ?", userID, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrGrantNotFound); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO app_token_revocations (user_id, app_id, revoked_at) VALUES (?, ?, ?)
+		ON CONFLICT (user_id, app_id) DO UPDATE SET revoked_at = excluded.revoked_at`,
+		userID, appID, revokedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AppTokensRevokedAt returns the time tokens the app got for the user were last revoked at,
+// zero if they never were.
+func (s *Repository) AppTokensRevokedAt(ctx context.Context, userID int64, appID int) (time.Time, error) {
+	const op = "repository.sqlite.AppTokensRevokedAt"
+
+	var revokedAt time.Time
+
+	err := s.db.QueryRowContext(
+		ctx,
+		"SELECT revoked_at FROM app_token_revocations WHERE user_id = ? AND app_id = ?",
+		userID, appID,
+	).Scan(&revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revokedAt, nil
+}
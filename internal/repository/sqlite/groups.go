@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func (s *Repository) CreateGroup(ctx context.Context, name string, description string) (int64, error) {
+	const op = "repository.sqlite.CreateGroup"
+
+	res, err := s.db.ExecContext(ctx, "INSERT INTO groups (name, description) VALUES (?, ?)", name, description)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrGroupExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) Group(ctx context.Context, id int64) (models.Group, error) {
+	const op = "repository.sqlite.Group"
+
+	var group models.Group
+	err := s.db.QueryRowContext(ctx, "SELECT id, name, description FROM groups WHERE id = ?", id).
+		Scan(&group.ID, &group.Name, &group.Description)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Group{}, fmt.Errorf("%s: %w", op, repository.ErrGroupNotFound)
+		}
+
+		return models.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return group, nil
+}
+
+func (s *Repository) Groups(ctx context.Context) ([]models.Group, error) {
+	const op = "repository.sqlite.Groups"
+
+	groups, err := s.queryGroups(ctx, "SELECT id, name, description FROM groups ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+// DeleteGroup deletes the group, its memberships are deleted by cascade.
+func (s *Repository) DeleteGroup(ctx context.Context, id int64) error {
+	const op = "repository.sqlite.DeleteGroup"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM groups WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrGroupNotFound)
+}
+
+// AddGroupUser adds the user to the group, adding a member again is a no-op.
+func (s *Repository) AddGroupUser(ctx context.Context, groupID int64, userID int64) error {
+	const op = "repository.sqlite.AddGroupUser"
+
+	_, err := s.db.ExecContext(ctx, "INSERT OR IGNORE INTO group_users (group_id, user_id) VALUES (?, ?)", groupID, userID)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			return fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Repository) RemoveGroupUser(ctx context.Context, groupID int64, userID int64) error {
+	const op = "repository.sqlite.RemoveGroupUser"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM group_users WHERE group_id = ? AND user_id = ?", groupID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrNotGroupMember)
+}
+
+// AddSubgroup nests the child group in the parent one, adding it again is a no-op.
+// Returns ErrGroupCycle if the child group already contains the parent one.
+func (s *Repository) AddSubgroup(ctx context.Context, parentID int64, childID int64) error {
+	const op = "repository.sqlite.AddSubgroup"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// the parent is reachable from the child if the parent is the child or one of its descendants
+	var cycle bool
+	err = tx.QueryRowContext(
+		ctx,
+		`WITH RECURSIVE descendants(id) AS (
+			SELECT ?1
+			UNION
+			SELECT gg.child_id FROM group_groups gg JOIN descendants d ON gg.parent_id = d.id
+		)
+		SELECT EXISTS (SELECT 1 FROM descendants WHERE id = ?2)`,
+		childID, parentID,
+	).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cycle {
+		return fmt.Errorf("%s: %w", op, repository.ErrGroupCycle)
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT OR IGNORE INTO group_groups (parent_id, child_id) VALUES (?, ?)", parentID, childID)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			return fmt.Errorf("%s: %w", op, repository.ErrGroupNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Repository) RemoveSubgroup(ctx context.Context, parentID int64, childID int64) error {
+	const op = "repository.sqlite.RemoveSubgroup"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM group_groups WHERE parent_id = ? AND child_id = ?", parentID, childID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrNotGroupMember)
+}
+
+// UserGroups returns groups the user belongs to directly and groups containing them, transitively.
+func (s *Repository) UserGroups(ctx context.Context, userID int64) ([]models.Group, error) {
+	const op = "repository.sqlite.UserGroups"
+
+	// UNION drops groups that were already visited, so the recursion ends even if nesting has a cycle
+	groups, err := s.queryGroups(
+		ctx,
+		`WITH RECURSIVE ancestors(id) AS (
+			SELECT group_id FROM group_users WHERE user_id = ?
+			UNION
+			SELECT gg.parent_id FROM group_groups gg JOIN ancestors a ON gg.child_id = a.id
+		)
+		SELECT id, name, description FROM groups WHERE id IN (SELECT id FROM ancestors) ORDER BY name`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (s *Repository) queryGroups(ctx context.Context, query string, args ...any) ([]models.Group, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var group models.Group
+		if err := rows.Scan(&group.ID, &group.Name, &group.Description); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
@@ -0,0 +1,297 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const identityColumns = "provider, subject, user_id, email, created_at, last_login_at"
+
+func scanIdentity(row rowScanner) (models.Identity, error) {
+	var identity models.Identity
+
+	err := row.Scan(
+		&identity.Provider,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+
+	return identity, err
+}
+
+func (s *Repository) Identity(ctx context.Context, provider string, subject string) (models.Identity, error) {
+	const op = "repository.sqlite.Identity"
+
+	row := s.db.QueryRowContext(
+		ctx,
+		"SELECT "+identityColumns+" FROM user_identities WHERE provider = ? AND subject = ?",
+		provider, subject,
+	)
+
+	identity, err := scanIdentity(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Identity{}, fmt.Errorf("%s: %w", op, repository.ErrIdentityNotFound)
+		}
+
+		return models.Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identity, nil
+}
+
+// Identities returns the identities linked to the user, the first linked first.
+func (s *Repository) Identities(ctx context.Context, userID int64) ([]models.Identity, error) {
+	const op = "repository.sqlite.Identities"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		"SELECT "+identityColumns+" FROM user_identities WHERE user_id = ? ORDER BY created_at, provider",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var identities []models.Identity
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		identities = append(identities, identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identities, nil
+}
+
+// SaveIdentity links the identity to the user. Identities linked before keep their user,
+// only the email and the time of the last login are updated.
+func (s *Repository) SaveIdentity(ctx context.Context, identity models.Identity) error {
+	const op = "repository.sqlite.SaveIdentity"
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO user_identities (provider, subject, user_id, email, created_at, last_login_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (provider, subject) DO UPDATE SET email = excluded.email, last_login_at = excluded.last_login_at`,
+		identity.Provider, identity.Subject, identity.UserID, identity.Email,
+		identity.CreatedAt.UTC(), identity.LastLoginAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreateFederatedUser saves the user who logged in through an upstream identity provider
+// along with the identity. The email was verified by the provider, the user has no password.
+func (s *Repository) CreateFederatedUser(ctx context.Context, user models.User, identity models.Identity) (int64, error) {
+	const op = "repository.sqlite.CreateFederatedUser"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO users (title, birth_date, name, last_name, email, email_verified, pass_hash, phone, created_at)
+		VALUES ('', '', ?, ?, ?, TRUE, X'', '', ?)`,
+		user.Name, user.LastName, user.Email, identity.CreatedAt.UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO user_identities (provider, subject, user_id, email, created_at, last_login_at) VALUES (?, ?, ?, ?, ?, ?)",
+		identity.Provider, identity.Subject, userID, identity.Email, identity.CreatedAt.UTC(), identity.LastLoginAt.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+// SaveFederatedLogin saves the login started at now and deletes logins expired by then.
+func (s *Repository) SaveFederatedLogin(ctx context.Context, login models.FederatedLogin, now time.Time) error {
+	const op = "repository.sqlite.SaveFederatedLogin"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM federated_logins WHERE expires_at <= ?", now.UTC()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	orgID := sql.NullInt64{Int64: login.OrgID, Valid: login.OrgID != 0}
+
+	scopes, err := json.Marshal(nonNil(login.Scopes))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO federated_logins (state_hash, provider, nonce, code_verifier, app_id, org_id, redirect_uri, client_state, scopes,
+			app_code_challenge, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		login.StateHash, login.Provider, login.Nonce, login.CodeVerifier, login.AppID, orgID,
+		login.RedirectURI, login.ClientState, scopes, login.AppCodeChallenge, login.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// TakeFederatedLogin returns the login with the state unexpired at now and forgets the state,
+// so that every state is used once.
+func (s *Repository) TakeFederatedLogin(ctx context.Context, stateHash []byte, now time.Time) (models.FederatedLogin, error) {
+	const op = "repository.sqlite.TakeFederatedLogin"
+
+	var (
+		login  models.FederatedLogin
+		orgID  sql.NullInt64
+		scopes string
+	)
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`UPDATE federated_logins SET state_hash = NULL WHERE state_hash = ? AND expires_at > ?
+		RETURNING id, provider, nonce, code_verifier, app_id, org_id, redirect_uri, client_state, scopes, expires_at`,
+		stateHash, now.UTC(),
+	).Scan(
+		&login.ID,
+		&login.Provider,
+		&login.Nonce,
+		&login.CodeVerifier,
+		&login.AppID,
+		&orgID,
+		&login.RedirectURI,
+		&login.ClientState,
+		&scopes,
+		&login.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.FederatedLogin{}, fmt.Errorf("%s: %w", op, repository.ErrFederatedLoginNotFound)
+		}
+
+		return models.FederatedLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+	login.OrgID = orgID.Int64
+
+	if err := json.Unmarshal([]byte(scopes), &login.Scopes); err != nil {
+		return models.FederatedLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return login, nil
+}
+
+// SetFederatedLoginCode records the user who logged in at the provider and the login code
+// the app exchanges for the token until expiresAt.
+func (s *Repository) SetFederatedLoginCode(
+	ctx context.Context,
+	loginID int64,
+	codeHash []byte,
+	userID int64,
+	expiresAt time.Time,
+) error {
+	const op = "repository.sqlite.SetFederatedLoginCode"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"UPDATE federated_logins SET code_hash = ?, user_id = ?, expires_at = ? WHERE id = ? AND code_hash IS NULL",
+		codeHash, userID, expiresAt.UTC(), loginID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrFederatedLoginNotFound)
+}
+
+// TakeFederatedLoginCode deletes the login with the code unexpired at now and returns it,
+// so that every code is used once.
+func (s *Repository) TakeFederatedLoginCode(ctx context.Context, codeHash []byte, now time.Time) (models.FederatedLogin, error) {
+	const op = "repository.sqlite.TakeFederatedLoginCode"
+
+	var (
+		login  models.FederatedLogin
+		orgID  sql.NullInt64
+		scopes string
+	)
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`DELETE FROM federated_logins WHERE code_hash = ? AND expires_at > ?
+		RETURNING id, provider, app_id, org_id, redirect_uri, scopes, app_code_challenge, expires_at, user_id`,
+		codeHash, now.UTC(),
+	).Scan(
+		&login.ID,
+		&login.Provider,
+		&login.AppID,
+		&orgID,
+		&login.RedirectURI,
+		&scopes,
+		&login.AppCodeChallenge,
+		&login.ExpiresAt,
+		&login.UserID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.FederatedLogin{}, fmt.Errorf("%s: %w", op, repository.ErrFederatedLoginNotFound)
+		}
+
+		return models.FederatedLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+	login.OrgID = orgID.Int64
+
+	if err := json.Unmarshal([]byte(scopes), &login.Scopes); err != nil {
+		return models.FederatedLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return login, nil
+}
@@ -0,0 +1,238 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const invitationColumns = "id, email, token_hash, inviter_id, app_id, role, org_id, org_role, created_at, expires_at, accepted_at, revoked_at"
+
+func scanInvitation(row rowScanner) (models.Invitation, error) {
+	var (
+		inv        models.Invitation
+		inviterID  sql.NullInt64
+		orgID      sql.NullInt64
+		acceptedAt sql.NullTime
+		revokedAt  sql.NullTime
+	)
+
+	err := row.Scan(
+		&inv.ID,
+		&inv.Email,
+		&inv.TokenHash,
+		&inviterID,
+		&inv.AppID,
+		&inv.Role,
+		&orgID,
+		&inv.OrgRole,
+		&inv.CreatedAt,
+		&inv.ExpiresAt,
+		&acceptedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return models.Invitation{}, err
+	}
+
+	inv.InviterID = inviterID.Int64
+	inv.OrgID = orgID.Int64
+	inv.AcceptedAt = acceptedAt.Time
+	inv.RevokedAt = revokedAt.Time
+
+	return inv, nil
+}
+
+func (s *Repository) CreateInvitation(ctx context.Context, inv models.Invitation) (int64, error) {
+	const op = "repository.sqlite.CreateInvitation"
+
+	inviterID := sql.NullInt64{Int64: inv.InviterID, Valid: inv.InviterID != 0}
+	orgID := sql.NullInt64{Int64: inv.OrgID, Valid: inv.OrgID != 0}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO invitations (email, token_hash, inviter_id, app_id, role, org_id, org_role, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		inv.Email, inv.TokenHash, inviterID, inv.AppID, inv.Role, orgID, inv.OrgRole, inv.CreatedAt.UTC(), inv.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) Invitation(ctx context.Context, id int64) (models.Invitation, error) {
+	const op = "repository.sqlite.Invitation"
+
+	inv, err := s.queryInvitation(ctx, "SELECT "+invitationColumns+" FROM invitations WHERE id = ?", id)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inv, nil
+}
+
+func (s *Repository) InvitationByTokenHash(ctx context.Context, tokenHash []byte) (models.Invitation, error) {
+	const op = "repository.sqlite.InvitationByTokenHash"
+
+	inv, err := s.queryInvitation(ctx, "SELECT "+invitationColumns+" FROM invitations WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inv, nil
+}
+
+func (s *Repository) queryInvitation(ctx context.Context, query string, args ...any) (models.Invitation, error) {
+	inv, err := scanInvitation(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Invitation{}, repository.ErrInvitationNotFound
+		}
+
+		return models.Invitation{}, err
+	}
+
+	return inv, nil
+}
+
+// PendingInvitations returns invitations to the organization that can still be accepted.
+// Zero orgID returns pending invitations of all organizations and those without one.
+func (s *Repository) PendingInvitations(ctx context.Context, orgID int64, now time.Time) ([]models.Invitation, error) {
+	const op = "repository.sqlite.PendingInvitations"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		"SELECT "+invitationColumns+` FROM invitations
+		WHERE (? = 0 OR org_id = ?) AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?
+		ORDER BY id`,
+		orgID, orgID, now.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var invitations []models.Invitation
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		invitations = append(invitations, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation revokes the invitation unless it was already accepted or revoked.
+func (s *Repository) RevokeInvitation(ctx context.Context, id int64, revokedAt time.Time) error {
+	const op = "repository.sqlite.RevokeInvitation"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"UPDATE invitations SET revoked_at = ? WHERE id = ? AND accepted_at IS NULL AND revoked_at IS NULL",
+		revokedAt.UTC(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrInvitationNotFound)
+}
+
+// AcceptInvitation creates the user with a verified email, grants the user the role and organization
+// membership preset by the invitation and marks the invitation accepted, all at once.
+// It fails with ErrInvitationNotFound if the invitation was accepted or revoked meanwhile.
+func (s *Repository) AcceptInvitation(
+	ctx context.Context,
+	inv models.Invitation,
+	user models.User,
+	acceptedAt time.Time,
+) (int64, error) {
+	const op = "repository.sqlite.AcceptInvitation"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE invitations SET accepted_at = ? WHERE id = ? AND accepted_at IS NULL AND revoked_at IS NULL",
+		acceptedAt.UTC(), inv.ID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrInvitationNotFound); err != nil {
+		return 0, err
+	}
+
+	res, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO users (title, birth_date, name, last_name, email, email_verified, pass_hash, phone, created_at)
+		VALUES (?, ?, ?, ?, ?, TRUE, ?, ?, ?)`,
+		user.Title, user.BirthDate, user.Name, user.LastName, inv.Email, user.PassHash, user.Phone, acceptedAt.UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if inv.Role != "" {
+		res, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO user_roles (user_id, role_id) SELECT ?, id FROM roles WHERE app_id = ? AND name = ?",
+			userID, inv.AppID, inv.Role,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := checkAffected(res, op, repository.ErrRoleNotFound); err != nil {
+			return 0, err
+		}
+	}
+
+	if inv.OrgID != 0 {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO organization_members (org_id, user_id, role) VALUES (?, ?, ?)",
+			inv.OrgID, userID, inv.OrgRole,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,261 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const orgColumns = "id, name, allowed_email_domains, require_mfa, created_at"
+
+func scanOrg(row rowScanner) (models.Organization, error) {
+	var (
+		org     models.Organization
+		domains string
+	)
+
+	if err := row.Scan(&org.ID, &org.Name, &domains, &org.RequireMFA, &org.CreatedAt); err != nil {
+		return models.Organization{}, err
+	}
+
+	if err := json.Unmarshal([]byte(domains), &org.AllowedEmailDomains); err != nil {
+		return models.Organization{}, err
+	}
+
+	return org, nil
+}
+
+// CreateOrganization saves the organization and makes the user its owner.
+func (s *Repository) CreateOrganization(ctx context.Context, org models.Organization, ownerID int64) (int64, error) {
+	const op = "repository.sqlite.CreateOrganization"
+
+	domains, err := json.Marshal(nonNil(org.AllowedEmailDomains))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO organizations (name, allowed_email_domains, require_mfa, created_at) VALUES (?, ?, ?, ?)",
+		org.Name, string(domains), org.RequireMFA, time.Now().UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrOrgExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO organization_members (org_id, user_id, role) VALUES (?, ?, ?)",
+		id, ownerID, models.OrgRoleOwner,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) Organization(ctx context.Context, id int64) (models.Organization, error) {
+	const op = "repository.sqlite.Organization"
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+orgColumns+" FROM organizations WHERE id = ?", id)
+
+	org, err := scanOrg(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Organization{}, fmt.Errorf("%s: %w", op, repository.ErrOrgNotFound)
+		}
+
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, nil
+}
+
+// Organizations returns all organizations if userID is zero, otherwise organizations the user is a member of.
+func (s *Repository) Organizations(ctx context.Context, userID int64) ([]models.Organization, error) {
+	const op = "repository.sqlite.Organizations"
+
+	query := "SELECT " + orgColumns + " FROM organizations ORDER BY id"
+	args := []any{}
+	if userID != 0 {
+		query = `SELECT ` + orgColumns + ` FROM organizations
+		WHERE id IN (SELECT org_id FROM organization_members WHERE user_id = ?) ORDER BY id`
+		args = append(args, userID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var orgs []models.Organization
+	for rows.Next() {
+		org, err := scanOrg(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orgs, nil
+}
+
+func (s *Repository) UpdateOrganization(ctx context.Context, org models.Organization) error {
+	const op = "repository.sqlite.UpdateOrganization"
+
+	domains, err := json.Marshal(nonNil(org.AllowedEmailDomains))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"UPDATE organizations SET name = ?, allowed_email_domains = ?, require_mfa = ? WHERE id = ?",
+		org.Name, string(domains), org.RequireMFA, org.ID,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, repository.ErrOrgExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrOrgNotFound)
+}
+
+// DeleteOrganization deletes the organization, its memberships and apps are deleted by cascade.
+func (s *Repository) DeleteOrganization(ctx context.Context, id int64) error {
+	const op = "repository.sqlite.DeleteOrganization"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM organizations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrOrgNotFound)
+}
+
+func (s *Repository) OrgMember(ctx context.Context, orgID int64, userID int64) (models.OrgMember, error) {
+	const op = "repository.sqlite.OrgMember"
+
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT m.org_id, m.user_id, u.email, m.role FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.org_id = ? AND m.user_id = ?`,
+		orgID, userID,
+	)
+
+	var member models.OrgMember
+	if err := row.Scan(&member.OrgID, &member.UserID, &member.Email, &member.Role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OrgMember{}, fmt.Errorf("%s: %w", op, repository.ErrNotOrgMember)
+		}
+
+		return models.OrgMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return member, nil
+}
+
+func (s *Repository) OrgMembers(ctx context.Context, orgID int64) ([]models.OrgMember, error) {
+	const op = "repository.sqlite.OrgMembers"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT m.org_id, m.user_id, u.email, m.role FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.org_id = ? ORDER BY m.user_id`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var members []models.OrgMember
+	for rows.Next() {
+		var member models.OrgMember
+		if err := rows.Scan(&member.OrgID, &member.UserID, &member.Email, &member.Role); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SetOrgMember adds the user to the organization or changes their role if they are already a member.
+func (s *Repository) SetOrgMember(ctx context.Context, orgID int64, userID int64, role string) error {
+	const op = "repository.sqlite.SetOrgMember"
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO organization_members (org_id, user_id, role) VALUES (?, ?, ?)
+		ON CONFLICT (org_id, user_id) DO UPDATE SET role = excluded.role`,
+		orgID, userID, role,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			return fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Repository) RemoveOrgMember(ctx context.Context, orgID int64, userID int64) error {
+	const op = "repository.sqlite.RemoveOrgMember"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM organization_members WHERE org_id = ? AND user_id = ?", orgID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrNotOrgMember)
+}
@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func (s *Repository) CreatePhoneChange(ctx context.Context, change models.PhoneChange) (int64, error) {
+	const op = "repository.sqlite.CreatePhoneChange"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO phone_changes (user_id, old_phone, new_phone, created_at) VALUES (?, ?, ?, ?)",
+		change.UserID, change.OldPhone, change.NewPhone, change.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// PendingPhoneChange returns the latest unconfirmed phone change of the user.
+func (s *Repository) PendingPhoneChange(ctx context.Context, userID int64) (models.PhoneChange, error) {
+	const op = "repository.sqlite.PendingPhoneChange"
+
+	var (
+		change      models.PhoneChange
+		confirmedAt sql.NullTime
+	)
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, user_id, old_phone, new_phone, created_at, confirmed_at FROM phone_changes
+		WHERE user_id = ? AND confirmed_at IS NULL ORDER BY id DESC LIMIT 1`,
+		userID,
+	).Scan(
+		&change.ID,
+		&change.UserID,
+		&change.OldPhone,
+		&change.NewPhone,
+		&change.CreatedAt,
+		&confirmedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.PhoneChange{}, fmt.Errorf("%s: %w", op, repository.ErrPhoneChangeNotFound)
+		}
+
+		return models.PhoneChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	change.ConfirmedAt = confirmedAt.Time
+
+	return change, nil
+}
+
+// ConfirmPhoneChange replaces the user's phone with the new one and records the change, all at once.
+// It fails with ErrPhoneChangeNotFound if the change was confirmed or the phone was changed meanwhile,
+// and with ErrUserExists if the new phone is taken.
+func (s *Repository) ConfirmPhoneChange(ctx context.Context, change models.PhoneChange) error {
+	const op = "repository.sqlite.ConfirmPhoneChange"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE phone_changes SET confirmed_at = ? WHERE id = ? AND confirmed_at IS NULL",
+		change.ConfirmedAt.UTC(), change.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrPhoneChangeNotFound); err != nil {
+		return err
+	}
+
+	res, err = tx.ExecContext(
+		ctx,
+		"UPDATE users SET phone = ? WHERE id = ? AND phone = ?",
+		change.NewPhone, change.UserID, change.OldPhone,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, repository.ErrUserExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrPhoneChangeNotFound); err != nil {
+		return err
+	}
+
+	err = insertProfileChange(ctx, tx, models.ProfileChange{
+		UserID:    change.UserID,
+		ChangedBy: change.UserID,
+		Field:     models.ProfileFieldPhone,
+		OldValue:  change.OldPhone,
+		NewValue:  change.NewPhone,
+		ChangedAt: change.ConfirmedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
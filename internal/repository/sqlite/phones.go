@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// UsersWithNationalPhones returns users whose phones lack the international prefix, so they aren't E.164.
+func (s *Repository) UsersWithNationalPhones(ctx context.Context) ([]models.User, error) {
+	const op = "repository.sqlite.UsersWithNationalPhones"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE phone != '' AND phone NOT LIKE '+%' ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// ReplacePhone replaces the user's phone unless it was changed meanwhile.
+// It fails with ErrUserExists if another user has the phone.
+func (s *Repository) ReplacePhone(ctx context.Context, userID int64, oldPhone string, phone string) error {
+	const op = "repository.sqlite.ReplacePhone"
+
+	res, err := s.db.ExecContext(ctx, "UPDATE users SET phone = ? WHERE id = ? AND phone = ?", phone, userID, oldPhone)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, repository.ErrUserExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrUserNotFound)
+}
+
+// PhoneConflicts returns phones set aside because another user had them, by user.
+func (s *Repository) PhoneConflicts(ctx context.Context) ([]models.PhoneConflict, error) {
+	const op = "repository.sqlite.PhoneConflicts"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT user_id, phone FROM phone_conflicts ORDER BY user_id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var conflicts []models.PhoneConflict
+	for rows.Next() {
+		var c models.PhoneConflict
+		if err := rows.Scan(&c.UserID, &c.Phone); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		conflicts = append(conflicts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return conflicts, nil
+}
+
+// DeletePhoneConflict forgets the phone set aside for the user.
+func (s *Repository) DeletePhoneConflict(ctx context.Context, userID int64) error {
+	const op = "repository.sqlite.DeletePhoneConflict"
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM phone_conflicts WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func (s *Repository) CreatePolicyRule(ctx context.Context, rule models.PolicyRule) (int64, error) {
+	const op = "repository.sqlite.CreatePolicyRule"
+
+	actions, err := json.Marshal(nonNil(rule.Actions))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	conditions := rule.Conditions
+	if conditions == nil {
+		conditions = []models.PolicyCondition{}
+	}
+	conds, err := json.Marshal(conditions)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO policy_rules (app_id, name, effect, actions, resource_type, conditions) VALUES (?, ?, ?, ?, ?, ?)`,
+		rule.AppID, rule.Name, rule.Effect, string(actions), rule.ResourceType, string(conds),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) {
+			switch sqliteErr.ExtendedCode {
+			case sqlite3.ErrConstraintUnique:
+				return 0, fmt.Errorf("%s: %w", op, repository.ErrRuleExists)
+			case sqlite3.ErrConstraintForeignKey:
+				return 0, fmt.Errorf("%s: %w", op, repository.ErrAppNotFound)
+			}
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// PolicyRules returns rules of the app policy in the order they were created.
+func (s *Repository) PolicyRules(ctx context.Context, appID int) ([]models.PolicyRule, error) {
+	const op = "repository.sqlite.PolicyRules"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, app_id, name, effect, actions, resource_type, conditions FROM policy_rules WHERE app_id = ? ORDER BY id`,
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var rules []models.PolicyRule
+	for rows.Next() {
+		var (
+			rule       models.PolicyRule
+			actions    string
+			conditions string
+		)
+
+		if err := rows.Scan(&rule.ID, &rule.AppID, &rule.Name, &rule.Effect, &actions, &rule.ResourceType, &conditions); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := json.Unmarshal([]byte(actions), &rule.Actions); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := json.Unmarshal([]byte(conditions), &rule.Conditions); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+func (s *Repository) DeletePolicyRule(ctx context.Context, id int64) error {
+	const op = "repository.sqlite.DeletePolicyRule"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM policy_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrRuleNotFound)
+}
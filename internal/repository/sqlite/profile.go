@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+)
+
+// UpdateProfile saves profile fields of the user along with the history of their changes.
+func (s *Repository) UpdateProfile(ctx context.Context, user models.User, changes []models.ProfileChange) error {
+	const op = "repository.sqlite.UpdateProfile"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		`UPDATE users SET title = ?, birth_date = ?, name = ?, last_name = ? WHERE id = ?`,
+		user.Title,
+		user.BirthDate,
+		user.Name,
+		user.LastName,
+		user.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkAffected(res, op, repository.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		change.UserID = user.ID
+		if err := insertProfileChange(ctx, tx, change); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func insertProfileChange(ctx context.Context, tx *sql.Tx, change models.ProfileChange) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO profile_changes (user_id, changed_by, field, old_value, new_value, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		change.UserID, change.ChangedBy, change.Field, change.OldValue, change.NewValue, change.ChangedAt.UTC(),
+	)
+
+	return err
+}
+
+// ProfileChanges returns the latest profile changes of the user, newest first.
+func (s *Repository) ProfileChanges(ctx context.Context, userID int64, limit int) ([]models.ProfileChange, error) {
+	const op = "repository.sqlite.ProfileChanges"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, user_id, changed_by, field, old_value, new_value, changed_at FROM profile_changes
+		WHERE user_id = ? ORDER BY id DESC LIMIT ?`,
+		userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var changes []models.ProfileChange
+	for rows.Next() {
+		var change models.ProfileChange
+
+		err := rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.ChangedBy,
+			&change.Field,
+			&change.OldValue,
+			&change.NewValue,
+			&change.ChangedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,363 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func (s *Repository) CreateRole(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "repository.sqlite.CreateRole"
+
+	res, err := s.db.ExecContext(ctx, "INSERT INTO roles (app_id, name) VALUES (?, ?)", appID, name)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrRoleExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) Role(ctx context.Context, appID int, name string) (models.Role, error) {
+	const op = "repository.sqlite.Role"
+
+	row := s.db.QueryRowContext(ctx, "SELECT id, app_id, name FROM roles WHERE app_id = ? AND name = ?", appID, name)
+
+	var role models.Role
+	if err := row.Scan(&role.ID, &role.AppID, &role.Name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Role{}, fmt.Errorf("%s: %w", op, repository.ErrRoleNotFound)
+		}
+
+		return models.Role{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return role, nil
+}
+
+func (s *Repository) Roles(ctx context.Context, appID int) ([]models.Role, error) {
+	const op = "repository.sqlite.Roles"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT id, app_id, name FROM roles WHERE app_id = ? ORDER BY name", appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var roles []models.Role
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role.ID, &role.AppID, &role.Name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+func (s *Repository) CreatePermission(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "repository.sqlite.CreatePermission"
+
+	res, err := s.db.ExecContext(ctx, "INSERT INTO permissions (app_id, name) VALUES (?, ?)", appID, name)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrPermExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// AppPermissions returns names of the permissions of the app.
+func (s *Repository) AppPermissions(ctx context.Context, appID int) ([]string, error) {
+	const op = "repository.sqlite.AppPermissions"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT name FROM permissions WHERE app_id = ? ORDER BY name", appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var perms []string
+	for rows.Next() {
+		var perm string
+		if err := rows.Scan(&perm); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		perms = append(perms, perm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return perms, nil
+}
+
+func (s *Repository) GrantPermission(ctx context.Context, roleID int64, permissionID int64) error {
+	const op = "repository.sqlite.GrantPermission"
+
+	_, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO role_permissions (role_id, permission_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		roleID,
+		permissionID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Repository) AssignRole(ctx context.Context, userID int64, roleID int64) error {
+	const op = "repository.sqlite.AssignRole"
+
+	_, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO user_roles (user_id, role_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		userID,
+		roleID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Repository) RevokeRole(ctx context.Context, userID int64, roleID int64) error {
+	const op = "repository.sqlite.RevokeRole"
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM user_roles WHERE user_id = ? AND role_id = ?", userID, roleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UserRoles returns names of the roles the user holds in the app.
+func (s *Repository) UserRoles(ctx context.Context, userID int64, appID int) ([]string, error) {
+	const op = "repository.sqlite.UserRoles"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT r.name FROM roles r
+		JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id = ? AND r.app_id = ?
+		ORDER BY r.name`,
+		userID,
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		roles = append(roles, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+// HasPermission checks if any role of the user in the app grants the permission.
+func (s *Repository) HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error) {
+	const op = "repository.sqlite.HasPermission"
+
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+			JOIN role_permissions rp ON rp.role_id = r.id
+			JOIN permissions p ON p.id = rp.permission_id
+			WHERE ur.user_id = ? AND r.app_id = ? AND p.name = ?
+		)`,
+		userID,
+		appID,
+		permission,
+	)
+
+	var has bool
+	if err := row.Scan(&has); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return has, nil
+}
+
+// SetUserRoles replaces roles of the user in the app with the named ones.
+func (s *Repository) SetUserRoles(ctx context.Context, userID int64, appID int, roles []string) error {
+	const op = "repository.sqlite.SetUserRoles"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM user_roles WHERE user_id = ? AND role_id IN (SELECT id FROM roles WHERE app_id = ?)",
+		userID,
+		appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, role := range roles {
+		res, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO user_roles (user_id, role_id)
+			SELECT ?, id FROM roles WHERE app_id = ? AND name = ?
+			ON CONFLICT DO NOTHING`,
+			userID,
+			appID,
+			role,
+		)
+		if err != nil {
+			var sqliteErr sqlite3.Error
+			if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+				return fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+			}
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if n == 0 {
+			return fmt.Errorf("%s: role %q: %w", op, role, repository.ErrRoleNotFound)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RolePermissions returns names of the permissions granted by each role of the app.
+func (s *Repository) RolePermissions(ctx context.Context, appID int) (map[string][]string, error) {
+	const op = "repository.sqlite.RolePermissions"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT r.name, p.name FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.app_id = ?
+		ORDER BY r.name, p.name`,
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	perms := make(map[string][]string)
+	for rows.Next() {
+		var (
+			role string
+			perm sql.NullString
+		)
+		if err := rows.Scan(&role, &perm); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// roles without permissions are listed too
+		if !perm.Valid {
+			perms[role] = nil
+			continue
+		}
+		perms[role] = append(perms[role], perm.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return perms, nil
+}
+
+// EnsureRole creates the role in the app if it doesn't exist and grants it the permissions,
+// creating missing ones. Permissions the role already has are kept.
+func (s *Repository) EnsureRole(ctx context.Context, appID int, role string, perms []string) error {
+	const op = "repository.sqlite.EnsureRole"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO roles (app_id, name) VALUES (?, ?) ON CONFLICT DO NOTHING", appID, role)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, perm := range perms {
+		_, err = tx.ExecContext(ctx, "INSERT INTO permissions (app_id, name) VALUES (?, ?) ON CONFLICT DO NOTHING", appID, perm)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p ON p.app_id = r.app_id AND p.name = ?
+			WHERE r.app_id = ? AND r.name = ?
+			ON CONFLICT DO NOTHING`,
+			perm,
+			appID,
+			role,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
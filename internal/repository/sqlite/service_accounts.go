@@ -0,0 +1,343 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const serviceAccountColumns = "id, name, description, app_id, org_id, client_id, secret_hash, disabled, created_at"
+
+func scanServiceAccount(row rowScanner) (models.ServiceAccount, error) {
+	var (
+		sa    models.ServiceAccount
+		appID sql.NullInt64
+		orgID sql.NullInt64
+	)
+
+	err := row.Scan(
+		&sa.ID,
+		&sa.Name,
+		&sa.Description,
+		&appID,
+		&orgID,
+		&sa.ClientID,
+		&sa.SecretHash,
+		&sa.Disabled,
+		&sa.CreatedAt,
+	)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	sa.AppID = int(appID.Int64)
+	sa.OrgID = orgID.Int64
+
+	return sa, nil
+}
+
+func (s *Repository) CreateServiceAccount(ctx context.Context, sa models.ServiceAccount) (int64, error) {
+	const op = "repository.sqlite.CreateServiceAccount"
+
+	appID := sql.NullInt64{Int64: int64(sa.AppID), Valid: sa.AppID != 0}
+	orgID := sql.NullInt64{Int64: sa.OrgID, Valid: sa.OrgID != 0}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO service_accounts (name, description, app_id, org_id, client_id, secret_hash, disabled, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		sa.Name, sa.Description, appID, orgID, sa.ClientID, sa.SecretHash, sa.Disabled, sa.CreatedAt.UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, repository.ErrServiceAccountExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Repository) ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error) {
+	const op = "repository.sqlite.ServiceAccount"
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+serviceAccountColumns+" FROM service_accounts WHERE id = ?", id)
+
+	sa, err := scanServiceAccount(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, repository.ErrServiceAccountNotFound)
+		}
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sa, nil
+}
+
+func (s *Repository) ServiceAccountByClientID(ctx context.Context, clientID string) (models.ServiceAccount, error) {
+	const op = "repository.sqlite.ServiceAccountByClientID"
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+serviceAccountColumns+" FROM service_accounts WHERE client_id = ?", clientID)
+
+	sa, err := scanServiceAccount(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, repository.ErrServiceAccountNotFound)
+		}
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sa, nil
+}
+
+func (s *Repository) ServiceAccounts(ctx context.Context) ([]models.ServiceAccount, error) {
+	const op = "repository.sqlite.ServiceAccounts"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+serviceAccountColumns+" FROM service_accounts ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var accounts []models.ServiceAccount
+	for rows.Next() {
+		sa, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		accounts = append(accounts, sa)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return accounts, nil
+}
+
+// UpdateServiceAccount overwrites the name, description, secret and disabled flag of the account.
+// The owner and client id never change.
+func (s *Repository) UpdateServiceAccount(ctx context.Context, sa models.ServiceAccount) error {
+	const op = "repository.sqlite.UpdateServiceAccount"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"UPDATE service_accounts SET name = ?, description = ?, secret_hash = ?, disabled = ? WHERE id = ?",
+		sa.Name, sa.Description, sa.SecretHash, sa.Disabled, sa.ID,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, repository.ErrServiceAccountExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrServiceAccountNotFound)
+}
+
+// DeleteServiceAccount deletes the account along with its roles and API keys.
+func (s *Repository) DeleteServiceAccount(ctx context.Context, id int64) error {
+	const op = "repository.sqlite.DeleteServiceAccount"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM service_accounts WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrServiceAccountNotFound)
+}
+
+// ServiceAccountRoles returns names of the roles the service account holds in the app.
+func (s *Repository) ServiceAccountRoles(ctx context.Context, serviceAccountID int64, appID int) ([]string, error) {
+	const op = "repository.sqlite.ServiceAccountRoles"
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT r.name FROM roles r
+		JOIN service_account_roles sr ON sr.role_id = r.id
+		WHERE sr.service_account_id = ? AND r.app_id = ?
+		ORDER BY r.name`,
+		serviceAccountID,
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		roles = append(roles, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+// ServiceAccountHasPermission checks if any role of the service account in the app grants the permission.
+func (s *Repository) ServiceAccountHasPermission(
+	ctx context.Context,
+	serviceAccountID int64,
+	appID int,
+	permission string,
+) (bool, error) {
+	const op = "repository.sqlite.ServiceAccountHasPermission"
+
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM service_account_roles sr
+			JOIN roles r ON r.id = sr.role_id
+			JOIN role_permissions rp ON rp.role_id = r.id
+			JOIN permissions p ON p.id = rp.permission_id
+			WHERE sr.service_account_id = ? AND r.app_id = ? AND p.name = ?
+		)`,
+		serviceAccountID,
+		appID,
+		permission,
+	)
+
+	var has bool
+	if err := row.Scan(&has); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return has, nil
+}
+
+// SetServiceAccountRoles replaces roles of the service account in the app with the named ones.
+func (s *Repository) SetServiceAccountRoles(ctx context.Context, serviceAccountID int64, appID int, roles []string) error {
+	const op = "repository.sqlite.SetServiceAccountRoles"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM service_account_roles WHERE service_account_id = ? AND role_id IN (SELECT id FROM roles WHERE app_id = ?)",
+		serviceAccountID,
+		appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, role := range roles {
+		res, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO service_account_roles (service_account_id, role_id)
+			SELECT ?, id FROM roles WHERE app_id = ? AND name = ?
+			ON CONFLICT DO NOTHING`,
+			serviceAccountID,
+			appID,
+			role,
+		)
+		if err != nil {
+			var sqliteErr sqlite3.Error
+			if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+				return fmt.Errorf("%s: %w", op, repository.ErrServiceAccountNotFound)
+			}
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if n == 0 {
+			return fmt.Errorf("%s: role %q: %w", op, role, repository.ErrRoleNotFound)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ServiceAccountLockoutState returns failed attempts of the service account to get a token,
+// the UserID of the state is the ID of the account. If the account has none, returns empty state.
+func (s *Repository) ServiceAccountLockoutState(ctx context.Context, serviceAccountID int64) (models.LockoutState, error) {
+	const op = "repository.sqlite.ServiceAccountLockoutState"
+
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT failed_attempts, first_failed_at, last_failed_at, locked_until
+		FROM service_account_login_attempts WHERE service_account_id = ?`,
+		serviceAccountID,
+	)
+
+	state := models.LockoutState{UserID: serviceAccountID}
+	err := row.Scan(&state.FailedAttempts, &state.FirstFailedAt, &state.LastFailedAt, &state.LockedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return state, nil
+		}
+
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return state, nil
+}
+
+func (s *Repository) SaveServiceAccountLockoutState(ctx context.Context, state models.LockoutState) error {
+	const op = "repository.sqlite.SaveServiceAccountLockoutState"
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO service_account_login_attempts (service_account_id, failed_attempts, first_failed_at, last_failed_at, locked_until)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (service_account_id) DO UPDATE SET
+			failed_attempts = excluded.failed_attempts,
+			first_failed_at = excluded.first_failed_at,
+			last_failed_at = excluded.last_failed_at,
+			locked_until = excluded.locked_until`,
+		state.UserID,
+		state.FailedAttempts,
+		state.FirstFailedAt.UTC(),
+		state.LastFailedAt.UTC(),
+		state.LockedUntil.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Repository) ResetServiceAccountLockoutState(ctx context.Context, serviceAccountID int64) error {
+	const op = "repository.sqlite.ResetServiceAccountLockoutState"
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM service_account_login_attempts WHERE service_account_id = ?", serviceAccountID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
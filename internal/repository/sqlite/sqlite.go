@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sso/internal/domain/models"
 	"sso/internal/repository"
+	"time"
 
 	"github.com/mattn/go-sqlite3"
 	_ "github.com/mattn/go-sqlite3"
@@ -19,7 +20,8 @@ type Repository struct {
 func New(storagePath string) (*Repository, error) {
 	const op = "repository.sqlite.New"
 
-	db, err := sql.Open("sqlite3", storagePath)
+	// foreign keys are off in SQLite by default, without them deletes don't cascade
+	db, err := sql.Open("sqlite3", storagePath+"?_foreign_keys=on")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -31,64 +33,16 @@ func (s *Repository) Stop() error {
 	return s.db.Close()
 }
 
-func (s *Repository) CreateApp(
-	ctx context.Context,
-	name string,
-	secret []byte,
-) (int, error) {
-	const op = "repository.sqlite.CreateApp"
-
-	stmt, err := s.db.Prepare(`INSERT INTO apps (name, secret) VALUES (?, ?)`)
-	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
-	}
-
-	res, err := stmt.ExecContext(ctx, name, secret)
-	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
-	}
-
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
-	}
-
-	return int(id), nil
-}
-
-func (s *Repository) UpdateApp(
-	ctx context.Context,
-	id int,
-	name string,
-	secret []byte,
-) error {
-	const op = "repository.sqlite.UpdateApp"
-
-	_, err := s.db.ExecContext(
-		ctx,
-		`UPDATE apps SET name = ?, secret = ? WHERE id = ?`,
-		name,
-		secret,
-		id,
-	)
-
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	return nil
-}
-
 // SaveUser saves user to db.
 func (s *Repository) SaveUser(ctx context.Context, title string, birthDate string, name string, lastName string, email string, passHash []byte, phone string) (int64, error) {
 	const op = "repository.sqlite.SaveUser"
 
-	stmt, err := s.db.Prepare("INSERT INTO users(title, birth_date, name, last_name, email, pass_hash, phone) VALUES(?, ?, ?, ?, ?, ?, ?)")
+	stmt, err := s.db.Prepare("INSERT INTO users(title, birth_date, name, last_name, email, pass_hash, phone, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := stmt.ExecContext(ctx, title, birthDate, name, lastName, email, passHash, phone)
+	res, err := stmt.ExecContext(ctx, title, birthDate, name, lastName, email, passHash, phone, time.Now().UTC())
 	if err != nil {
 		var sqliteErr sqlite3.Error
 		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
@@ -109,15 +63,15 @@ func (s *Repository) SaveUser(ctx context.Context, title string, birthDate strin
 func (s *Repository) User(ctx context.Context, email string, phone string) (models.User, error) {
 	const op = "repository.sqlite.User"
 
-	stmt, err := s.db.Prepare("SELECT id, title, birth_date, name, last_name, email, pass_hash, phone FROM users WHERE email = ? OR phone = ? LIMIT 1")
+	// empty values must not match users who have no phone
+	stmt, err := s.db.Prepare("SELECT " + userColumns + " FROM users WHERE (? != '' AND email = ?) OR (? != '' AND phone = ?) LIMIT 1")
 	if err != nil {
 		return models.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowContext(ctx, email, phone)
+	row := stmt.QueryRowContext(ctx, email, email, phone, phone)
 
-	var user models.User
-	err = row.Scan(&user.ID, &user.Title, &user.BirthDate, &user.Name, &user.LastName, &user.Email, &user.PassHash, &user.Phone)
+	user, err := scanUser(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
@@ -128,40 +82,38 @@ func (s *Repository) User(ctx context.Context, email string, phone string) (mode
 	return user, nil
 }
 
-func (s *Repository) SetPassword(ctx context.Context, email string, newPassword []byte) (bool, error) {
+func (s *Repository) SetPassword(ctx context.Context, userID int64, newPassword []byte) (bool, error) {
 	const op = "repository.sqlite.ChangePassword"
 
-	stmt, err := s.db.Prepare("UPDATE users SET pass_hash = ? WHERE email = ?")
-
+	res, err := s.db.ExecContext(ctx, "UPDATE users SET pass_hash = ? WHERE id = ?", newPassword, userID)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	//defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, newPassword, email)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
-		}
-		return false, fmt.Errorf("%s: %w", op, err)
+	if err := checkAffected(res, op, repository.ErrUserNotFound); err != nil {
+		return false, err
 	}
 
 	return true, nil
 }
 
-// todo: rearrange db structure,taking isAdmin field out of users column when selecting rights
-
-func (s *Repository) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+// IsAdmin checks if the user holds the admin permission in the app.
+// It is kept for compatibility with clients that predate roles, use HasPermission instead.
+func (s *Repository) IsAdmin(ctx context.Context, userID int64, appID int) (bool, error) {
 	const op = "repository.sqlite.IsAdmin"
 
-	stmt, err := s.db.Prepare("SELECT is_admin FROM users WHERE id = ?")
+	stmt, err := s.db.Prepare(`SELECT EXISTS (
+			SELECT 1 FROM user_roles ur
+			JOIN role_permissions rp ON rp.role_id = ur.role_id
+			JOIN permissions p ON p.id = rp.permission_id
+			WHERE ur.user_id = u.id AND p.app_id = ? AND p.name = ?
+		)
+		FROM users u WHERE u.id = ?`)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowContext(ctx, userID)
+	row := stmt.QueryRowContext(ctx, appID, models.PermissionAdmin, userID)
 	var isAdmin bool
 	err = row.Scan(&isAdmin)
 	if err != nil {
@@ -174,24 +126,68 @@ func (s *Repository) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 	return isAdmin, nil
 }
 
-func (s *Repository) App(ctx context.Context, id int) (models.App, error) {
-	const op = "repository.sqlite.App"
+// LockoutState returns failed login attempts of the user. If the user has none, returns empty state.
+func (s *Repository) LockoutState(ctx context.Context, userID int64) (models.LockoutState, error) {
+	const op = "repository.sqlite.LockoutState"
 
-	stmt, err := s.db.Prepare("SELECT id, name, secret FROM apps WHERE id = ?")
+	stmt, err := s.db.Prepare(`SELECT failed_attempts, first_failed_at, last_failed_at, locked_until
+		FROM login_attempts WHERE user_id = ?`)
 	if err != nil {
-		return models.App{}, fmt.Errorf("%s: %w", op, err)
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowContext(ctx, id)
+	row := stmt.QueryRowContext(ctx, userID)
 
-	var app models.App
-	err = row.Scan(&app.ID, &app.Name, &app.SecretHash)
+	state := models.LockoutState{UserID: userID}
+	var firstFailedAt sql.NullTime
+	err = row.Scan(&state.FailedAttempts, &firstFailedAt, &state.LastFailedAt, &state.LockedUntil)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return models.App{}, fmt.Errorf("%s: %w", op, repository.ErrAppNotFound)
+			return state, nil
 		}
 
-		return models.App{}, fmt.Errorf("%s: %w", op, err)
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	state.FirstFailedAt = state.LastFailedAt
+	if firstFailedAt.Valid {
+		state.FirstFailedAt = firstFailedAt.Time
+	}
+
+	return state, nil
+}
+
+func (s *Repository) SaveLockoutState(ctx context.Context, state models.LockoutState) error {
+	const op = "repository.sqlite.SaveLockoutState"
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO login_attempts (user_id, failed_attempts, first_failed_at, last_failed_at, locked_until) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			failed_attempts = excluded.failed_attempts,
+			first_failed_at = excluded.first_failed_at,
+			last_failed_at = excluded.last_failed_at,
+			locked_until = excluded.locked_until`,
+		state.UserID,
+		state.FailedAttempts,
+		state.FirstFailedAt.UTC(),
+		state.LastFailedAt.UTC(),
+		state.LockedUntil.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	return app, nil
+
+	return nil
+}
+
+func (s *Repository) ResetLockoutState(ctx context.Context, userID int64) error {
+	const op = "repository.sqlite.ResetLockoutState"
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM login_attempts WHERE user_id = ?", userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
 }
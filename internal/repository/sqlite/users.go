@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const userColumns = "id, title, birth_date, name, last_name, email, email_verified, pass_hash, phone, created_at, status, status_reason, status_changed_at, tokens_valid_after, delete_after"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (models.User, error) {
+	var (
+		user             models.User
+		createdAt        sql.NullTime
+		statusChangedAt  sql.NullTime
+		tokensValidAfter sql.NullTime
+		deleteAfter      sql.NullTime
+	)
+
+	err := row.Scan(
+		&user.ID,
+		&user.Title,
+		&user.BirthDate,
+		&user.Name,
+		&user.LastName,
+		&user.Email,
+		&user.EmailVerified,
+		&user.PassHash,
+		&user.Phone,
+		&createdAt,
+		&user.Status,
+		&user.StatusReason,
+		&statusChangedAt,
+		&tokensValidAfter,
+		&deleteAfter,
+	)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user.CreatedAt = createdAt.Time
+	user.StatusChangedAt = statusChangedAt.Time
+	user.TokensValidAfter = tokensValidAfter.Time
+	user.DeleteAfter = deleteAfter.Time
+
+	return user, nil
+}
+
+func (s *Repository) UserByID(ctx context.Context, id int64) (models.User, error) {
+	const op = "repository.sqlite.UserByID"
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = ?", id)
+
+	user, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, repository.ErrUserNotFound)
+		}
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// Users returns a page of users matching the filter and the number of matching users on all pages.
+func (s *Repository) Users(ctx context.Context, filter models.UserFilter) ([]models.User, int64, error) {
+	const op = "repository.sqlite.Users"
+
+	var (
+		where []string
+		args  []any
+	)
+	if filter.Email != "" {
+		where = append(where, "email LIKE ?")
+		args = append(args, "%"+filter.Email+"%")
+	}
+	if filter.Phone != "" {
+		where = append(where, "phone LIKE ?")
+		args = append(args, "%"+filter.Phone+"%")
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		where = append(where, "created_at > ?")
+		args = append(args, filter.CreatedAfter.UTC())
+	}
+	if !filter.CreatedBefore.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, filter.CreatedBefore.UTC())
+	}
+
+	cond := ""
+	if len(where) > 0 {
+		cond = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+cond, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		"SELECT "+userColumns+" FROM users"+cond+" ORDER BY id LIMIT ? OFFSET ?",
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, total, nil
+}
+
+// UpdateUser saves profile fields of the user along with whether the email is verified
+// and when tokens of the user were revoked.
+func (s *Repository) UpdateUser(ctx context.Context, user models.User) error {
+	const op = "repository.sqlite.UpdateUser"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE users SET title = ?, birth_date = ?, name = ?, last_name = ?, email = ?, email_verified = ?, phone = ?,
+			tokens_valid_after = ?
+		WHERE id = ?`,
+		user.Title,
+		user.BirthDate,
+		user.Name,
+		user.LastName,
+		user.Email,
+		user.EmailVerified,
+		user.Phone,
+		sql.NullTime{Time: user.TokensValidAfter.UTC(), Valid: !user.TokensValidAfter.IsZero()},
+		user.ID,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, repository.ErrUserExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrUserNotFound)
+}
+
+// SetUserStatus changes the status of the user, unless it was changed from the from status meanwhile.
+func (s *Repository) SetUserStatus(
+	ctx context.Context,
+	userID int64,
+	from string,
+	to string,
+	reason string,
+	changedAt time.Time,
+) error {
+	const op = "repository.sqlite.SetUserStatus"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		"UPDATE users SET status = ?, status_reason = ?, status_changed_at = ? WHERE id = ? AND status = ?",
+		to, reason, changedAt.UTC(), userID, from,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrUserNotFound)
+}
+
+// DeleteUser deletes the user, rows referencing the user are deleted by cascade.
+func (s *Repository) DeleteUser(ctx context.Context, id int64) error {
+	const op = "repository.sqlite.DeleteUser"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checkAffected(res, op, repository.ErrUserNotFound)
+}
+
+// checkAffected returns notFound if the statement didn't change any row.
+func checkAffected(res sql.Result, op string, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, notFound)
+	}
+
+	return nil
+}
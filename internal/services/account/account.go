@@ -0,0 +1,158 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/profile"
+	"sso/internal/repository"
+	"sso/internal/services"
+	"time"
+)
+
+// Account lets users manage their own accounts.
+type Account struct {
+	log          *slog.Logger
+	repo         Repository
+	dataProvider DataProvider
+	deletionRepo DeletionRepository
+	auditLog     AuditLog
+	grantRepo    GrantRepository
+	emailService *services.EmailService
+	deletion     config.AccountDeletionConfig
+}
+
+type Repository interface {
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	UpdateProfile(ctx context.Context, user models.User, changes []models.ProfileChange) error
+}
+
+type AuditLog interface {
+	SaveAuditEntry(ctx context.Context, entry models.AuditEntry) (int64, error)
+}
+
+var (
+	ErrInvalidProfile     = profile.ErrInvalidProfile
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrPermissionDenied   = errors.New("permission denied")
+)
+
+// New returns a new instance of the Account service.
+func New(
+	log *slog.Logger,
+	repo Repository,
+	dataProvider DataProvider,
+	deletionRepo DeletionRepository,
+	auditLog AuditLog,
+	grantRepo GrantRepository,
+	emailService *services.EmailService,
+	deletion config.AccountDeletionConfig,
+) *Account {
+	return &Account{
+		log:          log,
+		repo:         repo,
+		dataProvider: dataProvider,
+		deletionRepo: deletionRepo,
+		auditLog:     auditLog,
+		grantRepo:    grantRepo,
+		emailService: emailService,
+		deletion:     deletion,
+	}
+}
+
+// Profile returns the caller's user.
+func (a *Account) Profile(ctx context.Context, caller models.Principal) (models.User, error) {
+	const op = "account.Profile"
+
+	user, err := a.repo.UserByID(ctx, caller.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		a.log.Error("failed to get user", slog.String("op", op), sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// UpdateProfile overwrites profile fields of the caller with non-empty fields of upd
+// and records every changed field in the history. The email and phone are only changed
+// by confirming the new ones. Changes made while impersonating
+// are recorded as made by the actor.
+func (a *Account) UpdateProfile(ctx context.Context, caller models.Principal, upd models.User) (models.User, error) {
+	const op = "account.UpdateProfile"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+		slog.Int64("actorID", caller.ActorID),
+	)
+
+	if err := profile.Validate(upd); err != nil {
+		log.Warn("invalid profile", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.Profile(ctx, caller)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	changedBy := caller.UserID
+	if caller.ActorID != 0 {
+		changedBy = caller.ActorID
+	}
+	now := time.Now()
+
+	var changes []models.ProfileChange
+	for _, f := range []struct {
+		name     string
+		dst, src *string
+	}{
+		{models.ProfileFieldTitle, &user.Title, &upd.Title},
+		{models.ProfileFieldBirthDate, &user.BirthDate, &upd.BirthDate},
+		{models.ProfileFieldName, &user.Name, &upd.Name},
+		{models.ProfileFieldLastName, &user.LastName, &upd.LastName},
+	} {
+		if *f.src == "" || *f.src == *f.dst {
+			continue
+		}
+
+		changes = append(changes, models.ProfileChange{
+			UserID:    user.ID,
+			ChangedBy: changedBy,
+			Field:     f.name,
+			OldValue:  *f.dst,
+			NewValue:  *f.src,
+			ChangedAt: now,
+		})
+		*f.dst = *f.src
+	}
+
+	if len(changes) == 0 {
+		return user, nil
+	}
+
+	if err := a.repo.UpdateProfile(ctx, user, changes); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to update profile", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("profile updated", slog.Int("changes", len(changes)))
+
+	return user, nil
+}
@@ -0,0 +1,219 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/handlers/slogdiscard"
+	"sso/internal/repository"
+	"sso/internal/repository/sqlite"
+	"sso/internal/services"
+	"sso/internal/services/email"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const migrationsPath = "../../../migrations"
+
+type testEnv struct {
+	account *Account
+	purger  *Purger
+	repo    *sqlite.Repository
+	appID   int
+}
+
+type nopSender struct{}
+
+func (nopSender) Send(email.SendEmailInput) error { return nil }
+
+// newTestEnv returns Account and Purger over a migrated storage of its own with an app.
+func newTestEnv(t *testing.T, deletion config.AccountDeletionConfig) testEnv {
+	t.Helper()
+
+	storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://"+migrationsPath, "sqlite3://"+storagePath)
+	if err != nil {
+		t.Fatalf("open migrations: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	m.Close()
+
+	repo, err := sqlite.New(storagePath)
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	t.Cleanup(func() { repo.Stop() })
+
+	log := slogdiscard.NewDiscardLogger()
+	emails, _ := services.NewEmailService(log, nopSender{}, config.EmailConfig{
+		Templates: config.EmailTemplate{AccountStatus: "../../../templates/account_status_email.html"},
+	})
+
+	appID, err := repo.CreateApp(context.Background(), models.App{
+		Name:       "test-app",
+		SecretHash: []byte("test-app-secret"),
+	})
+	if err != nil {
+		t.Fatalf("create app: %v", err)
+	}
+
+	return testEnv{
+		account: New(log, repo, repo, repo, repo, repo, emails, deletion),
+		purger:  NewPurger(log, repo, repo, deletion),
+		repo:    repo,
+		appID:   appID,
+	}
+}
+
+// caller saves a user with the password and returns their principal in the app.
+func (e testEnv) caller(t *testing.T, email string, password string) models.Principal {
+	t.Helper()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := e.repo.SaveUser(context.Background(), "", "", "Test", "User", email, passHash, "")
+	if err != nil {
+		t.Fatalf("save user %s: %v", email, err)
+	}
+
+	return models.Principal{Type: models.PrincipalUser, UserID: id, Email: email, AppID: e.appID}
+}
+
+func TestRequestAccountDeletion(t *testing.T) {
+	env := newTestEnv(t, config.AccountDeletionConfig{GracePeriod: time.Hour})
+	caller := env.caller(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	impersonated := caller
+	impersonated.ActorID = 1
+	if _, err := env.account.RequestAccountDeletion(ctx, impersonated, "password"); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("RequestAccountDeletion() while impersonating error = %v, want %v", err, ErrPermissionDenied)
+	}
+	if _, err := env.account.RequestAccountDeletion(ctx, caller, "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("RequestAccountDeletion() with a wrong password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	deleteAfter, err := env.account.RequestAccountDeletion(ctx, caller, "password")
+	if err != nil {
+		t.Fatalf("RequestAccountDeletion() error = %v", err)
+	}
+	if d := time.Until(deleteAfter); d <= 0 || d > time.Hour {
+		t.Errorf("RequestAccountDeletion() = %v, want the grace period from now", deleteAfter)
+	}
+
+	again, err := env.account.RequestAccountDeletion(ctx, caller, "password")
+	if err != nil {
+		t.Fatalf("second RequestAccountDeletion() error = %v", err)
+	}
+	if again.Sub(deleteAfter).Abs() >= time.Second {
+		t.Errorf("second RequestAccountDeletion() = %v, want the scheduled %v", again, deleteAfter)
+	}
+
+	user, err := env.repo.UserByID(ctx, caller.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Status != models.UserStatusDeleted || !user.DeletionPending(time.Now()) {
+		t.Errorf("user status = %q, delete after %v, want the deletion pending", user.Status, user.DeleteAfter)
+	}
+
+	// nothing is due during the grace period
+	if purged, err := env.purger.Purge(ctx); err != nil || purged != 0 {
+		t.Errorf("Purge() = %d, %v, want nothing purged", purged, err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	env := newTestEnv(t, config.AccountDeletionConfig{})
+	ctx := context.Background()
+
+	deleted := env.caller(t, "deleted@example.com", "password")
+	kept := env.caller(t, "kept@example.com", "password")
+
+	if _, err := env.account.RequestAccountDeletion(ctx, deleted, "password"); err != nil {
+		t.Fatalf("RequestAccountDeletion() error = %v", err)
+	}
+
+	purged, err := env.purger.Purge(ctx)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Purge() = %d, want 1", purged)
+	}
+
+	if _, err := env.repo.UserByID(ctx, deleted.UserID); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("UserByID() of the purged user error = %v, want %v", err, repository.ErrUserNotFound)
+	}
+	if _, err := env.repo.UserByID(ctx, kept.UserID); err != nil {
+		t.Errorf("UserByID() of another user error = %v", err)
+	}
+
+	entries, err := env.repo.AuditEntries(ctx, deleted.UserID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || entries[0].Action != models.AuditActionPurge {
+		t.Errorf("AuditEntries() = %+v, want the purge recorded last", entries)
+	}
+}
+
+func TestPurgeAnonymize(t *testing.T) {
+	env := newTestEnv(t, config.AccountDeletionConfig{Anonymize: true})
+	ctx := context.Background()
+
+	caller := env.caller(t, "deleted@example.com", "password")
+
+	role, err := env.repo.Role(ctx, env.appID, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.AssignRole(ctx, caller.UserID, role.ID); err != nil {
+		t.Fatal(err)
+	}
+	orgID, err := env.repo.CreateOrganization(ctx, models.Organization{Name: "acme", CreatedAt: time.Now()}, caller.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.account.RequestAccountDeletion(ctx, caller, "password"); err != nil {
+		t.Fatalf("RequestAccountDeletion() error = %v", err)
+	}
+	if purged, err := env.purger.Purge(ctx); err != nil || purged != 1 {
+		t.Fatalf("Purge() = %d, %v, want 1 purged", purged, err)
+	}
+
+	user, err := env.repo.UserByID(ctx, caller.UserID)
+	if err != nil {
+		t.Fatalf("UserByID() of the anonymized user error = %v", err)
+	}
+	if user.Email == caller.Email || user.Name != "" || user.LastName != "" || len(user.PassHash) != 0 {
+		t.Errorf("anonymized user = %q %q %q, want personal data erased", user.Email, user.Name, user.LastName)
+	}
+	if user.Status != models.UserStatusDeleted || !user.DeleteAfter.IsZero() {
+		t.Errorf("anonymized user status = %q, delete after %v, want deleted for good", user.Status, user.DeleteAfter)
+	}
+
+	if roles, _ := env.repo.UserRoles(ctx, caller.UserID, env.appID); len(roles) != 0 {
+		t.Errorf("roles of the anonymized user = %v, want none", roles)
+	}
+	if _, err := env.repo.OrgMember(ctx, orgID, caller.UserID); !errors.Is(err, repository.ErrNotOrgMember) {
+		t.Errorf("OrgMember() of the anonymized user error = %v, want %v", err, repository.ErrNotOrgMember)
+	}
+	if _, err := env.repo.User(ctx, caller.Email, ""); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("User() by the erased email error = %v, want %v", err, repository.ErrUserNotFound)
+	}
+}
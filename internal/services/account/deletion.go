@@ -0,0 +1,96 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"sso/internal/services"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type DeletionRepository interface {
+	ScheduleUserDeletion(ctx context.Context, userID int64, deleteAfter time.Time, requestedAt time.Time) error
+	UsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	PurgeUser(ctx context.Context, userID int64, now time.Time) error
+	AnonymizeUser(ctx context.Context, userID int64, now time.Time) error
+}
+
+// RequestAccountDeletion schedules the caller's account to be purged once the grace period is over,
+// revokes the caller's tokens and notifies the caller by email. Logging in during the grace period
+// cancels the deletion. Repeated requests keep the scheduled time. The caller confirms the request
+// with the password, it can't be made while impersonating.
+func (a *Account) RequestAccountDeletion(ctx context.Context, caller models.Principal, password string) (time.Time, error) {
+	const op = "account.RequestAccountDeletion"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+	)
+
+	if caller.ActorID != 0 {
+		log.Warn("account deletion requested while impersonating", slog.Int64("actorID", caller.ActorID))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	user, err := a.Profile(ctx, caller)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
+		log.Info("invalid password", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	now := time.Now()
+	if user.DeletionPending(now) {
+		return user.DeleteAfter, nil
+	}
+
+	// the deletion is only scheduled once the request is on record
+	_, err = a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      user.ID,
+		Action:       models.AuditActionRequestDeletion,
+		TargetUserID: user.ID,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		log.Error("failed to save audit entry", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleteAfter := now.Add(a.deletion.GracePeriod)
+	if err := a.deletionRepo.ScheduleUserDeletion(ctx, user.ID, deleteAfter, now); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return time.Time{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to schedule account deletion", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the notice lets the user cancel the deletion nobody but the user should have requested
+	err = a.emailService.SendAccountStatusEmail(services.AccountStatusEmailInput{
+		Email:       user.Email,
+		Name:        user.Name,
+		Status:      models.UserStatusDeleted,
+		DeleteAfter: deleteAfter.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		log.Error("failed to send account status email", sl.Err(err))
+	}
+
+	log.Warn("account deletion requested", slog.Time("deleteAfter", deleteAfter))
+
+	return deleteAfter, nil
+}
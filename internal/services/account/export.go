@@ -0,0 +1,370 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"time"
+)
+
+// maxExportedEntries bounds history entries of each kind in the export.
+const maxExportedEntries = 100000
+
+type DataProvider interface {
+	Apps(ctx context.Context) ([]models.App, error)
+	UserRoles(ctx context.Context, userID int64, appID int) ([]string, error)
+	UserAttributes(ctx context.Context, userID int64, appID int) (models.Attributes, error)
+	UserGroups(ctx context.Context, userID int64) ([]models.Group, error)
+	Organizations(ctx context.Context, userID int64) ([]models.Organization, error)
+	OrgMember(ctx context.Context, orgID int64, userID int64) (models.OrgMember, error)
+	APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	Consents(ctx context.Context, userID int64) ([]models.Consent, error)
+	Grants(ctx context.Context, userID int64) ([]models.Grant, error)
+	Identities(ctx context.Context, userID int64) ([]models.Identity, error)
+	LockoutState(ctx context.Context, userID int64) (models.LockoutState, error)
+	ProfileChanges(ctx context.Context, userID int64, limit int) ([]models.ProfileChange, error)
+	AuditEntries(ctx context.Context, userID int64, limit int) ([]models.AuditEntry, error)
+}
+
+type userData struct {
+	ExportedAt     time.Time           `json:"exported_at"`
+	Profile        profileData         `json:"profile"`
+	Sessions       sessionsData        `json:"sessions"`
+	Roles          []appRolesData      `json:"roles"`
+	Attributes     []appAttributesData `json:"attributes"`
+	Groups         []string            `json:"groups"`
+	Organizations  []orgData           `json:"organizations"`
+	APIKeys        []apiKeyData        `json:"api_keys"`
+	Consents       []consentData       `json:"consents"`
+	AuthorizedApps []grantData         `json:"authorized_apps"`
+	Identities     []identityData      `json:"identities"`
+	ProfileChanges []profileChangeData `json:"profile_changes"`
+	AuditLog       []auditEntryData    `json:"audit_log"`
+}
+
+type profileData struct {
+	ID            int64      `json:"id"`
+	Title         string     `json:"title"`
+	Name          string     `json:"name"`
+	LastName      string     `json:"last_name"`
+	BirthDate     string     `json:"birth_date"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified"`
+	Phone         string     `json:"phone"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Status        string     `json:"status"`
+	StatusReason  string     `json:"status_reason,omitempty"`
+	DeleteAfter   *time.Time `json:"delete_after,omitempty"`
+}
+
+// sessionsData is what is stored about sign-ins, access tokens themselves aren't stored.
+type sessionsData struct {
+	TokensValidAfter *time.Time `json:"tokens_valid_after,omitempty"`
+	FailedAttempts   int        `json:"failed_login_attempts"`
+	LastFailedAt     *time.Time `json:"last_failed_login_at,omitempty"`
+	LockedUntil      *time.Time `json:"locked_until,omitempty"`
+}
+
+type appRolesData struct {
+	AppID   int      `json:"app_id"`
+	AppName string   `json:"app_name"`
+	Roles   []string `json:"roles"`
+}
+
+type appAttributesData struct {
+	AppID      int               `json:"app_id"`
+	AppName    string            `json:"app_name"`
+	Attributes models.Attributes `json:"attributes"`
+}
+
+type orgData struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type apiKeyData struct {
+	ID         int64      `json:"id"`
+	AppID      int        `json:"app_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type consentData struct {
+	DocumentType    string    `json:"document_type"`
+	DocumentVersion string    `json:"document_version"`
+	DocumentURL     string    `json:"document_url"`
+	AcceptedAt      time.Time `json:"accepted_at"`
+	IP              string    `json:"ip,omitempty"`
+}
+
+type grantData struct {
+	AppID     int       `json:"app_id"`
+	AppName   string    `json:"app_name"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type identityData struct {
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+type profileChangeData struct {
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	ChangedBy int64     `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type auditEntryData struct {
+	ActorID      int64     `json:"actor_id"`
+	Action       string    `json:"action"`
+	TargetUserID int64     `json:"target_user_id,omitempty"`
+	AppID        int       `json:"app_id,omitempty"`
+	Details      string    `json:"details,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ExportMyData returns everything stored about the caller as a JSON document:
+// the profile, sign-in state, roles and attributes in every app, groups, organizations, API keys
+// without secrets, accepted legal documents, apps granted access,
+// linked identity provider accounts, profile history and audit entries
+// the caller is the actor or target of.
+// It can't be done while impersonating.
+func (a *Account) ExportMyData(ctx context.Context, caller models.Principal) ([]byte, error) {
+	const op = "account.ExportMyData"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+	)
+
+	if caller.ActorID != 0 {
+		log.Warn("data export requested while impersonating", slog.Int64("actorID", caller.ActorID))
+
+		return nil, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	user, err := a.Profile(ctx, caller)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	data, err := a.collectUserData(ctx, user)
+	if err != nil {
+		log.Error("failed to collect user data", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	archive, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user data exported")
+
+	return archive, nil
+}
+
+func (a *Account) collectUserData(ctx context.Context, user models.User) (userData, error) {
+	data := userData{
+		ExportedAt: time.Now().UTC(),
+		Profile: profileData{
+			ID:            user.ID,
+			Title:         user.Title,
+			Name:          user.Name,
+			LastName:      user.LastName,
+			BirthDate:     user.BirthDate,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Phone:         user.Phone,
+			CreatedAt:     user.CreatedAt,
+			Status:        user.Status,
+			StatusReason:  user.StatusReason,
+			DeleteAfter:   optionalTime(user.DeleteAfter),
+		},
+		Roles:          []appRolesData{},
+		Attributes:     []appAttributesData{},
+		Groups:         []string{},
+		Organizations:  []orgData{},
+		APIKeys:        []apiKeyData{},
+		Consents:       []consentData{},
+		AuthorizedApps: []grantData{},
+		Identities:     []identityData{},
+		ProfileChanges: []profileChangeData{},
+		AuditLog:       []auditEntryData{},
+	}
+
+	lockout, err := a.dataProvider.LockoutState(ctx, user.ID)
+	if err != nil {
+		return userData{}, fmt.Errorf("lockout state: %w", err)
+	}
+	data.Sessions = sessionsData{
+		TokensValidAfter: optionalTime(user.TokensValidAfter),
+		FailedAttempts:   lockout.FailedAttempts,
+		LastFailedAt:     optionalTime(lockout.LastFailedAt),
+		LockedUntil:      optionalTime(lockout.LockedUntil),
+	}
+
+	apps, err := a.dataProvider.Apps(ctx)
+	if err != nil {
+		return userData{}, fmt.Errorf("apps: %w", err)
+	}
+	for _, app := range apps {
+		roles, err := a.dataProvider.UserRoles(ctx, user.ID, app.ID)
+		if err != nil {
+			return userData{}, fmt.Errorf("roles in app %d: %w", app.ID, err)
+		}
+		if len(roles) > 0 {
+			data.Roles = append(data.Roles, appRolesData{AppID: app.ID, AppName: app.Name, Roles: roles})
+		}
+
+		attrs, err := a.dataProvider.UserAttributes(ctx, user.ID, app.ID)
+		if err != nil {
+			return userData{}, fmt.Errorf("attributes in app %d: %w", app.ID, err)
+		}
+		if len(attrs) > 0 {
+			data.Attributes = append(data.Attributes, appAttributesData{AppID: app.ID, AppName: app.Name, Attributes: attrs})
+		}
+	}
+
+	groups, err := a.dataProvider.UserGroups(ctx, user.ID)
+	if err != nil {
+		return userData{}, fmt.Errorf("groups: %w", err)
+	}
+	for _, group := range groups {
+		data.Groups = append(data.Groups, group.Name)
+	}
+
+	orgs, err := a.dataProvider.Organizations(ctx, user.ID)
+	if err != nil {
+		return userData{}, fmt.Errorf("organizations: %w", err)
+	}
+	for _, org := range orgs {
+		member, err := a.dataProvider.OrgMember(ctx, org.ID, user.ID)
+		if err != nil {
+			// the user left the organization meanwhile
+			if errors.Is(err, repository.ErrNotOrgMember) {
+				continue
+			}
+
+			return userData{}, fmt.Errorf("membership in organization %d: %w", org.ID, err)
+		}
+		data.Organizations = append(data.Organizations, orgData{ID: org.ID, Name: org.Name, Role: member.Role})
+	}
+
+	keys, err := a.dataProvider.APIKeys(ctx, user.ID)
+	if err != nil {
+		return userData{}, fmt.Errorf("api keys: %w", err)
+	}
+	for _, key := range keys {
+		data.APIKeys = append(data.APIKeys, apiKeyData{
+			ID:         key.ID,
+			AppID:      key.AppID,
+			Name:       key.Name,
+			Prefix:     key.Prefix,
+			Scopes:     key.Scopes,
+			CreatedAt:  key.CreatedAt,
+			ExpiresAt:  key.ExpiresAt,
+			LastUsedAt: optionalTime(key.LastUsedAt),
+		})
+	}
+
+	consents, err := a.dataProvider.Consents(ctx, user.ID)
+	if err != nil {
+		return userData{}, fmt.Errorf("consents: %w", err)
+	}
+	for _, c := range consents {
+		data.Consents = append(data.Consents, consentData{
+			DocumentType:    c.Document.Type,
+			DocumentVersion: c.Document.Version,
+			DocumentURL:     c.Document.URL,
+			AcceptedAt:      c.AcceptedAt,
+			IP:              c.IP,
+		})
+	}
+
+	grants, err := a.dataProvider.Grants(ctx, user.ID)
+	if err != nil {
+		return userData{}, fmt.Errorf("grants: %w", err)
+	}
+	for _, g := range grants {
+		data.AuthorizedApps = append(data.AuthorizedApps, grantData{
+			AppID:     g.AppID,
+			AppName:   g.AppName,
+			Scopes:    g.Scopes,
+			CreatedAt: g.CreatedAt,
+			UpdatedAt: g.UpdatedAt,
+		})
+	}
+
+	identities, err := a.dataProvider.Identities(ctx, user.ID)
+	if err != nil {
+		return userData{}, fmt.Errorf("identities: %w", err)
+	}
+	for _, i := range identities {
+		data.Identities = append(data.Identities, identityData{
+			Provider:    i.Provider,
+			Subject:     i.Subject,
+			Email:       i.Email,
+			CreatedAt:   i.CreatedAt,
+			LastLoginAt: i.LastLoginAt,
+		})
+	}
+
+	changes, err := a.dataProvider.ProfileChanges(ctx, user.ID, maxExportedEntries)
+	if err != nil {
+		return userData{}, fmt.Errorf("profile changes: %w", err)
+	}
+	for _, c := range changes {
+		data.ProfileChanges = append(data.ProfileChanges, profileChangeData{
+			Field:     c.Field,
+			OldValue:  c.OldValue,
+			NewValue:  c.NewValue,
+			ChangedBy: c.ChangedBy,
+			ChangedAt: c.ChangedAt,
+		})
+	}
+
+	entries, err := a.dataProvider.AuditEntries(ctx, user.ID, maxExportedEntries)
+	if err != nil {
+		return userData{}, fmt.Errorf("audit entries: %w", err)
+	}
+	for _, e := range entries {
+		data.AuditLog = append(data.AuditLog, auditEntryData{
+			ActorID:      e.ActorID,
+			Action:       e.Action,
+			TargetUserID: e.TargetUserID,
+			AppID:        e.AppID,
+			Details:      e.Details,
+			CreatedAt:    e.CreatedAt,
+		})
+	}
+
+	return data, nil
+}
+
+// optionalTime returns nil for zero t, so that unset times are omitted from the export.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
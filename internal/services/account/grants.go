@@ -0,0 +1,205 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"strings"
+	"time"
+)
+
+type GrantRepository interface {
+	App(ctx context.Context, appID int) (models.App, error)
+	Grant(ctx context.Context, userID int64, appID int) (models.Grant, error)
+	Grants(ctx context.Context, userID int64) ([]models.Grant, error)
+	SaveGrant(ctx context.Context, grant models.Grant) error
+	RevokeGrant(ctx context.Context, userID int64, appID int, revokedAt time.Time) error
+}
+
+var (
+	ErrAppNotFound        = errors.New("app not found")
+	ErrInvalidRedirectURI = errors.New("redirect uri isn't registered for the app")
+	ErrInvalidScope       = errors.New("invalid scope")
+	ErrGrantNotFound      = errors.New("app has no access")
+)
+
+// ConsentRequiredError is returned by AuthorizeApp when the user must approve the scopes
+// on the consent screen. Scopes are all requested scopes, Missing are those not granted before.
+type ConsentRequiredError struct {
+	App     models.App
+	Scopes  []string
+	Missing []string
+}
+
+func (e *ConsentRequiredError) Error() string {
+	return fmt.Sprintf("consent required for scopes %s", strings.Join(e.Missing, " "))
+}
+
+// AuthorizeApp is the consent step of the authorization flow: it checks that the app
+// may send the caller to redirectURI and returns the caller's grant of the scopes.
+// Returning users who granted all the scopes before skip the consent screen, otherwise
+// ConsentRequiredError is returned unless approve tells the caller approved the scopes,
+// which are then added to the grant. It can't be done while impersonating.
+// Third-party apps only get tokens for scopes of the grant.
+func (a *Account) AuthorizeApp(
+	ctx context.Context,
+	caller models.Principal,
+	appID int,
+	redirectURI string,
+	scopes []string,
+	approve bool,
+) (models.Grant, error) {
+	const op = "account.AuthorizeApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+		slog.Int("appID", appID),
+	)
+
+	if caller.ActorID != 0 {
+		log.Warn("app authorized while impersonating", slog.Int64("actorID", caller.ActorID))
+
+		return models.Grant{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	for _, scope := range scopes {
+		if !models.ValidScope(scope) {
+			return models.Grant{}, fmt.Errorf("%s: %w: %q", op, ErrInvalidScope, scope)
+		}
+	}
+
+	app, err := a.grantRepo.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return models.Grant{}, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get app", sl.Err(err))
+
+		return models.Grant{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// only apps registered for the authorization code flow can ask users for access
+	if app.Disabled || !slices.Contains(app.GrantTypes, models.GrantTypeAuthorizationCode) {
+		return models.Grant{}, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+	}
+	if !slices.Contains(app.RedirectURIs, redirectURI) {
+		log.Warn("unregistered redirect uri", slog.String("redirectURI", redirectURI))
+
+		return models.Grant{}, fmt.Errorf("%s: %w", op, ErrInvalidRedirectURI)
+	}
+
+	grant, err := a.grantRepo.Grant(ctx, caller.UserID, app.ID)
+	if err != nil && !errors.Is(err, repository.ErrGrantNotFound) {
+		log.Error("failed to get grant", sl.Err(err))
+
+		return models.Grant{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	missing := grant.MissingScopes(scopes)
+	if grant.UserID != 0 && len(missing) == 0 {
+		return grant, nil
+	}
+
+	if !approve {
+		return models.Grant{}, fmt.Errorf("%s: %w", op, &ConsentRequiredError{
+			App:     app,
+			Scopes:  scopes,
+			Missing: missing,
+		})
+	}
+
+	now := time.Now()
+	if grant.UserID == 0 {
+		grant = models.Grant{UserID: caller.UserID, AppID: app.ID, CreatedAt: now}
+	}
+	grant.AppName = app.Name
+	grant.Scopes = append(grant.Scopes, missing...)
+	grant.UpdatedAt = now
+
+	if err := a.grantRepo.SaveGrant(ctx, grant); err != nil {
+		log.Error("failed to save grant", sl.Err(err))
+
+		return models.Grant{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      caller.UserID,
+		Action:       models.AuditActionGrantAppAccess,
+		TargetUserID: caller.UserID,
+		AppID:        app.ID,
+		Details:      strings.Join(missing, " "),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		log.Error("failed to save audit entry", sl.Err(err))
+	}
+
+	log.Info("app access granted", slog.Any("scopes", missing))
+
+	return grant, nil
+}
+
+// AuthorizedApps returns apps the caller granted access to, the latest granted first.
+func (a *Account) AuthorizedApps(ctx context.Context, caller models.Principal) ([]models.Grant, error) {
+	const op = "account.AuthorizedApps"
+
+	grants, err := a.grantRepo.Grants(ctx, caller.UserID)
+	if err != nil {
+		a.log.Error("failed to list grants", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return grants, nil
+}
+
+// RevokeAppAccess deletes the caller's grant to the app and revokes tokens the app got for the caller,
+// the app has to go through the consent screen again. It can't be done while impersonating.
+func (a *Account) RevokeAppAccess(ctx context.Context, caller models.Principal, appID int) error {
+	const op = "account.RevokeAppAccess"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+		slog.Int("appID", appID),
+	)
+
+	if caller.ActorID != 0 {
+		log.Warn("app access revoked while impersonating", slog.Int64("actorID", caller.ActorID))
+
+		return fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	now := time.Now()
+	if err := a.grantRepo.RevokeGrant(ctx, caller.UserID, appID, now); err != nil {
+		if errors.Is(err, repository.ErrGrantNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrGrantNotFound)
+		}
+
+		log.Error("failed to revoke grant", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      caller.UserID,
+		Action:       models.AuditActionRevokeAppAccess,
+		TargetUserID: caller.UserID,
+		AppID:        appID,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		log.Error("failed to save audit entry", sl.Err(err))
+	}
+
+	log.Info("app access revoked")
+
+	return nil
+}
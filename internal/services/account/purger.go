@@ -0,0 +1,141 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"time"
+)
+
+// purgeBatchSize bounds the number of accounts purged in one go.
+const purgeBatchSize = 100
+
+// Purger erases accounts whose deletion grace period is over.
+type Purger struct {
+	log          *slog.Logger
+	deletionRepo DeletionRepository
+	auditLog     AuditLog
+	deletion     config.AccountDeletionConfig
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+// NewPurger returns a new instance of the Purger.
+func NewPurger(
+	log *slog.Logger,
+	deletionRepo DeletionRepository,
+	auditLog AuditLog,
+	deletion config.AccountDeletionConfig,
+) *Purger {
+	return &Purger{
+		log:          log,
+		deletionRepo: deletionRepo,
+		auditLog:     auditLog,
+		deletion:     deletion,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Run purges accounts every purge interval until Stop is called.
+func (p *Purger) Run() {
+	const op = "account.Purger.Run"
+
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.deletion.PurgeInterval)
+	defer ticker.Stop()
+
+	p.log.Info("purger is running", slog.String("op", op), slog.Duration("interval", p.deletion.PurgeInterval))
+
+	for {
+		if _, err := p.Purge(context.Background()); err != nil {
+			p.log.Error("failed to purge accounts", slog.String("op", op), sl.Err(err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the purger and waits for the running purge to finish.
+func (p *Purger) Stop() {
+	const op = "account.Purger.Stop"
+
+	p.log.Info("stopping purger", slog.String("op", op))
+
+	close(p.stop)
+	<-p.done
+}
+
+// Purge deletes or anonymizes all accounts whose deletion grace period is over
+// and returns the number of purged accounts. Every purge is recorded in the audit log
+// as done by the user who requested it.
+func (p *Purger) Purge(ctx context.Context) (int, error) {
+	const op = "account.Purger.Purge"
+
+	log := p.log.With(slog.String("op", op))
+
+	purged := 0
+	for {
+		now := time.Now()
+
+		ids, err := p.deletionRepo.UsersDueForDeletion(ctx, now, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, id := range ids {
+			if err := p.purgeUser(ctx, id, now); err != nil {
+				if errors.Is(err, repository.ErrUserNotFound) {
+					log.Info("account deletion was cancelled", slog.Int64("userID", id))
+					continue
+				}
+
+				return purged, fmt.Errorf("%s: %w", op, err)
+			}
+
+			log.Warn("account purged", slog.Int64("userID", id), slog.Bool("anonymized", p.deletion.Anonymize))
+			purged++
+		}
+
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+func (p *Purger) purgeUser(ctx context.Context, userID int64, now time.Time) error {
+	purge := p.deletionRepo.PurgeUser
+	details := "deleted"
+	if p.deletion.Anonymize {
+		purge = p.deletionRepo.AnonymizeUser
+		details = "anonymized"
+	}
+
+	if err := purge(ctx, userID, now); err != nil {
+		return err
+	}
+
+	_, err := p.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      userID,
+		Action:       models.AuditActionPurge,
+		TargetUserID: userID,
+		Details:      details,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		// the account is gone already, failing the purge would only retry it in vain
+		p.log.Error("failed to save audit entry", slog.Int64("userID", userID), sl.Err(err))
+	}
+
+	return nil
+}
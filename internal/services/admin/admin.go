@@ -0,0 +1,304 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/phone"
+	"sso/internal/repository"
+	"sso/internal/services"
+	"time"
+)
+
+type Admin struct {
+	log                *slog.Logger
+	usrRepo            UserRepository
+	roleManager        RoleManager
+	lockoutProvider    LockoutProvider
+	appRepo            AppRepository
+	groupRepo          GroupRepository
+	policyRepo         PolicyRepository
+	saRepo             ServiceAccountRepository
+	saAPIKeyRepo       ServiceAccountAPIKeyRepository
+	auditRepo          AuditRepository
+	legalRepo          LegalDocumentRepository
+	iatRepo            InitialAccessTokenRepository
+	emailService       *services.EmailService
+	apiKeys            config.APIKeysConfig
+	clientRegistration config.ClientRegistrationConfig
+	phones             *phone.Normalizer
+}
+
+type UserRepository interface {
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	Users(ctx context.Context, filter models.UserFilter) ([]models.User, int64, error)
+	UpdateUser(ctx context.Context, user models.User) error
+	SetUserStatus(ctx context.Context, userID int64, from string, to string, reason string, changedAt time.Time) error
+	DeleteUser(ctx context.Context, id int64) error
+}
+
+type RoleManager interface {
+	UserRoles(ctx context.Context, userID int64, appID int) ([]string, error)
+	SetUserRoles(ctx context.Context, userID int64, appID int, roles []string) error
+}
+
+type LockoutProvider interface {
+	LockoutState(ctx context.Context, userID int64) (models.LockoutState, error)
+	ResetLockoutState(ctx context.Context, userID int64) error
+}
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user with this email or phone already exists")
+	ErrRoleNotFound = errors.New("role not found")
+	ErrInvalidPhone = phone.ErrInvalidPhone
+)
+
+// New returns a new instance of the Admin service.
+func New(
+	log *slog.Logger,
+	usrRepo UserRepository,
+	roleManager RoleManager,
+	lockoutProvider LockoutProvider,
+	appRepo AppRepository,
+	groupRepo GroupRepository,
+	policyRepo PolicyRepository,
+	saRepo ServiceAccountRepository,
+	saAPIKeyRepo ServiceAccountAPIKeyRepository,
+	auditRepo AuditRepository,
+	legalRepo LegalDocumentRepository,
+	iatRepo InitialAccessTokenRepository,
+	emailService *services.EmailService,
+	apiKeys config.APIKeysConfig,
+	phoneCfg config.PhoneConfig,
+	clientRegistration config.ClientRegistrationConfig,
+) *Admin {
+	return &Admin{
+		log:                log,
+		usrRepo:            usrRepo,
+		roleManager:        roleManager,
+		lockoutProvider:    lockoutProvider,
+		appRepo:            appRepo,
+		groupRepo:          groupRepo,
+		policyRepo:         policyRepo,
+		saRepo:             saRepo,
+		saAPIKeyRepo:       saAPIKeyRepo,
+		auditRepo:          auditRepo,
+		legalRepo:          legalRepo,
+		iatRepo:            iatRepo,
+		emailService:       emailService,
+		apiKeys:            apiKeys,
+		clientRegistration: clientRegistration,
+		phones:             phone.NewNormalizer(phoneCfg.DefaultCountryCode, phoneCfg.TrunkPrefix),
+	}
+}
+
+// ListUsers returns a page of users matching the filter and the number of matching users on all pages.
+func (a *Admin) ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, int64, error) {
+	const op = "admin.ListUsers"
+
+	users, total, err := a.usrRepo.Users(ctx, filter)
+	if err != nil {
+		a.log.Error("failed to list users", slog.String("op", op), sl.Err(err))
+
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, total, nil
+}
+
+func (a *Admin) User(ctx context.Context, userID int64) (models.User, error) {
+	const op = "admin.User"
+
+	user, err := a.usrRepo.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		a.log.Error("failed to get user", slog.String("op", op), sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// UpdateUser overwrites profile fields of the user with non-empty fields of upd.
+// A new email isn't verified, and tokens issued for the old one are revoked.
+func (a *Admin) UpdateUser(ctx context.Context, upd models.User) (models.User, error) {
+	const op = "admin.UpdateUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", upd.ID),
+	)
+
+	log.Info("updating user")
+
+	user, err := a.User(ctx, upd.ID)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if upd.Phone != "" {
+		normalized, err := a.phones.Normalize(upd.Phone)
+		if err != nil {
+			log.Warn("invalid phone", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrInvalidPhone)
+		}
+		upd.Phone = normalized
+	}
+
+	email := user.Email
+
+	for _, f := range []struct{ dst, src *string }{
+		{&user.Title, &upd.Title},
+		{&user.BirthDate, &upd.BirthDate},
+		{&user.Name, &upd.Name},
+		{&user.LastName, &upd.LastName},
+		{&user.Email, &upd.Email},
+		{&user.Phone, &upd.Phone},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+
+	if user.Email != email {
+		user.EmailVerified = false
+		// token iat has second precision, tokens issued later in the same second must stay valid
+		user.TokensValidAfter = time.Now().UTC().Truncate(time.Second)
+	}
+
+	if err := a.usrRepo.UpdateUser(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrUserExists) {
+			log.Warn("email or phone is taken", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserExists)
+		}
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to update user", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user updated")
+
+	return user, nil
+}
+
+func (a *Admin) DeleteUser(ctx context.Context, userID int64) error {
+	const op = "admin.DeleteUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", userID),
+	)
+
+	if err := a.usrRepo.DeleteUser(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to delete user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user deleted")
+
+	return nil
+}
+
+// SetRoles replaces roles of the user in the app and returns the resulting roles.
+func (a *Admin) SetRoles(ctx context.Context, userID int64, appID int, roles []string) ([]string, error) {
+	const op = "admin.SetRoles"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", userID),
+		slog.Int("appID", appID),
+	)
+
+	if _, err := a.User(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	roles = slices.Compact(slices.Sorted(slices.Values(roles)))
+
+	if err := a.roleManager.SetUserRoles(ctx, userID, appID, roles); err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			log.Warn("role not found", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrRoleNotFound)
+		}
+
+		log.Error("failed to set roles", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("roles set", slog.Any("roles", roles))
+
+	roles, err := a.roleManager.UserRoles(ctx, userID, appID)
+	if err != nil {
+		log.Error("failed to get roles", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+// UnlockUser clears failed login attempts of the user and lifts the lockout, locked users become active.
+func (a *Admin) UnlockUser(ctx context.Context, userID int64) error {
+	const op = "admin.UnlockUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", userID),
+	)
+
+	log.Info("unlocking user")
+
+	if err := a.lockoutProvider.ResetLockoutState(ctx, userID); err != nil {
+		log.Error("failed to reset lockout state", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := a.usrRepo.SetUserStatus(ctx, userID, models.UserStatusLocked, models.UserStatusActive, "", time.Now())
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		log.Error("failed to activate user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user unlocked")
+
+	return nil
+}
+
+// LockoutState returns failed login attempts and lockout of the user.
+func (a *Admin) LockoutState(ctx context.Context, userID int64) (models.LockoutState, error) {
+	const op = "admin.LockoutState"
+
+	state, err := a.lockoutProvider.LockoutState(ctx, userID)
+	if err != nil {
+		a.log.Error("failed to get lockout state", slog.String("op", op), sl.Err(err))
+
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return state, nil
+}
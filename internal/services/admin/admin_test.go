@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/handlers/slogdiscard"
+	"sso/internal/repository/sqlite"
+	"sso/internal/services"
+	"sso/internal/services/email"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+const migrationsPath = "../../../migrations"
+
+type testEnv struct {
+	admin       *Admin
+	repo        *sqlite.Repository
+	storagePath string
+}
+
+type nopSender struct{}
+
+func (nopSender) Send(email.SendEmailInput) error { return nil }
+
+// newTestEnv returns Admin over a migrated storage of its own.
+func newTestEnv(t *testing.T) testEnv {
+	t.Helper()
+
+	storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://"+migrationsPath, "sqlite3://"+storagePath)
+	if err != nil {
+		t.Fatalf("open migrations: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	m.Close()
+
+	repo, err := sqlite.New(storagePath)
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	t.Cleanup(func() { repo.Stop() })
+
+	log := slogdiscard.NewDiscardLogger()
+	emails, _ := services.NewEmailService(log, nopSender{}, config.EmailConfig{})
+
+	a := New(log, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, emails,
+		config.APIKeysConfig{}, config.PhoneConfig{}, config.ClientRegistrationConfig{})
+
+	return testEnv{admin: a, repo: repo, storagePath: storagePath}
+}
+
+// createUser saves a user and returns their ID.
+func (e testEnv) createUser(t *testing.T, email string) int64 {
+	t.Helper()
+
+	id, err := e.repo.SaveUser(context.Background(), "", "", "Test", "User", email, []byte("hash"), "")
+	if err != nil {
+		t.Fatalf("save user %s: %v", email, err)
+	}
+
+	return id
+}
+
+// exec runs the statement on the storage directly, for states the repository doesn't set.
+func (e testEnv) exec(t *testing.T, query string, args ...any) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", e.storagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
+
+func TestUnlockUser(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "user@example.com")
+	ctx := context.Background()
+
+	now := time.Now()
+	err := env.repo.SaveLockoutState(ctx, models.LockoutState{
+		UserID:         userID,
+		FailedAttempts: 5,
+		FirstFailedAt:  now,
+		LastFailedAt:   now,
+		LockedUntil:    now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = env.repo.SetUserStatus(ctx, userID, models.UserStatusActive, models.UserStatusLocked, "too many failed login attempts", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.admin.UnlockUser(ctx, userID); err != nil {
+		t.Fatalf("UnlockUser() error = %v", err)
+	}
+
+	state, err := env.admin.LockoutState(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FailedAttempts != 0 || state.Locked(time.Now()) {
+		t.Errorf("LockoutState() = %+v, want no failed attempts and no lockout", state)
+	}
+	if user, _ := env.admin.User(ctx, userID); user.Status != models.UserStatusActive {
+		t.Errorf("user status = %q, want %q", user.Status, models.UserStatusActive)
+	}
+}
+
+func TestUnlockUserKeepsSuspension(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "user@example.com")
+	ctx := context.Background()
+
+	err := env.repo.SetUserStatus(ctx, userID, models.UserStatusActive, models.UserStatusSuspended, "abuse", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.admin.UnlockUser(ctx, userID); err != nil {
+		t.Fatalf("UnlockUser() error = %v", err)
+	}
+	if user, _ := env.admin.User(ctx, userID); user.Status != models.UserStatusSuspended {
+		t.Errorf("user status = %q, want %q", user.Status, models.UserStatusSuspended)
+	}
+}
+
+func TestUpdateUserEmail(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.createUser(t, "user@example.com")
+	ctx := context.Background()
+
+	env.exec(t, "UPDATE users SET email_verified = TRUE WHERE id = ?", userID)
+
+	user, err := env.admin.UpdateUser(ctx, models.User{ID: userID, Name: "Renamed"})
+	if err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	if !user.EmailVerified || !user.TokensValidAfter.IsZero() {
+		t.Errorf("UpdateUser() of the name = verified %t, tokens valid after %v, want the email verified and tokens kept",
+			user.EmailVerified, user.TokensValidAfter)
+	}
+
+	before := time.Now().Truncate(time.Second)
+
+	if _, err := env.admin.UpdateUser(ctx, models.User{ID: userID, Email: "new@example.com"}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+
+	user, err = env.admin.User(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "new@example.com" || user.Name != "Renamed" {
+		t.Errorf("user = %q %q, want %q %q", user.Email, user.Name, "new@example.com", "Renamed")
+	}
+	if user.EmailVerified {
+		t.Error("new email is verified")
+	}
+	if user.TokensValidAfter.Before(before) {
+		t.Errorf("tokens valid after %v, want tokens issued before %v revoked", user.TokensValidAfter, before)
+	}
+}
+
+func TestCreateLegalDocument(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	terms := models.LegalDocument{Type: models.LegalDocumentTerms, Version: "1", URL: "https://example.com/terms", Mandatory: true}
+
+	doc, err := env.admin.CreateLegalDocument(ctx, terms)
+	if err != nil {
+		t.Fatalf("CreateLegalDocument() error = %v", err)
+	}
+	if doc.ID == 0 || !doc.Published(time.Now()) {
+		t.Errorf("CreateLegalDocument() = %+v, want the document saved and published now", doc)
+	}
+
+	if _, err := env.admin.CreateLegalDocument(ctx, terms); !errors.Is(err, ErrLegalDocumentExists) {
+		t.Errorf("CreateLegalDocument() of the same version error = %v, want %v", err, ErrLegalDocumentExists)
+	}
+
+	for name, doc := range map[string]models.LegalDocument{
+		"unknown type": {Type: "cookies", Version: "1", URL: "https://example.com/cookies"},
+		"no version":   {Type: models.LegalDocumentPrivacy, URL: "https://example.com/privacy"},
+		"relative url": {Type: models.LegalDocumentPrivacy, Version: "1", URL: "/privacy"},
+		"non-http url": {Type: models.LegalDocumentPrivacy, Version: "1", URL: "javascript:alert(1)"},
+	} {
+		if _, err := env.admin.CreateLegalDocument(ctx, doc); !errors.Is(err, ErrInvalidLegalDocument) {
+			t.Errorf("CreateLegalDocument() with %s error = %v, want %v", name, err, ErrInvalidLegalDocument)
+		}
+	}
+}
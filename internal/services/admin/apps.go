@@ -0,0 +1,259 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/appsecret"
+	"sso/internal/lib/jsonschema"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+)
+
+type AppRepository interface {
+	CreateApp(ctx context.Context, app models.App) (int, error)
+	UpdateApp(ctx context.Context, app models.App) error
+	App(ctx context.Context, id int) (models.App, error)
+	Apps(ctx context.Context) ([]models.App, error)
+	DeleteApp(ctx context.Context, id int) error
+	Organization(ctx context.Context, id int64) (models.Organization, error)
+}
+
+var (
+	ErrAppNotFound        = errors.New("app not found")
+	ErrAppExists          = errors.New("app with this name already exists")
+	ErrInvalidGrantType   = errors.New("invalid grant type")
+	ErrInvalidRedirectURI = errors.New("invalid redirect uri")
+	ErrOrgNotFound        = errors.New("organization not found")
+	ErrInvalidSchema      = errors.New("invalid attributes schema")
+	ErrInvalidClaim       = errors.New("invalid claim attribute")
+)
+
+// CreateApp registers the app and returns it with its plaintext secret.
+// Only the secret hash is stored, so the secret can't be shown again.
+func (a *Admin) CreateApp(ctx context.Context, app models.App) (models.App, string, error) {
+	const op = "admin.CreateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", app.Name),
+	)
+
+	log.Info("creating app")
+
+	if err := a.validateApp(ctx, app); err != nil {
+		return models.App{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, secretHash, err := appsecret.Generate()
+	if err != nil {
+		log.Error("failed to generate app secret", sl.Err(err))
+
+		return models.App{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+	app.SecretHash = secretHash
+
+	app.ID, err = a.appRepo.CreateApp(ctx, app)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppExists) {
+			log.Warn("app already exists", sl.Err(err))
+
+			return models.App{}, "", fmt.Errorf("%s: %w", op, ErrAppExists)
+		}
+
+		log.Error("failed to create app", sl.Err(err))
+
+		return models.App{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app created", slog.Int("appID", app.ID))
+
+	return app, secret, nil
+}
+
+func (a *Admin) ListApps(ctx context.Context) ([]models.App, error) {
+	const op = "admin.ListApps"
+
+	apps, err := a.appRepo.Apps(ctx)
+	if err != nil {
+		a.log.Error("failed to list apps", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+func (a *Admin) App(ctx context.Context, appID int) (models.App, error) {
+	const op = "admin.App"
+
+	app, err := a.appRepo.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return models.App{}, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		a.log.Error("failed to get app", slog.String("op", op), sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// UpdateApp replaces name, redirect URIs, grant types, owner, organization, disabled flag,
+// attributes schema and claim attributes of the app. The secret is kept.
+// Attributes saved before the schema changed aren't revalidated.
+func (a *Admin) UpdateApp(ctx context.Context, upd models.App) (models.App, error) {
+	const op = "admin.UpdateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("appID", upd.ID),
+	)
+
+	log.Info("updating app")
+
+	app, err := a.App(ctx, upd.ID)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.validateApp(ctx, upd); err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.Name = upd.Name
+	app.RedirectURIs = upd.RedirectURIs
+	app.GrantTypes = upd.GrantTypes
+	app.OwnerID = upd.OwnerID
+	app.Disabled = upd.Disabled
+	app.OrgID = upd.OrgID
+	app.AttributesSchema = upd.AttributesSchema
+	app.ClaimAttributes = upd.ClaimAttributes
+
+	if err := a.appRepo.UpdateApp(ctx, app); err != nil {
+		if errors.Is(err, repository.ErrAppExists) {
+			log.Warn("app name is taken", sl.Err(err))
+
+			return models.App{}, fmt.Errorf("%s: %w", op, ErrAppExists)
+		}
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return models.App{}, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to update app", sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app updated")
+
+	return app, nil
+}
+
+// RotateAppSecret replaces the secret of the app and returns the new plaintext one.
+// Tokens signed with the old secret stop working.
+func (a *Admin) RotateAppSecret(ctx context.Context, appID int) (string, error) {
+	const op = "admin.RotateAppSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("appID", appID),
+	)
+
+	app, err := a.App(ctx, appID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, secretHash, err := appsecret.Generate()
+	if err != nil {
+		log.Error("failed to generate app secret", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	app.SecretHash = secretHash
+
+	if err := a.appRepo.UpdateApp(ctx, app); err != nil {
+		log.Error("failed to save app secret", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app secret rotated")
+
+	return secret, nil
+}
+
+func (a *Admin) DeleteApp(ctx context.Context, appID int) error {
+	const op = "admin.DeleteApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("appID", appID),
+	)
+
+	if err := a.appRepo.DeleteApp(ctx, appID); err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to delete app", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app deleted")
+
+	return nil
+}
+
+func (a *Admin) validateApp(ctx context.Context, app models.App) error {
+	for _, grantType := range app.GrantTypes {
+		if !slices.Contains(models.GrantTypes, grantType) {
+			return fmt.Errorf("%w: %q", ErrInvalidGrantType, grantType)
+		}
+	}
+
+	for _, redirectURI := range app.RedirectURIs {
+		u, err := url.Parse(redirectURI)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return fmt.Errorf("%w: %q", ErrInvalidRedirectURI, redirectURI)
+		}
+	}
+
+	if app.AttributesSchema != "" {
+		if _, err := jsonschema.Compile([]byte(app.AttributesSchema)); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+		}
+	}
+
+	for i, key := range app.ClaimAttributes {
+		if key == "" || slices.Contains(app.ClaimAttributes[:i], key) {
+			return fmt.Errorf("%w: %q", ErrInvalidClaim, key)
+		}
+	}
+
+	if app.OwnerID != 0 {
+		if _, err := a.User(ctx, app.OwnerID); err != nil {
+			return err
+		}
+	}
+
+	if app.OrgID != 0 {
+		if _, err := a.appRepo.Organization(ctx, app.OrgID); err != nil {
+			if errors.Is(err, repository.ErrOrgNotFound) {
+				return ErrOrgNotFound
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+)
+
+type AuditRepository interface {
+	SaveAuditEntry(ctx context.Context, entry models.AuditEntry) (int64, error)
+	AuditEntries(ctx context.Context, userID int64, limit int) ([]models.AuditEntry, error)
+	ProfileChanges(ctx context.Context, userID int64, limit int) ([]models.ProfileChange, error)
+}
+
+// AuditLog returns the latest entries the user is the actor or the target of, or entries of all users if userID is zero.
+func (a *Admin) AuditLog(ctx context.Context, userID int64, limit int) ([]models.AuditEntry, error) {
+	const op = "admin.AuditLog"
+
+	entries, err := a.auditRepo.AuditEntries(ctx, userID, limit)
+	if err != nil {
+		a.log.Error("failed to list audit entries", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// ProfileChanges returns the latest changes the user or support staff made to the user's profile.
+func (a *Admin) ProfileChanges(ctx context.Context, userID int64, limit int) ([]models.ProfileChange, error) {
+	const op = "admin.ProfileChanges"
+
+	changes, err := a.auditRepo.ProfileChanges(ctx, userID, limit)
+	if err != nil {
+		a.log.Error("failed to list profile changes", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return changes, nil
+}
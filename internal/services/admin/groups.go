@@ -0,0 +1,238 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+)
+
+type GroupRepository interface {
+	CreateGroup(ctx context.Context, name string, description string) (int64, error)
+	Group(ctx context.Context, id int64) (models.Group, error)
+	Groups(ctx context.Context) ([]models.Group, error)
+	DeleteGroup(ctx context.Context, id int64) error
+	AddGroupUser(ctx context.Context, groupID int64, userID int64) error
+	RemoveGroupUser(ctx context.Context, groupID int64, userID int64) error
+	AddSubgroup(ctx context.Context, parentID int64, childID int64) error
+	RemoveSubgroup(ctx context.Context, parentID int64, childID int64) error
+	UserGroups(ctx context.Context, userID int64) ([]models.Group, error)
+}
+
+var (
+	ErrGroupNotFound  = errors.New("group not found")
+	ErrGroupExists    = errors.New("group with this name already exists")
+	ErrGroupCycle     = errors.New("group can't contain itself")
+	ErrNotGroupMember = errors.New("not a member of the group")
+)
+
+func (a *Admin) CreateGroup(ctx context.Context, name string, description string) (models.Group, error) {
+	const op = "admin.CreateGroup"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	id, err := a.groupRepo.CreateGroup(ctx, name, description)
+	if err != nil {
+		if errors.Is(err, repository.ErrGroupExists) {
+			log.Warn("group already exists", sl.Err(err))
+
+			return models.Group{}, fmt.Errorf("%s: %w", op, ErrGroupExists)
+		}
+
+		log.Error("failed to create group", sl.Err(err))
+
+		return models.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("group created", slog.Int64("groupID", id))
+
+	return models.Group{ID: id, Name: name, Description: description}, nil
+}
+
+func (a *Admin) ListGroups(ctx context.Context) ([]models.Group, error) {
+	const op = "admin.ListGroups"
+
+	groups, err := a.groupRepo.Groups(ctx)
+	if err != nil {
+		a.log.Error("failed to list groups", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (a *Admin) DeleteGroup(ctx context.Context, groupID int64) error {
+	const op = "admin.DeleteGroup"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("groupID", groupID),
+	)
+
+	if err := a.groupRepo.DeleteGroup(ctx, groupID); err != nil {
+		if errors.Is(err, repository.ErrGroupNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+
+		log.Error("failed to delete group", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("group deleted")
+
+	return nil
+}
+
+// AddGroupUser adds the user to the group.
+func (a *Admin) AddGroupUser(ctx context.Context, groupID int64, userID int64) error {
+	const op = "admin.AddGroupUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("groupID", groupID),
+		slog.Int64("userID", userID),
+	)
+
+	if err := a.group(ctx, groupID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.groupRepo.AddGroupUser(ctx, groupID, userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to add user to group", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user added to group")
+
+	return nil
+}
+
+func (a *Admin) RemoveGroupUser(ctx context.Context, groupID int64, userID int64) error {
+	const op = "admin.RemoveGroupUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("groupID", groupID),
+		slog.Int64("userID", userID),
+	)
+
+	if err := a.groupRepo.RemoveGroupUser(ctx, groupID, userID); err != nil {
+		if errors.Is(err, repository.ErrNotGroupMember) {
+			return fmt.Errorf("%s: %w", op, ErrNotGroupMember)
+		}
+
+		log.Error("failed to remove user from group", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user removed from group")
+
+	return nil
+}
+
+// AddSubgroup nests the child group in the parent one, so that members of the child become members of the parent.
+// Returns ErrGroupCycle if the child already contains the parent.
+func (a *Admin) AddSubgroup(ctx context.Context, parentID int64, childID int64) error {
+	const op = "admin.AddSubgroup"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("parentID", parentID),
+		slog.Int64("childID", childID),
+	)
+
+	for _, groupID := range []int64{parentID, childID} {
+		if err := a.group(ctx, groupID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := a.groupRepo.AddSubgroup(ctx, parentID, childID); err != nil {
+		if errors.Is(err, repository.ErrGroupCycle) {
+			log.Warn("nesting would create a cycle")
+
+			return fmt.Errorf("%s: %w", op, ErrGroupCycle)
+		}
+		if errors.Is(err, repository.ErrGroupNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+
+		log.Error("failed to nest group", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("group nested")
+
+	return nil
+}
+
+func (a *Admin) RemoveSubgroup(ctx context.Context, parentID int64, childID int64) error {
+	const op = "admin.RemoveSubgroup"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("parentID", parentID),
+		slog.Int64("childID", childID),
+	)
+
+	if err := a.groupRepo.RemoveSubgroup(ctx, parentID, childID); err != nil {
+		if errors.Is(err, repository.ErrNotGroupMember) {
+			return fmt.Errorf("%s: %w", op, ErrNotGroupMember)
+		}
+
+		log.Error("failed to remove nested group", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("nested group removed")
+
+	return nil
+}
+
+// UserGroups returns groups the user belongs to directly or through nested groups.
+func (a *Admin) UserGroups(ctx context.Context, userID int64) ([]models.Group, error) {
+	const op = "admin.UserGroups"
+
+	if _, err := a.User(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	groups, err := a.groupRepo.UserGroups(ctx, userID)
+	if err != nil {
+		a.log.Error("failed to get user groups", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (a *Admin) group(ctx context.Context, groupID int64) error {
+	if _, err := a.groupRepo.Group(ctx, groupID); err != nil {
+		if errors.Is(err, repository.ErrGroupNotFound) {
+			return ErrGroupNotFound
+		}
+
+		a.log.Error("failed to get group", sl.Err(err))
+
+		return err
+	}
+
+	return nil
+}
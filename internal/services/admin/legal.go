@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"time"
+)
+
+type LegalDocumentRepository interface {
+	CreateLegalDocument(ctx context.Context, doc models.LegalDocument) (int64, error)
+	LegalDocuments(ctx context.Context) ([]models.LegalDocument, error)
+}
+
+var (
+	ErrLegalDocumentExists  = errors.New("legal document with this type and version already exists")
+	ErrInvalidLegalDocument = errors.New("invalid legal document")
+)
+
+// CreateLegalDocument publishes a new version of a legal document. Zero PublishedAt publishes it now,
+// a mandatory version published later is required from users logging in once it takes effect.
+func (a *Admin) CreateLegalDocument(ctx context.Context, doc models.LegalDocument) (models.LegalDocument, error) {
+	const op = "admin.CreateLegalDocument"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("type", doc.Type),
+		slog.String("version", doc.Version),
+	)
+
+	if err := validateLegalDocument(doc); err != nil {
+		log.Warn("invalid legal document", sl.Err(err))
+
+		return models.LegalDocument{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	doc.CreatedAt = time.Now()
+	if doc.PublishedAt.IsZero() {
+		doc.PublishedAt = doc.CreatedAt
+	}
+
+	id, err := a.legalRepo.CreateLegalDocument(ctx, doc)
+	if err != nil {
+		if errors.Is(err, repository.ErrLegalDocumentExists) {
+			return models.LegalDocument{}, fmt.Errorf("%s: %w", op, ErrLegalDocumentExists)
+		}
+
+		log.Error("failed to create legal document", sl.Err(err))
+
+		return models.LegalDocument{}, fmt.Errorf("%s: %w", op, err)
+	}
+	doc.ID = id
+
+	log.Info("legal document created", slog.Int64("documentID", id), slog.Bool("mandatory", doc.Mandatory))
+
+	return doc, nil
+}
+
+// LegalDocuments returns all versions of legal documents, the latest published first.
+func (a *Admin) LegalDocuments(ctx context.Context) ([]models.LegalDocument, error) {
+	const op = "admin.LegalDocuments"
+
+	docs, err := a.legalRepo.LegalDocuments(ctx)
+	if err != nil {
+		a.log.Error("failed to list legal documents", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return docs, nil
+}
+
+func validateLegalDocument(doc models.LegalDocument) error {
+	if !slices.Contains(models.LegalDocumentTypes, doc.Type) {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidLegalDocument, doc.Type)
+	}
+
+	if doc.Version == "" {
+		return fmt.Errorf("%w: version is required", ErrInvalidLegalDocument)
+	}
+
+	u, err := url.Parse(doc.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidLegalDocument)
+	}
+
+	return nil
+}
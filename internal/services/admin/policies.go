@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/policy"
+	"sso/internal/repository"
+)
+
+type PolicyRepository interface {
+	CreatePolicyRule(ctx context.Context, rule models.PolicyRule) (int64, error)
+	PolicyRules(ctx context.Context, appID int) ([]models.PolicyRule, error)
+	DeletePolicyRule(ctx context.Context, id int64) error
+}
+
+var (
+	ErrInvalidRule  = policy.ErrInvalidRule
+	ErrRuleExists   = errors.New("policy rule with this name already exists in the app")
+	ErrRuleNotFound = errors.New("policy rule not found")
+)
+
+func (a *Admin) CreatePolicyRule(ctx context.Context, rule models.PolicyRule) (models.PolicyRule, error) {
+	const op = "admin.CreatePolicyRule"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("appID", rule.AppID),
+		slog.String("name", rule.Name),
+	)
+
+	if err := policy.Validate(rule); err != nil {
+		log.Warn("invalid policy rule", sl.Err(err))
+
+		return models.PolicyRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := a.policyRepo.CreatePolicyRule(ctx, rule)
+	if err != nil {
+		if errors.Is(err, repository.ErrRuleExists) {
+			return models.PolicyRule{}, fmt.Errorf("%s: %w", op, ErrRuleExists)
+		}
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return models.PolicyRule{}, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to create policy rule", sl.Err(err))
+
+		return models.PolicyRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+	rule.ID = id
+
+	log.Info("policy rule created", slog.Int64("ruleID", id))
+
+	return rule, nil
+}
+
+func (a *Admin) PolicyRules(ctx context.Context, appID int) ([]models.PolicyRule, error) {
+	const op = "admin.PolicyRules"
+
+	if _, err := a.App(ctx, appID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rules, err := a.policyRepo.PolicyRules(ctx, appID)
+	if err != nil {
+		a.log.Error("failed to list policy rules", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+func (a *Admin) DeletePolicyRule(ctx context.Context, ruleID int64) error {
+	const op = "admin.DeletePolicyRule"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("ruleID", ruleID),
+	)
+
+	if err := a.policyRepo.DeletePolicyRule(ctx, ruleID); err != nil {
+		if errors.Is(err, repository.ErrRuleNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+		}
+
+		log.Error("failed to delete policy rule", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("policy rule deleted")
+
+	return nil
+}
@@ -0,0 +1,423 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/apikey"
+	"sso/internal/lib/appsecret"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"time"
+)
+
+type ServiceAccountRepository interface {
+	CreateServiceAccount(ctx context.Context, sa models.ServiceAccount) (int64, error)
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
+	ServiceAccounts(ctx context.Context) ([]models.ServiceAccount, error)
+	UpdateServiceAccount(ctx context.Context, sa models.ServiceAccount) error
+	DeleteServiceAccount(ctx context.Context, id int64) error
+	ServiceAccountRoles(ctx context.Context, serviceAccountID int64, appID int) ([]string, error)
+	SetServiceAccountRoles(ctx context.Context, serviceAccountID int64, appID int, roles []string) error
+	ResetServiceAccountLockoutState(ctx context.Context, serviceAccountID int64) error
+}
+
+type ServiceAccountAPIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key models.APIKey) (int64, error)
+	ServiceAccountAPIKeys(ctx context.Context, serviceAccountID int64) ([]models.APIKey, error)
+	DeleteServiceAccountAPIKey(ctx context.Context, id int64, serviceAccountID int64) error
+}
+
+var (
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrServiceAccountExists   = errors.New("service account with this name already exists")
+	ErrInvalidOwner           = errors.New("service account must be owned by either an app or an organization")
+	ErrAppNotAccessible       = errors.New("service account can't access the app")
+	ErrAPIKeyExists           = errors.New("api key with this name already exists")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+	ErrInvalidTTL             = errors.New("api key lifetime exceeds the maximum")
+)
+
+const clientIDBytes = 8
+
+// CreateServiceAccount registers the account and returns it with its plaintext client secret.
+// Only the secret hash is stored, so the secret can't be shown again.
+func (a *Admin) CreateServiceAccount(ctx context.Context, sa models.ServiceAccount) (models.ServiceAccount, string, error) {
+	const op = "admin.CreateServiceAccount"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", sa.Name),
+	)
+
+	log.Info("creating service account")
+
+	if (sa.AppID == 0) == (sa.OrgID == 0) {
+		return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, ErrInvalidOwner)
+	}
+
+	if sa.AppID != 0 {
+		if _, err := a.App(ctx, sa.AppID); err != nil {
+			return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+	} else if _, err := a.appRepo.Organization(ctx, sa.OrgID); err != nil {
+		if errors.Is(err, repository.ErrOrgNotFound) {
+			return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, ErrOrgNotFound)
+		}
+
+		log.Error("failed to get organization", sl.Err(err))
+
+		return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	b := make([]byte, clientIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		log.Error("failed to generate client id", sl.Err(err))
+
+		return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+	sa.ClientID = "sa_" + hex.EncodeToString(b)
+
+	secret, secretHash, err := appsecret.Generate()
+	if err != nil {
+		log.Error("failed to generate client secret", sl.Err(err))
+
+		return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+	sa.SecretHash = secretHash
+	sa.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	sa.ID, err = a.saRepo.CreateServiceAccount(ctx, sa)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceAccountExists) {
+			log.Warn("service account already exists", sl.Err(err))
+
+			return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, ErrServiceAccountExists)
+		}
+
+		log.Error("failed to create service account", sl.Err(err))
+
+		return models.ServiceAccount{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account created", slog.Int64("serviceAccountID", sa.ID))
+
+	return sa, secret, nil
+}
+
+func (a *Admin) ListServiceAccounts(ctx context.Context) ([]models.ServiceAccount, error) {
+	const op = "admin.ListServiceAccounts"
+
+	accounts, err := a.saRepo.ServiceAccounts(ctx)
+	if err != nil {
+		a.log.Error("failed to list service accounts", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return accounts, nil
+}
+
+func (a *Admin) ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error) {
+	const op = "admin.ServiceAccount"
+
+	sa, err := a.saRepo.ServiceAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, ErrServiceAccountNotFound)
+		}
+
+		a.log.Error("failed to get service account", slog.String("op", op), sl.Err(err))
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sa, nil
+}
+
+// UpdateServiceAccount replaces name, description and disabled flag of the account.
+// The owner and credentials are kept.
+func (a *Admin) UpdateServiceAccount(ctx context.Context, upd models.ServiceAccount) (models.ServiceAccount, error) {
+	const op = "admin.UpdateServiceAccount"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", upd.ID),
+	)
+
+	sa, err := a.ServiceAccount(ctx, upd.ID)
+	if err != nil {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sa.Name = upd.Name
+	sa.Description = upd.Description
+	sa.Disabled = upd.Disabled
+
+	if err := a.saRepo.UpdateServiceAccount(ctx, sa); err != nil {
+		if errors.Is(err, repository.ErrServiceAccountExists) {
+			log.Warn("service account name is taken", sl.Err(err))
+
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, ErrServiceAccountExists)
+		}
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, ErrServiceAccountNotFound)
+		}
+
+		log.Error("failed to update service account", sl.Err(err))
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account updated")
+
+	return sa, nil
+}
+
+// DeleteServiceAccount deletes the account along with its roles and API keys.
+func (a *Admin) DeleteServiceAccount(ctx context.Context, id int64) error {
+	const op = "admin.DeleteServiceAccount"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", id),
+	)
+
+	if err := a.saRepo.DeleteServiceAccount(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrServiceAccountNotFound)
+		}
+
+		log.Error("failed to delete service account", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account deleted")
+
+	return nil
+}
+
+// RotateServiceAccountSecret replaces the client secret of the account and returns the new plaintext one.
+// Tokens issued with the old secret stay valid until they expire. A lockout after wrong secrets is lifted.
+func (a *Admin) RotateServiceAccountSecret(ctx context.Context, id int64) (string, error) {
+	const op = "admin.RotateServiceAccountSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", id),
+	)
+
+	sa, err := a.ServiceAccount(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, secretHash, err := appsecret.Generate()
+	if err != nil {
+		log.Error("failed to generate client secret", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	sa.SecretHash = secretHash
+
+	if err := a.saRepo.UpdateServiceAccount(ctx, sa); err != nil {
+		log.Error("failed to save client secret", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.saRepo.ResetServiceAccountLockoutState(ctx, sa.ID); err != nil {
+		log.Error("failed to reset lockout state", sl.Err(err))
+	}
+
+	log.Info("service account secret rotated")
+
+	return secret, nil
+}
+
+// SetServiceAccountRoles replaces roles of the account in the app, which the account must be able to access.
+// It returns the resulting roles.
+func (a *Admin) SetServiceAccountRoles(ctx context.Context, id int64, appID int, roles []string) ([]string, error) {
+	const op = "admin.SetServiceAccountRoles"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", id),
+		slog.Int("appID", appID),
+	)
+
+	log.Info("setting service account roles", slog.Any("roles", roles))
+
+	if _, err := a.accessibleApp(ctx, id, appID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var unique []string
+	for _, role := range roles {
+		if !slices.Contains(unique, role) {
+			unique = append(unique, role)
+		}
+	}
+
+	if err := a.saRepo.SetServiceAccountRoles(ctx, id, appID, unique); err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			log.Warn("role not found", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrRoleNotFound)
+		}
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrServiceAccountNotFound)
+		}
+
+		log.Error("failed to set service account roles", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := a.saRepo.ServiceAccountRoles(ctx, id, appID)
+	if err != nil {
+		log.Error("failed to get service account roles", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account roles set")
+
+	return result, nil
+}
+
+// CreateServiceAccountAPIKey issues a key for the account in the app and returns it with the plaintext key.
+// Zero ttl means the default lifetime.
+func (a *Admin) CreateServiceAccountAPIKey(
+	ctx context.Context,
+	id int64,
+	appID int,
+	name string,
+	scopes []string,
+	ttl time.Duration,
+) (models.APIKey, string, error) {
+	const op = "admin.CreateServiceAccountAPIKey"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", id),
+		slog.String("name", name),
+	)
+
+	if ttl == 0 {
+		ttl = a.apiKeys.DefaultTTL
+	}
+	if ttl > a.apiKeys.MaxTTL {
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, ErrInvalidTTL)
+	}
+
+	if _, err := a.accessibleApp(ctx, id, appID); err != nil {
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	plain, prefix, err := apikey.Generate()
+	if err != nil {
+		log.Error("failed to generate api key", sl.Err(err))
+
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	key := models.APIKey{
+		ServiceAccountID: id,
+		AppID:            appID,
+		Name:             name,
+		Prefix:           prefix,
+		KeyHash:          apikey.Hash(plain),
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(ttl),
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(key.Scopes, scope) {
+			key.Scopes = append(key.Scopes, scope)
+		}
+	}
+
+	key.ID, err = a.saAPIKeyRepo.CreateAPIKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyExists) {
+			log.Warn("api key already exists", sl.Err(err))
+
+			return models.APIKey{}, "", fmt.Errorf("%s: %w", op, ErrAPIKeyExists)
+		}
+
+		log.Error("failed to save api key", sl.Err(err))
+
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account api key created", slog.String("prefix", prefix))
+
+	return key, plain, nil
+}
+
+func (a *Admin) ServiceAccountAPIKeys(ctx context.Context, id int64) ([]models.APIKey, error) {
+	const op = "admin.ServiceAccountAPIKeys"
+
+	if _, err := a.ServiceAccount(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys, err := a.saAPIKeyRepo.ServiceAccountAPIKeys(ctx, id)
+	if err != nil {
+		a.log.Error("failed to list api keys", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (a *Admin) RevokeServiceAccountAPIKey(ctx context.Context, id int64, keyID int64) error {
+	const op = "admin.RevokeServiceAccountAPIKey"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", id),
+		slog.Int64("keyID", keyID),
+	)
+
+	if err := a.saAPIKeyRepo.DeleteServiceAccountAPIKey(ctx, keyID, id); err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrAPIKeyNotFound)
+		}
+
+		log.Error("failed to delete api key", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account api key revoked")
+
+	return nil
+}
+
+// accessibleApp returns the app if the service account may access it.
+func (a *Admin) accessibleApp(ctx context.Context, id int64, appID int) (models.App, error) {
+	sa, err := a.ServiceAccount(ctx, id)
+	if err != nil {
+		return models.App{}, err
+	}
+
+	app, err := a.App(ctx, appID)
+	if err != nil {
+		return models.App{}, err
+	}
+
+	if !sa.CanAccess(app) {
+		return models.App{}, ErrAppNotAccessible
+	}
+
+	return app, nil
+}
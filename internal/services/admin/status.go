@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"sso/internal/services"
+	"time"
+)
+
+var ErrInvalidStatus = errors.New("user status doesn't allow the change")
+
+// SuspendUser suspends the user for the reason until the user is reactivated. Suspended users
+// can't log in, their tokens and API keys are rejected. Users who requested deletion can't be suspended.
+// The change is recorded in the audit log and the user is notified by email.
+func (a *Admin) SuspendUser(ctx context.Context, caller models.Principal, userID int64, reason string) (models.User, error) {
+	const op = "admin.SuspendUser"
+
+	user, err := a.User(ctx, userID)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Status == models.UserStatusDeleted {
+		return models.User{}, fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+	}
+
+	user, err = a.changeStatus(ctx, caller, user, models.UserStatusSuspended, reason, models.AuditActionSuspend)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// ReactivateUser makes the suspended, pending or locked user active, lifting the lockout of locked users.
+// Users who requested deletion can't be reactivated, they cancel the deletion by logging in.
+// The change is recorded in the audit log and the user is notified by email.
+func (a *Admin) ReactivateUser(ctx context.Context, caller models.Principal, userID int64, reason string) (models.User, error) {
+	const op = "admin.ReactivateUser"
+
+	user, err := a.User(ctx, userID)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch user.Status {
+	case models.UserStatusActive:
+		return user, nil
+	case models.UserStatusDeleted:
+		return models.User{}, fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+	case models.UserStatusLocked:
+		if err := a.lockoutProvider.ResetLockoutState(ctx, user.ID); err != nil {
+			a.log.Error("failed to reset lockout state", slog.String("op", op), sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	user, err = a.changeStatus(ctx, caller, user, models.UserStatusActive, reason, models.AuditActionReactivate)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// changeStatus changes the status of the user unless it was changed meanwhile, records the change
+// in the audit log and notifies the user by email.
+func (a *Admin) changeStatus(
+	ctx context.Context,
+	caller models.Principal,
+	user models.User,
+	status string,
+	reason string,
+	action string,
+) (models.User, error) {
+	const op = "admin.changeStatus"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", user.ID),
+		slog.String("from", user.Status),
+		slog.String("to", status),
+	)
+
+	now := time.Now()
+
+	if err := a.usrRepo.SetUserStatus(ctx, user.ID, user.Status, status, reason, now); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Warn("user status changed meanwhile")
+
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+		}
+
+		log.Error("failed to change user status", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user.Status = status
+	user.StatusReason = reason
+	user.StatusChangedAt = now
+
+	_, err := a.auditRepo.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      caller.UserID,
+		Action:       action,
+		TargetUserID: user.ID,
+		AppID:        caller.AppID,
+		Details:      reason,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		log.Error("failed to save audit entry", sl.Err(err))
+	}
+
+	err = a.emailService.SendAccountStatusEmail(services.AccountStatusEmailInput{
+		Email:  user.Email,
+		Name:   user.Name,
+		Status: status,
+		Reason: reason,
+	})
+	if err != nil {
+		log.Error("failed to send account status email", sl.Err(err))
+	}
+
+	log.Warn("user status changed", slog.String("reason", reason))
+
+	return user, nil
+}
@@ -0,0 +1,338 @@
+package attributes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/jsonschema"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"strings"
+	"time"
+)
+
+const (
+	// maxAttributes bounds the number of attributes a user has in one app.
+	maxAttributes = 100
+	// maxValueBytes bounds the size of a single attribute value.
+	maxValueBytes = 4096
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// Attributes stores custom data apps attach to their users. Every app has its own attributes,
+// they are read and written with tokens issued for the app. Users can read their own attributes,
+// holders of the attributes or admin permission in the app can read and write anyone's.
+type Attributes struct {
+	log          *slog.Logger
+	repo         Repository
+	permProvider PermissionProvider
+	auditLog     AuditLog
+}
+
+type Repository interface {
+	UserAttributes(ctx context.Context, userID int64, appID int) (models.Attributes, error)
+	UpdateUserAttributes(
+		ctx context.Context,
+		userID int64,
+		appID int,
+		set models.Attributes,
+		remove []string,
+		updatedAt time.Time,
+	) error
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+type PermissionProvider interface {
+	HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error)
+	ServiceAccountHasPermission(ctx context.Context, serviceAccountID int64, appID int, permission string) (bool, error)
+}
+
+type AuditLog interface {
+	SaveAuditEntry(ctx context.Context, entry models.AuditEntry) (int64, error)
+}
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrAppNotFound       = errors.New("app not found")
+	ErrInvalidKey        = errors.New("invalid attribute key")
+	ErrInvalidValue      = errors.New("invalid attribute value")
+	ErrTooManyAttributes = errors.New("too many attributes")
+	ErrSchemaMismatch    = errors.New("attributes don't match the app schema")
+	ErrPermissionDenied  = errors.New("permission denied")
+)
+
+// New returns a new instance of the Attributes service.
+func New(
+	log *slog.Logger,
+	repo Repository,
+	permProvider PermissionProvider,
+	auditLog AuditLog,
+) *Attributes {
+	return &Attributes{
+		log:          log,
+		repo:         repo,
+		permProvider: permProvider,
+		auditLog:     auditLog,
+	}
+}
+
+// GetAttributes returns attributes the caller's app attached to the user.
+// Zero userID stands for the caller.
+func (a *Attributes) GetAttributes(ctx context.Context, caller models.Principal, userID int64) (models.Attributes, error) {
+	const op = "attributes.GetAttributes"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("callerID", caller.UserID),
+		slog.Int("appID", caller.AppID),
+	)
+
+	userID, err := a.checkAccess(ctx, caller, userID, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.user(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attrs, err := a.repo.UserAttributes(ctx, userID, caller.AppID)
+	if err != nil {
+		log.Error("failed to get attributes", slog.Int64("userID", userID), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attrs, nil
+}
+
+// SetAttributes merges the patch into attributes the caller's app attached to the user
+// and returns the resulting attributes. Null values in the patch delete attributes,
+// as in JSON merge patch. If the app has an attributes schema, the result must match it.
+// Zero userID stands for the caller.
+func (a *Attributes) SetAttributes(
+	ctx context.Context,
+	caller models.Principal,
+	userID int64,
+	patch models.Attributes,
+) (models.Attributes, error) {
+	const op = "attributes.SetAttributes"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("callerID", caller.UserID),
+		slog.Int("appID", caller.AppID),
+	)
+
+	userID, err := a.checkAccess(ctx, caller, userID, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log = log.With(slog.Int64("userID", userID))
+
+	set, remove, err := splitPatch(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.user(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.repo.App(ctx, caller.AppID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get app", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attrs, err := a.repo.UserAttributes(ctx, userID, app.ID)
+	if err != nil {
+		log.Error("failed to get attributes", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for key, value := range set {
+		attrs[key] = value
+	}
+	for _, key := range remove {
+		delete(attrs, key)
+	}
+
+	if len(attrs) > maxAttributes {
+		return nil, fmt.Errorf("%s: %w: at most %d are allowed", op, ErrTooManyAttributes, maxAttributes)
+	}
+
+	if err := validateSchema(app, attrs); err != nil {
+		log.Info("attributes don't match the schema", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	if err := a.repo.UpdateUserAttributes(ctx, userID, app.ID, set, remove, now); err != nil {
+		log.Error("failed to update attributes", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// service account IDs aren't user IDs, so only changes made by users are audited
+	if caller.Type != models.PrincipalServiceAccount {
+		_, err = a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+			ActorID:      caller.UserID,
+			Action:       models.AuditActionSetAttributes,
+			TargetUserID: userID,
+			AppID:        app.ID,
+			Details:      strings.Join(patchKeys(patch), ", "),
+			CreatedAt:    now,
+		})
+		if err != nil {
+			log.Error("failed to save audit entry", sl.Err(err))
+		}
+	}
+
+	log.Info("attributes updated", slog.Int("set", len(set)), slog.Int("removed", len(remove)))
+
+	return attrs, nil
+}
+
+// checkAccess resolves zero userID to the caller and checks that the caller may access
+// the user's attributes, writes always need the attributes or admin permission.
+func (a *Attributes) checkAccess(ctx context.Context, caller models.Principal, userID int64, write bool) (int64, error) {
+	isUser := caller.Type != models.PrincipalServiceAccount
+
+	if userID == 0 {
+		if !isUser {
+			return 0, ErrUserNotFound
+		}
+		userID = caller.UserID
+	}
+
+	if isUser && userID == caller.UserID && !write {
+		return userID, nil
+	}
+
+	// impersonation lets support staff see what the user sees, not use the user's privileges
+	if caller.ActorID != 0 {
+		return 0, ErrPermissionDenied
+	}
+
+	for _, permission := range []string{models.PermissionAttributes, models.PermissionAdmin} {
+		has, err := a.hasPermission(ctx, caller, permission)
+		if err != nil {
+			a.log.Error("failed to check permission", slog.String("permission", permission), sl.Err(err))
+
+			return 0, err
+		}
+		if has {
+			return userID, nil
+		}
+	}
+
+	return 0, ErrPermissionDenied
+}
+
+func (a *Attributes) hasPermission(ctx context.Context, caller models.Principal, permission string) (bool, error) {
+	if caller.Type == models.PrincipalServiceAccount {
+		return a.permProvider.ServiceAccountHasPermission(ctx, caller.UserID, caller.AppID, permission)
+	}
+
+	return a.permProvider.HasPermission(ctx, caller.UserID, caller.AppID, permission)
+}
+
+func (a *Attributes) user(ctx context.Context, userID int64) (models.User, error) {
+	user, err := a.repo.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, ErrUserNotFound
+		}
+
+		a.log.Error("failed to get user", slog.Int64("userID", userID), sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	if user.Deleted(time.Now()) {
+		return models.User{}, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// splitPatch validates the patch and splits it into attributes to set, with compacted values,
+// and keys of attributes to remove.
+func splitPatch(patch models.Attributes) (models.Attributes, []string, error) {
+	set := models.Attributes{}
+	var remove []string
+
+	for key, value := range patch {
+		if !keyPattern.MatchString(key) {
+			return nil, nil, fmt.Errorf("%w %q: up to 64 letters, digits, '_', '.' and '-' are allowed", ErrInvalidKey, key)
+		}
+
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err != nil {
+			return nil, nil, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+		}
+
+		if compact.String() == "null" {
+			remove = append(remove, key)
+			continue
+		}
+
+		if compact.Len() > maxValueBytes {
+			return nil, nil, fmt.Errorf("%w %q: at most %d bytes are allowed", ErrInvalidValue, key, maxValueBytes)
+		}
+
+		set[key] = compact.Bytes()
+	}
+
+	return set, remove, nil
+}
+
+// validateSchema checks the attributes, as a JSON object, against the app schema.
+// Schemas are validated when they are saved, so a broken one is a server error.
+func validateSchema(app models.App, attrs models.Attributes) error {
+	if app.AttributesSchema == "" {
+		return nil
+	}
+
+	schema, err := jsonschema.Compile([]byte(app.AttributesSchema))
+	if err != nil {
+		return fmt.Errorf("schema of app %d: %w", app.ID, err)
+	}
+
+	document, err := json.Marshal(attrs)
+	if err != nil {
+		return err
+	}
+
+	if err := schema.Validate(document); err != nil {
+		return fmt.Errorf("%w: %w", ErrSchemaMismatch, err)
+	}
+
+	return nil
+}
+
+func patchKeys(patch models.Attributes) []string {
+	keys := make([]string, 0, len(patch))
+	for key := range patch {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/apikey"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"time"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key models.APIKey) (int64, error)
+	APIKeyByPrefix(ctx context.Context, prefix string) (models.APIKey, error)
+	APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id int64, userID int64) error
+	TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyExists   = errors.New("api key with this name already exists")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidTTL     = errors.New("api key lifetime is negative or exceeds the maximum")
+	ErrUnknownScope   = errors.New("scope isn't a permission of the app")
+	ErrScopeNotHeld   = errors.New("scope is a permission the user doesn't hold")
+)
+
+// CreateAPIKey issues a key for the caller's user and app and returns it with the plaintext key.
+// Scopes must be permissions of the app the user holds, so that keys don't grant more than the user has.
+// Zero ttl means the default lifetime.
+// Only the key hash is stored, so the key can't be shown again.
+func (a *Auth) CreateAPIKey(
+	ctx context.Context,
+	caller models.Principal,
+	name string,
+	scopes []string,
+	ttl time.Duration,
+) (models.APIKey, string, error) {
+	const op = "auth.CreateAPIKey"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+		slog.String("name", name),
+	)
+
+	if ttl == 0 {
+		ttl = a.apiKeys.DefaultTTL
+	}
+	if ttl < 0 || ttl > a.apiKeys.MaxTTL {
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, ErrInvalidTTL)
+	}
+
+	if len(scopes) > 0 {
+		perms, err := a.permProvider.AppPermissions(ctx, caller.AppID)
+		if err != nil {
+			log.Error("failed to get app permissions", sl.Err(err))
+
+			return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, scope := range scopes {
+			if !slices.Contains(perms, scope) {
+				log.Warn("unknown scope", slog.String("scope", scope))
+
+				return models.APIKey{}, "", fmt.Errorf("%s: %w: %q", op, ErrUnknownScope, scope)
+			}
+
+			has, err := a.permProvider.HasPermission(ctx, caller.UserID, caller.AppID, scope)
+			if err != nil {
+				log.Error("failed to check permission", sl.Err(err))
+
+				return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+			}
+			if !has {
+				log.Warn("scope not held", slog.String("scope", scope))
+
+				return models.APIKey{}, "", fmt.Errorf("%s: %w: %q", op, ErrScopeNotHeld, scope)
+			}
+		}
+	}
+
+	plain, prefix, err := apikey.Generate()
+	if err != nil {
+		log.Error("failed to generate api key", sl.Err(err))
+
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	key := models.APIKey{
+		UserID:    caller.UserID,
+		AppID:     caller.AppID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   apikey.Hash(plain),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(key.Scopes, scope) {
+			key.Scopes = append(key.Scopes, scope)
+		}
+	}
+
+	key.ID, err = a.apiKeyRepo.CreateAPIKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyExists) {
+			log.Warn("api key already exists", sl.Err(err))
+
+			return models.APIKey{}, "", fmt.Errorf("%s: %w", op, ErrAPIKeyExists)
+		}
+
+		log.Error("failed to save api key", sl.Err(err))
+
+		return models.APIKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("api key created", slog.String("prefix", prefix))
+
+	return key, plain, nil
+}
+
+func (a *Auth) APIKeys(ctx context.Context, caller models.Principal) ([]models.APIKey, error) {
+	const op = "auth.APIKeys"
+
+	keys, err := a.apiKeyRepo.APIKeys(ctx, caller.UserID)
+	if err != nil {
+		a.log.Error("failed to list api keys", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey deletes the key if it belongs to the caller.
+func (a *Auth) RevokeAPIKey(ctx context.Context, caller models.Principal, keyID int64) error {
+	const op = "auth.RevokeAPIKey"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+		slog.Int64("keyID", keyID),
+	)
+
+	if err := a.apiKeyRepo.DeleteAPIKey(ctx, keyID, caller.UserID); err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrAPIKeyNotFound)
+		}
+
+		log.Error("failed to delete api key", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("api key revoked")
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the principal the key belongs to and records that the key was used.
+// Expired keys and keys of disabled users, service accounts or apps are rejected with ErrInvalidAPIKey.
+func (a *Auth) AuthenticateAPIKey(ctx context.Context, plain string) (models.Principal, error) {
+	const op = "auth.AuthenticateAPIKey"
+
+	log := a.log.With(slog.String("op", op))
+
+	prefix, ok := apikey.Prefix(plain)
+	if !ok {
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+	log = log.With(slog.String("prefix", prefix))
+
+	key, err := a.apiKeyRepo.APIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			log.Warn("api key not found")
+
+			return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+		}
+
+		log.Error("failed to get api key", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !apikey.Matches(plain, key.KeyHash) {
+		log.Warn("api key doesn't match")
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	now := time.Now()
+	if !now.Before(key.ExpiresAt) {
+		log.Warn("api key expired", slog.Time("expires_at", key.ExpiresAt))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	if key.ServiceAccountID != 0 {
+		principal, err := a.serviceAccountKeyPrincipal(ctx, key)
+		if err != nil {
+			return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := a.apiKeyRepo.TouchAPIKey(ctx, key.ID, now); err != nil {
+			log.Error("failed to record api key use", sl.Err(err))
+		}
+
+		return principal, nil
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, key.UserID)
+	if err != nil {
+		log.Error("failed to get api key user", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Blocked() {
+		log.Warn("api key user is not active", slog.String("status", user.Status))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	app, err := a.appProvider.App(ctx, key.AppID)
+	if err != nil {
+		log.Error("failed to get api key app", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if app.Disabled {
+		log.Warn("api key app is disabled")
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	principal := models.Principal{
+		Type:      models.PrincipalUser,
+		UserID:    user.ID,
+		Email:     user.Email,
+		AppID:     app.ID,
+		Scopes:    key.Scopes,
+		ExpiresAt: key.ExpiresAt,
+	}
+
+	principal.Roles, err = a.permProvider.UserRoles(ctx, user.ID, app.ID)
+	if err != nil {
+		log.Error("failed to get user roles", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if a.groupsClaim.Enabled {
+		if err := a.addGroups(ctx, &principal); err != nil {
+			return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := a.apiKeyRepo.TouchAPIKey(ctx, key.ID, now); err != nil {
+		log.Error("failed to record api key use", sl.Err(err))
+	}
+
+	return principal, nil
+}
+
+// Introspect returns the principal the token was issued to, or ErrInvalidToken if the token is no longer active.
+func (a *Auth) Introspect(ctx context.Context, token string) (models.Principal, error) {
+	const op = "auth.Introspect"
+
+	principal, err := a.Authorize(ctx, token, "")
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	env.auth.apiKeys = config.APIKeysConfig{DefaultTTL: time.Hour, MaxTTL: 24 * time.Hour}
+	ctx := context.Background()
+
+	caller := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "user@example.com", "password"),
+		AppID:  env.appID,
+	}
+
+	// the user holds docs.read through the reader role, not docs.write or admin
+	readID, err := env.repo.CreatePermission(ctx, env.appID, "docs.read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.repo.CreatePermission(ctx, env.appID, "docs.write"); err != nil {
+		t.Fatal(err)
+	}
+	roleID, err := env.repo.CreateRole(ctx, env.appID, "reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.GrantPermission(ctx, roleID, readID); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.AssignRole(ctx, caller.UserID, roleID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		ttl    time.Duration
+		want   error
+	}{
+		{name: "app permission", scopes: []string{"docs.read"}},
+		{name: "no scopes", ttl: 24 * time.Hour},
+		{name: "unknown scope", scopes: []string{"docs.read", "docs.delete"}, want: ErrUnknownScope},
+		{name: "permission not held", scopes: []string{"docs.read", "docs.write"}, want: ErrScopeNotHeld},
+		{name: "admin not held", scopes: []string{models.PermissionAdmin}, want: ErrScopeNotHeld},
+		{name: "negative ttl", ttl: -time.Hour, want: ErrInvalidTTL},
+		{name: "ttl over the maximum", ttl: 25 * time.Hour, want: ErrInvalidTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _, err := env.auth.CreateAPIKey(ctx, caller, tt.name, tt.scopes, tt.ttl)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CreateAPIKey() error = %v, want %v", err, tt.want)
+			}
+			if err == nil && !key.ExpiresAt.After(time.Now()) {
+				t.Errorf("CreateAPIKey() expires at %v, want a future time", key.ExpiresAt)
+			}
+		})
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sso/internal/config"
 	"sso/internal/domain/models"
 	"sso/internal/lib/jwt"
 	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/phone"
 	"sso/internal/otp"
 	"sso/internal/repository"
 	"sso/internal/services"
+	"sso/internal/services/directory"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -21,12 +26,44 @@ type Auth struct {
 	usrSaver               UserSaver
 	usrProvider            UserProvider
 	appProvider            AppProvider
+	lockoutProvider        LockoutProvider
+	permProvider           PermissionProvider
+	orgProvider            OrgProvider
+	groupProvider          GroupProvider
+	policyProvider         PolicyProvider
+	apiKeyRepo             APIKeyRepository
+	saProvider             ServiceAccountProvider
+	auditLog               AuditLog
+	invitationRepo         InvitationRepository
+	emailChangeRepo        EmailChangeRepository
+	phoneChangeRepo        PhoneChangeRepository
+	attributeProvider      AttributeProvider
+	consentRepo            ConsentRepository
+	grantProvider          GrantProvider
+	identityRepo           IdentityRepository
+	directoryRepo          DirectoryRepository
+	directory              directory.Directory
 	tokenTTL               time.Duration
 	emailService           *services.EmailService
+	smsService             *services.SMSService
 	otpGenerator           otp.Generator
 	verificationCodeLength int
 	repo                   repository.Redis
 	verCodeTTL             time.Duration
+	admin                  config.AdminConfig
+	lockout                config.LockoutConfig
+	codes                  config.CodesConfig
+	groupsClaim            config.GroupsClaimConfig
+	apiKeys                config.APIKeysConfig
+	impersonation          config.ImpersonationConfig
+	emailChange            config.EmailChangeConfig
+	consent                config.ConsentConfig
+	federation             config.FederationConfig
+	ldap                   config.LDAPConfig
+	providers              map[string]identityProvider
+	phones                 *phone.Normalizer
+	enumerationProtection  bool
+	dummyHash              []byte
 }
 
 type UserSaver interface {
@@ -44,58 +81,190 @@ type UserSaver interface {
 
 type UserProvider interface {
 	User(ctx context.Context, email string, phone string) (models.User, error)
-	IsAdmin(ctx context.Context, userID int64) (bool, error)
-	SetPassword(ctx context.Context, email string, newPassword []byte) (bool, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	IsAdmin(ctx context.Context, userID int64, appID int) (bool, error)
+	SetPassword(ctx context.Context, userID int64, newPassword []byte) (bool, error)
+	SetUserStatus(ctx context.Context, userID int64, from string, to string, reason string, changedAt time.Time) error
+	CancelUserDeletion(ctx context.Context, userID int64, cancelledAt time.Time) error
 }
 
 type AppProvider interface {
 	App(ctx context.Context, appID int) (models.App, error)
 }
 
+type PermissionProvider interface {
+	UserRoles(ctx context.Context, userID int64, appID int) ([]string, error)
+	HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error)
+	AppPermissions(ctx context.Context, appID int) ([]string, error)
+}
+
+type OrgProvider interface {
+	Organization(ctx context.Context, id int64) (models.Organization, error)
+	OrgMember(ctx context.Context, orgID int64, userID int64) (models.OrgMember, error)
+}
+
+type GroupProvider interface {
+	UserGroups(ctx context.Context, userID int64) ([]models.Group, error)
+}
+
+type AttributeProvider interface {
+	UserAttributes(ctx context.Context, userID int64, appID int) (models.Attributes, error)
+}
+
+type GrantProvider interface {
+	Grant(ctx context.Context, userID int64, appID int) (models.Grant, error)
+	AppTokensRevokedAt(ctx context.Context, userID int64, appID int) (time.Time, error)
+}
+
+type LockoutProvider interface {
+	LockoutState(ctx context.Context, userID int64) (models.LockoutState, error)
+	SaveLockoutState(ctx context.Context, state models.LockoutState) error
+	ResetLockoutState(ctx context.Context, userID int64) error
+}
+
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidAppID       = errors.New("invalid app id")
 	ErrUserExists         = errors.New("user already exists")
 	ErrUserNotFound       = errors.New("user not found")
+	ErrAccountLocked      = errors.New("account is temporarily locked")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrUserPending        = errors.New("user is not activated yet")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrInvalidOrgID       = errors.New("invalid organization id")
+	ErrNotOrgMember       = errors.New("user is not a member of the organization")
+	ErrEmailDomain        = errors.New("email domain is not allowed in the organization")
+	ErrMFARequired        = errors.New("organization requires multi-factor authentication")
+	ErrAppNotAuthorized   = errors.New("user hasn't granted the app access")
+	ErrInvalidScope       = errors.New("invalid scope")
 	//ErrNotValidCode       = errors.New("invalid code")
 )
 
+// Deps are the storages and services the Auth service works with.
+type Deps struct {
+	UserSaver         UserSaver
+	UserProvider      UserProvider
+	AppProvider       AppProvider
+	LockoutProvider   LockoutProvider
+	PermProvider      PermissionProvider
+	OrgProvider       OrgProvider
+	GroupProvider     GroupProvider
+	PolicyProvider    PolicyProvider
+	APIKeyRepo        APIKeyRepository
+	SAProvider        ServiceAccountProvider
+	AuditLog          AuditLog
+	InvitationRepo    InvitationRepository
+	EmailChangeRepo   EmailChangeRepository
+	PhoneChangeRepo   PhoneChangeRepository
+	AttributeProvider AttributeProvider
+	ConsentRepo       ConsentRepository
+	GrantProvider     GrantProvider
+	IdentityRepo      IdentityRepository
+	DirectoryRepo     DirectoryRepository
+	// Directory is nil unless logins are checked against a user directory.
+	Directory    directory.Directory
+	EmailService *services.EmailService
+	SMSService   *services.SMSService
+	OTPGenerator otp.Generator
+	Redis        repository.Redis
+}
+
+// Config is the configuration of the Auth service.
+type Config struct {
+	TokenTTL               time.Duration
+	VerificationCodeLength int
+	VerificationCodeTTL    time.Duration
+	Admin                  config.AdminConfig
+	Lockout                config.LockoutConfig
+	Codes                  config.CodesConfig
+	GroupsClaim            config.GroupsClaimConfig
+	APIKeys                config.APIKeysConfig
+	Impersonation          config.ImpersonationConfig
+	EmailChange            config.EmailChangeConfig
+	Phone                  config.PhoneConfig
+	Consent                config.ConsentConfig
+	Federation             config.FederationConfig
+	LDAP                   config.LDAPConfig
+	EnumerationProtection  bool
+}
+
 // New returns a new instance of the Auth service.
-func New(
-	log *slog.Logger,
-	userSaver UserSaver,
-	userProvider UserProvider,
-	appProvider AppProvider,
-	tokenTTL time.Duration,
-	emailService *services.EmailService,
-	otpGenerator otp.Generator,
-	verificationCodeLength int,
-	repo repository.Redis,
-	verCodeTTL time.Duration,
-) *Auth {
+func New(log *slog.Logger, deps Deps, cfg Config) *Auth {
+	// compared against on missing users, so it must have the same cost as real password hashes
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
 	return &Auth{
-		usrSaver:               userSaver,
-		usrProvider:            userProvider,
+		usrSaver:               deps.UserSaver,
+		usrProvider:            deps.UserProvider,
 		log:                    log,
-		emailService:           emailService,
-		appProvider:            appProvider,
-		tokenTTL:               tokenTTL,
-		otpGenerator:           otpGenerator,
-		verificationCodeLength: verificationCodeLength,
-		repo:                   repo,
-		verCodeTTL:             verCodeTTL,
+		emailService:           deps.EmailService,
+		smsService:             deps.SMSService,
+		appProvider:            deps.AppProvider,
+		lockoutProvider:        deps.LockoutProvider,
+		permProvider:           deps.PermProvider,
+		orgProvider:            deps.OrgProvider,
+		groupProvider:          deps.GroupProvider,
+		policyProvider:         deps.PolicyProvider,
+		apiKeyRepo:             deps.APIKeyRepo,
+		saProvider:             deps.SAProvider,
+		auditLog:               deps.AuditLog,
+		invitationRepo:         deps.InvitationRepo,
+		emailChangeRepo:        deps.EmailChangeRepo,
+		phoneChangeRepo:        deps.PhoneChangeRepo,
+		attributeProvider:      deps.AttributeProvider,
+		consentRepo:            deps.ConsentRepo,
+		grantProvider:          deps.GrantProvider,
+		identityRepo:           deps.IdentityRepo,
+		directoryRepo:          deps.DirectoryRepo,
+		directory:              deps.Directory,
+		tokenTTL:               cfg.TokenTTL,
+		otpGenerator:           deps.OTPGenerator,
+		verificationCodeLength: cfg.VerificationCodeLength,
+		repo:                   deps.Redis,
+		verCodeTTL:             cfg.VerificationCodeTTL,
+		admin:                  cfg.Admin,
+		lockout:                cfg.Lockout,
+		codes:                  cfg.Codes,
+		groupsClaim:            cfg.GroupsClaim,
+		apiKeys:                cfg.APIKeys,
+		impersonation:          cfg.Impersonation,
+		emailChange:            cfg.EmailChange,
+		consent:                cfg.Consent,
+		federation:             cfg.Federation,
+		ldap:                   cfg.LDAP,
+		providers:              newIdentityProviders(cfg.Federation),
+		phones:                 phone.NewNormalizer(cfg.Phone.DefaultCountryCode, cfg.Phone.TrunkPrefix),
+		enumerationProtection:  cfg.EnumerationProtection,
+		dummyHash:              dummyHash,
 	}
 }
 
 // Login checks if user with given credentials exists in the system and returns access token.
 //
 // If user exists, but password is incorrect, returns error. If user doesn’t exist, returns error.
+// Logging in cancels the deletion of the account requested by the user.
+// If user is locked out after too many failed attempts, returns ErrAccountLocked regardless of the password.
+// With enumeration protection on, missing and locked out users get ErrInvalidCredentials
+// after the same bcrypt work as existing ones.
+//
+// Non-zero orgID logs the user in to the organization, apps belonging to an organization
+// always log in to it. Users who aren't members of the organization or don't meet
+// its settings are rejected.
+//
+// Users who haven't accepted the current mandatory legal documents get ConsentRequiredError
+// with a consent challenge instead of the token, see AcceptTerms.
+//
+// With a directory configured, emails found in the directory log in with the directory password,
+// see directoryLogin, other ones with the local password. While the directory is unavailable,
+// users linked to it get ErrDirectoryUnavailable.
 func (a *Auth) Login(
 	ctx context.Context,
 	email string,
 	password string,
 	phone string,
 	appID int,
+	orgID int64,
 ) (models.User, string, error) {
 	const op = "auth.Login"
 
@@ -107,10 +276,26 @@ func (a *Auth) Login(
 
 	log.Info("logining user")
 
-	user, err := a.usrProvider.User(ctx, email, phone)
+	// local users can still log in while the directory is down, directory users can't
+	directoryDown := false
+	if a.directory != nil && email != "" {
+		user, token, err := a.directoryLogin(ctx, email, password, appID, orgID)
+		switch {
+		case err == nil:
+			return user, token, nil
+		case errors.Is(err, ErrDirectoryUnavailable):
+			directoryDown = true
+		case !errors.Is(err, directory.ErrUserNotFound):
+			return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	user, err := a.usrProvider.User(ctx, email, a.lookupPhone(phone))
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
 			a.log.Warn("user not found", sl.Err(err))
+			a.simulatePasswordCheck(password)
+
 			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
 
@@ -119,34 +304,353 @@ func (a *Auth) Login(
 		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	if directoryDown {
+		inDirectory, err := a.inDirectory(ctx, user.ID)
+		if err != nil {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+		if inDirectory {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrDirectoryUnavailable)
+		}
+	}
+
+	lockout, err := a.checkPasswordLogin(ctx, user, password)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
 		a.log.Info("invalid credentials", sl.Err(err))
+
+		if err := a.registerFailedLogin(ctx, user, lockout); err != nil {
+			a.log.Error("failed to register failed login", sl.Err(err))
+		}
+
 		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 	}
 
+	user, token, err := a.finishPasswordLogin(ctx, user, lockout, appID, orgID)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("user logged in succesfully")
+
+	return user, token, nil
+}
+
+// checkPasswordLogin checks that the user may log in with a password before the password is checked
+// and returns the lockout state of the user.
+func (a *Auth) checkPasswordLogin(ctx context.Context, user models.User, password string) (models.LockoutState, error) {
+	const op = "auth.checkPasswordLogin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", user.ID),
+	)
+
+	// the account is gone once the deletion grace period is over, even before it's purged
+	if user.Deleted(time.Now()) {
+		log.Warn("user is deleted")
+		a.simulatePasswordCheck(password)
+
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	if user.Status == models.UserStatusSuspended || user.Status == models.UserStatusPending {
+		log.Warn("user is not active", slog.String("status", user.Status))
+
+		if a.enumerationProtection {
+			a.simulatePasswordCheck(password)
+
+			return models.LockoutState{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+
+		if user.Status == models.UserStatusPending {
+			return models.LockoutState{}, fmt.Errorf("%s: %w", op, ErrUserPending)
+		}
+
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, ErrUserDisabled)
+	}
+
+	lockout, err := a.lockoutProvider.LockoutState(ctx, user.ID)
+	if err != nil {
+		log.Error("failed to get lockout state", sl.Err(err))
+
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if lockout.Locked(time.Now()) {
+		log.Warn("user is locked out", slog.Time("locked_until", lockout.LockedUntil))
+
+		if a.enumerationProtection {
+			a.simulatePasswordCheck(password)
+
+			return models.LockoutState{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+
+		return models.LockoutState{}, fmt.Errorf("%s: %w", op, ErrAccountLocked)
+	}
+
+	return lockout, nil
+}
+
+// finishPasswordLogin resets the lockout of the user whose password was right, cancels a requested
+// account deletion and issues the token.
+func (a *Auth) finishPasswordLogin(
+	ctx context.Context,
+	user models.User,
+	lockout models.LockoutState,
+	appID int,
+	orgID int64,
+) (models.User, string, error) {
+	const op = "auth.finishPasswordLogin"
+
+	if lockout.FailedAttempts > 0 {
+		if err := a.lockoutProvider.ResetLockoutState(ctx, user.ID); err != nil {
+			a.log.Error("failed to reset lockout state", sl.Err(err))
+		}
+	}
+
+	// the lockout is over once the password is right again
+	if user.Status == models.UserStatusLocked {
+		err := a.usrProvider.SetUserStatus(ctx, user.ID, models.UserStatusLocked, models.UserStatusActive, "", time.Now())
+		if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+			a.log.Error("failed to activate user", sl.Err(err))
+		}
+		user.Status = models.UserStatusActive
+	}
+
+	if user.DeletionPending(time.Now()) {
+		if err := a.cancelDeletion(ctx, user); err != nil {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+		user.Status = models.UserStatusActive
+		user.DeleteAfter = time.Time{}
+	}
+
+	token, err := a.loginToken(ctx, user, appID, orgID, models.GrantTypePassword, nil)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, token, nil
+}
+
+// loginToken issues the token of the user who proved their identity for the app and organization
+// with the scopes the app requested. Third-party apps only get tokens for scopes the user granted them.
+// Users who haven't accepted the current legal documents get a consent challenge instead.
+func (a *Auth) loginToken(
+	ctx context.Context,
+	user models.User,
+	appID int,
+	orgID int64,
+	grantType string,
+	scopes []string,
+) (string, error) {
+	const op = "auth.loginToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", user.ID),
+	)
+
 	app, err := a.appProvider.App(ctx, appID)
 	if err != nil {
 		if errors.Is(err, repository.ErrAppNotFound) {
 			a.log.Warn("app not found", sl.Err(err))
-			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
 		}
-		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.Disabled {
+		a.log.Warn("app is disabled", slog.Int("appID", app.ID))
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+	}
+
+	if !slices.Contains(app.GrantTypes, grantType) {
+		log.Warn("app doesn't allow the grant type", slog.Int("appID", app.ID), slog.String("grantType", grantType))
+
+		return "", fmt.Errorf("%s: %w", op, ErrGrantNotAllowed)
+	}
+
+	if app.ThirdParty() {
+		if err := a.checkGrant(ctx, user.ID, app.ID, scopes); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if app.OrgID != 0 {
+		if orgID != 0 && orgID != app.OrgID {
+			log.Warn("app belongs to another organization", slog.Int64("orgID", orgID), slog.Int64("appOrgID", app.OrgID))
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidOrgID)
+		}
+		orgID = app.OrgID
+	}
+
+	principal := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: user.ID,
+		Email:  user.Email,
+		AppID:  app.ID,
+		Scopes: scopes,
+	}
+
+	if orgID != 0 {
+		member, err := a.checkOrgAccess(ctx, user, orgID)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		principal.OrgID = member.OrgID
+		principal.OrgRole = member.Role
+	}
+
+	if err := a.checkConsents(ctx, user, app.ID, orgID, grantType, scopes); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	principal.Roles, err = a.permProvider.UserRoles(ctx, user.ID, app.ID)
+	if err != nil {
+		a.log.Error("failed to get user roles", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	token, err := jwt.NewToken(user, app, a.tokenTTL)
+	if a.groupsClaim.Enabled {
+		if err := a.addGroups(ctx, &principal); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := a.addAttributes(ctx, &principal, app); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
 
+	token, err := jwt.NewToken(principal, app, a.tokenTTL)
 	if err != nil {
 		a.log.Error("failed to generate token", sl.Err(err))
 
-		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		return "", fmt.Errorf("%s: %w", op, err)
 	}
-	log.Info("user logged in succesfully")
 
-	return user, token, nil
+	return token, nil
+}
+
+// checkGrant returns ErrAppNotAuthorized unless the user granted the app access to the scopes
+// on the consent screen. Revoking the access deletes the grant, so revoked apps need a new one.
+func (a *Auth) checkGrant(ctx context.Context, userID int64, appID int, scopes []string) error {
+	const op = "auth.checkGrant"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", userID),
+		slog.Int("appID", appID),
+	)
+
+	grant, err := a.grantProvider.Grant(ctx, userID, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrGrantNotFound) {
+			log.Warn("user hasn't granted the app access")
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotAuthorized)
+		}
+
+		log.Error("failed to get grant", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if missing := grant.MissingScopes(scopes); len(missing) > 0 {
+		log.Warn("user hasn't granted the app the scopes", slog.Any("scopes", missing))
+
+		return fmt.Errorf("%s: %w: %s", op, ErrAppNotAuthorized, strings.Join(missing, " "))
+	}
+
+	return nil
+}
+
+// cancelDeletion cancels the deletion of the account requested by the user.
+func (a *Auth) cancelDeletion(ctx context.Context, user models.User) error {
+	const op = "auth.cancelDeletion"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", user.ID),
+	)
+
+	if err := a.usrProvider.CancelUserDeletion(ctx, user.ID, time.Now()); err != nil {
+		log.Error("failed to cancel account deletion", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      user.ID,
+		Action:       models.AuditActionCancelDeletion,
+		TargetUserID: user.ID,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		log.Error("failed to save audit entry", sl.Err(err))
+	}
+
+	log.Warn("account deletion cancelled by logging in")
+
+	return nil
+}
+
+// addGroups lists groups of the principal's user in the principal, or marks the groups overage
+// if there are more of them than the groups claim can hold.
+func (a *Auth) addGroups(ctx context.Context, principal *models.Principal) error {
+	groups, err := a.groupProvider.UserGroups(ctx, principal.UserID)
+	if err != nil {
+		a.log.Error("failed to get user groups", sl.Err(err))
+
+		return err
+	}
+
+	if len(groups) > a.groupsClaim.MaxGroups {
+		principal.GroupsOverage = true
+
+		return nil
+	}
+
+	principal.Groups = make([]string, 0, len(groups))
+	for _, group := range groups {
+		principal.Groups = append(principal.Groups, group.Name)
+	}
+
+	return nil
+}
+
+// addAttributes puts attributes of the principal's user the app maps to claims into the principal.
+func (a *Auth) addAttributes(ctx context.Context, principal *models.Principal, app models.App) error {
+	if len(app.ClaimAttributes) == 0 {
+		return nil
+	}
+
+	attrs, err := a.attributeProvider.UserAttributes(ctx, principal.UserID, app.ID)
+	if err != nil {
+		a.log.Error("failed to get user attributes", sl.Err(err))
+
+		return err
+	}
+
+	principal.Attributes = models.Attributes{}
+	for _, key := range app.ClaimAttributes {
+		if value, ok := attrs[key]; ok {
+			principal.Attributes[key] = value
+		}
+	}
+
+	return nil
 }
 
 // RegisterNewUser registers new user in the system and returns user ID.
 // If user with given username already exists, returns error.
+// With enumeration protection on, returns zero ID and no error in both cases.
 func (a *Auth) RegisterNewUser(ctx context.Context,
 	title string,
 	birthDate string,
@@ -165,6 +669,13 @@ func (a *Auth) RegisterNewUser(ctx context.Context,
 
 	log.Info("registering user")
 
+	phone, err := a.phones.Normalize(phone)
+	if err != nil {
+		log.Warn("invalid phone", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidPhone)
+	}
+
 	// todo: add salt into password
 	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
 	if err != nil {
@@ -178,6 +689,10 @@ func (a *Auth) RegisterNewUser(ctx context.Context,
 		if errors.Is(err, repository.ErrUserExists) {
 			log.Warn("user already exists", sl.Err(err))
 
+			if a.enumerationProtection {
+				return 0, nil
+			}
+
 			return 0, fmt.Errorf("%s: %w", op, ErrUserExists)
 		}
 
@@ -187,10 +702,14 @@ func (a *Auth) RegisterNewUser(ctx context.Context,
 	}
 	log.Info("user registered!")
 
+	if a.enumerationProtection {
+		return 0, nil
+	}
+
 	return id, nil
 }
 
-// IsAdmin checks if user is admin.
+// IsAdmin checks if user administers the SSO, i.e. holds the admin permission in the SSO admin app.
 func (a *Auth) IsAdmin(ctx context.Context,
 	userID int64,
 ) (bool, error) {
@@ -203,12 +722,17 @@ func (a *Auth) IsAdmin(ctx context.Context,
 
 	log.Info("checking if user is admin")
 
-	isAdmin, err := a.usrProvider.IsAdmin(ctx, userID)
+	isAdmin, err := a.usrProvider.IsAdmin(ctx, userID, a.admin.AppID)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
 			log.Warn("user not found")
+
+			return false, fmt.Errorf("%s: %w", op, ErrUserNotFound)
 		}
-		return false, fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+
+		log.Error("failed to check if user is admin", sl.Err(err))
+
+		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Info("checked if user is admin", slog.Bool("is admin", isAdmin))
@@ -216,6 +740,171 @@ func (a *Auth) IsAdmin(ctx context.Context,
 	return isAdmin, nil
 }
 
+// Authorize verifies the access token and checks that its user or service account holds the permission
+// in the app the token was issued for. Empty permission only verifies the token.
+// Impersonated tokens only pass with empty permission.
+func (a *Auth) Authorize(ctx context.Context, token string, permission string) (models.Principal, error) {
+	const op = "auth.Authorize"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("permission", permission),
+	)
+
+	principal, err := jwt.ParseToken(token, func(appID int) ([]byte, error) {
+		app, err := a.appProvider.App(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+		if app.Disabled {
+			return nil, fmt.Errorf("app %d is disabled", appID)
+		}
+
+		return app.SecretHash, nil
+	})
+	if err != nil {
+		log.Warn("invalid token", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if principal.Type == models.PrincipalServiceAccount {
+		return a.authorizeServiceAccount(ctx, principal, permission)
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, principal.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Warn("token user not found", slog.Int64("userID", principal.UserID))
+
+			return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Blocked() {
+		log.Warn("token user is not active", slog.Int64("userID", user.ID), slog.String("status", user.Status))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	// tokens without iat predate revocation, so they are revoked too
+	if principal.IssuedAt.Before(user.TokensValidAfter) {
+		log.Warn("token was revoked", slog.Int64("userID", user.ID))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	// the user may have revoked the app's access since the token was issued
+	revokedAt, err := a.grantProvider.AppTokensRevokedAt(ctx, principal.UserID, principal.AppID)
+	if err != nil {
+		log.Error("failed to get app token revocation", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if principal.IssuedAt.Before(revokedAt) {
+		log.Warn("app access was revoked", slog.Int64("userID", user.ID), slog.Int("appID", principal.AppID))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if principal.OrgID != 0 {
+		if _, err := a.orgProvider.OrgMember(ctx, principal.OrgID, principal.UserID); err != nil {
+			if errors.Is(err, repository.ErrNotOrgMember) {
+				log.Warn("token user left the organization", slog.Int64("orgID", principal.OrgID))
+
+				return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+			}
+
+			log.Error("failed to get organization member", sl.Err(err))
+
+			return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if principal.ActorID != 0 {
+		if err := a.checkActor(ctx, principal); err != nil {
+			return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if permission == "" {
+		return principal, nil
+	}
+
+	// impersonation lets support staff see what the user sees, not use the user's privileges
+	if principal.ActorID != 0 {
+		log.Warn("impersonated token used for a privileged call", slog.Int64("actorID", principal.ActorID))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	has, err := a.permProvider.HasPermission(ctx, principal.UserID, principal.AppID, permission)
+	if err != nil {
+		log.Error("failed to check permission", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !has {
+		log.Warn("permission denied", slog.Int64("userID", principal.UserID), slog.Int("appID", principal.AppID))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	return principal, nil
+}
+
+// AuthorizeAdmin authorizes the caller administering the SSO itself: the token must be issued
+// for the SSO admin app to a user or service account holding the admin permission there.
+// Admins of other apps are denied, they administer only their app.
+func (a *Auth) AuthorizeAdmin(ctx context.Context, token string) (models.Principal, error) {
+	const op = "auth.AuthorizeAdmin"
+
+	principal, err := a.Authorize(ctx, token, models.PermissionAdmin)
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if a.admin.AppID == 0 || principal.AppID != a.admin.AppID {
+		a.log.Warn("token of another app used to administer the sso",
+			slog.String("op", op),
+			slog.Int64("userID", principal.UserID),
+			slog.Int("appID", principal.AppID),
+		)
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	return principal, nil
+}
+
+// HasPermission checks if any role of the user in the app grants the permission.
+func (a *Auth) HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error) {
+	const op = "auth.HasPermission"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", userID),
+		slog.Int("appID", appID),
+		slog.String("permission", permission),
+	)
+
+	has, err := a.permProvider.HasPermission(ctx, userID, appID, permission)
+	if err != nil {
+		log.Error("failed to check permission", sl.Err(err))
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("checked permission", slog.Bool("has permission", has))
+
+	return has, nil
+}
+
 func (a *Auth) ChangePasswordInit(ctx context.Context, email string, phone string, oldPassword string) (string, int64, error) {
 	const op = "auth.ChangePasswordInit"
 
@@ -226,10 +915,12 @@ func (a *Auth) ChangePasswordInit(ctx context.Context, email string, phone strin
 
 	log.Info("getting user...")
 
-	user, err := a.usrProvider.User(ctx, email, phone)
+	user, err := a.usrProvider.User(ctx, email, a.lookupPhone(phone))
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
 			a.log.Warn("user not found", sl.Err(err))
+			a.simulatePasswordCheck(oldPassword)
+
 			return "", 0, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
 		a.log.Error("failed to get user", sl.Err(err))
@@ -237,28 +928,37 @@ func (a *Auth) ChangePasswordInit(ctx context.Context, email string, phone strin
 		return "", 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// the old password is guessed here as easily as at Login, so the lockout applies as well
+	lockout, err := a.checkPasswordLogin(ctx, user, oldPassword)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(oldPassword)); err != nil {
 		a.log.Info("invalid credentials", sl.Err(err))
+
+		if err := a.registerFailedLogin(ctx, user, lockout); err != nil {
+			a.log.Error("failed to register failed login", sl.Err(err))
+		}
+
 		return "", 0, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 	}
 
-	log.Info("got user, sending verification email")
+	if lockout.FailedAttempts > 0 {
+		if err := a.lockoutProvider.ResetLockoutState(ctx, user.ID); err != nil {
+			a.log.Error("failed to reset lockout state", sl.Err(err))
+		}
+	}
 
-	verificationCode := a.otpGenerator.RandomSecret(a.verificationCodeLength)
+	log.Info("got user, sending verification email")
 
-	uid := user.ID
-	//a.log.Info("uid:", slog.Int64("uid", uid))
-	err = a.repo.SaveCode(ctx, verificationCode, uid)
+	verificationCode, expiresAt, err := a.issueCode(ctx, models.CodePurposePasswordChange, user.ID)
 	if err != nil {
-		a.log.Error("failed to save verification code", sl.Err(err))
+		return "", 0, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Info("code saved in redis")
 
-	redisTTL := a.verCodeTTL
-	expiresAt := time.Now().UTC().Add(redisTTL).Format(time.RFC3339)
-	//log.Info("expires at:", expiresAt)
-
 	err = a.emailService.SendVerificationEmail(services.VerificationEmailInput{
 		Email:            email,
 		Name:             user.Name,
@@ -270,10 +970,11 @@ func (a *Auth) ChangePasswordInit(ctx context.Context, email string, phone strin
 
 	log.Info("email has sent")
 
-	return expiresAt, uid, nil
+	return expiresAt.Format(time.RFC3339), user.ID, nil
 }
 
-func (a *Auth) ChangePasswordConfirm(ctx context.Context, verificationCode string, uid int64, email string, newPassword string) (bool, error) {
+// ChangePasswordConfirm sets the new password of the user the code was issued to.
+func (a *Auth) ChangePasswordConfirm(ctx context.Context, verificationCode string, uid int64, newPassword string) (bool, error) {
 	const op = "auth.ChangePasswordConfirm"
 
 	log := a.log.With(
@@ -282,23 +983,10 @@ func (a *Auth) ChangePasswordConfirm(ctx context.Context, verificationCode strin
 
 	log.Info("comparing verification code")
 
-	code, err := a.repo.Code(ctx, uid)
-	if err != nil {
-		if errors.Is(err, repository.ErrCodeNotFound) {
-			a.log.Warn("invalid verification code", sl.Err(err))
-			return false, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
-		}
-
-		a.log.Error("failed to get code", sl.Err(err))
-
+	if err := a.verifyCode(ctx, models.CodePurposePasswordChange, uid, verificationCode); err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if code.Code != verificationCode {
-		a.log.Warn("codes doesn't match")
-		return false, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
-	}
-
 	log.Info("correct code!")
 
 	passHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
@@ -308,7 +996,7 @@ func (a *Auth) ChangePasswordConfirm(ctx context.Context, verificationCode strin
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	success, err := a.usrProvider.SetPassword(ctx, email, passHash)
+	success, err := a.usrProvider.SetPassword(ctx, uid, passHash)
 	if err != nil {
 		a.log.Info("failed to change password", sl.Err(err))
 		return false, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
@@ -318,3 +1006,92 @@ func (a *Auth) ChangePasswordConfirm(ctx context.Context, verificationCode strin
 
 	return success, nil
 }
+
+// registerFailedLogin counts the failed attempt and delays the next one.
+// Every failure doubles the delay, and after lockout.MaxAttempts failures the user
+// is locked out for lockout.Duration and notified by email. Active users get the locked status
+// until they log in once the lockout is over.
+func (a *Auth) registerFailedLogin(ctx context.Context, user models.User, state models.LockoutState) error {
+	const op = "auth.registerFailedLogin"
+
+	if a.lockout.MaxAttempts <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	state.UserID = user.ID
+	state, locked := a.countFailedAttempt(state, now)
+
+	if err := a.lockoutProvider.SaveLockoutState(ctx, state); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if locked {
+		a.log.Warn("user locked out", slog.Int64("userID", user.ID), slog.Time("locked_until", state.LockedUntil))
+
+		if user.Status == models.UserStatusActive {
+			err := a.usrProvider.SetUserStatus(ctx, user.ID, models.UserStatusActive, models.UserStatusLocked, "too many failed login attempts", now)
+			if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		// sent in background so that the response time doesn't reveal the lockout
+		go func() {
+			err := a.emailService.SendAccountLockedEmail(services.AccountLockedEmailInput{
+				Email:       user.Email,
+				Name:        user.Name,
+				LockedUntil: state.LockedUntil.UTC().Format(time.RFC1123),
+			})
+			if err != nil {
+				a.log.Error("failed to send account locked email", sl.Err(err))
+			}
+		}()
+	}
+
+	return nil
+}
+
+// countFailedAttempt adds the failed attempt made at now to the state and delays the next one,
+// reporting whether the attempts are used up and the state is locked for lockout.Duration.
+func (a *Auth) countFailedAttempt(state models.LockoutState, now time.Time) (models.LockoutState, bool) {
+	// previous lockout has expired or the attempts are too old to count, start counting from scratch
+	if state.FailedAttempts >= a.lockout.MaxAttempts ||
+		(a.lockout.Window > 0 && now.Sub(state.FirstFailedAt) > a.lockout.Window) {
+		state.FailedAttempts = 0
+	}
+	if state.FailedAttempts == 0 {
+		state.FirstFailedAt = now
+	}
+
+	state.FailedAttempts++
+	state.LastFailedAt = now
+	state.LockedUntil = now.Add(a.failedLoginDelay(state.FailedAttempts))
+
+	locked := state.FailedAttempts >= a.lockout.MaxAttempts
+	if locked {
+		state.LockedUntil = now.Add(a.lockout.Duration)
+	}
+
+	return state, locked
+}
+
+// simulatePasswordCheck spends the same time as checking password of an existing user,
+// so that response time doesn't reveal whether the user exists.
+func (a *Auth) simulatePasswordCheck(password string) {
+	if !a.enumerationProtection {
+		return
+	}
+
+	_ = bcrypt.CompareHashAndPassword(a.dummyHash, []byte(password))
+}
+
+func (a *Auth) failedLoginDelay(attempts int) time.Duration {
+	delay := a.lockout.BaseDelay
+	for i := 1; i < attempts && delay < a.lockout.Duration; i++ {
+		delay *= 2
+	}
+
+	return min(delay, a.lockout.Duration)
+}
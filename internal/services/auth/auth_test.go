@@ -0,0 +1,369 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/handlers/slogdiscard"
+	"sso/internal/repository/sqlite"
+	"sso/internal/services"
+	"sso/internal/services/directory"
+	"sso/internal/services/email"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const migrationsPath = "../../../migrations"
+
+// testOptions are the settings of the Auth under test that tests vary, zero values are fine.
+type testOptions struct {
+	lockout               config.LockoutConfig
+	federation            config.FederationConfig
+	ldap                  config.LDAPConfig
+	directory             directory.Directory
+	enumerationProtection bool
+}
+
+type testEnv struct {
+	auth        *Auth
+	repo        *sqlite.Repository
+	storagePath string
+	appID       int
+}
+
+type nopSender struct{}
+
+func (nopSender) Send(email.SendEmailInput) error { return nil }
+
+// newTestEnv returns Auth over a migrated storage of its own with an app users log in to
+// with passwords.
+func newTestEnv(t *testing.T, opts testOptions) testEnv {
+	t.Helper()
+
+	storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://"+migrationsPath, "sqlite3://"+storagePath)
+	if err != nil {
+		t.Fatalf("open migrations: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	m.Close()
+
+	repo, err := sqlite.New(storagePath)
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	t.Cleanup(func() { repo.Stop() })
+
+	log := slogdiscard.NewDiscardLogger()
+	emails, _ := services.NewEmailService(log, nopSender{}, config.EmailConfig{})
+
+	a := New(log, Deps{
+		UserSaver:         repo,
+		UserProvider:      repo,
+		AppProvider:       repo,
+		LockoutProvider:   repo,
+		PermProvider:      repo,
+		OrgProvider:       repo,
+		GroupProvider:     repo,
+		PolicyProvider:    repo,
+		APIKeyRepo:        repo,
+		SAProvider:        repo,
+		AuditLog:          repo,
+		InvitationRepo:    repo,
+		EmailChangeRepo:   repo,
+		PhoneChangeRepo:   repo,
+		AttributeProvider: repo,
+		ConsentRepo:       repo,
+		GrantProvider:     repo,
+		IdentityRepo:      repo,
+		DirectoryRepo:     repo,
+		Directory:         opts.directory,
+		EmailService:      emails,
+	}, Config{
+		TokenTTL:               time.Hour,
+		VerificationCodeLength: 6,
+		VerificationCodeTTL:    time.Minute,
+		Lockout:                opts.lockout,
+		Consent:                config.ConsentConfig{ChallengeTTL: time.Minute},
+		Federation:             opts.federation,
+		LDAP:                   opts.ldap,
+		EnumerationProtection:  opts.enumerationProtection,
+	})
+
+	appID, err := repo.CreateApp(context.Background(), models.App{
+		Name:         "test-app",
+		SecretHash:   []byte("test-app-secret"),
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		GrantTypes:   []string{models.GrantTypePassword, models.GrantTypeAuthorizationCode},
+	})
+	if err != nil {
+		t.Fatalf("create app: %v", err)
+	}
+
+	return testEnv{auth: a, repo: repo, storagePath: storagePath, appID: appID}
+}
+
+// createUser saves a user with the password and returns their ID.
+func (e testEnv) createUser(t *testing.T, email string, password string) int64 {
+	t.Helper()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := e.repo.SaveUser(context.Background(), "", "", "Test", "User", email, passHash, "")
+	if err != nil {
+		t.Fatalf("save user %s: %v", email, err)
+	}
+
+	return id
+}
+
+// exec runs the statement on the storage directly, for states the repository doesn't set.
+func (e testEnv) exec(t *testing.T, query string, args ...any) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", e.storagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
+
+// medianDuration runs f n times and returns its median duration.
+func medianDuration(n int, f func()) time.Duration {
+	durations := make([]time.Duration, n)
+	for i := range durations {
+		start := time.Now()
+		f()
+		durations[i] = time.Since(start)
+	}
+	slices.Sort(durations)
+
+	return durations[n/2]
+}
+
+// comparable tells whether the durations differ less than the bcrypt work a path may skip.
+func comparable(a, b time.Duration) bool {
+	return max(a, b) < 2*min(a, b)
+}
+
+func TestEnumerationProtectionTiming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("measures bcrypt work")
+	}
+
+	env := newTestEnv(t, testOptions{enumerationProtection: true})
+	env.createUser(t, "known@example.com", "right password")
+
+	ctx := context.Background()
+	const runs = 5
+
+	t.Run("login", func(t *testing.T) {
+		login := func(email string) func() {
+			return func() {
+				_, _, err := env.auth.Login(ctx, email, "wrong password", "", env.appID, 0)
+				if !errors.Is(err, ErrInvalidCredentials) {
+					t.Errorf("Login(%s) error = %v, want %v", email, err, ErrInvalidCredentials)
+				}
+			}
+		}
+
+		known := medianDuration(runs, login("known@example.com"))
+		unknown := medianDuration(runs, login("unknown@example.com"))
+
+		if !comparable(known, unknown) {
+			t.Errorf("Login took %v for a known email and %v for an unknown one", known, unknown)
+		}
+	})
+
+	t.Run("register", func(t *testing.T) {
+		taken := medianDuration(runs, func() {
+			id, err := env.auth.RegisterNewUser(ctx, "", "", "Test", "", "known@example.com", "password", "+79990000000")
+			if err != nil || id != 0 {
+				t.Errorf("RegisterNewUser(taken) = %d, %v, want 0, nil", id, err)
+			}
+		})
+
+		var i int
+		free := medianDuration(runs, func() {
+			i++
+			id, err := env.auth.RegisterNewUser(ctx, "", "", "Test", "", fmt.Sprintf("new%d@example.com", i), "password", fmt.Sprintf("+7999000000%d", i))
+			if err != nil || id != 0 {
+				t.Errorf("RegisterNewUser(free) = %d, %v, want 0, nil", id, err)
+			}
+		})
+
+		if !comparable(taken, free) {
+			t.Errorf("RegisterNewUser took %v for a taken email and %v for a free one", taken, free)
+		}
+	})
+}
+
+// createThirdPartyApp saves an app that registered itself with the grant types and returns its ID.
+func (e testEnv) createThirdPartyApp(t *testing.T, grantTypes ...string) int {
+	t.Helper()
+
+	appID, err := e.repo.CreateApp(context.Background(), models.App{
+		Name:                  "third-party",
+		SecretHash:            []byte("third-party-secret"),
+		RedirectURIs:          []string{"https://app.example.com/callback"},
+		GrantTypes:            grantTypes,
+		RegistrationTokenHash: []byte("registration token hash"),
+		RegisteredAt:          time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("create app: %v", err)
+	}
+
+	return appID
+}
+
+func TestLoginRequiresPasswordGrant(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	appID, err := env.repo.CreateApp(ctx, models.App{
+		Name:         "code-only",
+		SecretHash:   []byte("code-only-secret"),
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		GrantTypes:   []string{models.GrantTypeAuthorizationCode},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", appID, 0); !errors.Is(err, ErrGrantNotAllowed) {
+		t.Errorf("Login() error = %v, want %v", err, ErrGrantNotAllowed)
+	}
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); err != nil {
+		t.Errorf("Login() to app with the password grant error = %v", err)
+	}
+}
+
+func TestLoginThirdPartyAppRequiresGrant(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	userID := env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	appID := env.createThirdPartyApp(t, models.GrantTypePassword)
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", appID, 0); !errors.Is(err, ErrAppNotAuthorized) {
+		t.Errorf("Login() without grant error = %v, want %v", err, ErrAppNotAuthorized)
+	}
+
+	err := env.repo.SaveGrant(ctx, models.Grant{UserID: userID, AppID: appID, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", appID, 0); err != nil {
+		t.Errorf("Login() with grant error = %v", err)
+	}
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	ctx := context.Background()
+
+	ssoAppID, err := env.repo.CreateApp(ctx, models.App{
+		Name:       "sso-admin",
+		SecretHash: []byte("sso-admin-secret"),
+		GrantTypes: []string{models.GrantTypePassword},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.auth.admin = config.AdminConfig{AppID: ssoAppID}
+
+	// admins of the apps their tokens are issued for
+	ssoAdminID := env.createUser(t, "sso-admin@example.com", "password")
+	appAdminID := env.createUser(t, "app-admin@example.com", "password")
+	for userID, appID := range map[int64]int{ssoAdminID: ssoAppID, appAdminID: env.appID} {
+		role, err := env.repo.Role(ctx, appID, "admin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := env.repo.AssignRole(ctx, userID, role.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, ssoToken, err := env.auth.Login(ctx, "sso-admin@example.com", "password", "", ssoAppID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, appToken, err := env.auth.Login(ctx, "app-admin@example.com", "password", "", env.appID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal, err := env.auth.AuthorizeAdmin(ctx, ssoToken); err != nil || principal.UserID != ssoAdminID {
+		t.Errorf("AuthorizeAdmin() of the SSO admin = %+v, %v, want the admin", principal, err)
+	}
+	if _, err := env.auth.AuthorizeAdmin(ctx, appToken); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("AuthorizeAdmin() of an app admin error = %v, want %v", err, ErrPermissionDenied)
+	}
+
+	if isAdmin, err := env.auth.IsAdmin(ctx, ssoAdminID); err != nil || !isAdmin {
+		t.Errorf("IsAdmin() of the SSO admin = %v, %v, want true", isAdmin, err)
+	}
+	if isAdmin, err := env.auth.IsAdmin(ctx, appAdminID); err != nil || isAdmin {
+		t.Errorf("IsAdmin() of an app admin = %v, %v, want false", isAdmin, err)
+	}
+
+	// without the SSO admin app nobody administers the SSO
+	env.auth.admin = config.AdminConfig{}
+	if _, err := env.auth.AuthorizeAdmin(ctx, ssoToken); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("AuthorizeAdmin() without the SSO admin app error = %v, want %v", err, ErrPermissionDenied)
+	}
+}
+
+func TestLoginCancelsAccountDeletion(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	userID := env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	now := time.Now()
+	if err := env.repo.ScheduleUserDeletion(ctx, userID, now.Add(time.Hour), now); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); err != nil {
+		t.Fatalf("Login() during the grace period error = %v", err)
+	}
+
+	user, err := env.repo.UserByID(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Status != models.UserStatusActive || !user.DeleteAfter.IsZero() {
+		t.Errorf("user status = %q, delete after %v, want the deletion cancelled", user.Status, user.DeleteAfter)
+	}
+
+	// once the grace period is over the account is gone
+	if err := env.repo.ScheduleUserDeletion(ctx, userID, now.Add(-time.Second), now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login() after the grace period error = %v, want %v", err, ErrInvalidCredentials)
+	}
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/policy"
+	"sso/internal/repository"
+	"strconv"
+)
+
+type PolicyProvider interface {
+	PolicyRules(ctx context.Context, appID int) ([]models.PolicyRule, error)
+}
+
+// Check decides whether the subject may perform the action on the resource according to policy rules of the app.
+func (a *Auth) Check(ctx context.Context, req models.AccessRequest) (models.Decision, error) {
+	const op = "auth.Check"
+
+	decisions, err := a.BatchCheck(ctx, []models.AccessRequest{req})
+	if err != nil {
+		return models.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return decisions[0], nil
+}
+
+// BatchCheck decides the access requests in order. Rules of each app and attributes
+// of each subject are loaded once for the whole batch.
+func (a *Auth) BatchCheck(ctx context.Context, reqs []models.AccessRequest) ([]models.Decision, error) {
+	const op = "auth.BatchCheck"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("checks", len(reqs)),
+	)
+
+	type subjectKey struct {
+		userID int64
+		appID  int
+		orgID  int64
+	}
+
+	var (
+		rules     = make(map[int][]models.PolicyRule)
+		subjects  = make(map[subjectKey]policy.Attributes)
+		decisions = make([]models.Decision, 0, len(reqs))
+	)
+
+	for _, req := range reqs {
+		appRules, ok := rules[req.AppID]
+		if !ok {
+			var err error
+			appRules, err = a.policyProvider.PolicyRules(ctx, req.AppID)
+			if err != nil {
+				log.Error("failed to get policy rules", sl.Err(err))
+
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			rules[req.AppID] = appRules
+		}
+
+		key := subjectKey{userID: req.SubjectID, appID: req.AppID, orgID: req.OrgID}
+		subject, ok := subjects[key]
+		if !ok {
+			var err error
+			subject, err = a.subjectAttributes(ctx, req)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			subjects[key] = subject
+		}
+
+		if subject == nil {
+			decisions = append(decisions, models.Decision{Reason: "subject is not an active user"})
+
+			continue
+		}
+
+		attrs := make(policy.Attributes, len(req.ResourceAttrs)+3)
+		for name, value := range req.ResourceAttrs {
+			attrs["resource."+name] = []string{value}
+		}
+		// set last, so that resource attributes named type or id don't override them
+		attrs["action"] = []string{req.Action}
+		attrs["resource.type"] = []string{req.ResourceType}
+		attrs["resource.id"] = []string{req.ResourceID}
+		for name, values := range subject {
+			attrs[name] = values
+		}
+
+		decision := policy.Evaluate(appRules, attrs, req.Action, req.ResourceType)
+
+		log.Debug(
+			"checked access",
+			slog.Int64("subjectID", req.SubjectID),
+			slog.String("action", req.Action),
+			slog.String("resourceType", req.ResourceType),
+			slog.Bool("allowed", decision.Allowed),
+			slog.String("reason", decision.Reason),
+		)
+
+		decisions = append(decisions, decision)
+	}
+
+	return decisions, nil
+}
+
+// subjectAttributes returns attributes of the subject of the request, or nil if the subject
+// is missing or disabled, so that it is denied everything.
+func (a *Auth) subjectAttributes(ctx context.Context, req models.AccessRequest) (policy.Attributes, error) {
+	user, err := a.usrProvider.UserByID(ctx, req.SubjectID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil
+		}
+
+		a.log.Error("failed to get user", sl.Err(err))
+
+		return nil, err
+	}
+
+	if user.Blocked() {
+		return nil, nil
+	}
+
+	roles, err := a.permProvider.UserRoles(ctx, user.ID, req.AppID)
+	if err != nil {
+		a.log.Error("failed to get user roles", sl.Err(err))
+
+		return nil, err
+	}
+
+	groups, err := a.groupProvider.UserGroups(ctx, user.ID)
+	if err != nil {
+		a.log.Error("failed to get user groups", sl.Err(err))
+
+		return nil, err
+	}
+
+	attrs := policy.Attributes{
+		"subject.id":     {strconv.FormatInt(user.ID, 10)},
+		"subject.email":  {user.Email},
+		"subject.roles":  roles,
+		"subject.groups": make([]string, 0, len(groups)),
+	}
+	for _, group := range groups {
+		attrs["subject.groups"] = append(attrs["subject.groups"], group.Name)
+	}
+
+	if req.OrgID != 0 {
+		member, err := a.orgProvider.OrgMember(ctx, req.OrgID, user.ID)
+		if err != nil && !errors.Is(err, repository.ErrNotOrgMember) {
+			a.log.Error("failed to get organization member", sl.Err(err))
+
+			return nil, err
+		}
+
+		// non-members have no organization attributes, so conditions on them fail
+		if err == nil {
+			attrs["subject.org_id"] = []string{strconv.FormatInt(member.OrgID, 10)}
+			attrs["subject.org_role"] = []string{member.Role}
+		}
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"sso/internal/domain/models"
+	"testing"
+)
+
+func TestCheckResourceAttrsDontOverrideResource(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	ctx := context.Background()
+	userID := env.createUser(t, "user@example.com", "password")
+
+	_, err := env.repo.CreatePolicyRule(ctx, models.PolicyRule{
+		AppID:   env.appID,
+		Name:    "public documents",
+		Effect:  models.EffectAllow,
+		Actions: []string{"document.read"},
+		Conditions: []models.PolicyCondition{
+			{Attribute: "resource.id", Op: models.ConditionEq, Value: "public"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := models.AccessRequest{
+		SubjectID:    userID,
+		AppID:        env.appID,
+		Action:       "document.read",
+		ResourceType: "document",
+		ResourceID:   "public",
+	}
+	if decision, err := env.auth.Check(ctx, req); err != nil || !decision.Allowed {
+		t.Fatalf("Check() of the public document = %+v, %v, want allowed", decision, err)
+	}
+
+	req.ResourceID = "secret"
+	req.ResourceAttrs = map[string]string{"id": "public", "type": "other"}
+	if decision, err := env.auth.Check(ctx, req); err != nil || decision.Allowed {
+		t.Errorf("Check() of the secret document with an id attribute = %+v, %v, want denied", decision, err)
+	}
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidCode        = errors.New("invalid verification code")
+	ErrTooManyAttempts    = errors.New("too many verification attempts")
+	ErrCodeResendCooldown = errors.New("verification code was sent recently")
+)
+
+// issueCode generates a verification code for the given flow and stores its digest.
+// The plaintext code is returned to be delivered to the user and is never stored.
+// Resend cooldown starts once the code is stored, so that failing to store it doesn't hold the user back.
+func (a *Auth) issueCode(ctx context.Context, purpose models.CodePurpose, uid int64) (string, time.Time, error) {
+	const op = "auth.issueCode"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("purpose", string(purpose)),
+		slog.Int64("uid", uid),
+	)
+
+	cooling, err := a.repo.CodeCooldown(ctx, purpose, uid)
+	if err != nil {
+		log.Error("failed to get resend cooldown", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if cooling {
+		log.Warn("code was sent recently")
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrCodeResendCooldown)
+	}
+
+	code := a.otpGenerator.RandomSecret(a.verificationCodeLength)
+
+	err = a.repo.SaveCode(ctx, models.Code{
+		UserID:  uid,
+		Purpose: purpose,
+		Digest:  a.codeDigest(purpose, uid, code),
+	})
+	if err != nil {
+		log.Error("failed to save verification code", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.repo.SetCodeCooldown(ctx, purpose, uid, a.codes.ResendCooldown); err != nil {
+		log.Error("failed to set resend cooldown", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, time.Now().UTC().Add(a.verCodeTTL), nil
+}
+
+// verifyCode checks the code issued for the given flow. The code is deleted once it is used
+// or when it runs out of attempts, concurrent checks can't use it twice.
+func (a *Auth) verifyCode(ctx context.Context, purpose models.CodePurpose, uid int64, code string) error {
+	const op = "auth.verifyCode"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("purpose", string(purpose)),
+		slog.Int64("uid", uid),
+	)
+
+	err := a.repo.UseCode(ctx, purpose, uid, a.codeDigest(purpose, uid, code), a.codes.MaxAttempts)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCodeNotFound):
+			log.Warn("code not found")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidCode)
+		case errors.Is(err, repository.ErrCodeMismatch):
+			log.Warn("codes don't match")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidCode)
+		case errors.Is(err, repository.ErrCodeExhausted):
+			log.Warn("too many attempts")
+
+			return fmt.Errorf("%s: %w", op, ErrTooManyAttempts)
+		}
+
+		log.Error("failed to use code", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a *Auth) codeDigest(purpose models.CodePurpose, uid int64, code string) string {
+	mac := hmac.New(sha256.New, []byte(a.codes.Secret))
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(uid, 10)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(code))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
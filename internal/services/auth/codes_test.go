@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/otp"
+	"sso/internal/repository"
+	"testing"
+	"time"
+)
+
+// codeRepo keeps codes and cooldowns in memory, saving codes fails while saveErr is set.
+type codeRepo struct {
+	codes     map[models.CodePurpose]models.Code
+	cooldowns map[models.CodePurpose]bool
+	saveErr   error
+}
+
+func (r *codeRepo) SaveCode(_ context.Context, code models.Code) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	r.codes[code.Purpose] = code
+
+	return nil
+}
+
+func (r *codeRepo) UseCode(
+	_ context.Context,
+	purpose models.CodePurpose,
+	_ int64,
+	digest string,
+	maxAttempts int,
+) error {
+	code, ok := r.codes[purpose]
+	if !ok {
+		return repository.ErrCodeNotFound
+	}
+
+	code.Attempts++
+	r.codes[purpose] = code
+
+	switch {
+	case maxAttempts > 0 && code.Attempts > maxAttempts:
+		delete(r.codes, purpose)
+
+		return repository.ErrCodeExhausted
+	case code.Digest == digest:
+		delete(r.codes, purpose)
+
+		return nil
+	default:
+		return repository.ErrCodeMismatch
+	}
+}
+
+func (r *codeRepo) DeleteCode(_ context.Context, purpose models.CodePurpose, _ int64) error {
+	delete(r.codes, purpose)
+
+	return nil
+}
+
+func (r *codeRepo) CodeCooldown(_ context.Context, purpose models.CodePurpose, _ int64) (bool, error) {
+	return r.cooldowns[purpose], nil
+}
+
+func (r *codeRepo) SetCodeCooldown(_ context.Context, purpose models.CodePurpose, _ int64, _ time.Duration) error {
+	r.cooldowns[purpose] = true
+
+	return nil
+}
+
+func TestIssueCodeCooldownStartsOnceSaved(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	ctx := context.Background()
+
+	repo := &codeRepo{
+		codes:     make(map[models.CodePurpose]models.Code),
+		cooldowns: make(map[models.CodePurpose]bool),
+		saveErr:   errors.New("storage is down"),
+	}
+	env.auth.repo = repo
+	env.auth.otpGenerator = otp.NewGOTPGenerator()
+	env.auth.codes.ResendCooldown = time.Minute
+
+	if _, _, err := env.auth.issueCode(ctx, models.CodePurposePhoneVerify, 1); !errors.Is(err, repo.saveErr) {
+		t.Fatalf("issueCode() error = %v, want %v", err, repo.saveErr)
+	}
+
+	repo.saveErr = nil
+	code, _, err := env.auth.issueCode(ctx, models.CodePurposePhoneVerify, 1)
+	if err != nil {
+		t.Fatalf("issueCode() after a failed save error = %v", err)
+	}
+
+	if _, _, err := env.auth.issueCode(ctx, models.CodePurposePhoneVerify, 1); !errors.Is(err, ErrCodeResendCooldown) {
+		t.Errorf("issueCode() during cooldown error = %v, want %v", err, ErrCodeResendCooldown)
+	}
+	if err := env.auth.verifyCode(ctx, models.CodePurposePhoneVerify, 1, code); err != nil {
+		t.Errorf("verifyCode() error = %v", err)
+	}
+}
+
+// newCodesTestEnv returns the test env keeping codes in memory.
+func newCodesTestEnv(t *testing.T, maxAttempts int) testEnv {
+	t.Helper()
+
+	env := newTestEnv(t, testOptions{})
+	env.auth.repo = &codeRepo{
+		codes:     make(map[models.CodePurpose]models.Code),
+		cooldowns: make(map[models.CodePurpose]bool),
+	}
+	env.auth.otpGenerator = otp.NewGOTPGenerator()
+	env.auth.codes = config.CodesConfig{Secret: "secret", MaxAttempts: maxAttempts}
+
+	return env
+}
+
+func TestVerifyCodeOneTime(t *testing.T) {
+	env := newCodesTestEnv(t, 5)
+	ctx := context.Background()
+
+	code, _, err := env.auth.issueCode(ctx, models.CodePurposeLogin, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.auth.verifyCode(ctx, models.CodePurposeLogin, 1, code); err != nil {
+		t.Fatalf("verifyCode() error = %v", err)
+	}
+	if err := env.auth.verifyCode(ctx, models.CodePurposeLogin, 1, code); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("verifyCode() of a used code error = %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestVerifyCodePurposeBinding(t *testing.T) {
+	env := newCodesTestEnv(t, 5)
+	ctx := context.Background()
+
+	code, _, err := env.auth.issueCode(ctx, models.CodePurposeLogin, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a code of the same user and value, stored under another flow
+	env.auth.repo.(*codeRepo).codes[models.CodePurposePasswordChange] = models.Code{
+		UserID:  1,
+		Purpose: models.CodePurposePasswordChange,
+		Digest:  env.auth.codeDigest(models.CodePurposeLogin, 1, code),
+	}
+
+	err = env.auth.verifyCode(ctx, models.CodePurposePasswordChange, 1, code)
+	if !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("verifyCode() for another purpose error = %v, want %v", err, ErrInvalidCode)
+	}
+	if err := env.auth.verifyCode(ctx, models.CodePurposeLogin, 1, code); err != nil {
+		t.Errorf("verifyCode() for the purpose error = %v", err)
+	}
+}
+
+func TestVerifyCodeMaxAttempts(t *testing.T) {
+	env := newCodesTestEnv(t, 2)
+	ctx := context.Background()
+
+	code, _, err := env.auth.issueCode(ctx, models.CodePurposeEmailVerify, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if err := env.auth.verifyCode(ctx, models.CodePurposeEmailVerify, 1, "wrong"); !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("verifyCode() of a wrong code error = %v, want %v", err, ErrInvalidCode)
+		}
+	}
+
+	if err := env.auth.verifyCode(ctx, models.CodePurposeEmailVerify, 1, code); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("verifyCode() over the attempts error = %v, want %v", err, ErrTooManyAttempts)
+	}
+	// the code is gone, so the next attempt doesn't get a fresh count
+	if err := env.auth.verifyCode(ctx, models.CodePurposeEmailVerify, 1, code); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("verifyCode() of a code out of attempts error = %v, want %v", err, ErrInvalidCode)
+	}
+}
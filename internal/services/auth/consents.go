@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/randtoken"
+	"sso/internal/repository"
+	"time"
+)
+
+type ConsentRepository interface {
+	LegalDocument(ctx context.Context, id int64) (models.LegalDocument, error)
+	PendingConsents(ctx context.Context, userID int64, now time.Time) ([]models.LegalDocument, error)
+	SaveConsents(ctx context.Context, userID int64, documentIDs []int64, acceptedAt time.Time, ip string) error
+	SaveConsentChallenge(ctx context.Context, challenge models.ConsentChallenge, now time.Time) error
+	TakeConsentChallenge(ctx context.Context, tokenHash []byte, now time.Time) (models.ConsentChallenge, error)
+}
+
+var (
+	ErrConsentRequired       = errors.New("legal documents must be accepted")
+	ErrInvalidConsentToken   = errors.New("invalid consent token")
+	ErrLegalDocumentNotFound = errors.New("legal document not found")
+)
+
+// ConsentRequiredError is returned instead of a token to users who haven't accepted
+// the current legal documents. Token is the consent challenge AcceptTerms takes.
+type ConsentRequiredError struct {
+	Token     string
+	ExpiresAt time.Time
+	Documents []models.LegalDocument
+}
+
+func (e *ConsentRequiredError) Error() string {
+	return ErrConsentRequired.Error()
+}
+
+func (e *ConsentRequiredError) Unwrap() error {
+	return ErrConsentRequired
+}
+
+// AcceptTerms records the user of the consent challenge accepting the documents from the address ip
+// and finishes the login the challenge was issued for. If mandatory documents are still not accepted,
+// it returns ConsentRequiredError with a new challenge.
+func (a *Auth) AcceptTerms(
+	ctx context.Context,
+	consentToken string,
+	documentIDs []int64,
+	ip string,
+) (models.User, string, error) {
+	const op = "auth.AcceptTerms"
+
+	log := a.log.With(slog.String("op", op))
+
+	now := time.Now()
+
+	challenge, err := a.consentRepo.TakeConsentChallenge(ctx, randtoken.Hash(consentToken), now)
+	if err != nil {
+		if errors.Is(err, repository.ErrConsentChallengeNotFound) {
+			log.Warn("consent challenge not found or expired")
+
+			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidConsentToken)
+		}
+
+		log.Error("failed to get consent challenge", sl.Err(err))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("userID", challenge.UserID))
+
+	user, err := a.usrProvider.UserByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidConsentToken)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the user may have been suspended since the challenge was issued
+	if user.Blocked() {
+		log.Warn("user is not active", slog.String("status", user.Status))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidConsentToken)
+	}
+
+	for _, id := range documentIDs {
+		doc, err := a.consentRepo.LegalDocument(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrLegalDocumentNotFound) {
+				return models.User{}, "", fmt.Errorf("%s: %w: %d", op, ErrLegalDocumentNotFound, id)
+			}
+
+			log.Error("failed to get legal document", sl.Err(err))
+
+			return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		// documents that haven't taken effect yet can't be accepted
+		if !doc.Published(now) {
+			return models.User{}, "", fmt.Errorf("%s: %w: %d", op, ErrLegalDocumentNotFound, id)
+		}
+	}
+
+	if err := a.consentRepo.SaveConsents(ctx, user.ID, slices.Compact(slices.Sorted(slices.Values(documentIDs))), now, ip); err != nil {
+		log.Error("failed to save consents", sl.Err(err))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("legal documents accepted", slog.Any("documentIDs", documentIDs), slog.String("ip", ip))
+
+	token, err := a.loginToken(ctx, user, challenge.AppID, challenge.OrgID, challenge.GrantType, challenge.Scopes)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in succesfully")
+
+	return user, token, nil
+}
+
+// checkConsents returns ConsentRequiredError with a new consent challenge for logging in
+// to the app and organization with the grant type and scopes if the user hasn't accepted
+// the current mandatory legal documents.
+func (a *Auth) checkConsents(
+	ctx context.Context,
+	user models.User,
+	appID int,
+	orgID int64,
+	grantType string,
+	scopes []string,
+) error {
+	const op = "auth.checkConsents"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", user.ID),
+	)
+
+	now := time.Now()
+
+	docs, err := a.consentRepo.PendingConsents(ctx, user.ID, now)
+	if err != nil {
+		log.Error("failed to get pending consents", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	token, err := randtoken.Generate()
+	if err != nil {
+		log.Error("failed to generate consent token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	challenge := models.ConsentChallenge{
+		TokenHash: randtoken.Hash(token),
+		UserID:    user.ID,
+		AppID:     appID,
+		OrgID:     orgID,
+		GrantType: grantType,
+		Scopes:    scopes,
+		ExpiresAt: now.Add(a.consent.ChallengeTTL),
+	}
+	if err := a.consentRepo.SaveConsentChallenge(ctx, challenge, now); err != nil {
+		log.Error("failed to save consent challenge", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("consent required", slog.Int("documents", len(docs)))
+
+	return &ConsentRequiredError{
+		Token:     token,
+		ExpiresAt: challenge.ExpiresAt,
+		Documents: docs,
+	}
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// createLegalDocument saves the version of the document published at publishedAt and returns its ID.
+func (e testEnv) createLegalDocument(t *testing.T, docType string, version string, mandatory bool, publishedAt time.Time) int64 {
+	t.Helper()
+
+	id, err := e.repo.CreateLegalDocument(context.Background(), models.LegalDocument{
+		Type:        docType,
+		Version:     version,
+		URL:         "https://example.com/" + docType + "/" + version,
+		Mandatory:   mandatory,
+		PublishedAt: publishedAt,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("create legal document: %v", err)
+	}
+
+	return id
+}
+
+// loginConsent logs the user in and returns the consent challenge the login gets.
+func (e testEnv) loginConsent(t *testing.T, email string, password string) *ConsentRequiredError {
+	t.Helper()
+
+	_, _, err := e.auth.Login(context.Background(), email, password, "", e.appID, 0)
+
+	var consentErr *ConsentRequiredError
+	if !errors.As(err, &consentErr) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrConsentRequired)
+	}
+
+	return consentErr
+}
+
+func TestLoginConsent(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	past := time.Now().Add(-time.Minute)
+	termsID := env.createLegalDocument(t, models.LegalDocumentTerms, "1", true, past)
+	env.createLegalDocument(t, models.LegalDocumentPrivacy, "1", false, past)
+	// mandatory once it takes effect
+	futureID := env.createLegalDocument(t, models.LegalDocumentPrivacy, "2", true, time.Now().Add(time.Hour))
+
+	consent := env.loginConsent(t, "user@example.com", "password")
+	if len(consent.Documents) != 1 || consent.Documents[0].ID != termsID {
+		t.Errorf("Login() documents = %+v, want the mandatory terms in effect", consent.Documents)
+	}
+
+	// documents that aren't in effect can't be accepted
+	_, _, err := env.auth.AcceptTerms(ctx, consent.Token, []int64{termsID, futureID}, "127.0.0.1")
+	if !errors.Is(err, ErrLegalDocumentNotFound) {
+		t.Errorf("AcceptTerms() of a document not in effect error = %v, want %v", err, ErrLegalDocumentNotFound)
+	}
+
+	// the challenge was used
+	_, _, err = env.auth.AcceptTerms(ctx, consent.Token, []int64{termsID}, "127.0.0.1")
+	if !errors.Is(err, ErrInvalidConsentToken) {
+		t.Errorf("AcceptTerms() with a used challenge error = %v, want %v", err, ErrInvalidConsentToken)
+	}
+
+	consent = env.loginConsent(t, "user@example.com", "password")
+
+	// accepting nothing gets a new challenge
+	_, _, err = env.auth.AcceptTerms(ctx, consent.Token, nil, "127.0.0.1")
+	if !errors.As(err, &consent) {
+		t.Fatalf("AcceptTerms() of no documents error = %v, want %v", err, ErrConsentRequired)
+	}
+
+	user, token, err := env.auth.AcceptTerms(ctx, consent.Token, []int64{termsID}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("AcceptTerms() error = %v", err)
+	}
+	if user.Email != "user@example.com" || token == "" {
+		t.Errorf("AcceptTerms() = %q, %q, want the user logged in", user.Email, token)
+	}
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); err != nil {
+		t.Errorf("Login() once the terms are accepted error = %v", err)
+	}
+
+	// a new version is required again
+	newTermsID := env.createLegalDocument(t, models.LegalDocumentTerms, "2", true, time.Now().Add(-time.Second))
+
+	consent = env.loginConsent(t, "user@example.com", "password")
+	if len(consent.Documents) != 1 || consent.Documents[0].ID != newTermsID {
+		t.Errorf("Login() documents = %+v, want the new terms", consent.Documents)
+	}
+}
+
+func TestAcceptTermsExpiredChallenge(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	userID := env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	termsID := env.createLegalDocument(t, models.LegalDocumentTerms, "1", true, time.Now().Add(-time.Minute))
+
+	consent := env.loginConsent(t, "user@example.com", "password")
+	env.exec(t, "UPDATE consent_challenges SET expires_at = ? WHERE user_id = ?", time.Now().Add(-time.Second).UTC(), userID)
+
+	_, _, err := env.auth.AcceptTerms(ctx, consent.Token, []int64{termsID}, "127.0.0.1")
+	if !errors.Is(err, ErrInvalidConsentToken) {
+		t.Errorf("AcceptTerms() with an expired challenge error = %v, want %v", err, ErrInvalidConsentToken)
+	}
+}
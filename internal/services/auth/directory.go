@@ -0,0 +1,363 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"sso/internal/services/directory"
+	"strings"
+	"time"
+)
+
+// directoryProvider is the provider of the identities linking shadow users to directory entries.
+const directoryProvider = "ldap"
+
+type DirectoryRepository interface {
+	UpdateUser(ctx context.Context, user models.User) error
+	Identities(ctx context.Context, userID int64) ([]models.Identity, error)
+	Apps(ctx context.Context) ([]models.App, error)
+	Role(ctx context.Context, appID int, name string) (models.Role, error)
+	AssignRole(ctx context.Context, userID int64, roleID int64) error
+	RevokeRole(ctx context.Context, userID int64, roleID int64) error
+}
+
+var (
+	ErrDirectoryUnavailable     = errors.New("user directory is unavailable")
+	ErrDirectoryUserNotLinkable = errors.New("directory entry can't be linked to a local user")
+)
+
+// directoryLogin logs the user in with the password of their directory entry. The local shadow user
+// of the entry is the user linked to it or, on the first login, the user with its email or a new one.
+// Entries without an email and local users whose email isn't verified aren't linked on the first login,
+// they get ErrDirectoryUserNotLinkable.
+// The profile of the shadow user and the roles mapped from directory groups are synced on every login,
+// the lockout applies as to local passwords. Logins missing in the directory get directory.ErrUserNotFound.
+func (a *Auth) directoryLogin(
+	ctx context.Context,
+	login string,
+	password string,
+	appID int,
+	orgID int64,
+) (models.User, string, error) {
+	const op = "auth.directoryLogin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("login", login),
+	)
+
+	entry, err := a.directory.User(ctx, login)
+	if err != nil {
+		if errors.Is(err, directory.ErrUserNotFound) {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to find user in directory", sl.Err(err))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrDirectoryUnavailable)
+	}
+	log = log.With(slog.String("dn", entry.DN))
+
+	user, linked, err := a.shadowUser(ctx, entry)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var lockout models.LockoutState
+	if user.ID != 0 {
+		lockout, err = a.checkPasswordLogin(ctx, user, password)
+		if err != nil {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := a.directory.Authenticate(ctx, entry, password); err != nil {
+		if errors.Is(err, directory.ErrInvalidCredentials) {
+			log.Info("invalid credentials")
+
+			if user.ID != 0 {
+				if err := a.registerFailedLogin(ctx, user, lockout); err != nil {
+					log.Error("failed to register failed login", sl.Err(err))
+				}
+			}
+
+			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+
+		log.Error("failed to authenticate user in directory", sl.Err(err))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrDirectoryUnavailable)
+	}
+
+	user, err = a.syncShadowUser(ctx, user, linked, entry)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.syncDirectoryRoles(ctx, user, entry.Groups); err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, token, err := a.finishPasswordLogin(ctx, user, lockout, appID, orgID)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in with directory password", slog.Int64("userID", user.ID))
+
+	return user, token, nil
+}
+
+// inDirectory tells whether the user is linked to a directory entry.
+func (a *Auth) inDirectory(ctx context.Context, userID int64) (bool, error) {
+	const op = "auth.inDirectory"
+
+	identities, err := a.directoryRepo.Identities(ctx, userID)
+	if err != nil {
+		a.log.Error("failed to get identities", slog.Int64("userID", userID), sl.Err(err))
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return slices.ContainsFunc(identities, func(identity models.Identity) bool {
+		return identity.Provider == directoryProvider
+	}), nil
+}
+
+// shadowUser returns the user linked to the entry and true, or the user with the entry's email
+// and false. The zero user is returned if there is neither or the entry has no email.
+func (a *Auth) shadowUser(ctx context.Context, entry directory.User) (models.User, bool, error) {
+	const op = "auth.shadowUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("dn", entry.DN),
+	)
+
+	identity, err := a.identityRepo.Identity(ctx, directoryProvider, entry.ID)
+	if err == nil {
+		user, err := a.usrProvider.UserByID(ctx, identity.UserID)
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			return models.User{}, false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return user, true, nil
+	}
+	if !errors.Is(err, repository.ErrIdentityNotFound) {
+		log.Error("failed to get identity", sl.Err(err))
+
+		return models.User{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if entry.Email == "" {
+		return models.User{}, false, nil
+	}
+
+	user, err := a.usrProvider.User(ctx, entry.Email, "")
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, false, nil
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return models.User{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, false, nil
+}
+
+// syncShadowUser creates the shadow user of the entry or links the found user to the entry,
+// then updates the profile to the non-empty attributes of the entry. Unlinked entries without an email
+// and found users whose email isn't verified get ErrDirectoryUserNotLinkable, anyone could set
+// the email in either of them. Profiles that can't be updated,
+// e.g. to an email of another user, are kept as they are.
+func (a *Auth) syncShadowUser(ctx context.Context, user models.User, linked bool, entry directory.User) (models.User, error) {
+	const op = "auth.syncShadowUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("dn", entry.DN),
+	)
+
+	now := time.Now()
+	identity := models.Identity{
+		Provider:    directoryProvider,
+		Subject:     entry.ID,
+		UserID:      user.ID,
+		Email:       entry.Email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}
+
+	switch {
+	case user.ID == 0 && entry.Email == "":
+		log.Warn("directory entry has no email")
+
+		return models.User{}, fmt.Errorf("%s: %w", op, ErrDirectoryUserNotLinkable)
+	case !linked && user.ID != 0 && !user.EmailVerified:
+		log.Warn("email of the user isn't verified", slog.Int64("userID", user.ID))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, ErrDirectoryUserNotLinkable)
+	case user.ID == 0:
+		id, err := a.identityRepo.CreateFederatedUser(ctx, models.User{
+			Name:     entry.Name,
+			LastName: entry.LastName,
+			Email:    entry.Email,
+		}, identity)
+		if err != nil {
+			log.Error("failed to create user", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		user, err = a.usrProvider.UserByID(ctx, id)
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("shadow user created", slog.Int64("userID", user.ID))
+	case !linked:
+		if err := a.identityRepo.SaveIdentity(ctx, identity); err != nil {
+			log.Error("failed to link user", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("user linked to directory entry", slog.Int64("userID", user.ID))
+	default:
+		if err := a.identityRepo.SaveIdentity(ctx, identity); err != nil {
+			log.Error("failed to update identity", sl.Err(err))
+		}
+	}
+
+	if !linked {
+		_, err := a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+			ActorID:      user.ID,
+			Action:       models.AuditActionLinkIdentity,
+			TargetUserID: user.ID,
+			Details:      directoryProvider,
+			CreatedAt:    now,
+		})
+		if err != nil {
+			log.Error("failed to save audit entry", sl.Err(err))
+		}
+	}
+
+	profile := user
+	if entry.Name != "" {
+		profile.Name = entry.Name
+	}
+	if entry.LastName != "" {
+		profile.LastName = entry.LastName
+	}
+	if entry.Email != "" {
+		profile.Email = entry.Email
+	}
+	if entry.Phone != "" {
+		phone, err := a.phones.Normalize(entry.Phone)
+		if err != nil {
+			log.Warn("invalid phone in directory", slog.String("phone", entry.Phone), sl.Err(err))
+		} else {
+			profile.Phone = phone
+		}
+	}
+
+	if profile.Name == user.Name && profile.LastName == user.LastName &&
+		profile.Email == user.Email && profile.Phone == user.Phone {
+		return user, nil
+	}
+
+	if err := a.directoryRepo.UpdateUser(ctx, profile); err != nil {
+		if errors.Is(err, repository.ErrUserExists) {
+			log.Warn("email or phone of the entry belongs to another user", slog.Int64("userID", user.ID))
+		} else {
+			log.Error("failed to update user", sl.Err(err))
+		}
+
+		return user, nil
+	}
+
+	log.Info("user profile synced with directory", slog.Int64("userID", user.ID))
+
+	return profile, nil
+}
+
+// syncDirectoryRoles grants the user the roles mapped from the directory groups they are a member of
+// and revokes the mapped roles of other groups. Roles granted by any of the user's groups are kept.
+// Mappings of missing apps or roles are skipped.
+func (a *Auth) syncDirectoryRoles(ctx context.Context, user models.User, groups []string) error {
+	const op = "auth.syncDirectoryRoles"
+
+	if len(a.ldap.GroupRoles) == 0 {
+		return nil
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", user.ID),
+	)
+
+	apps, err := a.directoryRepo.Apps(ctx)
+	if err != nil {
+		log.Error("failed to get apps", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	type appRole struct {
+		app  string
+		role string
+	}
+
+	granted := make(map[appRole]bool, len(a.ldap.GroupRoles))
+	for _, m := range a.ldap.GroupRoles {
+		key := appRole{app: m.App, role: m.Role}
+		member := slices.ContainsFunc(groups, func(group string) bool { return strings.EqualFold(group, m.Group) })
+		granted[key] = granted[key] || member
+	}
+
+	for key, grant := range granted {
+		i := slices.IndexFunc(apps, func(app models.App) bool { return app.Name == key.app })
+		if i < 0 {
+			log.Warn("mapped app not found", slog.String("app", key.app))
+
+			continue
+		}
+
+		role, err := a.directoryRepo.Role(ctx, apps[i].ID, key.role)
+		if err != nil {
+			if errors.Is(err, repository.ErrRoleNotFound) {
+				log.Warn("mapped role not found", slog.String("app", key.app), slog.String("role", key.role))
+
+				continue
+			}
+
+			log.Error("failed to get role", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if grant {
+			err = a.directoryRepo.AssignRole(ctx, user.ID, role.ID)
+		} else {
+			err = a.directoryRepo.RevokeRole(ctx, user.ID, role.ID)
+		}
+		if err != nil {
+			log.Error("failed to sync role", slog.String("app", key.app), slog.String("role", key.role), sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
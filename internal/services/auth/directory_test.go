@@ -0,0 +1,411 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/repository"
+	"sso/internal/services/directory/ldap"
+	"sso/internal/services/directory/ldap/ldaptest"
+	"testing"
+	"time"
+)
+
+const (
+	ldapAliceDN  = "uid=alice,ou=people,dc=example,dc=com"
+	ldapAdminsDN = "cn=admins,ou=groups,dc=example,dc=com"
+	ldapStaffDN  = "cn=staff,ou=groups,dc=example,dc=com"
+)
+
+// newLDAPServer returns the directory of alice, a member of admins and staff, and bob.
+func newLDAPServer(t *testing.T) *ldaptest.Server {
+	t.Helper()
+
+	return ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "cn=sso,dc=example,dc=com", Password: "service password"},
+		ldaptest.Entry{
+			DN:       ldapAliceDN,
+			Password: "alice password",
+			Attributes: map[string][]string{
+				"objectClass":     {"person"},
+				"uid":             {"alice"},
+				"mail":            {"alice@example.com"},
+				"givenName":       {"Alice"},
+				"sn":              {"Liddell"},
+				"telephoneNumber": {"+1 555 000 1234"},
+				"entryUUID":       {"alice-uuid"},
+			},
+		},
+		ldaptest.Entry{
+			DN:       "uid=bob,ou=people,dc=example,dc=com",
+			Password: "bob password",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"bob"},
+				"mail":        {"bob@example.com"},
+				"givenName":   {"Robert"},
+				"entryUUID":   {"bob-uuid"},
+			},
+		},
+		ldaptest.Entry{
+			DN: ldapAdminsDN,
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"member":      {ldapAliceDN},
+			},
+		},
+		ldaptest.Entry{
+			DN: ldapStaffDN,
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"member":      {ldapAliceDN},
+			},
+		},
+	)
+}
+
+// newDirectoryTestEnv returns the test env logging users in with the directory of the server.
+// Members of admins get the admin and viewer roles of the app, members of staff the viewer role.
+func newDirectoryTestEnv(t *testing.T, srv *ldaptest.Server) testEnv {
+	t.Helper()
+
+	t.Setenv("TEST_LDAP_BIND_PASSWORD", "service password")
+
+	cfg := config.LDAPConfig{
+		Enabled:         true,
+		URL:             srv.URL(),
+		Timeout:         5 * time.Second,
+		BindDN:          "cn=sso,dc=example,dc=com",
+		BindPasswordEnv: "TEST_LDAP_BIND_PASSWORD",
+		BaseDN:          "ou=people,dc=example,dc=com",
+		UserFilter:      "(&(objectClass=person)(|(mail={login})(uid={login})))",
+		GroupBaseDN:     "ou=groups,dc=example,dc=com",
+		GroupFilter:     "(&(objectClass=groupOfNames)(member={dn}))",
+		Attributes: config.LDAPAttributesConfig{
+			ID:       "entryUUID",
+			Email:    "mail",
+			Name:     "givenName",
+			LastName: "sn",
+			Phone:    "telephoneNumber",
+		},
+		GroupRoles: []config.LDAPGroupRoleConfig{
+			{Group: "CN=Admins,ou=groups,dc=example,dc=com", App: "test-app", Role: "admin"},
+			{Group: ldapAdminsDN, App: "test-app", Role: "viewer"},
+			{Group: ldapStaffDN, App: "test-app", Role: "viewer"},
+			{Group: ldapStaffDN, App: "missing-app", Role: "viewer"},
+			{Group: ldapStaffDN, App: "test-app", Role: "missing-role"},
+		},
+	}
+
+	dir, err := ldap.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := newTestEnv(t, testOptions{
+		lockout:   config.LockoutConfig{MaxAttempts: 3, Duration: time.Minute},
+		ldap:      cfg,
+		directory: dir,
+	})
+
+	// admin comes with the app
+	for _, role := range []string{"viewer", "manual"} {
+		if _, err := env.repo.CreateRole(context.Background(), env.appID, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return env
+}
+
+func (e testEnv) roles(t *testing.T, userID int64) []string {
+	t.Helper()
+
+	roles, err := e.repo.UserRoles(context.Background(), userID, e.appID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return roles
+}
+
+func TestDirectoryLoginCreatesShadowUser(t *testing.T) {
+	env := newDirectoryTestEnv(t, newLDAPServer(t))
+	ctx := context.Background()
+
+	user, token, err := env.auth.Login(ctx, "alice", "alice password", "", env.appID, 0)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if token == "" {
+		t.Error("Login() returned no token")
+	}
+
+	want := models.User{Name: "Alice", LastName: "Liddell", Email: "alice@example.com", Phone: "+15550001234"}
+	if user.Name != want.Name || user.LastName != want.LastName || user.Email != want.Email || user.Phone != want.Phone {
+		t.Errorf("Login() user = %q %q %q %q, want %q %q %q %q",
+			user.Name, user.LastName, user.Email, user.Phone, want.Name, want.LastName, want.Email, want.Phone)
+	}
+
+	identity, err := env.repo.Identity(ctx, directoryProvider, "alice-uuid")
+	if err != nil {
+		t.Fatalf("identity not found: %v", err)
+	}
+	if identity.UserID != user.ID {
+		t.Errorf("identity linked to user %d, want %d", identity.UserID, user.ID)
+	}
+
+	again, _, err := env.auth.Login(ctx, "alice@example.com", "alice password", "", env.appID, 0)
+	if err != nil {
+		t.Fatalf("second Login() error = %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second Login() user = %d, want %d", again.ID, user.ID)
+	}
+}
+
+func TestDirectoryLoginLinksUserByEmail(t *testing.T) {
+	srv := newLDAPServer(t)
+	env := newDirectoryTestEnv(t, srv)
+	ctx := context.Background()
+
+	bobID := env.createUser(t, "bob@example.com", "local password")
+	env.exec(t, "UPDATE users SET email_verified = TRUE WHERE id = ?", bobID)
+
+	// the directory owns the password of its users
+	if _, _, err := env.auth.Login(ctx, "bob@example.com", "local password", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login() with local password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	user, _, err := env.auth.Login(ctx, "bob@example.com", "bob password", "", env.appID, 0)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if user.ID != bobID {
+		t.Errorf("Login() user = %d, want the local user %d", user.ID, bobID)
+	}
+	if user.Name != "Robert" || user.LastName != "User" {
+		t.Errorf("Login() user name = %q %q, want the directory name and the local last name", user.Name, user.LastName)
+	}
+
+	identities, err := env.repo.Identities(ctx, bobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identities) != 1 || identities[0].Provider != directoryProvider || identities[0].Subject != "bob-uuid" {
+		t.Errorf("identities = %+v, want the directory entry", identities)
+	}
+
+	// linked users are found by the identity once the email changes in the directory
+	srv.SetAttribute("uid=bob,ou=people,dc=example,dc=com", "mail", "robert@example.com")
+
+	user, _, err = env.auth.Login(ctx, "bob", "bob password", "", env.appID, 0)
+	if err != nil {
+		t.Fatalf("Login() after rename error = %v", err)
+	}
+	if user.ID != bobID || user.Email != "robert@example.com" {
+		t.Errorf("Login() after rename user = %d %q, want %d %q", user.ID, user.Email, bobID, "robert@example.com")
+	}
+}
+
+func TestDirectoryLoginWrongPassword(t *testing.T) {
+	srv := newLDAPServer(t)
+	env := newDirectoryTestEnv(t, srv)
+	ctx := context.Background()
+
+	// wrong passwords don't create shadow users
+	if _, _, err := env.auth.Login(ctx, "alice", "wrong password", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if _, err := env.repo.User(ctx, "alice@example.com", ""); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("user after failed login error = %v, want %v", err, repository.ErrUserNotFound)
+	}
+
+	user, _, err := env.auth.Login(ctx, "alice", "alice password", "", env.appID, 0)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	for i := range 3 {
+		if _, _, err := env.auth.Login(ctx, "alice", "wrong password", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Login() attempt %d error = %v, want %v", i+1, err, ErrInvalidCredentials)
+		}
+	}
+
+	state, err := env.repo.LockoutState(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FailedAttempts != 3 || !state.Locked(time.Now()) {
+		t.Errorf("lockout state = %+v, want 3 failed attempts and locked", state)
+	}
+
+	// locked out users don't get to guess at the directory
+	binds := srv.Binds(ldapAliceDN)
+	if _, _, err := env.auth.Login(ctx, "alice", "alice password", "", env.appID, 0); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("Login() when locked error = %v, want %v", err, ErrAccountLocked)
+	}
+	if got := srv.Binds(ldapAliceDN); got != binds {
+		t.Errorf("Login() when locked sent %d binds", got-binds)
+	}
+
+	if err := env.repo.ResetLockoutState(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := env.auth.Login(ctx, "alice", "alice password", "", env.appID, 0); err != nil {
+		t.Errorf("Login() after the lockout error = %v", err)
+	}
+}
+
+func TestDirectoryLoginEmptyPassword(t *testing.T) {
+	srv := newLDAPServer(t)
+	env := newDirectoryTestEnv(t, srv)
+	ctx := context.Background()
+
+	user, _, err := env.auth.Login(ctx, "alice", "alice password", "", env.appID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binds := srv.Binds(ldapAliceDN)
+	if _, _, err := env.auth.Login(ctx, "alice", "", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if got := srv.Binds(ldapAliceDN); got != binds {
+		t.Errorf("Login() with empty password sent %d binds", got-binds)
+	}
+
+	state, err := env.repo.LockoutState(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FailedAttempts != 1 {
+		t.Errorf("failed attempts = %d, want 1", state.FailedAttempts)
+	}
+}
+
+func TestSyncDirectoryRoles(t *testing.T) {
+	srv := newLDAPServer(t)
+	env := newDirectoryTestEnv(t, srv)
+	ctx := context.Background()
+
+	login := func() models.User {
+		t.Helper()
+
+		user, _, err := env.auth.Login(ctx, "alice", "alice password", "", env.appID, 0)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+
+		return user
+	}
+
+	user := login()
+	if got, want := env.roles(t, user.ID), []string{"admin", "viewer"}; !slices.Equal(got, want) {
+		t.Errorf("roles = %v, want %v", got, want)
+	}
+
+	manual, err := env.repo.Role(ctx, env.appID, "manual")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.AssignRole(ctx, user.ID, manual.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// viewer is still granted by staff
+	srv.SetAttribute(ldapAdminsDN, "member")
+	login()
+	if got, want := env.roles(t, user.ID), []string{"manual", "viewer"}; !slices.Equal(got, want) {
+		t.Errorf("roles after leaving admins = %v, want %v", got, want)
+	}
+
+	// roles of no mapping are left to admins
+	srv.SetAttribute(ldapStaffDN, "member")
+	login()
+	if got, want := env.roles(t, user.ID), []string{"manual"}; !slices.Equal(got, want) {
+		t.Errorf("roles after leaving staff = %v, want %v", got, want)
+	}
+
+	srv.SetAttribute(ldapAdminsDN, "member", ldapAliceDN)
+	login()
+	if got, want := env.roles(t, user.ID), []string{"admin", "manual", "viewer"}; !slices.Equal(got, want) {
+		t.Errorf("roles after joining admins = %v, want %v", got, want)
+	}
+}
+
+func TestDirectoryDown(t *testing.T) {
+	srv := newLDAPServer(t)
+	env := newDirectoryTestEnv(t, srv)
+	ctx := context.Background()
+
+	env.createUser(t, "carol@example.com", "carol password")
+	if _, _, err := env.auth.Login(ctx, "alice", "alice password", "", env.appID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Close()
+
+	// local users don't depend on the directory
+	if _, _, err := env.auth.Login(ctx, "carol@example.com", "carol password", "", env.appID, 0); err != nil {
+		t.Errorf("Login() of local user error = %v", err)
+	}
+	if _, _, err := env.auth.Login(ctx, "alice@example.com", "alice password", "", env.appID, 0); !errors.Is(err, ErrDirectoryUnavailable) {
+		t.Errorf("Login() of directory user error = %v, want %v", err, ErrDirectoryUnavailable)
+	}
+}
+
+func TestDirectoryLoginDoesntLinkUnverifiedEmail(t *testing.T) {
+	env := newDirectoryTestEnv(t, newLDAPServer(t))
+	ctx := context.Background()
+
+	// anyone could have registered the email locally
+	bobID := env.createUser(t, "bob@example.com", "local password")
+
+	if _, _, err := env.auth.Login(ctx, "bob", "bob password", "", env.appID, 0); !errors.Is(err, ErrDirectoryUserNotLinkable) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrDirectoryUserNotLinkable)
+	}
+
+	identities, err := env.repo.Identities(ctx, bobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identities) != 0 {
+		t.Errorf("identities = %+v, want none", identities)
+	}
+}
+
+func TestDirectoryLoginEntryWithoutEmail(t *testing.T) {
+	srv := newLDAPServer(t)
+	env := newDirectoryTestEnv(t, srv)
+	ctx := context.Background()
+
+	// the login is a uid, not an email, so it can't stand in for the missing one
+	env.createUser(t, "bob", "local password")
+	srv.SetAttribute("uid=bob,ou=people,dc=example,dc=com", "mail")
+
+	if _, _, err := env.auth.Login(ctx, "bob", "bob password", "", env.appID, 0); !errors.Is(err, ErrDirectoryUserNotLinkable) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrDirectoryUserNotLinkable)
+	}
+	if _, err := env.repo.Identity(ctx, directoryProvider, "bob-uuid"); !errors.Is(err, repository.ErrIdentityNotFound) {
+		t.Errorf("Identity() error = %v, want %v", err, repository.ErrIdentityNotFound)
+	}
+
+	// linked entries keep logging in once the email is removed, the user keeps the email
+	srv.SetAttribute("uid=bob,ou=people,dc=example,dc=com", "mail", "bob@example.com")
+	user, _, err := env.auth.Login(ctx, "bob", "bob password", "", env.appID, 0)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	srv.SetAttribute("uid=bob,ou=people,dc=example,dc=com", "mail")
+
+	again, _, err := env.auth.Login(ctx, "bob", "bob password", "", env.appID, 0)
+	if err != nil {
+		t.Fatalf("Login() without email error = %v", err)
+	}
+	if again.ID != user.ID || again.Email != "bob@example.com" {
+		t.Errorf("Login() without email user = %d %q, want %d %q", again.ID, again.Email, user.ID, "bob@example.com")
+	}
+}
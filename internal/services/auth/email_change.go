@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/randtoken"
+	"sso/internal/repository"
+	"sso/internal/services"
+	"strings"
+	"time"
+)
+
+type EmailChangeRepository interface {
+	CreateEmailChange(ctx context.Context, change models.EmailChange) (int64, error)
+	PendingEmailChange(ctx context.Context, userID int64) (models.EmailChange, error)
+	ConfirmEmailChange(ctx context.Context, change models.EmailChange) error
+	EmailChangeByRevertTokenHash(ctx context.Context, tokenHash []byte) (models.EmailChange, error)
+	RevertEmailChange(ctx context.Context, change models.EmailChange, revertedAt time.Time) error
+}
+
+var (
+	ErrInvalidEmail       = errors.New("invalid email")
+	ErrInvalidEmailChange = errors.New("email change is invalid or expired")
+)
+
+// ChangeEmailInit sends a code confirming the new email to the new address and returns when the code expires.
+// Impersonating support staff can't change emails. With enumeration protection on, a taken email
+// gets no code instead of ErrUserExists.
+func (a *Auth) ChangeEmailInit(ctx context.Context, caller models.Principal, newEmail string) (time.Time, error) {
+	const op = "auth.ChangeEmailInit"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+	)
+
+	if caller.Type != models.PrincipalUser || caller.ActorID != 0 {
+		log.Warn("caller can't change email", slog.String("type", caller.Type), slog.Int64("actorID", caller.ActorID))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if _, domain, ok := strings.Cut(newEmail, "@"); !ok || domain == "" {
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidEmail)
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, caller.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return time.Time{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if strings.EqualFold(user.Email, newEmail) {
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidEmail)
+	}
+
+	_, err = a.usrProvider.User(ctx, newEmail, "")
+	if err == nil {
+		log.Warn("email is taken")
+
+		if a.enumerationProtection {
+			return time.Now().UTC().Add(a.verCodeTTL), nil
+		}
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrUserExists)
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		log.Error("failed to get user", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the code is issued first so that the resend cooldown keeps it bound to the latest pending change
+	code, expiresAt, err := a.issueCode(ctx, models.CodePurposeEmailVerify, user.ID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = a.emailChangeRepo.CreateEmailChange(ctx, models.EmailChange{
+		UserID:    user.ID,
+		OldEmail:  user.Email,
+		NewEmail:  newEmail,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Error("failed to save email change", sl.Err(err))
+
+		if err := a.repo.DeleteCode(ctx, models.CodePurposeEmailVerify, user.ID); err != nil {
+			log.Error("failed to delete code", sl.Err(err))
+		}
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = a.emailService.SendVerificationEmail(services.VerificationEmailInput{
+		Email:            newEmail,
+		Name:             user.Name,
+		VerificationCode: code,
+	})
+	if err != nil {
+		log.Error("failed to send verification email", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email change requested")
+
+	return expiresAt, nil
+}
+
+// ChangeEmailConfirm replaces the caller's email with the one the code was sent to and logs the user out
+// everywhere. The old address is notified and can revert the change within EmailChangeConfig.RevertTTL.
+func (a *Auth) ChangeEmailConfirm(ctx context.Context, caller models.Principal, code string) error {
+	const op = "auth.ChangeEmailConfirm"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+	)
+
+	if caller.Type != models.PrincipalUser || caller.ActorID != 0 {
+		return fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	change, err := a.emailChangeRepo.PendingEmailChange(ctx, caller.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailChangeNotFound) {
+			log.Warn("no pending email change")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidCode)
+		}
+
+		log.Error("failed to get email change", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.verifyCode(ctx, models.CodePurposeEmailVerify, caller.UserID, code); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := randtoken.Generate()
+	if err != nil {
+		log.Error("failed to generate revert token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	change.RevertTokenHash = randtoken.Hash(token)
+	change.ConfirmedAt = now
+	change.RevertExpiresAt = now.Add(a.emailChange.RevertTTL)
+
+	if err := a.emailChangeRepo.ConfirmEmailChange(ctx, change); err != nil {
+		if errors.Is(err, repository.ErrUserExists) {
+			log.Warn("email was taken meanwhile")
+
+			return fmt.Errorf("%s: %w", op, ErrUserExists)
+		}
+		if errors.Is(err, repository.ErrEmailChangeNotFound) {
+			log.Warn("email was changed meanwhile")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidEmailChange)
+		}
+
+		log.Error("failed to confirm email change", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email changed", slog.Int64("emailChangeID", change.ID))
+
+	user, err := a.usrProvider.UserByID(ctx, caller.UserID)
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+	}
+
+	// the change is done, a lost notice only costs the old address its chance to revert
+	err = a.emailService.SendEmailChangedEmail(services.EmailChangedEmailInput{
+		Email:     change.OldEmail,
+		Name:      user.Name,
+		NewEmail:  change.NewEmail,
+		Link:      a.emailChange.RevertURL + "?token=" + url.QueryEscape(token),
+		ExpiresAt: change.RevertExpiresAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		log.Error("failed to send email changed notice", sl.Err(err))
+	}
+
+	return nil
+}
+
+// RevertEmailChange restores the email replaced by the change the token was sent for
+// and logs the user out everywhere.
+func (a *Auth) RevertEmailChange(ctx context.Context, token string) error {
+	const op = "auth.RevertEmailChange"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	change, err := a.emailChangeRepo.EmailChangeByRevertTokenHash(ctx, randtoken.Hash(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailChangeNotFound) {
+			log.Warn("email change not found")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidEmailChange)
+		}
+
+		log.Error("failed to get email change", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("emailChangeID", change.ID), slog.Int64("userID", change.UserID))
+
+	now := time.Now()
+	if !change.Revertible(now) {
+		log.Warn("email change can't be reverted")
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidEmailChange)
+	}
+
+	if err := a.emailChangeRepo.RevertEmailChange(ctx, change, now); err != nil {
+		if errors.Is(err, repository.ErrEmailChangeNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidEmailChange)
+		}
+		if errors.Is(err, repository.ErrUserExists) {
+			log.Warn("old email was taken meanwhile")
+
+			return fmt.Errorf("%s: %w", op, ErrUserExists)
+		}
+
+		log.Error("failed to revert email change", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Warn("email change reverted")
+
+	return nil
+}
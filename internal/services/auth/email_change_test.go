@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/services"
+	"sso/internal/services/email"
+	"testing"
+	"time"
+)
+
+const templatesPath = "../../../templates/"
+
+// recordingSender keeps the emails sent.
+type recordingSender struct {
+	sent *[]email.SendEmailInput
+}
+
+func (s recordingSender) Send(input email.SendEmailInput) error {
+	*s.sent = append(*s.sent, input)
+
+	return nil
+}
+
+// fixedOTP generates the same code every time.
+type fixedOTP string
+
+func (c fixedOTP) RandomSecret(int) string { return string(c) }
+
+var revertTokenRe = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// newEmailChangeTestEnv returns the test env keeping codes in memory, issuing code and sending
+// emails to sent.
+func newEmailChangeTestEnv(t *testing.T, code string) (testEnv, *[]email.SendEmailInput) {
+	t.Helper()
+
+	env := newCodesTestEnv(t, 5)
+	env.auth.otpGenerator = fixedOTP(code)
+	env.auth.emailChange = config.EmailChangeConfig{RevertTTL: time.Hour, RevertURL: "https://app.example.com/revert"}
+
+	sent := &[]email.SendEmailInput{}
+	env.auth.emailService, _ = services.NewEmailService(env.auth.log, recordingSender{sent: sent}, config.EmailConfig{
+		Templates: config.EmailTemplate{
+			VerificationCode: templatesPath + "verification_email.html",
+			EmailChanged:     templatesPath + "email_changed_email.html",
+		},
+	})
+
+	return env, sent
+}
+
+// changeEmail changes the email of the caller to newEmail and returns the revert token sent to the old one.
+func (e testEnv) changeEmail(t *testing.T, sent *[]email.SendEmailInput, caller models.Principal, newEmail string, code string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := e.auth.ChangeEmailInit(ctx, caller, newEmail); err != nil {
+		t.Fatalf("ChangeEmailInit() error = %v", err)
+	}
+	if err := e.auth.ChangeEmailConfirm(ctx, caller, code); err != nil {
+		t.Fatalf("ChangeEmailConfirm() error = %v", err)
+	}
+
+	notice := (*sent)[len(*sent)-1]
+	if notice.To != caller.Email {
+		t.Fatalf("email changed notice sent to %s, want %s", notice.To, caller.Email)
+	}
+	m := revertTokenRe.FindStringSubmatch(notice.Body)
+	if m == nil {
+		t.Fatalf("email changed notice %q has no revert link", notice.Body)
+	}
+
+	return m[1]
+}
+
+func TestChangeEmail(t *testing.T) {
+	env, sent := newEmailChangeTestEnv(t, "123456")
+	ctx := context.Background()
+
+	caller := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "old@example.com", "password"),
+		Email:  "old@example.com",
+		AppID:  env.appID,
+	}
+
+	if _, err := env.auth.ChangeEmailInit(ctx, caller, "new@example.com"); err != nil {
+		t.Fatalf("ChangeEmailInit() error = %v", err)
+	}
+	if len(*sent) != 1 || (*sent)[0].To != "new@example.com" {
+		t.Fatalf("sent %+v, want the code sent to the new email", *sent)
+	}
+
+	if err := env.auth.ChangeEmailConfirm(ctx, caller, "654321"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("ChangeEmailConfirm() with a wrong code error = %v, want %v", err, ErrInvalidCode)
+	}
+
+	before := time.Now().Truncate(time.Second)
+	if err := env.auth.ChangeEmailConfirm(ctx, caller, "123456"); err != nil {
+		t.Fatalf("ChangeEmailConfirm() error = %v", err)
+	}
+
+	user, err := env.repo.UserByID(ctx, caller.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "new@example.com" || !user.EmailVerified {
+		t.Errorf("user email = %q verified %t, want the new email verified", user.Email, user.EmailVerified)
+	}
+	if user.TokensValidAfter.Before(before) {
+		t.Errorf("tokens valid after %v, want tokens issued before %v revoked", user.TokensValidAfter, before)
+	}
+	if last := (*sent)[len(*sent)-1]; last.To != "old@example.com" {
+		t.Errorf("last email sent to %s, want the notice sent to the old email", last.To)
+	}
+	if _, _, err := env.auth.Login(ctx, "new@example.com", "password", "", env.appID, 0); err != nil {
+		t.Errorf("Login() with the new email error = %v", err)
+	}
+}
+
+func TestChangeEmailInitInvalid(t *testing.T) {
+	env, _ := newEmailChangeTestEnv(t, "123456")
+	ctx := context.Background()
+
+	env.createUser(t, "taken@example.com", "password")
+	caller := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "user@example.com", "password"),
+		Email:  "user@example.com",
+		AppID:  env.appID,
+	}
+	impersonated := caller
+	impersonated.ActorID = 1
+
+	tests := []struct {
+		name     string
+		caller   models.Principal
+		newEmail string
+		want     error
+	}{
+		{name: "impersonated", caller: impersonated, newEmail: "new@example.com", want: ErrPermissionDenied},
+		{name: "no domain", caller: caller, newEmail: "new@", want: ErrInvalidEmail},
+		{name: "same email", caller: caller, newEmail: "USER@example.com", want: ErrInvalidEmail},
+		{name: "taken email", caller: caller, newEmail: "taken@example.com", want: ErrUserExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.auth.ChangeEmailInit(ctx, tt.caller, tt.newEmail); !errors.Is(err, tt.want) {
+				t.Errorf("ChangeEmailInit() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevertEmailChange(t *testing.T) {
+	env, sent := newEmailChangeTestEnv(t, "123456")
+	ctx := context.Background()
+
+	caller := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "old@example.com", "password"),
+		Email:  "old@example.com",
+		AppID:  env.appID,
+	}
+
+	token := env.changeEmail(t, sent, caller, "new@example.com", "123456")
+
+	if err := env.auth.RevertEmailChange(ctx, "unknown token"); !errors.Is(err, ErrInvalidEmailChange) {
+		t.Errorf("RevertEmailChange() with an unknown token error = %v, want %v", err, ErrInvalidEmailChange)
+	}
+	if err := env.auth.RevertEmailChange(ctx, token); err != nil {
+		t.Fatalf("RevertEmailChange() error = %v", err)
+	}
+
+	user, err := env.repo.UserByID(ctx, caller.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "old@example.com" {
+		t.Errorf("user email = %q, want the old email back", user.Email)
+	}
+
+	// the link works once
+	if err := env.auth.RevertEmailChange(ctx, token); !errors.Is(err, ErrInvalidEmailChange) {
+		t.Errorf("second RevertEmailChange() error = %v, want %v", err, ErrInvalidEmailChange)
+	}
+}
+
+func TestRevertEmailChangeExpired(t *testing.T) {
+	env, sent := newEmailChangeTestEnv(t, "123456")
+	ctx := context.Background()
+
+	caller := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "old@example.com", "password"),
+		Email:  "old@example.com",
+		AppID:  env.appID,
+	}
+
+	token := env.changeEmail(t, sent, caller, "new@example.com", "123456")
+	env.exec(t, "UPDATE email_changes SET revert_expires_at = ? WHERE user_id = ?", time.Now().Add(-time.Second).UTC(), caller.UserID)
+
+	if err := env.auth.RevertEmailChange(ctx, token); !errors.Is(err, ErrInvalidEmailChange) {
+		t.Errorf("RevertEmailChange() once the revert expired error = %v, want %v", err, ErrInvalidEmailChange)
+	}
+}
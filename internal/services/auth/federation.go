@@ -0,0 +1,450 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/oidc"
+	"sso/internal/lib/randtoken"
+	"sso/internal/repository"
+	"time"
+)
+
+// providerTimeout bounds requests to upstream identity providers.
+const providerTimeout = 10 * time.Second
+
+type IdentityRepository interface {
+	Identity(ctx context.Context, provider string, subject string) (models.Identity, error)
+	SaveIdentity(ctx context.Context, identity models.Identity) error
+	CreateFederatedUser(ctx context.Context, user models.User, identity models.Identity) (int64, error)
+	SaveFederatedLogin(ctx context.Context, login models.FederatedLogin, now time.Time) error
+	TakeFederatedLogin(ctx context.Context, stateHash []byte, now time.Time) (models.FederatedLogin, error)
+	SetFederatedLoginCode(ctx context.Context, loginID int64, codeHash []byte, userID int64, expiresAt time.Time) error
+	TakeFederatedLoginCode(ctx context.Context, codeHash []byte, now time.Time) (models.FederatedLogin, error)
+}
+
+var (
+	ErrUnknownProvider       = errors.New("unknown identity provider")
+	ErrInvalidRedirectURI    = errors.New("redirect uri isn't registered for the app")
+	ErrInvalidFederatedLogin = errors.New("federated login is invalid or expired")
+	ErrFederatedLoginDenied  = errors.New("identity provider denied the login")
+	ErrFederatedLoginFailed  = errors.New("identity provider login failed")
+	ErrEmailNotVerified      = errors.New("identity provider didn't verify the email")
+)
+
+// identityProvider is an upstream OpenID Connect provider from the config.
+type identityProvider struct {
+	*oidc.Provider
+	cfg config.OIDCProviderConfig
+}
+
+// newIdentityProviders returns the providers of the config by name.
+func newIdentityProviders(cfg config.FederationConfig) map[string]identityProvider {
+	client := &http.Client{Timeout: providerTimeout}
+
+	providers := make(map[string]identityProvider, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		scopes := p.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"email", "profile"}
+		}
+
+		providers[p.Name] = identityProvider{
+			Provider: oidc.NewProvider(oidc.Config{
+				Issuer:       p.Issuer,
+				ClientID:     p.ClientID,
+				ClientSecret: os.Getenv(p.ClientSecretEnv),
+				RedirectURL:  p.RedirectURL,
+				Scopes:       scopes,
+			}, client),
+			cfg: p,
+		}
+	}
+
+	return providers
+}
+
+// IdentityProviders returns names of the upstream identity providers users can log in with.
+func (a *Auth) IdentityProviders() []string {
+	names := make([]string, 0, len(a.providers))
+	for name := range a.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// StartFederatedLogin starts logging the user in to the app through the upstream identity provider
+// and returns the URL of the provider's login page to send the user to. Once the user logs in there,
+// the provider sends them to the federation callback, which sends them on to redirectURI of the app
+// with the login code for FederatedLogin and clientState. The token is issued for the scopes,
+// third-party apps need the user to grant them the scopes with AuthorizeApp first.
+// codeChallenge is the S256 PKCE challenge of a verifier the app keeps, as in RFC 7636,
+// FederatedLogin exchanges the login code only with the verifier.
+func (a *Auth) StartFederatedLogin(
+	ctx context.Context,
+	provider string,
+	appID int,
+	orgID int64,
+	redirectURI string,
+	clientState string,
+	codeChallenge string,
+	scopes []string,
+) (string, error) {
+	const op = "auth.StartFederatedLogin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("provider", provider),
+		slog.Int("appID", appID),
+	)
+
+	p, ok := a.providers[provider]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", op, ErrUnknownProvider)
+	}
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		log.Error("failed to get app", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if app.Disabled {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+	}
+	// the app gets a login code to exchange, as in the authorization code flow
+	if !slices.Contains(app.GrantTypes, models.GrantTypeAuthorizationCode) {
+		log.Warn("app doesn't allow the authorization code grant")
+
+		return "", fmt.Errorf("%s: %w", op, ErrGrantNotAllowed)
+	}
+	if !slices.Contains(app.RedirectURIs, redirectURI) {
+		log.Warn("unregistered redirect uri", slog.String("redirectURI", redirectURI))
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidRedirectURI)
+	}
+	for _, scope := range scopes {
+		if !models.ValidScope(scope) {
+			return "", fmt.Errorf("%s: %w: %q", op, ErrInvalidScope, scope)
+		}
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		if secrets[i], err = randtoken.Generate(); err != nil {
+			log.Error("failed to generate login secrets", sl.Err(err))
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	state, nonce, codeVerifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		log.Error("identity provider is unavailable", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, ErrFederatedLoginFailed)
+	}
+
+	now := time.Now()
+	err = a.identityRepo.SaveFederatedLogin(ctx, models.FederatedLogin{
+		StateHash:        randtoken.Hash(state),
+		Provider:         provider,
+		Nonce:            nonce,
+		CodeVerifier:     codeVerifier,
+		AppID:            app.ID,
+		OrgID:            orgID,
+		RedirectURI:      redirectURI,
+		ClientState:      clientState,
+		Scopes:           scopes,
+		AppCodeChallenge: codeChallenge,
+		ExpiresAt:        now.Add(a.federation.LoginTTL),
+	}, now)
+	if err != nil {
+		log.Error("failed to save federated login", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("federated login started")
+
+	return authURL, nil
+}
+
+// FederatedCallback finishes the login at the upstream identity provider the user came back from
+// with the state and the authorization code, or with providerError if the provider didn't log them in.
+// The user with the provider's identity is found, linked by the verified email or created.
+// It returns the login and the login code the app exchanges for the token with FederatedLogin.
+// Once the state is known, the login is returned on errors as well, so that the user can be sent
+// back to the app with the error.
+func (a *Auth) FederatedCallback(
+	ctx context.Context,
+	state string,
+	code string,
+	providerError string,
+) (models.FederatedLogin, string, error) {
+	const op = "auth.FederatedCallback"
+
+	log := a.log.With(slog.String("op", op))
+
+	now := time.Now()
+
+	login, err := a.identityRepo.TakeFederatedLogin(ctx, randtoken.Hash(state), now)
+	if err != nil {
+		if errors.Is(err, repository.ErrFederatedLoginNotFound) {
+			log.Warn("federated login not found or expired")
+
+			return models.FederatedLogin{}, "", fmt.Errorf("%s: %w", op, ErrInvalidFederatedLogin)
+		}
+
+		log.Error("failed to get federated login", sl.Err(err))
+
+		return models.FederatedLogin{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("provider", login.Provider), slog.Int("appID", login.AppID))
+
+	if providerError != "" {
+		log.Info("identity provider denied the login", slog.String("error", providerError))
+
+		return login, "", fmt.Errorf("%s: %w", op, ErrFederatedLoginDenied)
+	}
+
+	// the provider may be gone from the config since the login started
+	p, ok := a.providers[login.Provider]
+	if !ok {
+		return login, "", fmt.Errorf("%s: %w", op, ErrUnknownProvider)
+	}
+
+	idToken, err := p.Exchange(ctx, code, login.CodeVerifier)
+	if err != nil {
+		log.Error("failed to exchange authorization code", sl.Err(err))
+
+		return login, "", fmt.Errorf("%s: %w", op, ErrFederatedLoginFailed)
+	}
+
+	claims, err := p.Verify(ctx, idToken, login.Nonce)
+	if err != nil {
+		log.Warn("invalid id token", sl.Err(err))
+
+		return login, "", fmt.Errorf("%s: %w", op, ErrFederatedLoginFailed)
+	}
+
+	user, err := a.federatedUser(ctx, p, claims)
+	if err != nil {
+		return login, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	loginCode, err := randtoken.Generate()
+	if err != nil {
+		log.Error("failed to generate login code", sl.Err(err))
+
+		return login, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = a.identityRepo.SetFederatedLoginCode(ctx, login.ID, randtoken.Hash(loginCode), user.ID, now.Add(a.federation.CodeTTL))
+	if err != nil {
+		log.Error("failed to save login code", sl.Err(err))
+
+		return login, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in at identity provider", slog.Int64("userID", user.ID))
+
+	return login, loginCode, nil
+}
+
+// FederatedLogin exchanges the login code FederatedCallback sent to the app for the token
+// of the user who logged in at the upstream identity provider. codeVerifier must be the verifier
+// of the challenge the app started the login with, the code is used up even if it isn't. Like Login, it cancels a requested
+// account deletion and returns ConsentRequiredError to users who must accept legal documents first.
+// The password lockout doesn't apply, the user proved their identity at the provider.
+func (a *Auth) FederatedLogin(ctx context.Context, loginCode string, codeVerifier string) (models.User, string, error) {
+	const op = "auth.FederatedLogin"
+
+	log := a.log.With(slog.String("op", op))
+
+	now := time.Now()
+
+	login, err := a.identityRepo.TakeFederatedLoginCode(ctx, randtoken.Hash(loginCode), now)
+	if err != nil {
+		if errors.Is(err, repository.ErrFederatedLoginNotFound) {
+			log.Warn("login code not found or expired")
+
+			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidFederatedLogin)
+		}
+
+		log.Error("failed to get federated login", sl.Err(err))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("userID", login.UserID), slog.String("provider", login.Provider))
+
+	// the code went through the user's browser, only the app that started the login holds the verifier
+	challenge := oidc.CodeChallenge(codeVerifier)
+	if login.AppCodeChallenge == "" || subtle.ConstantTimeCompare([]byte(challenge), []byte(login.AppCodeChallenge)) != 1 {
+		log.Warn("code verifier doesn't match the challenge", slog.Int("appID", login.AppID))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidFederatedLogin)
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, login.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidFederatedLogin)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Deleted(now) {
+		log.Warn("user is deleted")
+
+		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrInvalidFederatedLogin)
+	}
+	if user.Status == models.UserStatusPending {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrUserPending)
+	}
+	if user.Status == models.UserStatusSuspended {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, ErrUserDisabled)
+	}
+
+	if user.DeletionPending(now) {
+		if err := a.cancelDeletion(ctx, user); err != nil {
+			return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+		}
+		user.Status = models.UserStatusActive
+		user.DeleteAfter = time.Time{}
+	}
+
+	token, err := a.loginToken(ctx, user, login.AppID, login.OrgID, models.GrantTypeAuthorizationCode, login.Scopes)
+	if err != nil {
+		return models.User{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in through identity provider")
+
+	return user, token, nil
+}
+
+// federatedUser returns the user the provider's identity is linked to. Identities seen for the first
+// time are linked to the user with the same email or to a new user, but only if the provider
+// verified the email, so that nobody takes over an account by registering its email at a provider.
+func (a *Auth) federatedUser(ctx context.Context, p identityProvider, claims oidc.Claims) (models.User, error) {
+	const op = "auth.federatedUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("provider", p.cfg.Name),
+		slog.String("subject", claims.Subject),
+	)
+
+	now := time.Now()
+	identity := models.Identity{
+		Provider:    p.cfg.Name,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}
+
+	linked, err := a.identityRepo.Identity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, err := a.usrProvider.UserByID(ctx, linked.UserID)
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		identity.UserID = user.ID
+		if err := a.identityRepo.SaveIdentity(ctx, identity); err != nil {
+			log.Error("failed to update identity", sl.Err(err))
+		}
+
+		return user, nil
+	}
+	if !errors.Is(err, repository.ErrIdentityNotFound) {
+		log.Error("failed to get identity", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if claims.Email == "" || !(claims.EmailVerified || p.cfg.TrustEmail) {
+		log.Warn("email isn't verified by the provider", slog.String("email", claims.Email))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, ErrEmailNotVerified)
+	}
+
+	user, err := a.usrProvider.User(ctx, claims.Email, "")
+	switch {
+	case err == nil:
+		identity.UserID = user.ID
+		if err := a.identityRepo.SaveIdentity(ctx, identity); err != nil {
+			log.Error("failed to link identity", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("identity linked to user", slog.Int64("userID", user.ID))
+	case errors.Is(err, repository.ErrUserNotFound):
+		profile := models.User{
+			Name:     claims.GivenName,
+			LastName: claims.FamilyName,
+			Email:    claims.Email,
+		}
+		if profile.Name == "" {
+			profile.Name = claims.Name
+		}
+
+		id, err := a.identityRepo.CreateFederatedUser(ctx, profile, identity)
+		if err != nil {
+			log.Error("failed to create user", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		user, err = a.usrProvider.UserByID(ctx, id)
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("user created for identity", slog.Int64("userID", user.ID))
+	default:
+		log.Error("failed to get user", sl.Err(err))
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      user.ID,
+		Action:       models.AuditActionLinkIdentity,
+		TargetUserID: user.ID,
+		Details:      identity.Provider,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		log.Error("failed to save audit entry", sl.Err(err))
+	}
+
+	return user, nil
+}
@@ -0,0 +1,356 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/oidc"
+	"sso/internal/lib/oidc/oidctest"
+	"sso/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testProvider = "corp"
+	// testCodeVerifier is the PKCE verifier of the app logging users in.
+	testCodeVerifier = "app code verifier"
+)
+
+// newFederationTestEnv returns the test env with the provider configured as testProvider.
+func newFederationTestEnv(t *testing.T, op *oidctest.Provider, trustEmail bool) testEnv {
+	t.Helper()
+
+	t.Setenv("TEST_OIDC_CLIENT_SECRET", oidctest.ClientSecret)
+
+	return newTestEnv(t, testOptions{
+		federation: config.FederationConfig{
+			LoginTTL: time.Minute,
+			CodeTTL:  time.Minute,
+			Providers: []config.OIDCProviderConfig{{
+				Name:            testProvider,
+				Issuer:          op.URL,
+				ClientID:        oidctest.ClientID,
+				ClientSecretEnv: "TEST_OIDC_CLIENT_SECRET",
+				RedirectURL:     op.Config().RedirectURL,
+				TrustEmail:      trustEmail,
+			}},
+		},
+	})
+}
+
+// federate logs the user with the claims in at the provider and returns the login code
+// the callback sends to the app.
+func (e testEnv) federate(t *testing.T, op *oidctest.Provider, claims jwt.MapClaims) (string, error) {
+	t.Helper()
+
+	return e.federateApp(t, op, e.appID, nil, claims)
+}
+
+// federateApp is federate for logging in to the app with the scopes.
+func (e testEnv) federateApp(
+	t *testing.T,
+	op *oidctest.Provider,
+	appID int,
+	scopes []string,
+	claims jwt.MapClaims,
+) (string, error) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	authURL, err := e.auth.StartFederatedLogin(
+		ctx, testProvider, appID, 0, "https://app.example.com/callback", "", oidc.CodeChallenge(testCodeVerifier), scopes,
+	)
+	if err != nil {
+		t.Fatalf("StartFederatedLogin() error = %v", err)
+	}
+
+	state, code := op.Authorize(t, authURL, claims)
+
+	_, loginCode, err := e.auth.FederatedCallback(ctx, state, code, "")
+
+	return loginCode, err
+}
+
+func TestFederatedUser(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+	ctx := context.Background()
+
+	existingID := env.createUser(t, "existing@example.com", "password")
+
+	identityUser := func(subject string) int64 {
+		t.Helper()
+
+		identity, err := env.repo.Identity(ctx, testProvider, subject)
+		if errors.Is(err, repository.ErrIdentityNotFound) {
+			return 0
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return identity.UserID
+	}
+
+	t.Run("unverified email isn't linked", func(t *testing.T) {
+		_, err := env.federate(t, op, jwt.MapClaims{"sub": "unverified", "email": "existing@example.com", "email_verified": false})
+		if !errors.Is(err, ErrEmailNotVerified) {
+			t.Errorf("FederatedCallback() error = %v, want %v", err, ErrEmailNotVerified)
+		}
+		if id := identityUser("unverified"); id != 0 {
+			t.Errorf("identity linked to user %d", id)
+		}
+	})
+
+	t.Run("missing email isn't linked", func(t *testing.T) {
+		_, err := env.federate(t, op, jwt.MapClaims{"sub": "no-email", "email_verified": true})
+		if !errors.Is(err, ErrEmailNotVerified) {
+			t.Errorf("FederatedCallback() error = %v, want %v", err, ErrEmailNotVerified)
+		}
+	})
+
+	t.Run("verified email is linked to its user", func(t *testing.T) {
+		if _, err := env.federate(t, op, jwt.MapClaims{"sub": "verified", "email": "existing@example.com", "email_verified": true}); err != nil {
+			t.Fatalf("FederatedCallback() error = %v", err)
+		}
+		if id := identityUser("verified"); id != existingID {
+			t.Errorf("identity linked to user %d, want %d", id, existingID)
+		}
+	})
+
+	t.Run("linked identity keeps its user", func(t *testing.T) {
+		// the email no longer needs to be verified, nor to be the user's one
+		if _, err := env.federate(t, op, jwt.MapClaims{"sub": "verified", "email": "renamed@example.com"}); err != nil {
+			t.Fatalf("FederatedCallback() error = %v", err)
+		}
+		if id := identityUser("verified"); id != existingID {
+			t.Errorf("identity linked to user %d, want %d", id, existingID)
+		}
+	})
+
+	t.Run("verified new email creates user", func(t *testing.T) {
+		_, err := env.federate(t, op, jwt.MapClaims{
+			"sub":            "new",
+			"email":          "new@example.com",
+			"email_verified": true,
+			"given_name":     "New",
+			"family_name":    "User",
+		})
+		if err != nil {
+			t.Fatalf("FederatedCallback() error = %v", err)
+		}
+
+		user, err := env.repo.User(ctx, "new@example.com", "")
+		if err != nil {
+			t.Fatalf("created user not found: %v", err)
+		}
+		if user.Name != "New" || user.LastName != "User" {
+			t.Errorf("created user name = %q %q, want %q %q", user.Name, user.LastName, "New", "User")
+		}
+		if id := identityUser("new"); id != user.ID {
+			t.Errorf("identity linked to user %d, want %d", id, user.ID)
+		}
+	})
+}
+
+func TestFederatedUserTrustEmail(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, true)
+
+	existingID := env.createUser(t, "existing@example.com", "password")
+
+	if _, err := env.federate(t, op, jwt.MapClaims{"sub": "trusted", "email": "existing@example.com"}); err != nil {
+		t.Fatalf("FederatedCallback() error = %v", err)
+	}
+
+	identity, err := env.repo.Identity(context.Background(), testProvider, "trusted")
+	if err != nil {
+		t.Fatalf("identity not found: %v", err)
+	}
+	if identity.UserID != existingID {
+		t.Errorf("identity linked to user %d, want %d", identity.UserID, existingID)
+	}
+}
+
+func TestFederatedCallbackStateOneTime(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+	ctx := context.Background()
+
+	authURL, err := env.auth.StartFederatedLogin(
+		ctx, testProvider, env.appID, 0, "https://app.example.com/callback", "", oidc.CodeChallenge(testCodeVerifier), nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := op.Authorize(t, authURL, jwt.MapClaims{"sub": "user", "email": "user@example.com", "email_verified": true})
+
+	if _, _, err := env.auth.FederatedCallback(ctx, state, code, ""); err != nil {
+		t.Fatalf("FederatedCallback() error = %v", err)
+	}
+	if _, _, err := env.auth.FederatedCallback(ctx, state, code, ""); !errors.Is(err, ErrInvalidFederatedLogin) {
+		t.Errorf("FederatedCallback() with used state error = %v, want %v", err, ErrInvalidFederatedLogin)
+	}
+}
+
+func TestFederatedLoginCodeOneTime(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+	ctx := context.Background()
+
+	loginCode, err := env.federate(t, op, jwt.MapClaims{"sub": "user", "email": "user@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("FederatedCallback() error = %v", err)
+	}
+
+	user, token, err := env.auth.FederatedLogin(ctx, loginCode, testCodeVerifier)
+	if err != nil {
+		t.Fatalf("FederatedLogin() error = %v", err)
+	}
+	if user.Email != "user@example.com" || token == "" {
+		t.Errorf("FederatedLogin() = %q, %q, want the user's token", user.Email, token)
+	}
+
+	if _, _, err := env.auth.FederatedLogin(ctx, loginCode, testCodeVerifier); !errors.Is(err, ErrInvalidFederatedLogin) {
+		t.Errorf("FederatedLogin() with used code error = %v, want %v", err, ErrInvalidFederatedLogin)
+	}
+	if _, _, err := env.auth.FederatedLogin(ctx, "unknown code", testCodeVerifier); !errors.Is(err, ErrInvalidFederatedLogin) {
+		t.Errorf("FederatedLogin() with unknown code error = %v, want %v", err, ErrInvalidFederatedLogin)
+	}
+}
+
+func TestFederatedLoginRequiresCodeVerifier(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+	ctx := context.Background()
+
+	loginCode, err := env.federate(t, op, jwt.MapClaims{"sub": "user", "email": "user@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("FederatedCallback() error = %v", err)
+	}
+
+	// whoever intercepted the code doesn't have the app's verifier
+	if _, _, err := env.auth.FederatedLogin(ctx, loginCode, "other verifier"); !errors.Is(err, ErrInvalidFederatedLogin) {
+		t.Fatalf("FederatedLogin() with another verifier error = %v, want %v", err, ErrInvalidFederatedLogin)
+	}
+	// and the code is used up, so that verifiers can't be guessed
+	if _, _, err := env.auth.FederatedLogin(ctx, loginCode, testCodeVerifier); !errors.Is(err, ErrInvalidFederatedLogin) {
+		t.Errorf("FederatedLogin() after a wrong verifier error = %v, want %v", err, ErrInvalidFederatedLogin)
+	}
+}
+
+func TestFederatedLoginDeniedUser(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+
+	userID := env.createUser(t, "suspended@example.com", "password")
+	if err := env.repo.SetUserStatus(context.Background(), userID, models.UserStatusActive, models.UserStatusSuspended, "test", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	loginCode, err := env.federate(t, op, jwt.MapClaims{"sub": "user", "email": "suspended@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("FederatedCallback() error = %v", err)
+	}
+
+	if _, _, err := env.auth.FederatedLogin(context.Background(), loginCode, testCodeVerifier); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("FederatedLogin() error = %v, want %v", err, ErrUserDisabled)
+	}
+}
+
+func TestFederatedLoginRequiresAuthorizationCodeGrant(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+	ctx := context.Background()
+
+	appID, err := env.repo.CreateApp(ctx, models.App{
+		Name:         "password-only",
+		SecretHash:   []byte("password-only-secret"),
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		GrantTypes:   []string{models.GrantTypePassword},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.auth.StartFederatedLogin(
+		ctx, testProvider, appID, 0, "https://app.example.com/callback", "", oidc.CodeChallenge(testCodeVerifier), nil,
+	)
+	if !errors.Is(err, ErrGrantNotAllowed) {
+		t.Errorf("StartFederatedLogin() error = %v, want %v", err, ErrGrantNotAllowed)
+	}
+}
+
+func TestStartFederatedLoginInvalidScope(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+
+	_, err := env.auth.StartFederatedLogin(
+		context.Background(), testProvider, env.appID, 0, "https://app.example.com/callback", "",
+		oidc.CodeChallenge(testCodeVerifier), []string{"read write"},
+	)
+	if !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("StartFederatedLogin() error = %v, want %v", err, ErrInvalidScope)
+	}
+}
+
+func TestFederatedLoginThirdPartyAppRequiresGrant(t *testing.T) {
+	op := oidctest.NewProvider(t)
+	env := newFederationTestEnv(t, op, false)
+	ctx := context.Background()
+
+	userID := env.createUser(t, "user@example.com", "password")
+	appID := env.createThirdPartyApp(t, models.GrantTypeAuthorizationCode)
+	claims := jwt.MapClaims{"sub": "user", "email": "user@example.com", "email_verified": true}
+
+	login := func(scopes ...string) (string, error) {
+		t.Helper()
+
+		loginCode, err := env.federateApp(t, op, appID, scopes, claims)
+		if err != nil {
+			t.Fatalf("FederatedCallback() error = %v", err)
+		}
+
+		_, token, err := env.auth.FederatedLogin(ctx, loginCode, testCodeVerifier)
+
+		return token, err
+	}
+
+	if _, err := login(); !errors.Is(err, ErrAppNotAuthorized) {
+		t.Fatalf("FederatedLogin() without grant error = %v, want %v", err, ErrAppNotAuthorized)
+	}
+
+	err := env.repo.SaveGrant(ctx, models.Grant{UserID: userID, AppID: appID, Scopes: []string{"read"}, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := login("read")
+	if err != nil {
+		t.Fatalf("FederatedLogin() with granted scopes error = %v", err)
+	}
+	principal, err := env.auth.Authorize(ctx, token, "")
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if !slices.Equal(principal.Scopes, []string{"read"}) {
+		t.Errorf("token scopes = %v, want [read]", principal.Scopes)
+	}
+
+	if _, err := login("read", "write"); !errors.Is(err, ErrAppNotAuthorized) {
+		t.Errorf("FederatedLogin() with scopes not granted error = %v, want %v", err, ErrAppNotAuthorized)
+	}
+
+	if err := env.repo.RevokeGrant(ctx, userID, appID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := login("read"); !errors.Is(err, ErrAppNotAuthorized) {
+		t.Errorf("FederatedLogin() after revoking the grant error = %v, want %v", err, ErrAppNotAuthorized)
+	}
+}
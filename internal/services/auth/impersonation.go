@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/jwt"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"time"
+)
+
+type AuditLog interface {
+	SaveAuditEntry(ctx context.Context, entry models.AuditEntry) (int64, error)
+}
+
+var ErrInvalidImpersonation = errors.New("can't impersonate this user")
+
+// Impersonate issues the caller a short-lived token of the target user in the caller's app.
+// The token carries the act claim naming the caller, can't be used for calls requiring a permission
+// and stops working once the caller loses the impersonate permission. Every impersonation
+// is recorded in the audit log along with the reason.
+func (a *Auth) Impersonate(
+	ctx context.Context,
+	caller models.Principal,
+	targetUserID int64,
+	reason string,
+) (string, time.Time, error) {
+	const op = "auth.Impersonate"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("actorID", caller.UserID),
+		slog.Int64("targetUserID", targetUserID),
+	)
+
+	if caller.Type != models.PrincipalUser || caller.ActorID != 0 || caller.UserID == targetUserID {
+		log.Warn("caller can't impersonate", slog.String("type", caller.Type), slog.Int64("callerActorID", caller.ActorID))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidImpersonation)
+	}
+
+	target, err := a.usrProvider.UserByID(ctx, targetUserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if target.Blocked() {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrUserDisabled)
+	}
+
+	app, err := a.appProvider.App(ctx, caller.AppID)
+	if err != nil {
+		log.Error("failed to get app", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	principal := models.Principal{
+		Type:       models.PrincipalUser,
+		UserID:     target.ID,
+		Email:      target.Email,
+		AppID:      app.ID,
+		ActorID:    caller.UserID,
+		ActorEmail: caller.Email,
+	}
+
+	if app.OrgID != 0 {
+		member, err := a.orgProvider.OrgMember(ctx, app.OrgID, target.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotOrgMember) {
+				return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrNotOrgMember)
+			}
+
+			log.Error("failed to get organization member", sl.Err(err))
+
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		principal.OrgID = member.OrgID
+		principal.OrgRole = member.Role
+	}
+
+	principal.Roles, err = a.permProvider.UserRoles(ctx, target.ID, app.ID)
+	if err != nil {
+		log.Error("failed to get user roles", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if a.groupsClaim.Enabled {
+		if err := a.addGroups(ctx, &principal); err != nil {
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := a.addAttributes(ctx, &principal, app); err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the token is only issued once the impersonation is on record
+	now := time.Now()
+	_, err = a.auditLog.SaveAuditEntry(ctx, models.AuditEntry{
+		ActorID:      caller.UserID,
+		Action:       models.AuditActionImpersonate,
+		TargetUserID: target.ID,
+		AppID:        app.ID,
+		Details:      reason,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		log.Error("failed to save audit entry", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := jwt.NewToken(principal, app, a.impersonation.TokenTTL)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Warn("user impersonated", slog.String("reason", reason))
+
+	return token, now.Add(a.impersonation.TokenTTL), nil
+}
+
+// checkActor verifies that the user impersonating the principal may still do so.
+func (a *Auth) checkActor(ctx context.Context, principal models.Principal) error {
+	const op = "auth.checkActor"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("actorID", principal.ActorID),
+	)
+
+	actor, err := a.usrProvider.UserByID(ctx, principal.ActorID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Warn("token actor not found")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if actor.Blocked() {
+		log.Warn("token actor is not active", slog.String("status", actor.Status))
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	has, err := a.permProvider.HasPermission(ctx, actor.ID, principal.AppID, models.PermissionImpersonate)
+	if err != nil {
+		log.Error("failed to check permission", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !has {
+		log.Warn("token actor can't impersonate anymore")
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// newImpersonationTestEnv returns the test env with a support user holding the impersonate permission
+// through the support role, their principal and a user to impersonate.
+func newImpersonationTestEnv(t *testing.T) (testEnv, models.Principal, int64) {
+	t.Helper()
+
+	env := newTestEnv(t, testOptions{})
+	env.auth.impersonation = config.ImpersonationConfig{TokenTTL: time.Minute}
+	ctx := context.Background()
+
+	permID, err := env.repo.CreatePermission(ctx, env.appID, models.PermissionImpersonate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roleID, err := env.repo.CreateRole(ctx, env.appID, "support")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.GrantPermission(ctx, roleID, permID); err != nil {
+		t.Fatal(err)
+	}
+
+	support := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "support@example.com", "password"),
+		Email:  "support@example.com",
+		AppID:  env.appID,
+	}
+	if err := env.repo.AssignRole(ctx, support.UserID, roleID); err != nil {
+		t.Fatal(err)
+	}
+
+	return env, support, env.createUser(t, "user@example.com", "password")
+}
+
+func TestImpersonate(t *testing.T) {
+	env, support, userID := newImpersonationTestEnv(t)
+	ctx := context.Background()
+
+	token, expiresAt, err := env.auth.Impersonate(ctx, support, userID, "ticket 42")
+	if err != nil {
+		t.Fatalf("Impersonate() error = %v", err)
+	}
+	if expiresAt.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Impersonate() expires at %v, want within the impersonation token ttl", expiresAt)
+	}
+
+	principal, err := env.auth.Authorize(ctx, token, "")
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if principal.UserID != userID || principal.ActorID != support.UserID || principal.ActorEmail != support.Email {
+		t.Errorf("Authorize() = user %d acted by %d %q, want user %d acted by %d %q",
+			principal.UserID, principal.ActorID, principal.ActorEmail, userID, support.UserID, support.Email)
+	}
+
+	// the token doesn't carry privileges, even those the user holds
+	if _, err := env.auth.Authorize(ctx, token, models.PermissionImpersonate); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Authorize() with a permission error = %v, want %v", err, ErrPermissionDenied)
+	}
+
+	entries, err := env.repo.AuditEntries(ctx, userID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Action != models.AuditActionImpersonate ||
+		entries[0].ActorID != support.UserID || entries[0].Details != "ticket 42" {
+		t.Errorf("AuditEntries() = %+v, want the impersonation with the reason", entries)
+	}
+}
+
+func TestImpersonateRevokedWithActorPermission(t *testing.T) {
+	env, support, userID := newImpersonationTestEnv(t)
+	ctx := context.Background()
+
+	token, _, err := env.auth.Impersonate(ctx, support, userID, "ticket 42")
+	if err != nil {
+		t.Fatalf("Impersonate() error = %v", err)
+	}
+
+	if err := env.repo.SetUserRoles(ctx, support.UserID, env.appID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.auth.Authorize(ctx, token, ""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authorize() once the actor can't impersonate error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestImpersonateInvalid(t *testing.T) {
+	env, support, userID := newImpersonationTestEnv(t)
+	ctx := context.Background()
+
+	impersonated := support
+	impersonated.ActorID = env.createUser(t, "other@example.com", "password")
+
+	tests := []struct {
+		name   string
+		caller models.Principal
+		target int64
+		want   error
+	}{
+		{name: "self", caller: support, target: support.UserID, want: ErrInvalidImpersonation},
+		{name: "impersonated caller", caller: impersonated, target: userID, want: ErrInvalidImpersonation},
+		{name: "service account", caller: models.Principal{Type: models.PrincipalServiceAccount, UserID: 1, AppID: env.appID}, target: userID, want: ErrInvalidImpersonation},
+		{name: "missing user", caller: support, target: userID + 100, want: ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := env.auth.Impersonate(ctx, tt.caller, tt.target, "reason"); !errors.Is(err, tt.want) {
+				t.Errorf("Impersonate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/randtoken"
+	"sso/internal/repository"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type InvitationRepository interface {
+	InvitationByTokenHash(ctx context.Context, tokenHash []byte) (models.Invitation, error)
+	AcceptInvitation(ctx context.Context, inv models.Invitation, user models.User, acceptedAt time.Time) (int64, error)
+}
+
+var ErrInvalidInvitation = errors.New("invitation is invalid or expired")
+
+// AcceptInvitation registers the invited user with the given password and profile.
+// The email comes from the invitation and is verified by the link itself, the app role
+// and organization membership preset by the inviter are granted at once.
+func (a *Auth) AcceptInvitation(
+	ctx context.Context,
+	token string,
+	pass string,
+	profile models.User,
+) (int64, error) {
+	const op = "auth.AcceptInvitation"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	inv, err := a.invitationRepo.InvitationByTokenHash(ctx, randtoken.Hash(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			log.Warn("invitation not found")
+
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+		}
+
+		log.Error("failed to get invitation", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("invitationID", inv.ID))
+
+	now := time.Now()
+	if !inv.Pending(now) {
+		log.Warn("invitation is not pending")
+
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+	}
+
+	if profile.Phone != "" {
+		if profile.Phone, err = a.phones.Normalize(profile.Phone); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidPhone)
+		}
+	}
+
+	profile.PassHash, err = bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := a.invitationRepo.AcceptInvitation(ctx, inv, profile, now)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) || errors.Is(err, repository.ErrRoleNotFound) {
+			log.Warn("invitation was used or its role removed meanwhile", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+		}
+		if errors.Is(err, repository.ErrUserExists) {
+			log.Warn("user already exists", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrUserExists)
+		}
+
+		log.Error("failed to accept invitation", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation accepted", slog.Int64("userID", id))
+
+	return id, nil
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/randtoken"
+	"testing"
+	"time"
+)
+
+// createInvitation saves the invitation made by the inviter with the token and returns its ID.
+func (e testEnv) createInvitation(t *testing.T, inv models.Invitation, token string) int64 {
+	t.Helper()
+
+	inv.TokenHash = randtoken.Hash(token)
+	inv.AppID = e.appID
+	if inv.CreatedAt.IsZero() {
+		inv.CreatedAt = time.Now()
+	}
+	if inv.ExpiresAt.IsZero() {
+		inv.ExpiresAt = inv.CreatedAt.Add(time.Hour)
+	}
+
+	id, err := e.repo.CreateInvitation(context.Background(), inv)
+	if err != nil {
+		t.Fatalf("create invitation: %v", err)
+	}
+
+	return id
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	ctx := context.Background()
+
+	inviterID := env.createUser(t, "owner@example.com", "password")
+	orgID, err := env.repo.CreateOrganization(ctx, models.Organization{Name: "acme", CreatedAt: time.Now()}, inviterID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.repo.CreateRole(ctx, env.appID, "editor"); err != nil {
+		t.Fatal(err)
+	}
+
+	env.createInvitation(t, models.Invitation{
+		Email:     "invited@example.com",
+		InviterID: inviterID,
+		Role:      "editor",
+		OrgID:     orgID,
+		OrgRole:   models.OrgRoleAdmin,
+	}, "invitation token")
+
+	userID, err := env.auth.AcceptInvitation(ctx, "invitation token", "password", models.User{Name: "Invited", LastName: "User"})
+	if err != nil {
+		t.Fatalf("AcceptInvitation() error = %v", err)
+	}
+
+	user, err := env.repo.UserByID(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "invited@example.com" || !user.EmailVerified {
+		t.Errorf("user email = %q verified %t, want the invited email verified", user.Email, user.EmailVerified)
+	}
+	if roles, _ := env.repo.UserRoles(ctx, userID, env.appID); !slices.Equal(roles, []string{"editor"}) {
+		t.Errorf("user roles = %v, want [editor]", roles)
+	}
+	if member, err := env.repo.OrgMember(ctx, orgID, userID); err != nil || member.Role != models.OrgRoleAdmin {
+		t.Errorf("OrgMember() = %+v, %v, want the %s", member, err, models.OrgRoleAdmin)
+	}
+
+	if _, _, err := env.auth.Login(ctx, "invited@example.com", "password", "", env.appID, 0); err != nil {
+		t.Errorf("Login() of the invited user error = %v", err)
+	}
+
+	// the link works once
+	_, err = env.auth.AcceptInvitation(ctx, "invitation token", "password", models.User{Name: "Again", LastName: "User"})
+	if !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("second AcceptInvitation() error = %v, want %v", err, ErrInvalidInvitation)
+	}
+}
+
+func TestAcceptInvitationNotPending(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	ctx := context.Background()
+
+	inviterID := env.createUser(t, "admin@example.com", "password")
+
+	env.createInvitation(t, models.Invitation{
+		Email:     "expired@example.com",
+		InviterID: inviterID,
+		CreatedAt: time.Now().Add(-2 * time.Hour),
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}, "expired token")
+
+	revokedID := env.createInvitation(t, models.Invitation{Email: "revoked@example.com", InviterID: inviterID}, "revoked token")
+	if err := env.repo.RevokeInvitation(ctx, revokedID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"expired token", "revoked token", "unknown token"} {
+		_, err := env.auth.AcceptInvitation(ctx, token, "password", models.User{Name: "Invited", LastName: "User"})
+		if !errors.Is(err, ErrInvalidInvitation) {
+			t.Errorf("AcceptInvitation() with %s error = %v, want %v", token, err, ErrInvalidInvitation)
+		}
+	}
+}
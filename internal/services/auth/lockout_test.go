@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func TestLoginLockout(t *testing.T) {
+	env := newTestEnv(t, testOptions{
+		lockout: config.LockoutConfig{MaxAttempts: 3, Duration: time.Minute, Window: time.Hour},
+	})
+	userID := env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := env.auth.Login(ctx, "user@example.com", "wrong", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Login() with a wrong password error = %v, want %v", err, ErrInvalidCredentials)
+		}
+	}
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("Login() of the locked out user error = %v, want %v", err, ErrAccountLocked)
+	}
+	if user, _ := env.repo.UserByID(ctx, userID); user.Status != models.UserStatusLocked {
+		t.Errorf("user status = %q, want %q", user.Status, models.UserStatusLocked)
+	}
+
+	// the lockout is over
+	env.exec(t, "UPDATE login_attempts SET locked_until = ? WHERE user_id = ?", time.Now().Add(-time.Second).UTC(), userID)
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); err != nil {
+		t.Fatalf("Login() once the lockout is over error = %v", err)
+	}
+	if user, _ := env.repo.UserByID(ctx, userID); user.Status != models.UserStatusActive {
+		t.Errorf("user status = %q, want %q", user.Status, models.UserStatusActive)
+	}
+	if state, _ := env.repo.LockoutState(ctx, userID); state.FailedAttempts != 0 {
+		t.Errorf("failed attempts = %d, want them reset", state.FailedAttempts)
+	}
+}
+
+func TestLoginLockoutDelay(t *testing.T) {
+	env := newTestEnv(t, testOptions{
+		lockout: config.LockoutConfig{MaxAttempts: 5, BaseDelay: time.Minute, Duration: time.Hour},
+	})
+	env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "wrong", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() with a wrong password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	// the next attempt waits for the delay, even with the right password
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("Login() during the delay error = %v, want %v", err, ErrAccountLocked)
+	}
+}
+
+func TestLoginLockoutWindow(t *testing.T) {
+	env := newTestEnv(t, testOptions{
+		lockout: config.LockoutConfig{MaxAttempts: 2, Duration: time.Minute, Window: time.Hour},
+	})
+	userID := env.createUser(t, "user@example.com", "password")
+	ctx := context.Background()
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "wrong", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() with a wrong password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	// the first failure is too old to count
+	env.exec(t, "UPDATE login_attempts SET first_failed_at = ? WHERE user_id = ?", time.Now().Add(-2*time.Hour).UTC(), userID)
+
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "wrong", "", env.appID, 0); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() with a wrong password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if _, _, err := env.auth.Login(ctx, "user@example.com", "password", "", env.appID, 0); err != nil {
+		t.Errorf("Login() after failures in different windows error = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"strings"
+)
+
+// checkOrgAccess checks that the user is a member of the organization and meets its settings.
+func (a *Auth) checkOrgAccess(ctx context.Context, user models.User, orgID int64) (models.OrgMember, error) {
+	const op = "auth.checkOrgAccess"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", user.ID),
+		slog.Int64("orgID", orgID),
+	)
+
+	org, err := a.orgProvider.Organization(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrgNotFound) {
+			log.Warn("organization not found")
+
+			return models.OrgMember{}, fmt.Errorf("%s: %w", op, ErrInvalidOrgID)
+		}
+
+		log.Error("failed to get organization", sl.Err(err))
+
+		return models.OrgMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	member, err := a.orgProvider.OrgMember(ctx, orgID, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotOrgMember) {
+			log.Warn("user is not a member of the organization")
+
+			return models.OrgMember{}, fmt.Errorf("%s: %w", op, ErrNotOrgMember)
+		}
+
+		log.Error("failed to get organization member", sl.Err(err))
+
+		return models.OrgMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(org.AllowedEmailDomains) > 0 && !slices.Contains(org.AllowedEmailDomains, emailDomain(user.Email)) {
+		log.Warn("email domain is not allowed in the organization")
+
+		return models.OrgMember{}, fmt.Errorf("%s: %w", op, ErrEmailDomain)
+	}
+
+	// there is no second factor to check yet, so organizations requiring it can't be logged in to
+	if org.RequireMFA {
+		log.Warn("organization requires multi-factor authentication")
+
+		return models.OrgMember{}, fmt.Errorf("%s: %w", op, ErrMFARequired)
+	}
+
+	return member, nil
+}
+
+func emailDomain(email string) string {
+	_, domain, _ := strings.Cut(email, "@")
+
+	return strings.ToLower(domain)
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/phone"
+	"sso/internal/repository"
+	"sso/internal/services"
+	"time"
+)
+
+type PhoneChangeRepository interface {
+	CreatePhoneChange(ctx context.Context, change models.PhoneChange) (int64, error)
+	PendingPhoneChange(ctx context.Context, userID int64) (models.PhoneChange, error)
+	ConfirmPhoneChange(ctx context.Context, change models.PhoneChange) error
+}
+
+var (
+	ErrInvalidPhone       = phone.ErrInvalidPhone
+	ErrInvalidPhoneChange = errors.New("phone was changed meanwhile")
+)
+
+// ChangePhoneInit sends a code confirming the new phone to it by SMS and returns when the code expires.
+// Impersonating support staff can't change phones. With enumeration protection on, a taken phone
+// gets no code instead of ErrUserExists.
+func (a *Auth) ChangePhoneInit(ctx context.Context, caller models.Principal, newPhone string) (time.Time, error) {
+	const op = "auth.ChangePhoneInit"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+	)
+
+	if caller.Type != models.PrincipalUser || caller.ActorID != 0 {
+		log.Warn("caller can't change phone", slog.String("type", caller.Type), slog.Int64("actorID", caller.ActorID))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	newPhone, err := a.phones.Normalize(newPhone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidPhone)
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, caller.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return time.Time{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Phone == newPhone {
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidPhone)
+	}
+
+	_, err = a.usrProvider.User(ctx, "", newPhone)
+	if err == nil {
+		log.Warn("phone is taken")
+
+		if a.enumerationProtection {
+			return time.Now().UTC().Add(a.verCodeTTL), nil
+		}
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrUserExists)
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		log.Error("failed to get user", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the code is issued first so that the resend cooldown keeps it bound to the latest pending change
+	code, expiresAt, err := a.issueCode(ctx, models.CodePurposePhoneVerify, user.ID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = a.phoneChangeRepo.CreatePhoneChange(ctx, models.PhoneChange{
+		UserID:    user.ID,
+		OldPhone:  user.Phone,
+		NewPhone:  newPhone,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Error("failed to save phone change", sl.Err(err))
+
+		if err := a.repo.DeleteCode(ctx, models.CodePurposePhoneVerify, user.ID); err != nil {
+			log.Error("failed to delete code", sl.Err(err))
+		}
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = a.smsService.SendVerificationSMS(services.VerificationSMSInput{
+		Phone:            newPhone,
+		VerificationCode: code,
+	})
+	if err != nil {
+		log.Error("failed to send verification sms", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("phone change requested")
+
+	return expiresAt, nil
+}
+
+// ChangePhoneConfirm replaces the caller's phone with the one the code was sent to.
+func (a *Auth) ChangePhoneConfirm(ctx context.Context, caller models.Principal, code string) error {
+	const op = "auth.ChangePhoneConfirm"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("userID", caller.UserID),
+	)
+
+	if caller.Type != models.PrincipalUser || caller.ActorID != 0 {
+		return fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	change, err := a.phoneChangeRepo.PendingPhoneChange(ctx, caller.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPhoneChangeNotFound) {
+			log.Warn("no pending phone change")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidCode)
+		}
+
+		log.Error("failed to get phone change", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.verifyCode(ctx, models.CodePurposePhoneVerify, caller.UserID, code); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	change.ConfirmedAt = time.Now()
+
+	if err := a.phoneChangeRepo.ConfirmPhoneChange(ctx, change); err != nil {
+		if errors.Is(err, repository.ErrUserExists) {
+			log.Warn("phone was taken meanwhile")
+
+			return fmt.Errorf("%s: %w", op, ErrUserExists)
+		}
+		if errors.Is(err, repository.ErrPhoneChangeNotFound) {
+			log.Warn("phone was changed meanwhile")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidPhoneChange)
+		}
+
+		log.Error("failed to confirm phone change", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("phone changed", slog.Int64("phoneChangeID", change.ID))
+
+	return nil
+}
+
+// lookupPhone normalizes the phone to look the user up by. Phones that can't be normalized match nobody.
+func (a *Auth) lookupPhone(phone string) string {
+	normalized, err := a.phones.Normalize(phone)
+	if err != nil {
+		return ""
+	}
+
+	return normalized
+}
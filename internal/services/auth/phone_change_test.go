@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/domain/models"
+	"sso/internal/lib/phone"
+	"sso/internal/services"
+	"sso/internal/services/sms"
+	"testing"
+)
+
+// recordingSMSSender keeps the messages sent.
+type recordingSMSSender struct {
+	sent *[]sms.SendSMSInput
+}
+
+func (s recordingSMSSender) Send(input sms.SendSMSInput) error {
+	*s.sent = append(*s.sent, input)
+
+	return nil
+}
+
+// newPhoneChangeTestEnv returns the test env keeping codes in memory, issuing code, sending messages
+// to sent and taking national phones of Russia.
+func newPhoneChangeTestEnv(t *testing.T, code string) (testEnv, *[]sms.SendSMSInput) {
+	t.Helper()
+
+	env := newCodesTestEnv(t, 5)
+	env.auth.otpGenerator = fixedOTP(code)
+	env.auth.phones = phone.NewNormalizer("7", "8")
+
+	sent := &[]sms.SendSMSInput{}
+	env.auth.smsService = services.NewSMSService(env.auth.log, recordingSMSSender{sent: sent})
+
+	return env, sent
+}
+
+func TestChangePhone(t *testing.T) {
+	env, sent := newPhoneChangeTestEnv(t, "123456")
+	ctx := context.Background()
+
+	caller := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "user@example.com", "password"),
+		Email:  "user@example.com",
+		AppID:  env.appID,
+	}
+
+	if _, err := env.auth.ChangePhoneInit(ctx, caller, "8 (999) 111-11-11"); err != nil {
+		t.Fatalf("ChangePhoneInit() error = %v", err)
+	}
+	if len(*sent) != 1 || (*sent)[0].To != "+79991111111" {
+		t.Fatalf("sent %+v, want the code sent to the normalized phone", *sent)
+	}
+
+	if err := env.auth.ChangePhoneConfirm(ctx, caller, "654321"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("ChangePhoneConfirm() with a wrong code error = %v, want %v", err, ErrInvalidCode)
+	}
+	if err := env.auth.ChangePhoneConfirm(ctx, caller, "123456"); err != nil {
+		t.Fatalf("ChangePhoneConfirm() error = %v", err)
+	}
+
+	user, err := env.repo.UserByID(ctx, caller.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Phone != "+79991111111" {
+		t.Errorf("user phone = %q, want %q", user.Phone, "+79991111111")
+	}
+
+	// the phone logs in however it's typed
+	if _, _, err := env.auth.Login(ctx, "", "password", "89991111111", env.appID, 0); err != nil {
+		t.Errorf("Login() with the new phone error = %v", err)
+	}
+
+	if err := env.auth.ChangePhoneConfirm(ctx, caller, "123456"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("second ChangePhoneConfirm() error = %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestChangePhoneInitInvalid(t *testing.T) {
+	env, sent := newPhoneChangeTestEnv(t, "123456")
+	ctx := context.Background()
+
+	_, err := env.repo.SaveUser(ctx, "", "", "Test", "User", "taken@example.com", []byte("hash"), "+79992222222")
+	if err != nil {
+		t.Fatal(err)
+	}
+	caller := models.Principal{
+		Type:   models.PrincipalUser,
+		UserID: env.createUser(t, "user@example.com", "password"),
+		Email:  "user@example.com",
+		AppID:  env.appID,
+	}
+	impersonated := caller
+	impersonated.ActorID = 1
+
+	tests := []struct {
+		name     string
+		caller   models.Principal
+		newPhone string
+		want     error
+	}{
+		{name: "impersonated", caller: impersonated, newPhone: "+79993333333", want: ErrPermissionDenied},
+		{name: "invalid phone", caller: caller, newPhone: "abc", want: ErrInvalidPhone},
+		{name: "taken phone", caller: caller, newPhone: "8 999 222 22 22", want: ErrUserExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.auth.ChangePhoneInit(ctx, tt.caller, tt.newPhone); !errors.Is(err, tt.want) {
+				t.Errorf("ChangePhoneInit() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if len(*sent) != 0 {
+		t.Errorf("sent %+v, want no codes", *sent)
+	}
+}
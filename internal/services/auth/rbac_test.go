@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/repository/sqlite"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// newLegacyAdminsRepo returns the storage of users flagged with is_admin before roles were introduced,
+// migrated to the latest version. The storage has apps 1 and 2.
+func newLegacyAdminsRepo(t *testing.T, admins map[string]bool) (*sqlite.Repository, map[string]int64) {
+	t.Helper()
+
+	storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://"+migrationsPath, "sqlite3://"+storagePath)
+	if err != nil {
+		t.Fatalf("open migrations: %v", err)
+	}
+	defer m.Close()
+
+	// the last version before roles were introduced
+	if err := m.Migrate(6); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", storagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO apps (id, name, secret) VALUES (2, 'other', 'other-secret')"); err != nil {
+		t.Fatalf("save app: %v", err)
+	}
+	ids := make(map[string]int64, len(admins))
+	for email, isAdmin := range admins {
+		res, err := db.Exec(
+			"INSERT INTO users (name, last_name, email, pass_hash, is_admin) VALUES ('Test', 'User', ?, '', ?)",
+			email, isAdmin,
+		)
+		if err != nil {
+			t.Fatalf("save user: %v", err)
+		}
+		ids[email], _ = res.LastInsertId()
+	}
+	db.Close()
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	repo, err := sqlite.New(storagePath)
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	t.Cleanup(func() { repo.Stop() })
+
+	return repo, ids
+}
+
+func TestRBACMigrationKeepsAdmins(t *testing.T) {
+	repo, ids := newLegacyAdminsRepo(t, map[string]bool{
+		"admin@example.com": true,
+		"user@example.com":  false,
+	})
+	ctx := context.Background()
+
+	for _, appID := range []int{1, 2} {
+		roles, err := repo.UserRoles(ctx, ids["admin@example.com"], appID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(roles, []string{"admin"}) {
+			t.Errorf("roles of the admin in app %d = %v, want [admin]", appID, roles)
+		}
+
+		has, err := repo.HasPermission(ctx, ids["admin@example.com"], appID, models.PermissionAdmin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !has {
+			t.Errorf("admin doesn't have the %q permission in app %d", models.PermissionAdmin, appID)
+		}
+
+		roles, err = repo.UserRoles(ctx, ids["user@example.com"], appID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(roles) != 0 {
+			t.Errorf("roles of the user in app %d = %v, want none", appID, roles)
+		}
+	}
+}
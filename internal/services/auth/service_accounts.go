@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/jwt"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ServiceAccountProvider interface {
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
+	ServiceAccountByClientID(ctx context.Context, clientID string) (models.ServiceAccount, error)
+	ServiceAccountRoles(ctx context.Context, serviceAccountID int64, appID int) ([]string, error)
+	ServiceAccountHasPermission(ctx context.Context, serviceAccountID int64, appID int, permission string) (bool, error)
+	ServiceAccountLockoutState(ctx context.Context, serviceAccountID int64) (models.LockoutState, error)
+	SaveServiceAccountLockoutState(ctx context.Context, state models.LockoutState) error
+	ResetServiceAccountLockoutState(ctx context.Context, serviceAccountID int64) error
+}
+
+var ErrGrantNotAllowed = errors.New("grant type is not allowed for the app")
+
+// ServiceAccountToken exchanges client credentials of a service account for an access token to the app.
+// The app must allow the client_credentials grant and be accessible to the account.
+// Wrong secrets delay the next attempt and lock the account out the way wrong passwords lock out users.
+func (a *Auth) ServiceAccountToken(ctx context.Context, clientID string, clientSecret string, appID int) (string, error) {
+	const op = "auth.ServiceAccountToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("clientID", clientID),
+		slog.Int("appID", appID),
+	)
+
+	sa, err := a.saProvider.ServiceAccountByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			log.Warn("service account not found")
+			a.simulatePasswordCheck(clientSecret)
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+
+		log.Error("failed to get service account", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	lockout, err := a.saProvider.ServiceAccountLockoutState(ctx, sa.ID)
+	if err != nil {
+		log.Error("failed to get lockout state", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if lockout.Locked(time.Now()) {
+		log.Warn("service account is locked out", slog.Time("locked_until", lockout.LockedUntil))
+
+		if a.enumerationProtection {
+			a.simulatePasswordCheck(clientSecret)
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, ErrAccountLocked)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(sa.SecretHash, []byte(clientSecret)); err != nil {
+		log.Warn("invalid client secret")
+
+		if err := a.registerFailedSecret(ctx, sa, lockout); err != nil {
+			log.Error("failed to register failed attempt", sl.Err(err))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	if lockout.FailedAttempts > 0 {
+		if err := a.saProvider.ResetServiceAccountLockoutState(ctx, sa.ID); err != nil {
+			log.Error("failed to reset lockout state", sl.Err(err))
+		}
+	}
+
+	if sa.Disabled {
+		log.Warn("service account is disabled")
+
+		return "", fmt.Errorf("%s: %w", op, ErrUserDisabled)
+	}
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		log.Error("failed to get app", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if app.Disabled || !sa.CanAccess(app) {
+		log.Warn("app is not accessible to the service account")
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+	}
+	if !slices.Contains(app.GrantTypes, models.GrantTypeClientCredentials) {
+		log.Warn("app doesn't allow client credentials")
+
+		return "", fmt.Errorf("%s: %w", op, ErrGrantNotAllowed)
+	}
+
+	principal := models.Principal{
+		Type:   models.PrincipalServiceAccount,
+		UserID: sa.ID,
+		AppID:  app.ID,
+		OrgID:  sa.OrgID,
+	}
+
+	principal.Roles, err = a.saProvider.ServiceAccountRoles(ctx, sa.ID, app.ID)
+	if err != nil {
+		log.Error("failed to get service account roles", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := jwt.NewToken(principal, app, a.tokenTTL)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account token issued", slog.Int64("serviceAccountID", sa.ID))
+
+	return token, nil
+}
+
+// registerFailedSecret counts the failed attempt to get a token of the service account
+// and delays the next one, see registerFailedLogin.
+func (a *Auth) registerFailedSecret(ctx context.Context, sa models.ServiceAccount, state models.LockoutState) error {
+	const op = "auth.registerFailedSecret"
+
+	if a.lockout.MaxAttempts <= 0 {
+		return nil
+	}
+
+	state.UserID = sa.ID
+	state, locked := a.countFailedAttempt(state, time.Now())
+
+	if err := a.saProvider.SaveServiceAccountLockoutState(ctx, state); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if locked {
+		a.log.Warn("service account locked out",
+			slog.Int64("serviceAccountID", sa.ID),
+			slog.Time("locked_until", state.LockedUntil),
+		)
+	}
+
+	return nil
+}
+
+// authorizeServiceAccount finishes Authorize for tokens issued to service accounts.
+func (a *Auth) authorizeServiceAccount(
+	ctx context.Context,
+	principal models.Principal,
+	permission string,
+) (models.Principal, error) {
+	const op = "auth.authorizeServiceAccount"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", principal.UserID),
+		slog.String("permission", permission),
+	)
+
+	sa, err := a.saProvider.ServiceAccount(ctx, principal.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			log.Warn("token service account not found")
+
+			return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get service account", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if sa.Disabled {
+		log.Warn("token service account is disabled")
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if permission == "" {
+		return principal, nil
+	}
+
+	has, err := a.saProvider.ServiceAccountHasPermission(ctx, sa.ID, principal.AppID, permission)
+	if err != nil {
+		log.Error("failed to check permission", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !has {
+		log.Warn("permission denied", slog.Int("appID", principal.AppID))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	return principal, nil
+}
+
+// serviceAccountKeyPrincipal finishes AuthenticateAPIKey for keys of service accounts.
+func (a *Auth) serviceAccountKeyPrincipal(ctx context.Context, key models.APIKey) (models.Principal, error) {
+	const op = "auth.serviceAccountKeyPrincipal"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("serviceAccountID", key.ServiceAccountID),
+	)
+
+	sa, err := a.saProvider.ServiceAccount(ctx, key.ServiceAccountID)
+	if err != nil {
+		log.Error("failed to get api key service account", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if sa.Disabled {
+		log.Warn("api key service account is disabled")
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	app, err := a.appProvider.App(ctx, key.AppID)
+	if err != nil {
+		log.Error("failed to get api key app", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if app.Disabled || !sa.CanAccess(app) {
+		log.Warn("api key app is not accessible")
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	principal := models.Principal{
+		Type:      models.PrincipalServiceAccount,
+		UserID:    sa.ID,
+		AppID:     app.ID,
+		OrgID:     sa.OrgID,
+		Scopes:    key.Scopes,
+		ExpiresAt: key.ExpiresAt,
+	}
+
+	principal.Roles, err = a.saProvider.ServiceAccountRoles(ctx, sa.ID, app.ID)
+	if err != nil {
+		log.Error("failed to get service account roles", sl.Err(err))
+
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	testClientID     = "test-client"
+	testClientSecret = "test client secret"
+)
+
+// newServiceAccountTestEnv returns the test env with an app allowing client credentials
+// and a service account of the app.
+func newServiceAccountTestEnv(t *testing.T) (testEnv, int) {
+	t.Helper()
+
+	env := newTestEnv(t, testOptions{
+		lockout: config.LockoutConfig{MaxAttempts: 3, Duration: time.Minute},
+	})
+	ctx := context.Background()
+
+	appID, err := env.repo.CreateApp(ctx, models.App{
+		Name:       "machine-app",
+		SecretHash: []byte("machine-app-secret"),
+		GrantTypes: []string{models.GrantTypeClientCredentials},
+	})
+	if err != nil {
+		t.Fatalf("create app: %v", err)
+	}
+
+	secretHash, err := bcrypt.GenerateFromPassword([]byte(testClientSecret), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.repo.CreateServiceAccount(ctx, models.ServiceAccount{
+		Name:       "worker",
+		AppID:      appID,
+		ClientID:   testClientID,
+		SecretHash: secretHash,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("create service account: %v", err)
+	}
+
+	return env, appID
+}
+
+func TestServiceAccountTokenUnknownClient(t *testing.T) {
+	env, appID := newServiceAccountTestEnv(t)
+
+	_, err := env.auth.ServiceAccountToken(context.Background(), "unknown-client", testClientSecret, appID)
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("ServiceAccountToken() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+}
+
+func TestServiceAccountTokenLockout(t *testing.T) {
+	env, appID := newServiceAccountTestEnv(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := env.auth.ServiceAccountToken(ctx, testClientID, "wrong secret", appID); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("ServiceAccountToken() with a wrong secret error = %v, want %v", err, ErrInvalidCredentials)
+		}
+	}
+
+	// the right secret starts counting from scratch
+	if _, err := env.auth.ServiceAccountToken(ctx, testClientID, testClientSecret, appID); err != nil {
+		t.Fatalf("ServiceAccountToken() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := env.auth.ServiceAccountToken(ctx, testClientID, "wrong secret", appID); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("ServiceAccountToken() with a wrong secret error = %v, want %v", err, ErrInvalidCredentials)
+		}
+	}
+
+	if _, err := env.auth.ServiceAccountToken(ctx, testClientID, testClientSecret, appID); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("ServiceAccountToken() of the locked out account error = %v, want %v", err, ErrAccountLocked)
+	}
+}
@@ -0,0 +1,32 @@
+package directory
+
+import (
+	"context"
+	"errors"
+)
+
+// Directory verifies passwords of users kept in an external user directory, e.g. LDAP.
+type Directory interface {
+	// User looks up the entry of the login along with the groups it's a member of.
+	User(ctx context.Context, login string) (User, error)
+	// Authenticate checks the password of the user's entry.
+	Authenticate(ctx context.Context, user User, password string) error
+}
+
+// User is an entry of the directory mapped to the fields of models.User.
+type User struct {
+	// ID identifies the entry for good, it survives renames unlike the DN if the directory has such an attribute.
+	ID       string
+	DN       string
+	Email    string
+	Name     string
+	LastName string
+	Phone    string
+	// Groups lists DNs of the groups the entry is a member of.
+	Groups []string
+}
+
+var (
+	ErrUserNotFound       = errors.New("user not found in directory")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
@@ -0,0 +1,230 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"sso/internal/config"
+	"sso/internal/services/directory"
+)
+
+// ErrAmbiguousLogin is returned when the user filter matches several entries, which is
+// a misconfigured filter rather than a wrong login.
+var ErrAmbiguousLogin = errors.New("login matches several directory entries")
+
+// Directory looks users up and checks their passwords in an LDAP directory. Every call uses
+// a new connection, searches bind as the service account and password checks as the user.
+type Directory struct {
+	cfg          config.LDAPConfig
+	bindPassword string
+	tlsConfig    *tls.Config
+}
+
+func New(cfg config.LDAPConfig) (*Directory, error) {
+	const op = "directory.ldap.New"
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates in %s", op, cfg.CAFile)
+		}
+	}
+
+	if cfg.GroupBaseDN == "" {
+		cfg.GroupBaseDN = cfg.BaseDN
+	}
+
+	return &Directory{
+		cfg:          cfg,
+		bindPassword: os.Getenv(cfg.BindPasswordEnv),
+		tlsConfig:    tlsConfig,
+	}, nil
+}
+
+func (d *Directory) User(ctx context.Context, login string) (directory.User, error) {
+	const op = "directory.ldap.User"
+
+	conn, err := d.connect()
+	if err != nil {
+		return directory.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(d.cfg.BindDN, d.bindPassword); err != nil {
+		return directory.User{}, fmt.Errorf("%s: bind service account: %w", op, err)
+	}
+
+	attrs := d.cfg.Attributes
+
+	res, err := conn.Search(ldap.NewSearchRequest(
+		d.cfg.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2, // one more than needed to tell ambiguous logins
+		d.timeLimit(),
+		false,
+		strings.ReplaceAll(d.cfg.UserFilter, "{login}", ldap.EscapeFilter(login)),
+		nonEmpty(attrs.ID, attrs.Email, attrs.Name, attrs.LastName, attrs.Phone, attrs.MemberOf),
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return directory.User{}, fmt.Errorf("%s: %w", op, ErrAmbiguousLogin)
+		}
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return directory.User{}, fmt.Errorf("%s: %w", op, directory.ErrUserNotFound)
+		}
+
+		return directory.User{}, fmt.Errorf("%s: search user: %w", op, err)
+	}
+
+	switch len(res.Entries) {
+	case 0:
+		return directory.User{}, fmt.Errorf("%s: %w", op, directory.ErrUserNotFound)
+	case 1:
+	default:
+		return directory.User{}, fmt.Errorf("%s: %w", op, ErrAmbiguousLogin)
+	}
+
+	entry := res.Entries[0]
+	user := directory.User{
+		ID:       strings.ToLower(entry.DN),
+		DN:       entry.DN,
+		Email:    attribute(entry, attrs.Email),
+		Name:     attribute(entry, attrs.Name),
+		LastName: attribute(entry, attrs.LastName),
+		Phone:    attribute(entry, attrs.Phone),
+	}
+
+	if attrs.ID != "" {
+		id := entry.GetEqualFoldRawAttributeValue(attrs.ID)
+		if len(id) == 0 {
+			return directory.User{}, fmt.Errorf("%s: entry %s has no %s", op, entry.DN, attrs.ID)
+		}
+		user.ID = idString(id)
+	}
+
+	if attrs.MemberOf != "" {
+		user.Groups = entry.GetEqualFoldAttributeValues(attrs.MemberOf)
+	}
+
+	if d.cfg.GroupFilter != "" {
+		res, err := conn.Search(ldap.NewSearchRequest(
+			d.cfg.GroupBaseDN,
+			ldap.ScopeWholeSubtree,
+			ldap.NeverDerefAliases,
+			0,
+			d.timeLimit(),
+			false,
+			strings.ReplaceAll(d.cfg.GroupFilter, "{dn}", ldap.EscapeFilter(entry.DN)),
+			[]string{"1.1"}, // no attributes, only DNs
+			nil,
+		))
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return directory.User{}, fmt.Errorf("%s: search groups: %w", op, err)
+		}
+
+		if res != nil {
+			for _, group := range res.Entries {
+				if !slices.ContainsFunc(user.Groups, func(dn string) bool { return strings.EqualFold(dn, group.DN) }) {
+					user.Groups = append(user.Groups, group.DN)
+				}
+			}
+		}
+	}
+
+	return user, nil
+}
+
+func (d *Directory) Authenticate(ctx context.Context, user directory.User, password string) error {
+	const op = "directory.ldap.Authenticate"
+
+	// servers take binds without a password for anonymous ones
+	if password == "" {
+		return fmt.Errorf("%s: %w", op, directory.ErrInvalidCredentials)
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(user.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return fmt.Errorf("%s: %w", op, directory.ErrInvalidCredentials)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// connect dials the server and upgrades the connection to TLS if configured.
+func (d *Directory) connect() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(
+		d.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: d.cfg.Timeout}),
+		ldap.DialWithTLSConfig(d.tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+	conn.SetTimeout(d.cfg.Timeout)
+
+	if d.cfg.StartTLS {
+		if err := conn.StartTLS(d.tlsConfig); err != nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("start tls: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// timeLimit is the time limit of searches in seconds the server enforces, zero for none.
+func (d *Directory) timeLimit() int {
+	return int(d.cfg.Timeout.Seconds())
+}
+
+func attribute(entry *ldap.Entry, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return strings.TrimSpace(entry.GetEqualFoldAttributeValue(name))
+}
+
+// idString returns text IDs such as entryUUID as they are and binary ones such as objectGUID in hex.
+func idString(id []byte) string {
+	if utf8.Valid(id) && !strings.ContainsFunc(string(id), func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return string(id)
+	}
+
+	return hex.EncodeToString(id)
+}
+
+func nonEmpty(values ...string) []string {
+	return slices.DeleteFunc(values, func(v string) bool { return v == "" })
+}
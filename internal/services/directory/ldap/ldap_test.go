@@ -0,0 +1,215 @@
+package ldap_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"sso/internal/config"
+	"sso/internal/services/directory"
+	"sso/internal/services/directory/ldap"
+	"sso/internal/services/directory/ldap/ldaptest"
+)
+
+const (
+	serviceDN       = "cn=sso,ou=services,dc=example,dc=com"
+	servicePassword = "service password"
+	aliceDN         = "uid=alice,ou=people,dc=example,dc=com"
+	adminsDN        = "cn=admins,ou=groups,dc=example,dc=com"
+	staffDN         = "cn=staff,ou=groups,dc=example,dc=com"
+)
+
+func newServer(t *testing.T) *ldaptest.Server {
+	t.Helper()
+
+	return ldaptest.NewServer(t,
+		ldaptest.Entry{DN: serviceDN, Password: servicePassword},
+		ldaptest.Entry{
+			DN:       aliceDN,
+			Password: "alice password",
+			Attributes: map[string][]string{
+				"objectClass":     {"person"},
+				"uid":             {"alice"},
+				"mail":            {"alice@example.com"},
+				"givenName":       {"Alice"},
+				"sn":              {"Liddell"},
+				"telephoneNumber": {" +1 555 000 1234 "},
+				"entryUUID":       {"5f0c2a3e-alice"},
+				"memberOf":        {staffDN},
+			},
+		},
+		ldaptest.Entry{
+			DN:       "uid=twin1,ou=people,dc=example,dc=com",
+			Password: "twin password",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"mail":        {"twins@example.com"},
+				"entryUUID":   {"twin1"},
+			},
+		},
+		ldaptest.Entry{
+			DN:       "uid=twin2,ou=people,dc=example,dc=com",
+			Password: "twin password",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"mail":        {"twins@example.com"},
+				"entryUUID":   {"twin2"},
+			},
+		},
+		ldaptest.Entry{
+			DN: adminsDN,
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"member":      {aliceDN},
+			},
+		},
+		ldaptest.Entry{
+			DN: staffDN,
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"member":      {aliceDN},
+			},
+		},
+	)
+}
+
+// newDirectory returns the directory of the server searched with the bind password.
+func newDirectory(t *testing.T, srv *ldaptest.Server, bindPassword string) *ldap.Directory {
+	t.Helper()
+
+	t.Setenv("TEST_LDAP_BIND_PASSWORD", bindPassword)
+
+	dir, err := ldap.New(config.LDAPConfig{
+		URL:             srv.URL(),
+		Timeout:         5 * time.Second,
+		BindDN:          serviceDN,
+		BindPasswordEnv: "TEST_LDAP_BIND_PASSWORD",
+		BaseDN:          "ou=people,dc=example,dc=com",
+		UserFilter:      "(&(objectClass=person)(|(mail={login})(uid={login})))",
+		GroupBaseDN:     "ou=groups,dc=example,dc=com",
+		GroupFilter:     "(&(objectClass=groupOfNames)(member={dn}))",
+		Attributes: config.LDAPAttributesConfig{
+			ID:       "entryUUID",
+			Email:    "mail",
+			Name:     "givenName",
+			LastName: "sn",
+			Phone:    "telephoneNumber",
+			MemberOf: "memberOf",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestUser(t *testing.T) {
+	dir := newDirectory(t, newServer(t), servicePassword)
+
+	for _, login := range []string{"alice", "alice@example.com", "ALICE@example.com"} {
+		t.Run(login, func(t *testing.T) {
+			user, err := dir.User(context.Background(), login)
+			if err != nil {
+				t.Fatalf("User() error = %v", err)
+			}
+
+			// memberOf and the group search both find staff, it's listed once
+			slices.Sort(user.Groups)
+			want := directory.User{
+				ID:       "5f0c2a3e-alice",
+				DN:       aliceDN,
+				Email:    "alice@example.com",
+				Name:     "Alice",
+				LastName: "Liddell",
+				Phone:    "+1 555 000 1234",
+				Groups:   []string{adminsDN, staffDN},
+			}
+			if !reflect.DeepEqual(user, want) {
+				t.Errorf("User() = %+v, want %+v", user, want)
+			}
+		})
+	}
+}
+
+func TestUserErrors(t *testing.T) {
+	dir := newDirectory(t, newServer(t), servicePassword)
+
+	tests := []struct {
+		name  string
+		login string
+		want  error
+	}{
+		{name: "unknown", login: "bob@example.com", want: directory.ErrUserNotFound},
+		{name: "filter injection", login: "*", want: directory.ErrUserNotFound},
+		{name: "entry that isn't a person", login: "admins", want: directory.ErrUserNotFound},
+		{name: "ambiguous", login: "twins@example.com", want: ldap.ErrAmbiguousLogin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dir.User(context.Background(), tt.login); !errors.Is(err, tt.want) {
+				t.Errorf("User() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserWrongBindPassword(t *testing.T) {
+	dir := newDirectory(t, newServer(t), "wrong password")
+
+	_, err := dir.User(context.Background(), "alice@example.com")
+	if err == nil || errors.Is(err, directory.ErrUserNotFound) {
+		t.Errorf("User() error = %v, want a bind error", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	srv := newServer(t)
+	dir := newDirectory(t, srv, servicePassword)
+	ctx := context.Background()
+
+	alice, err := dir.User(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dir.Authenticate(ctx, alice, "alice password"); err != nil {
+		t.Errorf("Authenticate() error = %v", err)
+	}
+	if err := dir.Authenticate(ctx, alice, "wrong password"); !errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Errorf("Authenticate() with wrong password error = %v, want %v", err, directory.ErrInvalidCredentials)
+	}
+
+	binds := srv.Binds(aliceDN)
+	if err := dir.Authenticate(ctx, alice, ""); !errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Errorf("Authenticate() with empty password error = %v, want %v", err, directory.ErrInvalidCredentials)
+	}
+	// an unauthenticated bind would succeed at most servers
+	if got := srv.Binds(aliceDN); got != binds {
+		t.Errorf("Authenticate() with empty password sent %d binds", got-binds)
+	}
+}
+
+func TestDirectoryDown(t *testing.T) {
+	srv := newServer(t)
+	dir := newDirectory(t, srv, servicePassword)
+	ctx := context.Background()
+
+	alice, err := dir.User(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Close()
+
+	if _, err := dir.User(ctx, "alice"); err == nil || errors.Is(err, directory.ErrUserNotFound) {
+		t.Errorf("User() error = %v, want a connection error", err)
+	}
+	if err := dir.Authenticate(ctx, alice, "alice password"); err == nil || errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Errorf("Authenticate() error = %v, want a connection error", err)
+	}
+}
@@ -0,0 +1,349 @@
+// Package ldaptest runs an in-process LDAP server for tests. It serves simple binds and searches
+// of its entries with and, or, not, equality and presence filters, which is all Directory uses.
+package ldaptest
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+// Entry is an entry of the directory. Entries with a password can bind, e.g. users and service accounts.
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server serves its entries over plain LDAP on a local port.
+type Server struct {
+	ln net.Listener
+
+	mu      sync.Mutex
+	entries []Entry
+	binds   map[string]int
+	conns   map[net.Conn]struct{}
+	closed  bool
+}
+
+// Protocol numbers of RFC 4511.
+const (
+	resultSuccess            = 0
+	resultSizeLimitExceeded  = 4
+	resultInvalidCredentials = 49
+	resultUnwillingToPerform = 53
+)
+
+const (
+	opBindRequest   = 0
+	opBindResponse  = 1
+	opUnbindRequest = 2
+	opSearchRequest = 3
+	opSearchEntry   = 4
+	opSearchDone    = 5
+)
+
+const (
+	filterAnd           = 0
+	filterOr            = 1
+	filterNot           = 2
+	filterEqualityMatch = 3
+	filterPresent       = 7
+)
+
+// NewServer starts the server with the entries, it's stopped when the test ends.
+func NewServer(t testing.TB, entries ...Entry) *Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		ln:      ln,
+		entries: entries,
+		binds:   make(map[string]int),
+		conns:   make(map[net.Conn]struct{}),
+	}
+	t.Cleanup(s.Close)
+
+	go s.accept()
+
+	return s
+}
+
+// URL is the ldap:// URL of the server.
+func (s *Server) URL() string {
+	return "ldap://" + s.ln.Addr().String()
+}
+
+// Close stops the server and drops open connections, e.g. to take the directory down.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+
+	s.ln.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// SetAttribute replaces the values of the attribute of the entry, no values remove it.
+func (s *Server) SetAttribute(dn string, name string, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		if strings.EqualFold(e.DN, dn) {
+			if len(values) == 0 {
+				delete(e.Attributes, name)
+			} else {
+				e.Attributes[name] = values
+			}
+		}
+	}
+}
+
+// Binds returns the number of binds as the DN, successful or not.
+func (s *Server) Binds(dn string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.binds[strings.ToLower(dn)]
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		id, _ := packet.Children[0].Value.(int64)
+		req := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch req.Tag {
+		case opBindRequest:
+			responses = []*ber.Packet{s.bind(id, req)}
+		case opSearchRequest:
+			responses = s.search(id, req)
+		case opUnbindRequest:
+			return
+		default:
+			// operations the server doesn't know, e.g. StartTLS, fail like a broken connection
+			return
+		}
+
+		for _, resp := range responses {
+			if _, err := conn.Write(resp.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) bind(id int64, req *ber.Packet) *ber.Packet {
+	dn := str(req.Children[1])
+	password := string(req.Children[2].Data.Bytes())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.binds[strings.ToLower(dn)]++
+
+	for _, e := range s.entries {
+		if strings.EqualFold(e.DN, dn) && e.Password != "" && e.Password == password {
+			return result(id, opBindResponse, resultSuccess)
+		}
+	}
+
+	return result(id, opBindResponse, resultInvalidCredentials)
+}
+
+func (s *Server) search(id int64, req *ber.Packet) []*ber.Packet {
+	base := str(req.Children[0])
+	sizeLimit, _ := req.Children[3].Value.(int64)
+	filter := req.Children[6]
+
+	var selected []string
+	for _, attr := range req.Children[7].Children {
+		selected = append(selected, str(attr))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var responses []*ber.Packet
+	code := int64(resultSuccess)
+	for _, e := range s.entries {
+		if !under(e.DN, base) {
+			continue
+		}
+
+		ok, err := e.match(filter)
+		if err != nil {
+			return []*ber.Packet{result(id, opSearchDone, resultUnwillingToPerform)}
+		}
+		if !ok {
+			continue
+		}
+
+		if sizeLimit > 0 && int64(len(responses)) == sizeLimit {
+			code = resultSizeLimitExceeded
+
+			break
+		}
+		responses = append(responses, e.packet(id, selected))
+	}
+
+	return append(responses, result(id, opSearchDone, code))
+}
+
+// under tells whether the DN is the base DN or below it.
+func under(dn string, base string) bool {
+	dn, base = strings.ToLower(dn), strings.ToLower(base)
+
+	return base == "" || dn == base || strings.HasSuffix(dn, ","+base)
+}
+
+var errUnsupportedFilter = errors.New("unsupported filter")
+
+func (e Entry) match(filter *ber.Packet) (bool, error) {
+	switch filter.Tag {
+	case filterAnd, filterOr:
+		want := filter.Tag == filterOr
+		for _, f := range filter.Children {
+			ok, err := e.match(f)
+			if err != nil {
+				return false, err
+			}
+			if ok == want {
+				return want, nil
+			}
+		}
+
+		return !want, nil
+	case filterNot:
+		ok, err := e.match(filter.Children[0])
+
+		return !ok, err
+	case filterEqualityMatch:
+		value := str(filter.Children[1])
+
+		return slices.ContainsFunc(e.values(str(filter.Children[0])), func(v string) bool {
+			return strings.EqualFold(v, value)
+		}), nil
+	case filterPresent:
+		return len(e.values(string(filter.Data.Bytes()))) > 0, nil
+	}
+
+	return false, errUnsupportedFilter
+}
+
+func (e Entry) values(name string) []string {
+	for attr, values := range e.Attributes {
+		if strings.EqualFold(attr, name) {
+			return values
+		}
+	}
+
+	return nil
+}
+
+func (e Entry) packet(id int64, selected []string) *ber.Packet {
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range e.Attributes {
+		if !isSelected(name, selected) {
+			continue
+		}
+
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "values")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		attr.AppendChild(set)
+		attrs.AppendChild(attr)
+	}
+
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, opSearchEntry, nil, "search entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, "dn"))
+	entry.AppendChild(attrs)
+
+	return message(id, entry)
+}
+
+// isSelected tells whether the attribute was requested, "1.1" requests none.
+func isSelected(name string, selected []string) bool {
+	if len(selected) == 0 || slices.Contains(selected, "*") {
+		return true
+	}
+
+	return slices.ContainsFunc(selected, func(s string) bool { return strings.EqualFold(s, name) })
+}
+
+func result(id int64, tag ber.Tag, code int64) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "result")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "code"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matched dn"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnostic message"))
+
+	return message(id, res)
+}
+
+func message(id int64, op *ber.Packet) *ber.Packet {
+	msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "message")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "message id"))
+	msg.AppendChild(op)
+
+	return msg
+}
+
+func str(p *ber.Packet) string {
+	if s, ok := p.Value.(string); ok {
+		return s
+	}
+
+	return string(p.Data.Bytes())
+}
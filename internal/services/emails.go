@@ -40,3 +40,74 @@ func (s *EmailService) SendVerificationEmail(input VerificationEmailInput) error
 
 	return s.sender.Send(sendInput)
 }
+
+type AccountLockedEmailInput struct {
+	Email       string
+	Name        string
+	LockedUntil string
+}
+
+func (s *EmailService) SendAccountLockedEmail(input AccountLockedEmailInput) error {
+	sendInput := email.SendEmailInput{Subject: s.config.Subjects.AccountLocked, To: input.Email}
+
+	if err := sendInput.GenerateBodyFromHTML(s.config.Templates.AccountLocked, input); err != nil {
+		return err
+	}
+
+	return s.sender.Send(sendInput)
+}
+
+type InvitationEmailInput struct {
+	Email        string
+	Inviter      string
+	Organization string
+	Link         string
+	ExpiresAt    string
+}
+
+func (s *EmailService) SendInvitationEmail(input InvitationEmailInput) error {
+	sendInput := email.SendEmailInput{Subject: s.config.Subjects.Invitation, To: input.Email}
+
+	if err := sendInput.GenerateBodyFromHTML(s.config.Templates.Invitation, input); err != nil {
+		return err
+	}
+
+	return s.sender.Send(sendInput)
+}
+
+type EmailChangedEmailInput struct {
+	Email     string
+	Name      string
+	NewEmail  string
+	Link      string
+	ExpiresAt string
+}
+
+func (s *EmailService) SendEmailChangedEmail(input EmailChangedEmailInput) error {
+	sendInput := email.SendEmailInput{Subject: s.config.Subjects.EmailChanged, To: input.Email}
+
+	if err := sendInput.GenerateBodyFromHTML(s.config.Templates.EmailChanged, input); err != nil {
+		return err
+	}
+
+	return s.sender.Send(sendInput)
+}
+
+type AccountStatusEmailInput struct {
+	Email  string
+	Name   string
+	Status string
+	Reason string
+	// DeleteAfter is when the account is deleted, only set for the deleted status.
+	DeleteAfter string
+}
+
+func (s *EmailService) SendAccountStatusEmail(input AccountStatusEmailInput) error {
+	sendInput := email.SendEmailInput{Subject: s.config.Subjects.AccountStatus, To: input.Email}
+
+	if err := sendInput.GenerateBodyFromHTML(s.config.Templates.AccountStatus, input); err != nil {
+		return err
+	}
+
+	return s.sender.Send(sendInput)
+}
@@ -0,0 +1,350 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/randtoken"
+	"sso/internal/repository"
+	"sso/internal/services"
+	"strings"
+	"time"
+)
+
+// Invitations sends invitations on behalf of the caller. Admins of the caller's app can invite anyone,
+// owners and admins of an organization can invite people to the organization.
+type Invitations struct {
+	log          *slog.Logger
+	repo         Repository
+	permProvider PermissionProvider
+	emailService *services.EmailService
+	cfg          config.InvitationsConfig
+}
+
+type Repository interface {
+	CreateInvitation(ctx context.Context, inv models.Invitation) (int64, error)
+	Invitation(ctx context.Context, id int64) (models.Invitation, error)
+	PendingInvitations(ctx context.Context, orgID int64, now time.Time) ([]models.Invitation, error)
+	RevokeInvitation(ctx context.Context, id int64, revokedAt time.Time) error
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	Role(ctx context.Context, appID int, name string) (models.Role, error)
+	Organization(ctx context.Context, id int64) (models.Organization, error)
+	OrgMember(ctx context.Context, orgID int64, userID int64) (models.OrgMember, error)
+}
+
+type PermissionProvider interface {
+	HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error)
+}
+
+var (
+	ErrInvalidEmail       = errors.New("invalid email")
+	ErrInvalidRole        = errors.New("invalid organization role")
+	ErrRoleNotFound       = errors.New("role not found")
+	ErrOrgNotFound        = errors.New("organization not found")
+	ErrEmailDomain        = errors.New("email domain is not allowed in the organization")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrPermissionDenied   = errors.New("permission denied")
+)
+
+// New returns a new instance of the Invitations service.
+func New(
+	log *slog.Logger,
+	repo Repository,
+	permProvider PermissionProvider,
+	emailService *services.EmailService,
+	cfg config.InvitationsConfig,
+) *Invitations {
+	return &Invitations{
+		log:          log,
+		repo:         repo,
+		permProvider: permProvider,
+		emailService: emailService,
+		cfg:          cfg,
+	}
+}
+
+// CreateInvitation saves the invitation to the caller's app and emails its link.
+// Only admins can preset an app role, organization invitations default to the member role.
+func (i *Invitations) CreateInvitation(
+	ctx context.Context,
+	caller models.Principal,
+	inv models.Invitation,
+) (models.Invitation, error) {
+	const op = "invitation.CreateInvitation"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("inviterID", caller.UserID),
+		slog.Int64("orgID", inv.OrgID),
+	)
+
+	log.Info("creating invitation")
+
+	inv.Email = strings.TrimSpace(inv.Email)
+	if _, domain, ok := strings.Cut(inv.Email, "@"); !ok || domain == "" {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrInvalidEmail)
+	}
+
+	inv.InviterID = caller.UserID
+	inv.AppID = caller.AppID
+
+	isAdmin, err := i.isAdmin(ctx, caller)
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if inv.Role != "" {
+		if !isAdmin {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+		}
+
+		if _, err := i.repo.Role(ctx, inv.AppID, inv.Role); err != nil {
+			if errors.Is(err, repository.ErrRoleNotFound) {
+				return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrRoleNotFound)
+			}
+
+			log.Error("failed to get role", sl.Err(err))
+
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	var org models.Organization
+	if inv.OrgID != 0 {
+		org, err = i.checkOrgInvitation(ctx, caller, isAdmin, &inv)
+		if err != nil {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+		}
+	} else {
+		if !isAdmin {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+		}
+		inv.OrgRole = ""
+	}
+
+	token, err := randtoken.Generate()
+	if err != nil {
+		log.Error("failed to generate invitation token", sl.Err(err))
+
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	inv.TokenHash = randtoken.Hash(token)
+	inv.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	inv.ExpiresAt = inv.CreatedAt.Add(i.cfg.TTL)
+
+	inv.ID, err = i.repo.CreateInvitation(ctx, inv)
+	if err != nil {
+		log.Error("failed to save invitation", sl.Err(err))
+
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := i.sendInvitation(ctx, inv, org, token); err != nil {
+		log.Error("failed to send invitation email", sl.Err(err))
+
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation sent", slog.Int64("invitationID", inv.ID))
+
+	return inv, nil
+}
+
+// PendingInvitations returns invitations to the organization that can still be accepted.
+// Zero orgID lists all pending invitations and is allowed to admins only.
+func (i *Invitations) PendingInvitations(
+	ctx context.Context,
+	caller models.Principal,
+	orgID int64,
+) ([]models.Invitation, error) {
+	const op = "invitation.PendingInvitations"
+
+	if err := i.checkManager(ctx, caller, orgID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitations, err := i.repo.PendingInvitations(ctx, orgID, time.Now())
+	if err != nil {
+		i.log.Error("failed to list invitations", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation makes a pending invitation unusable.
+func (i *Invitations) RevokeInvitation(ctx context.Context, caller models.Principal, id int64) error {
+	const op = "invitation.RevokeInvitation"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("invitationID", id),
+	)
+
+	inv, err := i.repo.Invitation(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+		}
+
+		log.Error("failed to get invitation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if inv.InviterID != caller.UserID {
+		if err := i.checkManager(ctx, caller, inv.OrgID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := i.repo.RevokeInvitation(ctx, id, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+		}
+
+		log.Error("failed to revoke invitation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation revoked")
+
+	return nil
+}
+
+// checkOrgInvitation checks that the caller may invite to the organization with the invitation's role
+// and that the invited email is allowed in it. It defaults the role to member.
+func (i *Invitations) checkOrgInvitation(
+	ctx context.Context,
+	caller models.Principal,
+	isAdmin bool,
+	inv *models.Invitation,
+) (models.Organization, error) {
+	if inv.OrgRole == "" {
+		inv.OrgRole = models.OrgRoleMember
+	}
+	if !slices.Contains(models.OrgRoles, inv.OrgRole) {
+		return models.Organization{}, ErrInvalidRole
+	}
+
+	org, err := i.organization(ctx, inv.OrgID)
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	if !isAdmin {
+		role, err := i.memberRole(ctx, caller, inv.OrgID)
+		if err != nil {
+			return models.Organization{}, err
+		}
+
+		// only owners can make new owners, as in changing members
+		if role == models.OrgRoleMember || (inv.OrgRole == models.OrgRoleOwner && role != models.OrgRoleOwner) {
+			return models.Organization{}, ErrPermissionDenied
+		}
+	}
+
+	if len(org.AllowedEmailDomains) > 0 {
+		_, domain, _ := strings.Cut(inv.Email, "@")
+		if !slices.Contains(org.AllowedEmailDomains, strings.ToLower(domain)) {
+			return models.Organization{}, ErrEmailDomain
+		}
+	}
+
+	return org, nil
+}
+
+// checkManager checks that the caller manages invitations to the organization,
+// or all invitations if orgID is zero.
+func (i *Invitations) checkManager(ctx context.Context, caller models.Principal, orgID int64) error {
+	isAdmin, err := i.isAdmin(ctx, caller)
+	if err != nil {
+		return err
+	}
+	if isAdmin {
+		return nil
+	}
+	if orgID == 0 {
+		return ErrPermissionDenied
+	}
+
+	role, err := i.memberRole(ctx, caller, orgID)
+	if err != nil {
+		return err
+	}
+	if role == models.OrgRoleMember {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+func (i *Invitations) sendInvitation(ctx context.Context, inv models.Invitation, org models.Organization, token string) error {
+	inviter, err := i.repo.UserByID(ctx, inv.InviterID)
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(inviter.Name + " " + inviter.LastName)
+	if name == "" {
+		name = inviter.Email
+	}
+
+	return i.emailService.SendInvitationEmail(services.InvitationEmailInput{
+		Email:        inv.Email,
+		Inviter:      name,
+		Organization: org.Name,
+		Link:         i.cfg.URL + "?token=" + url.QueryEscape(token),
+		ExpiresAt:    inv.ExpiresAt.Format(time.RFC1123),
+	})
+}
+
+func (i *Invitations) memberRole(ctx context.Context, caller models.Principal, orgID int64) (string, error) {
+	member, err := i.repo.OrgMember(ctx, orgID, caller.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotOrgMember) {
+			return "", ErrPermissionDenied
+		}
+
+		i.log.Error("failed to get organization member", sl.Err(err))
+
+		return "", err
+	}
+
+	return member.Role, nil
+}
+
+func (i *Invitations) isAdmin(ctx context.Context, caller models.Principal) (bool, error) {
+	isAdmin, err := i.permProvider.HasPermission(ctx, caller.UserID, caller.AppID, models.PermissionAdmin)
+	if err != nil {
+		i.log.Error("failed to check admin permission", sl.Err(err))
+
+		return false, err
+	}
+
+	return isAdmin, nil
+}
+
+func (i *Invitations) organization(ctx context.Context, orgID int64) (models.Organization, error) {
+	org, err := i.repo.Organization(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrgNotFound) {
+			return models.Organization{}, ErrOrgNotFound
+		}
+
+		i.log.Error("failed to get organization", sl.Err(err))
+
+		return models.Organization{}, err
+	}
+
+	return org, nil
+}
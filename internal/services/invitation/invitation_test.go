@@ -0,0 +1,198 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sso/internal/config"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/handlers/slogdiscard"
+	"sso/internal/repository/sqlite"
+	"sso/internal/services"
+	"sso/internal/services/email"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+const migrationsPath = "../../../migrations"
+
+type testEnv struct {
+	invitations *Invitations
+	repo        *sqlite.Repository
+	sent        *[]email.SendEmailInput
+	appID       int
+}
+
+type recordingSender struct {
+	sent *[]email.SendEmailInput
+}
+
+func (s recordingSender) Send(input email.SendEmailInput) error {
+	*s.sent = append(*s.sent, input)
+
+	return nil
+}
+
+// newTestEnv returns Invitations over a migrated storage of its own with an app to invite to.
+func newTestEnv(t *testing.T) testEnv {
+	t.Helper()
+
+	storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://"+migrationsPath, "sqlite3://"+storagePath)
+	if err != nil {
+		t.Fatalf("open migrations: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	m.Close()
+
+	repo, err := sqlite.New(storagePath)
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	t.Cleanup(func() { repo.Stop() })
+
+	log := slogdiscard.NewDiscardLogger()
+	sent := &[]email.SendEmailInput{}
+	emails, _ := services.NewEmailService(log, recordingSender{sent: sent}, config.EmailConfig{
+		Templates: config.EmailTemplate{Invitation: "../../../templates/invitation_email.html"},
+	})
+
+	i := New(log, repo, repo, emails, config.InvitationsConfig{TTL: time.Hour, URL: "https://app.example.com/invite"})
+
+	appID, err := repo.CreateApp(context.Background(), models.App{
+		Name:       "test-app",
+		SecretHash: []byte("test-app-secret"),
+	})
+	if err != nil {
+		t.Fatalf("create app: %v", err)
+	}
+
+	return testEnv{invitations: i, repo: repo, sent: sent, appID: appID}
+}
+
+// caller saves a user and returns their principal in the app.
+func (e testEnv) caller(t *testing.T, email string) models.Principal {
+	t.Helper()
+
+	id, err := e.repo.SaveUser(context.Background(), "", "", "Test", "User", email, []byte("hash"), "")
+	if err != nil {
+		t.Fatalf("save user %s: %v", email, err)
+	}
+
+	return models.Principal{Type: models.PrincipalUser, UserID: id, Email: email, AppID: e.appID}
+}
+
+func TestCreateInvitation(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	admin := env.caller(t, "admin@example.com")
+	role, err := env.repo.Role(ctx, env.appID, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.AssignRole(ctx, admin.UserID, role.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.repo.CreateRole(ctx, env.appID, "editor"); err != nil {
+		t.Fatal(err)
+	}
+
+	owner := env.caller(t, "owner@example.com")
+	orgID, err := env.repo.CreateOrganization(ctx, models.Organization{
+		Name:                "acme",
+		AllowedEmailDomains: []string{"acme.com"},
+		CreatedAt:           time.Now(),
+	}, owner.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member := env.caller(t, "member@acme.com")
+	if err := env.repo.SetOrgMember(ctx, orgID, member.UserID, models.OrgRoleMember); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		caller models.Principal
+		inv    models.Invitation
+		want   error
+	}{
+		{name: "admin with app role", caller: admin, inv: models.Invitation{Email: "a@example.com", Role: "editor"}},
+		{name: "admin with missing role", caller: admin, inv: models.Invitation{Email: "b@example.com", Role: "missing"}, want: ErrRoleNotFound},
+		{name: "owner to org", caller: owner, inv: models.Invitation{Email: "c@acme.com", OrgID: orgID, OrgRole: models.OrgRoleOwner}},
+		{name: "owner to app", caller: owner, inv: models.Invitation{Email: "d@acme.com"}, want: ErrPermissionDenied},
+		{name: "owner with app role", caller: owner, inv: models.Invitation{Email: "e@acme.com", OrgID: orgID, Role: "editor"}, want: ErrPermissionDenied},
+		{name: "owner outside allowed domains", caller: owner, inv: models.Invitation{Email: "f@example.com", OrgID: orgID}, want: ErrEmailDomain},
+		{name: "member to org", caller: member, inv: models.Invitation{Email: "g@acme.com", OrgID: orgID}, want: ErrPermissionDenied},
+		{name: "invalid email", caller: admin, inv: models.Invitation{Email: "nobody"}, want: ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*env.sent = nil
+
+			inv, err := env.invitations.CreateInvitation(ctx, tt.caller, tt.inv)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CreateInvitation() error = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if len(*env.sent) != 0 {
+					t.Errorf("CreateInvitation() sent %d emails, want none", len(*env.sent))
+				}
+
+				return
+			}
+
+			if inv.InviterID != tt.caller.UserID || inv.AppID != env.appID {
+				t.Errorf("CreateInvitation() = inviter %d app %d, want inviter %d app %d",
+					inv.InviterID, inv.AppID, tt.caller.UserID, env.appID)
+			}
+			if len(*env.sent) != 1 || (*env.sent)[0].To != tt.inv.Email {
+				t.Errorf("CreateInvitation() sent %+v, want one email to %s", *env.sent, tt.inv.Email)
+			}
+		})
+	}
+}
+
+func TestRevokeInvitation(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	owner := env.caller(t, "owner@example.com")
+	orgID, err := env.repo.CreateOrganization(ctx, models.Organization{Name: "acme", CreatedAt: time.Now()}, owner.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member := env.caller(t, "member@example.com")
+	if err := env.repo.SetOrgMember(ctx, orgID, member.UserID, models.OrgRoleMember); err != nil {
+		t.Fatal(err)
+	}
+
+	inv, err := env.invitations.CreateInvitation(ctx, owner, models.Invitation{Email: "invited@example.com", OrgID: orgID})
+	if err != nil {
+		t.Fatalf("CreateInvitation() error = %v", err)
+	}
+
+	if err := env.invitations.RevokeInvitation(ctx, member, inv.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("RevokeInvitation() by a member error = %v, want %v", err, ErrPermissionDenied)
+	}
+	if err := env.invitations.RevokeInvitation(ctx, owner, inv.ID); err != nil {
+		t.Fatalf("RevokeInvitation() error = %v", err)
+	}
+
+	pending, err := env.invitations.PendingInvitations(ctx, owner, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("PendingInvitations() = %+v, want none", pending)
+	}
+}
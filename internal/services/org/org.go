@@ -0,0 +1,455 @@
+package org
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/repository"
+	"strings"
+)
+
+// Org manages organizations on behalf of the caller. Admins of the caller's app
+// act as owners of every organization, others act with their role in the organization.
+type Org struct {
+	log          *slog.Logger
+	repo         Repository
+	permProvider PermissionProvider
+}
+
+type Repository interface {
+	CreateOrganization(ctx context.Context, org models.Organization, ownerID int64) (int64, error)
+	Organization(ctx context.Context, id int64) (models.Organization, error)
+	Organizations(ctx context.Context, userID int64) ([]models.Organization, error)
+	UpdateOrganization(ctx context.Context, org models.Organization) error
+	DeleteOrganization(ctx context.Context, id int64) error
+	OrgMember(ctx context.Context, orgID int64, userID int64) (models.OrgMember, error)
+	OrgMembers(ctx context.Context, orgID int64) ([]models.OrgMember, error)
+	SetOrgMember(ctx context.Context, orgID int64, userID int64, role string) error
+	RemoveOrgMember(ctx context.Context, orgID int64, userID int64) error
+}
+
+type PermissionProvider interface {
+	HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error)
+}
+
+var (
+	ErrOrgNotFound      = errors.New("organization not found")
+	ErrOrgExists        = errors.New("organization with this name already exists")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrNotMember        = errors.New("user is not a member of the organization")
+	ErrInvalidRole      = errors.New("invalid organization role")
+	ErrInvalidDomain    = errors.New("invalid email domain")
+	ErrLastOwner        = errors.New("organization must have an owner")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// New returns a new instance of the Org service.
+func New(
+	log *slog.Logger,
+	repo Repository,
+	permProvider PermissionProvider,
+) *Org {
+	return &Org{
+		log:          log,
+		repo:         repo,
+		permProvider: permProvider,
+	}
+}
+
+// CreateOrganization creates the organization owned by the user. Only admins can create organizations.
+func (o *Org) CreateOrganization(
+	ctx context.Context,
+	caller models.Principal,
+	org models.Organization,
+	ownerID int64,
+) (models.Organization, error) {
+	const op = "org.CreateOrganization"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.String("name", org.Name),
+		slog.Int64("ownerID", ownerID),
+	)
+
+	log.Info("creating organization")
+
+	isAdmin, err := o.isAdmin(ctx, caller)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !isAdmin {
+		log.Warn("caller is not an admin", slog.Int64("callerID", caller.UserID))
+
+		return models.Organization{}, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	org.AllowedEmailDomains, err = normalizeDomains(org.AllowedEmailDomains)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	org.ID, err = o.repo.CreateOrganization(ctx, org, ownerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrgExists) {
+			log.Warn("organization already exists", sl.Err(err))
+
+			return models.Organization{}, fmt.Errorf("%s: %w", op, ErrOrgExists)
+		}
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Warn("owner not found", sl.Err(err))
+
+			return models.Organization{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to create organization", sl.Err(err))
+
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("organization created", slog.Int64("orgID", org.ID))
+
+	created, err := o.organization(ctx, org.ID)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
+
+// Organizations returns all organizations to admins and organizations the caller is a member of to others.
+func (o *Org) Organizations(ctx context.Context, caller models.Principal) ([]models.Organization, error) {
+	const op = "org.Organizations"
+
+	isAdmin, err := o.isAdmin(ctx, caller)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID := caller.UserID
+	if isAdmin {
+		userID = 0
+	}
+
+	orgs, err := o.repo.Organizations(ctx, userID)
+	if err != nil {
+		o.log.Error("failed to list organizations", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orgs, nil
+}
+
+// Organization returns the organization if the caller is its member.
+func (o *Org) Organization(ctx context.Context, caller models.Principal, orgID int64) (models.Organization, error) {
+	const op = "org.Organization"
+
+	if err := o.authorize(ctx, caller, orgID, models.OrgRoles...); err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	org, err := o.organization(ctx, orgID)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, nil
+}
+
+// UpdateOrganization replaces name and settings of the organization.
+func (o *Org) UpdateOrganization(ctx context.Context, caller models.Principal, upd models.Organization) (models.Organization, error) {
+	const op = "org.UpdateOrganization"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("orgID", upd.ID),
+	)
+
+	log.Info("updating organization")
+
+	if err := o.authorize(ctx, caller, upd.ID, models.OrgRoleOwner, models.OrgRoleAdmin); err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	org, err := o.organization(ctx, upd.ID)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	org.AllowedEmailDomains, err = normalizeDomains(upd.AllowedEmailDomains)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+	org.Name = upd.Name
+	org.RequireMFA = upd.RequireMFA
+
+	if err := o.repo.UpdateOrganization(ctx, org); err != nil {
+		if errors.Is(err, repository.ErrOrgExists) {
+			log.Warn("organization name is taken", sl.Err(err))
+
+			return models.Organization{}, fmt.Errorf("%s: %w", op, ErrOrgExists)
+		}
+		if errors.Is(err, repository.ErrOrgNotFound) {
+			return models.Organization{}, fmt.Errorf("%s: %w", op, ErrOrgNotFound)
+		}
+
+		log.Error("failed to update organization", sl.Err(err))
+
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("organization updated")
+
+	return org, nil
+}
+
+// DeleteOrganization deletes the organization along with its apps.
+func (o *Org) DeleteOrganization(ctx context.Context, caller models.Principal, orgID int64) error {
+	const op = "org.DeleteOrganization"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("orgID", orgID),
+	)
+
+	if err := o.authorize(ctx, caller, orgID, models.OrgRoleOwner); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.repo.DeleteOrganization(ctx, orgID); err != nil {
+		if errors.Is(err, repository.ErrOrgNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrOrgNotFound)
+		}
+
+		log.Error("failed to delete organization", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("organization deleted")
+
+	return nil
+}
+
+func (o *Org) Members(ctx context.Context, caller models.Principal, orgID int64) ([]models.OrgMember, error) {
+	const op = "org.Members"
+
+	if err := o.authorize(ctx, caller, orgID, models.OrgRoleOwner, models.OrgRoleAdmin); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := o.organization(ctx, orgID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := o.repo.OrgMembers(ctx, orgID)
+	if err != nil {
+		o.log.Error("failed to list organization members", slog.String("op", op), sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SetMember adds the user to the organization or changes their role.
+// Only owners can appoint or demote owners, and the last owner can't be demoted.
+func (o *Org) SetMember(ctx context.Context, caller models.Principal, orgID int64, userID int64, role string) error {
+	const op = "org.SetMember"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("orgID", orgID),
+		slog.Int64("userID", userID),
+		slog.String("role", role),
+	)
+
+	log.Info("setting organization member")
+
+	if !slices.Contains(models.OrgRoles, role) {
+		return fmt.Errorf("%s: %w", op, ErrInvalidRole)
+	}
+
+	if err := o.changeMember(ctx, caller, orgID, userID, role == models.OrgRoleOwner); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.repo.SetOrgMember(ctx, orgID, userID, role); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to set organization member", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("organization member set")
+
+	return nil
+}
+
+// RemoveMember removes the user from the organization. Only owners can remove owners,
+// and the last owner can't be removed.
+func (o *Org) RemoveMember(ctx context.Context, caller models.Principal, orgID int64, userID int64) error {
+	const op = "org.RemoveMember"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("orgID", orgID),
+		slog.Int64("userID", userID),
+	)
+
+	if err := o.changeMember(ctx, caller, orgID, userID, false); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.repo.RemoveOrgMember(ctx, orgID, userID); err != nil {
+		if errors.Is(err, repository.ErrNotOrgMember) {
+			return fmt.Errorf("%s: %w", op, ErrNotMember)
+		}
+
+		log.Error("failed to remove organization member", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("organization member removed")
+
+	return nil
+}
+
+// changeMember checks that the caller may change membership of the user in the organization,
+// where toOwner tells if the user is going to be an owner afterwards.
+func (o *Org) changeMember(ctx context.Context, caller models.Principal, orgID int64, userID int64, toOwner bool) error {
+	role, err := o.callerRole(ctx, caller, orgID)
+	if err != nil {
+		return err
+	}
+	if role != models.OrgRoleOwner && role != models.OrgRoleAdmin {
+		return ErrPermissionDenied
+	}
+
+	if _, err := o.organization(ctx, orgID); err != nil {
+		return err
+	}
+
+	members, err := o.repo.OrgMembers(ctx, orgID)
+	if err != nil {
+		o.log.Error("failed to list organization members", sl.Err(err))
+
+		return err
+	}
+
+	owners := 0
+	isOwner := false
+	for _, member := range members {
+		if member.Role == models.OrgRoleOwner {
+			owners++
+			isOwner = isOwner || member.UserID == userID
+		}
+	}
+
+	if (toOwner || isOwner) && role != models.OrgRoleOwner {
+		return ErrPermissionDenied
+	}
+	if isOwner && !toOwner && owners == 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+// authorize checks that the caller has one of the roles in the organization.
+func (o *Org) authorize(ctx context.Context, caller models.Principal, orgID int64, roles ...string) error {
+	role, err := o.callerRole(ctx, caller, orgID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(roles, role) {
+		o.log.Warn(
+			"organization role is not allowed",
+			slog.Int64("callerID", caller.UserID),
+			slog.Int64("orgID", orgID),
+			slog.String("role", role),
+		)
+
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// callerRole returns the role of the caller in the organization, admins are owners of every organization.
+func (o *Org) callerRole(ctx context.Context, caller models.Principal, orgID int64) (string, error) {
+	isAdmin, err := o.isAdmin(ctx, caller)
+	if err != nil {
+		return "", err
+	}
+	if isAdmin {
+		return models.OrgRoleOwner, nil
+	}
+
+	member, err := o.repo.OrgMember(ctx, orgID, caller.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotOrgMember) {
+			return "", ErrPermissionDenied
+		}
+
+		o.log.Error("failed to get organization member", sl.Err(err))
+
+		return "", err
+	}
+
+	return member.Role, nil
+}
+
+func (o *Org) isAdmin(ctx context.Context, caller models.Principal) (bool, error) {
+	isAdmin, err := o.permProvider.HasPermission(ctx, caller.UserID, caller.AppID, models.PermissionAdmin)
+	if err != nil {
+		o.log.Error("failed to check admin permission", sl.Err(err))
+
+		return false, err
+	}
+
+	return isAdmin, nil
+}
+
+func (o *Org) organization(ctx context.Context, orgID int64) (models.Organization, error) {
+	org, err := o.repo.Organization(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrgNotFound) {
+			return models.Organization{}, ErrOrgNotFound
+		}
+
+		o.log.Error("failed to get organization", sl.Err(err))
+
+		return models.Organization{}, err
+	}
+
+	return org, nil
+}
+
+// normalizeDomains lowercases email domains and drops duplicates.
+func normalizeDomains(domains []string) ([]string, error) {
+	var normalized []string
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain == "" || strings.ContainsAny(domain, "@ ") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidDomain, domain)
+		}
+
+		if !slices.Contains(normalized, domain) {
+			normalized = append(normalized, domain)
+		}
+	}
+
+	return normalized, nil
+}
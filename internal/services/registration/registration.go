@@ -0,0 +1,327 @@
+package registration
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sso/internal/domain/models"
+	"sso/internal/lib/appsecret"
+	"sso/internal/lib/logger/sl"
+	"sso/internal/lib/randtoken"
+	"sso/internal/repository"
+	"strings"
+	"time"
+)
+
+// maxContacts bounds the number of contacts of a client.
+const maxContacts = 10
+
+// GrantTypes lists grant types clients can register for. The password grant hands user passwords
+// to the client, so only apps created by admins can use it.
+var GrantTypes = []string{
+	models.GrantTypeAuthorizationCode,
+	models.GrantTypeRefreshToken,
+	models.GrantTypeClientCredentials,
+}
+
+// Registration lets partners register OAuth clients themselves, as in RFC 7591, and manage
+// their registrations, as in RFC 7592. Clients are registered with initial access tokens admins
+// issue, every registered client gets a registration access token to manage it.
+type Registration struct {
+	log  *slog.Logger
+	repo Repository
+}
+
+type Repository interface {
+	InitialAccessToken(ctx context.Context, tokenHash []byte) (models.InitialAccessToken, error)
+	RegisterApp(ctx context.Context, app models.App, tokenID int64, now time.Time) (int, error)
+	App(ctx context.Context, id int) (models.App, error)
+	UpdateApp(ctx context.Context, app models.App) error
+	DeleteApp(ctx context.Context, id int) error
+}
+
+var (
+	ErrInvalidToken          = errors.New("invalid token")
+	ErrInvalidClientMetadata = errors.New("invalid client metadata")
+	ErrInvalidRedirectURI    = errors.New("invalid redirect uri")
+	ErrClientExists          = errors.New("client with this name already exists")
+)
+
+// New returns a new instance of the Registration service.
+func New(log *slog.Logger, repo Repository) *Registration {
+	return &Registration{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// RegisterClient creates an app with the client metadata: name, redirect URIs, grant types,
+// logo URI and contacts. It returns the app with its plaintext secret and registration access token,
+// only their hashes are stored. Every initial access token registers one client.
+func (r *Registration) RegisterClient(
+	ctx context.Context,
+	initialToken string,
+	meta models.App,
+) (models.App, string, string, error) {
+	const op = "registration.RegisterClient"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("name", meta.Name),
+	)
+
+	now := time.Now()
+
+	token, err := r.repo.InitialAccessToken(ctx, randtoken.Hash(initialToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrInitialAccessTokenNotFound) {
+			log.Warn("initial access token not found")
+
+			return models.App{}, "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get initial access token", sl.Err(err))
+
+		return models.App{}, "", "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !token.Usable(now) {
+		log.Warn("initial access token is used or expired", slog.Int64("tokenID", token.ID))
+
+		return models.App{}, "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	meta, err = normalizeMetadata(meta)
+	if err != nil {
+		log.Info("invalid client metadata", sl.Err(err))
+
+		return models.App{}, "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, secretHash, err := appsecret.Generate()
+	if err != nil {
+		log.Error("failed to generate client secret", sl.Err(err))
+
+		return models.App{}, "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	registrationToken, err := randtoken.Generate()
+	if err != nil {
+		log.Error("failed to generate registration access token", sl.Err(err))
+
+		return models.App{}, "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app := models.App{
+		Name:                  meta.Name,
+		SecretHash:            secretHash,
+		RedirectURIs:          meta.RedirectURIs,
+		GrantTypes:            meta.GrantTypes,
+		LogoURI:               meta.LogoURI,
+		Contacts:              meta.Contacts,
+		RegistrationTokenHash: randtoken.Hash(registrationToken),
+		RegisteredAt:          now,
+	}
+
+	app.ID, err = r.repo.RegisterApp(ctx, app, token.ID, now)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppExists) {
+			return models.App{}, "", "", fmt.Errorf("%s: %w", op, ErrClientExists)
+		}
+		// the token was used by a concurrent registration
+		if errors.Is(err, repository.ErrInitialAccessTokenNotFound) {
+			return models.App{}, "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to register client", sl.Err(err))
+
+		return models.App{}, "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("client registered", slog.Int("appID", app.ID), slog.Int64("tokenID", token.ID))
+
+	return app, secret, registrationToken, nil
+}
+
+// Client returns the registration of the client the registration access token manages.
+func (r *Registration) Client(ctx context.Context, clientID int, registrationToken string) (models.App, error) {
+	const op = "registration.Client"
+
+	app, err := r.client(ctx, clientID, registrationToken)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// UpdateClient replaces the metadata of the client the registration access token manages.
+// The secret and the registration access token are kept.
+func (r *Registration) UpdateClient(
+	ctx context.Context,
+	clientID int,
+	registrationToken string,
+	meta models.App,
+) (models.App, error) {
+	const op = "registration.UpdateClient"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.Int("appID", clientID),
+	)
+
+	app, err := r.client(ctx, clientID, registrationToken)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	meta, err = normalizeMetadata(meta)
+	if err != nil {
+		log.Info("invalid client metadata", sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.Name = meta.Name
+	app.RedirectURIs = meta.RedirectURIs
+	app.GrantTypes = meta.GrantTypes
+	app.LogoURI = meta.LogoURI
+	app.Contacts = meta.Contacts
+
+	if err := r.repo.UpdateApp(ctx, app); err != nil {
+		if errors.Is(err, repository.ErrAppExists) {
+			return models.App{}, fmt.Errorf("%s: %w", op, ErrClientExists)
+		}
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return models.App{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to update client", sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("client updated")
+
+	return app, nil
+}
+
+// DeleteClient deletes the client the registration access token manages along with its roles,
+// grants and tokens.
+func (r *Registration) DeleteClient(ctx context.Context, clientID int, registrationToken string) error {
+	const op = "registration.DeleteClient"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.Int("appID", clientID),
+	)
+
+	if _, err := r.client(ctx, clientID, registrationToken); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.repo.DeleteApp(ctx, clientID); err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to delete client", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("client deleted")
+
+	return nil
+}
+
+// client returns the app if the registration access token manages it. Missing apps and apps
+// created by admins are reported as invalid tokens, so that the token can't probe app IDs.
+func (r *Registration) client(ctx context.Context, clientID int, registrationToken string) (models.App, error) {
+	app, err := r.repo.App(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAppNotFound) {
+			return models.App{}, ErrInvalidToken
+		}
+
+		r.log.Error("failed to get app", slog.Int("appID", clientID), sl.Err(err))
+
+		return models.App{}, err
+	}
+
+	if app.RegistrationTokenHash == nil ||
+		subtle.ConstantTimeCompare(app.RegistrationTokenHash, randtoken.Hash(registrationToken)) != 1 {
+		r.log.Warn("invalid registration access token", slog.Int("appID", clientID))
+
+		return models.App{}, ErrInvalidToken
+	}
+
+	return app, nil
+}
+
+// normalizeMetadata validates the client metadata and fills in the defaults of RFC 7591:
+// clients that don't name grant types use the authorization code grant.
+func normalizeMetadata(meta models.App) (models.App, error) {
+	meta.Name = strings.TrimSpace(meta.Name)
+	if meta.Name == "" {
+		return models.App{}, fmt.Errorf("%w: client_name is required", ErrInvalidClientMetadata)
+	}
+
+	if len(meta.GrantTypes) == 0 {
+		meta.GrantTypes = []string{models.GrantTypeAuthorizationCode}
+	}
+	for i, grantType := range meta.GrantTypes {
+		if !slices.Contains(GrantTypes, grantType) || slices.Contains(meta.GrantTypes[:i], grantType) {
+			return models.App{}, fmt.Errorf("%w: grant type %q isn't allowed", ErrInvalidClientMetadata, grantType)
+		}
+	}
+
+	if slices.Contains(meta.GrantTypes, models.GrantTypeAuthorizationCode) && len(meta.RedirectURIs) == 0 {
+		return models.App{}, fmt.Errorf("%w: the authorization code grant needs redirect uris", ErrInvalidRedirectURI)
+	}
+	for _, redirectURI := range meta.RedirectURIs {
+		if !validRedirectURI(redirectURI) {
+			return models.App{}, fmt.Errorf("%w: %q", ErrInvalidRedirectURI, redirectURI)
+		}
+	}
+
+	if meta.LogoURI != "" {
+		u, err := url.Parse(meta.LogoURI)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return models.App{}, fmt.Errorf("%w: logo_uri must be an https URL", ErrInvalidClientMetadata)
+		}
+	}
+
+	if len(meta.Contacts) > maxContacts {
+		return models.App{}, fmt.Errorf("%w: at most %d contacts are allowed", ErrInvalidClientMetadata, maxContacts)
+	}
+	for _, contact := range meta.Contacts {
+		if local, domain, ok := strings.Cut(contact, "@"); !ok || local == "" || domain == "" {
+			return models.App{}, fmt.Errorf("%w: contact %q isn't an email", ErrInvalidClientMetadata, contact)
+		}
+	}
+
+	return meta, nil
+}
+
+// validRedirectURI tells whether the client may redirect users to the URI: an https URL,
+// or an http URL of the loopback interface for native apps, without a fragment.
+func validRedirectURI(redirectURI string) bool {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Host == "" || u.Fragment != "" {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+
+	return false
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"sso/internal/services/sms"
+)
+
+const verificationSMSTmpl = "HKIA verification code: %s"
+
+type SMSService struct {
+	log    *slog.Logger
+	sender sms.Sender
+}
+
+type VerificationSMSInput struct {
+	Phone            string
+	VerificationCode string
+}
+
+func NewSMSService(log *slog.Logger, sender sms.Sender) *SMSService {
+	return &SMSService{log: log, sender: sender}
+}
+
+func (s *SMSService) SendVerificationSMS(input VerificationSMSInput) error {
+	return s.sender.Send(sms.SendSMSInput{
+		To:   input.Phone,
+		Text: fmt.Sprintf(verificationSMSTmpl, input.VerificationCode),
+	})
+}
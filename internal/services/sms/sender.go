@@ -0,0 +1,23 @@
+package sms
+
+import "errors"
+
+type Sender interface {
+	Send(input SendSMSInput) error
+}
+
+type SendSMSInput struct {
+	To   string
+	Text string
+}
+
+func (s *SendSMSInput) Validate() error {
+	if s.To == "" {
+		return errors.New("empty phone")
+	}
+	if s.Text == "" {
+		return errors.New("empty sms text")
+	}
+
+	return nil
+}
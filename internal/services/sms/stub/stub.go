@@ -0,0 +1,29 @@
+package stub
+
+import (
+	"log/slog"
+
+	"sso/internal/services/sms"
+)
+
+// StubService logs messages instead of sending them, for running without an SMS provider.
+type StubService struct {
+	log *slog.Logger
+}
+
+func NewStubService(log *slog.Logger) *StubService {
+	return &StubService{log: log}
+}
+
+func (s *StubService) Send(input sms.SendSMSInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	s.log.Info("sms is not sent, no provider configured",
+		slog.String("to", input.To),
+		slog.String("text", input.Text),
+	)
+
+	return nil
+}
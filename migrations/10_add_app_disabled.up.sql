@@ -0,0 +1,2 @@
+ALTER TABLE apps
+    ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
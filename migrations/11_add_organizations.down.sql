@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_apps_org;
+ALTER TABLE apps DROP COLUMN org_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id                    INTEGER PRIMARY KEY,
+    name                  TEXT     NOT NULL UNIQUE,
+    allowed_email_domains TEXT     NOT NULL DEFAULT '[]',
+    require_mfa           BOOLEAN  NOT NULL DEFAULT FALSE,
+    created_at            DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS organization_members
+(
+    org_id  INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role    TEXT    NOT NULL,
+    PRIMARY KEY (org_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_organization_members_user ON organization_members (user_id);
+
+ALTER TABLE apps
+    ADD COLUMN org_id INTEGER REFERENCES organizations (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_apps_org ON apps (org_id);
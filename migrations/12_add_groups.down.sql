@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS group_groups;
+DROP TABLE IF EXISTS group_users;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS groups
+(
+    id          INTEGER PRIMARY KEY,
+    name        TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS group_users
+(
+    group_id INTEGER NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id  INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_users_user ON group_users (user_id);
+
+CREATE TABLE IF NOT EXISTS group_groups
+(
+    parent_id INTEGER NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    child_id  INTEGER NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    PRIMARY KEY (parent_id, child_id),
+    CHECK (parent_id <> child_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_groups_child ON group_groups (child_id);
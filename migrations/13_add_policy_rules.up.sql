@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS policy_rules
+(
+    id            INTEGER PRIMARY KEY,
+    app_id        INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name          TEXT    NOT NULL,
+    effect        TEXT    NOT NULL CHECK (effect IN ('allow', 'deny')),
+    actions       TEXT    NOT NULL DEFAULT '[]',
+    resource_type TEXT    NOT NULL DEFAULT '*',
+    conditions    TEXT    NOT NULL DEFAULT '[]',
+    UNIQUE (app_id, name)
+);
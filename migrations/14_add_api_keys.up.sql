@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id       INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name         TEXT     NOT NULL,
+    prefix       TEXT     NOT NULL UNIQUE,
+    key_hash     BLOB     NOT NULL,
+    scopes       TEXT     NOT NULL DEFAULT '[]',
+    created_at   DATETIME NOT NULL,
+    expires_at   DATETIME NOT NULL,
+    last_used_at DATETIME,
+    UNIQUE (user_id, name)
+);
@@ -0,0 +1,26 @@
+DELETE FROM api_keys WHERE service_account_id IS NOT NULL;
+
+CREATE TABLE api_keys_old
+(
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id       INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name         TEXT     NOT NULL,
+    prefix       TEXT     NOT NULL UNIQUE,
+    key_hash     BLOB     NOT NULL,
+    scopes       TEXT     NOT NULL DEFAULT '[]',
+    created_at   DATETIME NOT NULL,
+    expires_at   DATETIME NOT NULL,
+    last_used_at DATETIME,
+    UNIQUE (user_id, name)
+);
+
+INSERT INTO api_keys_old (id, user_id, app_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at)
+SELECT id, user_id, app_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at
+FROM api_keys;
+
+DROP TABLE api_keys;
+ALTER TABLE api_keys_old RENAME TO api_keys;
+
+DROP TABLE IF EXISTS service_account_roles;
+DROP TABLE IF EXISTS service_accounts;
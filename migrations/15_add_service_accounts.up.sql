@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS service_accounts
+(
+    id          INTEGER PRIMARY KEY,
+    name        TEXT     NOT NULL UNIQUE,
+    description TEXT     NOT NULL DEFAULT '',
+    app_id      INTEGER REFERENCES apps (id) ON DELETE CASCADE,
+    org_id      INTEGER REFERENCES organizations (id) ON DELETE CASCADE,
+    client_id   TEXT     NOT NULL UNIQUE,
+    secret_hash BLOB     NOT NULL,
+    disabled    BOOLEAN  NOT NULL DEFAULT FALSE,
+    created_at  DATETIME NOT NULL,
+    CHECK ((app_id IS NULL) <> (org_id IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS service_account_roles
+(
+    service_account_id INTEGER NOT NULL REFERENCES service_accounts (id) ON DELETE CASCADE,
+    role_id            INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (service_account_id, role_id)
+);
+
+-- api keys belong either to a user or to a service account
+CREATE TABLE api_keys_new
+(
+    id                 INTEGER PRIMARY KEY,
+    user_id            INTEGER REFERENCES users (id) ON DELETE CASCADE,
+    service_account_id INTEGER REFERENCES service_accounts (id) ON DELETE CASCADE,
+    app_id             INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name               TEXT     NOT NULL,
+    prefix             TEXT     NOT NULL UNIQUE,
+    key_hash           BLOB     NOT NULL,
+    scopes             TEXT     NOT NULL DEFAULT '[]',
+    created_at         DATETIME NOT NULL,
+    expires_at         DATETIME NOT NULL,
+    last_used_at       DATETIME,
+    UNIQUE (user_id, name),
+    UNIQUE (service_account_id, name),
+    CHECK ((user_id IS NULL) <> (service_account_id IS NULL))
+);
+
+INSERT INTO api_keys_new (id, user_id, app_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at)
+SELECT id, user_id, app_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at
+FROM api_keys;
+
+DROP TABLE api_keys;
+ALTER TABLE api_keys_new RENAME TO api_keys;
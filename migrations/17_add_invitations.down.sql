@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_invitations_org;
+DROP TABLE IF EXISTS invitations;
+ALTER TABLE users DROP COLUMN email_verified;
@@ -0,0 +1,19 @@
+ALTER TABLE users
+    ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS invitations
+(
+    id          INTEGER PRIMARY KEY,
+    email       TEXT     NOT NULL,
+    token_hash  BLOB     NOT NULL UNIQUE,
+    inviter_id  INTEGER  REFERENCES users (id) ON DELETE SET NULL,
+    app_id      INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    role        TEXT     NOT NULL DEFAULT '',
+    org_id      INTEGER REFERENCES organizations (id) ON DELETE CASCADE,
+    org_role    TEXT     NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL,
+    expires_at  DATETIME NOT NULL,
+    accepted_at DATETIME,
+    revoked_at  DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_invitations_org ON invitations (org_id);
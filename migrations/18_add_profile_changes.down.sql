@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_profile_changes_user;
+DROP TABLE IF EXISTS profile_changes;
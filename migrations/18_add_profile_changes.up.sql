@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS profile_changes
+(
+    id         INTEGER PRIMARY KEY,
+    user_id    INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    changed_by INTEGER  NOT NULL,
+    field      TEXT     NOT NULL,
+    old_value  TEXT     NOT NULL,
+    new_value  TEXT     NOT NULL,
+    changed_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_profile_changes_user ON profile_changes (user_id);
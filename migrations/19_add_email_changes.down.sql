@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_email_changes_user;
+DROP TABLE IF EXISTS email_changes;
+ALTER TABLE users DROP COLUMN tokens_valid_after;
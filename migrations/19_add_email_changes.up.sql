@@ -0,0 +1,16 @@
+ALTER TABLE users
+    ADD COLUMN tokens_valid_after DATETIME;
+
+CREATE TABLE IF NOT EXISTS email_changes
+(
+    id                INTEGER PRIMARY KEY,
+    user_id           INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    old_email         TEXT     NOT NULL,
+    new_email         TEXT     NOT NULL,
+    revert_token_hash BLOB UNIQUE,
+    created_at        DATETIME NOT NULL,
+    confirmed_at      DATETIME,
+    revert_expires_at DATETIME,
+    reverted_at       DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_email_changes_user ON email_changes (user_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_phone_changes_user;
+DROP TABLE IF EXISTS phone_changes;
+DROP INDEX IF EXISTS idx_users_phone;
+DROP TABLE IF EXISTS phone_conflicts;
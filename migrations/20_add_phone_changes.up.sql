@@ -0,0 +1,35 @@
+-- phones were free text, strip formatting so that equal numbers collide below;
+-- national numbers need the configured country code, the service converts them to E.164 at startup
+UPDATE users
+SET phone = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(TRIM(phone), ' ', ''), '-', ''), '(', ''), ')', ''), '.', '');
+
+-- a phone shared by several users stays with the one who registered first, the others are set aside
+-- until the phone is free again, the service reports them at startup
+CREATE TABLE IF NOT EXISTS phone_conflicts
+(
+    user_id INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    phone   TEXT NOT NULL
+);
+
+INSERT INTO phone_conflicts (user_id, phone)
+SELECT id, phone
+FROM users
+WHERE phone != ''
+  AND id NOT IN (SELECT MIN(id) FROM users WHERE phone != '' GROUP BY phone);
+
+UPDATE users
+SET phone = ''
+WHERE id IN (SELECT user_id FROM phone_conflicts);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone ON users (phone) WHERE phone != '';
+
+CREATE TABLE IF NOT EXISTS phone_changes
+(
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    old_phone    TEXT     NOT NULL,
+    new_phone    TEXT     NOT NULL,
+    created_at   DATETIME NOT NULL,
+    confirmed_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_phone_changes_user ON phone_changes (user_id);
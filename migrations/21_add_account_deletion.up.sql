@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN delete_after DATETIME;
+CREATE INDEX IF NOT EXISTS idx_users_delete_after ON users (delete_after) WHERE delete_after IS NOT NULL;
@@ -0,0 +1,12 @@
+ALTER TABLE users
+    ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users
+SET disabled = TRUE
+WHERE status IN ('pending', 'suspended')
+   OR (status = 'deleted' AND delete_after IS NULL);
+
+DROP INDEX IF EXISTS idx_users_status;
+ALTER TABLE users DROP COLUMN status_changed_at;
+ALTER TABLE users DROP COLUMN status_reason;
+ALTER TABLE users DROP COLUMN status;
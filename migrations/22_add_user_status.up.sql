@@ -0,0 +1,19 @@
+ALTER TABLE users
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE users
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE users
+    ADD COLUMN status_changed_at DATETIME;
+
+UPDATE users
+SET status = 'suspended'
+WHERE disabled;
+
+-- accounts that requested deletion, anonymized ones have no password left
+UPDATE users
+SET status = 'deleted'
+WHERE delete_after IS NOT NULL
+   OR (disabled AND pass_hash = X'' AND email LIKE 'deleted-%@invalid');
+
+ALTER TABLE users DROP COLUMN disabled;
+CREATE INDEX IF NOT EXISTS idx_users_status ON users (status);
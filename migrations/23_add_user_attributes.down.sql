@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_attributes_app;
+DROP TABLE IF EXISTS user_attributes;
+ALTER TABLE apps DROP COLUMN claim_attributes;
+ALTER TABLE apps DROP COLUMN attributes_schema;
@@ -0,0 +1,15 @@
+ALTER TABLE apps
+    ADD COLUMN attributes_schema TEXT NOT NULL DEFAULT '';
+ALTER TABLE apps
+    ADD COLUMN claim_attributes TEXT NOT NULL DEFAULT '[]';
+
+CREATE TABLE IF NOT EXISTS user_attributes
+(
+    user_id    INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    key        TEXT     NOT NULL,
+    value      TEXT     NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, app_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_user_attributes_app ON user_attributes (app_id);
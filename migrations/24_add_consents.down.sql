@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_consent_challenges_user;
+DROP TABLE IF EXISTS consent_challenges;
+DROP INDEX IF EXISTS idx_consents_document;
+DROP TABLE IF EXISTS consents;
+DROP TABLE IF EXISTS legal_documents;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS legal_documents
+(
+    id           INTEGER PRIMARY KEY,
+    type         TEXT     NOT NULL,
+    version      TEXT     NOT NULL,
+    url          TEXT     NOT NULL,
+    mandatory    BOOLEAN  NOT NULL DEFAULT FALSE,
+    published_at DATETIME NOT NULL,
+    created_at   DATETIME NOT NULL,
+    UNIQUE (type, version)
+);
+
+CREATE TABLE IF NOT EXISTS consents
+(
+    user_id     INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    document_id INTEGER  NOT NULL REFERENCES legal_documents (id) ON DELETE CASCADE,
+    accepted_at DATETIME NOT NULL,
+    ip          TEXT     NOT NULL DEFAULT '',
+    PRIMARY KEY (user_id, document_id)
+);
+CREATE INDEX IF NOT EXISTS idx_consents_document ON consents (document_id);
+
+CREATE TABLE IF NOT EXISTS consent_challenges
+(
+    token_hash BLOB PRIMARY KEY,
+    user_id    INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    org_id     INTEGER REFERENCES organizations (id) ON DELETE CASCADE,
+    expires_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_consent_challenges_user ON consent_challenges (user_id);
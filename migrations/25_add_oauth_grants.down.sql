@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS app_token_revocations;
+DROP INDEX IF EXISTS idx_oauth_grants_app;
+DROP TABLE IF EXISTS oauth_grants;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS oauth_grants
+(
+    user_id    INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    scopes     TEXT     NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, app_id)
+);
+CREATE INDEX IF NOT EXISTS idx_oauth_grants_app ON oauth_grants (app_id);
+
+CREATE TABLE IF NOT EXISTS app_token_revocations
+(
+    user_id    INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    revoked_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, app_id)
+);
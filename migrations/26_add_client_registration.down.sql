@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS initial_access_tokens;
+ALTER TABLE apps DROP COLUMN registered_at;
+ALTER TABLE apps DROP COLUMN registration_token_hash;
+ALTER TABLE apps DROP COLUMN contacts;
+ALTER TABLE apps DROP COLUMN logo_uri;
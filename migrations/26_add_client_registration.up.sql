@@ -0,0 +1,20 @@
+ALTER TABLE apps
+    ADD COLUMN logo_uri TEXT NOT NULL DEFAULT '';
+ALTER TABLE apps
+    ADD COLUMN contacts TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE apps
+    ADD COLUMN registration_token_hash BLOB;
+ALTER TABLE apps
+    ADD COLUMN registered_at DATETIME;
+
+CREATE TABLE IF NOT EXISTS initial_access_tokens
+(
+    id          INTEGER PRIMARY KEY,
+    token_hash  BLOB     NOT NULL UNIQUE,
+    description TEXT     NOT NULL DEFAULT '',
+    created_by  INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at  DATETIME NOT NULL,
+    expires_at  DATETIME NOT NULL,
+    used_at     DATETIME,
+    app_id      INTEGER REFERENCES apps (id) ON DELETE SET NULL
+);
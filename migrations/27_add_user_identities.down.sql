@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_federated_logins_expires;
+DROP TABLE IF EXISTS federated_logins;
+DROP INDEX IF EXISTS idx_user_identities_user;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    provider      TEXT     NOT NULL,
+    subject       TEXT     NOT NULL,
+    user_id       INTEGER  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email         TEXT     NOT NULL DEFAULT '',
+    created_at    DATETIME NOT NULL,
+    last_login_at DATETIME NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS federated_logins
+(
+    id            INTEGER PRIMARY KEY,
+    state_hash    BLOB UNIQUE,
+    provider      TEXT     NOT NULL,
+    nonce         TEXT     NOT NULL,
+    code_verifier TEXT     NOT NULL,
+    app_id        INTEGER  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    org_id        INTEGER REFERENCES organizations (id) ON DELETE CASCADE,
+    redirect_uri  TEXT     NOT NULL,
+    client_state  TEXT     NOT NULL DEFAULT '',
+    expires_at    DATETIME NOT NULL,
+    code_hash     BLOB UNIQUE,
+    user_id       INTEGER REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_federated_logins_expires ON federated_logins (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE login_attempts DROP COLUMN first_failed_at;
@@ -0,0 +1,4 @@
+ALTER TABLE login_attempts ADD COLUMN first_failed_at DATETIME;
+
+-- the window of attempts counted so far starts at the last one, so old counts expire soon
+UPDATE login_attempts SET first_failed_at = last_failed_at;
@@ -0,0 +1 @@
+ALTER TABLE consent_challenges DROP COLUMN grant_type;
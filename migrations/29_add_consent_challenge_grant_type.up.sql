@@ -0,0 +1,5 @@
+-- challenges issued before the grant type was recorded come from password logins
+ALTER TABLE consent_challenges ADD COLUMN grant_type TEXT NOT NULL DEFAULT 'password';
+
+-- logins now require the grant type, apps without any kept logging users in with passwords so far
+UPDATE apps SET grant_types = '["password"]' WHERE grant_types = '[]' AND registration_token_hash IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE consent_challenges DROP COLUMN scopes;
+ALTER TABLE federated_logins DROP COLUMN scopes;
@@ -0,0 +1,3 @@
+-- scopes the app requested, third-party apps only get tokens for scopes the user granted them
+ALTER TABLE federated_logins ADD COLUMN scopes TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE consent_challenges ADD COLUMN scopes TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1 @@
+ALTER TABLE federated_logins DROP COLUMN app_code_challenge;
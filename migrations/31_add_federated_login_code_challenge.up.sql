@@ -0,0 +1,2 @@
+-- S256 PKCE challenge of the app, the login code is exchanged only with its verifier
+ALTER TABLE federated_logins ADD COLUMN app_code_challenge TEXT NOT NULL DEFAULT '';
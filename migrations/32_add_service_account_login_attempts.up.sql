@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS service_account_login_attempts
+(
+    service_account_id INTEGER PRIMARY KEY REFERENCES service_accounts (id) ON DELETE CASCADE,
+    failed_attempts    INTEGER  NOT NULL DEFAULT 0,
+    first_failed_at    DATETIME NOT NULL,
+    last_failed_at     DATETIME NOT NULL,
+    locked_until       DATETIME NOT NULL
+);
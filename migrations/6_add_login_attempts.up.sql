@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS login_attempts
+(
+    user_id         INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    failed_attempts INTEGER  NOT NULL DEFAULT 0,
+    last_failed_at  DATETIME NOT NULL,
+    locked_until    DATETIME NOT NULL
+);
@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS roles
+(
+    id     INTEGER PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name   TEXT    NOT NULL,
+    UNIQUE (app_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS permissions
+(
+    id     INTEGER PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name   TEXT    NOT NULL,
+    UNIQUE (app_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role_id       INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles (role_id);
+
+INSERT INTO roles (app_id, name)
+SELECT id, 'admin' FROM apps WHERE TRUE
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (app_id, name)
+SELECT id, 'admin' FROM apps WHERE TRUE
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.app_id = r.app_id AND p.name = 'admin'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id
+FROM users u
+         JOIN roles r ON r.name = 'admin'
+WHERE u.is_admin
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users DROP COLUMN is_admin;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN created_at DATETIME;
+ALTER TABLE users
+    ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
@@ -0,0 +1,6 @@
+ALTER TABLE apps
+    ADD COLUMN redirect_uris TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE apps
+    ADD COLUMN grant_types TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE apps
+    ADD COLUMN owner_id INTEGER REFERENCES users (id) ON DELETE SET NULL;
@@ -0,0 +1,1430 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: sso/account.proto
+
+package sso
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Profile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	EmailVerified bool                   `protobuf:"varint,6,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
+	Phone         string                 `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`
+	BirthDate     string                 `protobuf:"bytes,8,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339 time the user registered at, empty if unknown.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_sso_account_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Profile) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Profile) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Profile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Profile) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Profile) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Profile) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *Profile) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Profile) GetBirthDate() string {
+	if x != nil {
+		return x.BirthDate
+	}
+	return ""
+}
+
+func (x *Profile) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	mi := &file_sso_account_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileRequest) ProtoMessage() {}
+
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{1}
+}
+
+type GetProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       *Profile               `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileResponse) Reset() {
+	*x = GetProfileResponse{}
+	mi := &file_sso_account_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileResponse) ProtoMessage() {}
+
+func (x *GetProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileResponse.ProtoReflect.Descriptor instead.
+func (*GetProfileResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetProfileResponse) GetProfile() *Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+type UpdateProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"` // One of Mr, Mrs, Ms, Miss, Mx, Dr.
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	BirthDate     string                 `protobuf:"bytes,5,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"` // YYYY-MM-DD.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProfileRequest) Reset() {
+	*x = UpdateProfileRequest{}
+	mi := &file_sso_account_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileRequest) ProtoMessage() {}
+
+func (x *UpdateProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProfileRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateProfileRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetBirthDate() string {
+	if x != nil {
+		return x.BirthDate
+	}
+	return ""
+}
+
+type UpdateProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       *Profile               `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProfileResponse) Reset() {
+	*x = UpdateProfileResponse{}
+	mi := &file_sso_account_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileResponse) ProtoMessage() {}
+
+func (x *UpdateProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProfileResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateProfileResponse) GetProfile() *Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+type ChangeEmailInitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NewEmail      string                 `protobuf:"bytes,1,opt,name=new_email,json=newEmail,proto3" json:"new_email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEmailInitRequest) Reset() {
+	*x = ChangeEmailInitRequest{}
+	mi := &file_sso_account_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailInitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailInitRequest) ProtoMessage() {}
+
+func (x *ChangeEmailInitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailInitRequest.ProtoReflect.Descriptor instead.
+func (*ChangeEmailInitRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ChangeEmailInitRequest) GetNewEmail() string {
+	if x != nil {
+		return x.NewEmail
+	}
+	return ""
+}
+
+type ChangeEmailInitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExpiresAt     string                 `protobuf:"bytes,1,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // RFC3339 time the code expires at.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEmailInitResponse) Reset() {
+	*x = ChangeEmailInitResponse{}
+	mi := &file_sso_account_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailInitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailInitResponse) ProtoMessage() {}
+
+func (x *ChangeEmailInitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailInitResponse.ProtoReflect.Descriptor instead.
+func (*ChangeEmailInitResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChangeEmailInitResponse) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type ChangeEmailConfirmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEmailConfirmRequest) Reset() {
+	*x = ChangeEmailConfirmRequest{}
+	mi := &file_sso_account_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailConfirmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailConfirmRequest) ProtoMessage() {}
+
+func (x *ChangeEmailConfirmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailConfirmRequest.ProtoReflect.Descriptor instead.
+func (*ChangeEmailConfirmRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ChangeEmailConfirmRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ChangeEmailConfirmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEmailConfirmResponse) Reset() {
+	*x = ChangeEmailConfirmResponse{}
+	mi := &file_sso_account_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailConfirmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailConfirmResponse) ProtoMessage() {}
+
+func (x *ChangeEmailConfirmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailConfirmResponse.ProtoReflect.Descriptor instead.
+func (*ChangeEmailConfirmResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChangeEmailConfirmResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ChangePhoneInitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NewPhone      string                 `protobuf:"bytes,1,opt,name=new_phone,json=newPhone,proto3" json:"new_phone,omitempty"` // E.164 or national format, normalized to E.164.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePhoneInitRequest) Reset() {
+	*x = ChangePhoneInitRequest{}
+	mi := &file_sso_account_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePhoneInitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePhoneInitRequest) ProtoMessage() {}
+
+func (x *ChangePhoneInitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePhoneInitRequest.ProtoReflect.Descriptor instead.
+func (*ChangePhoneInitRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ChangePhoneInitRequest) GetNewPhone() string {
+	if x != nil {
+		return x.NewPhone
+	}
+	return ""
+}
+
+type ChangePhoneInitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExpiresAt     string                 `protobuf:"bytes,1,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // RFC3339 time the code expires at.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePhoneInitResponse) Reset() {
+	*x = ChangePhoneInitResponse{}
+	mi := &file_sso_account_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePhoneInitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePhoneInitResponse) ProtoMessage() {}
+
+func (x *ChangePhoneInitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePhoneInitResponse.ProtoReflect.Descriptor instead.
+func (*ChangePhoneInitResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ChangePhoneInitResponse) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type ChangePhoneConfirmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePhoneConfirmRequest) Reset() {
+	*x = ChangePhoneConfirmRequest{}
+	mi := &file_sso_account_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePhoneConfirmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePhoneConfirmRequest) ProtoMessage() {}
+
+func (x *ChangePhoneConfirmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePhoneConfirmRequest.ProtoReflect.Descriptor instead.
+func (*ChangePhoneConfirmRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ChangePhoneConfirmRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ChangePhoneConfirmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePhoneConfirmResponse) Reset() {
+	*x = ChangePhoneConfirmResponse{}
+	mi := &file_sso_account_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePhoneConfirmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePhoneConfirmResponse) ProtoMessage() {}
+
+func (x *ChangePhoneConfirmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePhoneConfirmResponse.ProtoReflect.Descriptor instead.
+func (*ChangePhoneConfirmResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ChangePhoneConfirmResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RequestAccountDeletionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"` // Current password confirming the request.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestAccountDeletionRequest) Reset() {
+	*x = RequestAccountDeletionRequest{}
+	mi := &file_sso_account_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestAccountDeletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestAccountDeletionRequest) ProtoMessage() {}
+
+func (x *RequestAccountDeletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestAccountDeletionRequest.ProtoReflect.Descriptor instead.
+func (*RequestAccountDeletionRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RequestAccountDeletionRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RequestAccountDeletionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeleteAfter   string                 `protobuf:"bytes,1,opt,name=delete_after,json=deleteAfter,proto3" json:"delete_after,omitempty"` // RFC3339 time the account is deleted after.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestAccountDeletionResponse) Reset() {
+	*x = RequestAccountDeletionResponse{}
+	mi := &file_sso_account_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestAccountDeletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestAccountDeletionResponse) ProtoMessage() {}
+
+func (x *RequestAccountDeletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestAccountDeletionResponse.ProtoReflect.Descriptor instead.
+func (*RequestAccountDeletionResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RequestAccountDeletionResponse) GetDeleteAfter() string {
+	if x != nil {
+		return x.DeleteAfter
+	}
+	return ""
+}
+
+type ExportMyDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportMyDataRequest) Reset() {
+	*x = ExportMyDataRequest{}
+	mi := &file_sso_account_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportMyDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMyDataRequest) ProtoMessage() {}
+
+func (x *ExportMyDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMyDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportMyDataRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{15}
+}
+
+type ExportMyDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`                                  // JSON document.
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // Always application/json.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportMyDataResponse) Reset() {
+	*x = ExportMyDataResponse{}
+	mi := &file_sso_account_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportMyDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMyDataResponse) ProtoMessage() {}
+
+func (x *ExportMyDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMyDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportMyDataResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportMyDataResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportMyDataResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type AppGrant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         int32                  `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	AppName       string                 `protobuf:"bytes,2,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339 time the access was first granted at.
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // RFC3339 time scopes were last granted at.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppGrant) Reset() {
+	*x = AppGrant{}
+	mi := &file_sso_account_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppGrant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppGrant) ProtoMessage() {}
+
+func (x *AppGrant) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppGrant.ProtoReflect.Descriptor instead.
+func (*AppGrant) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AppGrant) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *AppGrant) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *AppGrant) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *AppGrant) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *AppGrant) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type AuthorizeAppRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         int32                  `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`                  // ID of the app requesting access, the client_id.
+	RedirectUri   string                 `protobuf:"bytes,2,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"` // Must be one of the app's redirect URIs.
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`                              // Requested scopes.
+	Approve       bool                   `protobuf:"varint,4,opt,name=approve,proto3" json:"approve,omitempty"`                           // Set once the user approved the scopes on the consent screen.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeAppRequest) Reset() {
+	*x = AuthorizeAppRequest{}
+	mi := &file_sso_account_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeAppRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeAppRequest) ProtoMessage() {}
+
+func (x *AuthorizeAppRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeAppRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizeAppRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AuthorizeAppRequest) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *AuthorizeAppRequest) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *AuthorizeAppRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *AuthorizeAppRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+type AuthorizeAppResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConsentRequired bool                   `protobuf:"varint,1,opt,name=consent_required,json=consentRequired,proto3" json:"consent_required,omitempty"` // Whether the consent screen must be shown, the grant is empty then.
+	AppName         string                 `protobuf:"bytes,2,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`                          // Name of the app to show on the consent screen.
+	Scopes          []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`                                           // Requested scopes.
+	NewScopes       []string               `protobuf:"bytes,4,rep,name=new_scopes,json=newScopes,proto3" json:"new_scopes,omitempty"`                    // Requested scopes the user hasn't granted before.
+	Grant           *AppGrant              `protobuf:"bytes,5,opt,name=grant,proto3" json:"grant,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AuthorizeAppResponse) Reset() {
+	*x = AuthorizeAppResponse{}
+	mi := &file_sso_account_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeAppResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeAppResponse) ProtoMessage() {}
+
+func (x *AuthorizeAppResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeAppResponse.ProtoReflect.Descriptor instead.
+func (*AuthorizeAppResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AuthorizeAppResponse) GetConsentRequired() bool {
+	if x != nil {
+		return x.ConsentRequired
+	}
+	return false
+}
+
+func (x *AuthorizeAppResponse) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *AuthorizeAppResponse) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *AuthorizeAppResponse) GetNewScopes() []string {
+	if x != nil {
+		return x.NewScopes
+	}
+	return nil
+}
+
+func (x *AuthorizeAppResponse) GetGrant() *AppGrant {
+	if x != nil {
+		return x.Grant
+	}
+	return nil
+}
+
+type ListAuthorizedAppsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorizedAppsRequest) Reset() {
+	*x = ListAuthorizedAppsRequest{}
+	mi := &file_sso_account_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorizedAppsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorizedAppsRequest) ProtoMessage() {}
+
+func (x *ListAuthorizedAppsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorizedAppsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuthorizedAppsRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{20}
+}
+
+type ListAuthorizedAppsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grants        []*AppGrant            `protobuf:"bytes,1,rep,name=grants,proto3" json:"grants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthorizedAppsResponse) Reset() {
+	*x = ListAuthorizedAppsResponse{}
+	mi := &file_sso_account_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthorizedAppsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthorizedAppsResponse) ProtoMessage() {}
+
+func (x *ListAuthorizedAppsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthorizedAppsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuthorizedAppsResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListAuthorizedAppsResponse) GetGrants() []*AppGrant {
+	if x != nil {
+		return x.Grants
+	}
+	return nil
+}
+
+type RevokeAppAccessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         int32                  `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAppAccessRequest) Reset() {
+	*x = RevokeAppAccessRequest{}
+	mi := &file_sso_account_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAppAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAppAccessRequest) ProtoMessage() {}
+
+func (x *RevokeAppAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAppAccessRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAppAccessRequest) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RevokeAppAccessRequest) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+type RevokeAppAccessResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAppAccessResponse) Reset() {
+	*x = RevokeAppAccessResponse{}
+	mi := &file_sso_account_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAppAccessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAppAccessResponse) ProtoMessage() {}
+
+func (x *RevokeAppAccessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_account_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAppAccessResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAppAccessResponse) Descriptor() ([]byte, []int) {
+	return file_sso_account_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RevokeAppAccessResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+var File_sso_account_proto protoreflect.FileDescriptor
+
+const file_sso_account_proto_rawDesc = "" +
+	"\n" +
+	"\x11sso/account.proto\x12\x04auth\"\xfa\x01\n" +
+	"\aProfile\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12%\n" +
+	"\x0eemail_verified\x18\x06 \x01(\bR\remailVerified\x12\x14\n" +
+	"\x05phone\x18\a \x01(\tR\x05phone\x12\x1d\n" +
+	"\n" +
+	"birth_date\x18\b \x01(\tR\tbirthDate\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\x13\n" +
+	"\x11GetProfileRequest\"=\n" +
+	"\x12GetProfileResponse\x12'\n" +
+	"\aprofile\x18\x01 \x01(\v2\r.auth.ProfileR\aprofile\"\x82\x01\n" +
+	"\x14UpdateProfileRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x12\x1d\n" +
+	"\n" +
+	"birth_date\x18\x05 \x01(\tR\tbirthDateJ\x04\b\x04\x10\x05\"@\n" +
+	"\x15UpdateProfileResponse\x12'\n" +
+	"\aprofile\x18\x01 \x01(\v2\r.auth.ProfileR\aprofile\"5\n" +
+	"\x16ChangeEmailInitRequest\x12\x1b\n" +
+	"\tnew_email\x18\x01 \x01(\tR\bnewEmail\"8\n" +
+	"\x17ChangeEmailInitResponse\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x01 \x01(\tR\texpiresAt\"/\n" +
+	"\x19ChangeEmailConfirmRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"6\n" +
+	"\x1aChangeEmailConfirmResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"5\n" +
+	"\x16ChangePhoneInitRequest\x12\x1b\n" +
+	"\tnew_phone\x18\x01 \x01(\tR\bnewPhone\"8\n" +
+	"\x17ChangePhoneInitResponse\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x01 \x01(\tR\texpiresAt\"/\n" +
+	"\x19ChangePhoneConfirmRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"6\n" +
+	"\x1aChangePhoneConfirmResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\";\n" +
+	"\x1dRequestAccountDeletionRequest\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\"C\n" +
+	"\x1eRequestAccountDeletionResponse\x12!\n" +
+	"\fdelete_after\x18\x01 \x01(\tR\vdeleteAfter\"\x15\n" +
+	"\x13ExportMyDataRequest\"M\n" +
+	"\x14ExportMyDataResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\"\x92\x01\n" +
+	"\bAppGrant\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\x05R\x05appId\x12\x19\n" +
+	"\bapp_name\x18\x02 \x01(\tR\aappName\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"\x81\x01\n" +
+	"\x13AuthorizeAppRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\x05R\x05appId\x12!\n" +
+	"\fredirect_uri\x18\x02 \x01(\tR\vredirectUri\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x18\n" +
+	"\aapprove\x18\x04 \x01(\bR\aapprove\"\xb9\x01\n" +
+	"\x14AuthorizeAppResponse\x12)\n" +
+	"\x10consent_required\x18\x01 \x01(\bR\x0fconsentRequired\x12\x19\n" +
+	"\bapp_name\x18\x02 \x01(\tR\aappName\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"new_scopes\x18\x04 \x03(\tR\tnewScopes\x12$\n" +
+	"\x05grant\x18\x05 \x01(\v2\x0e.auth.AppGrantR\x05grant\"\x1b\n" +
+	"\x19ListAuthorizedAppsRequest\"D\n" +
+	"\x1aListAuthorizedAppsResponse\x12&\n" +
+	"\x06grants\x18\x01 \x03(\v2\x0e.auth.AppGrantR\x06grants\"/\n" +
+	"\x16RevokeAppAccessRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\x05R\x05appId\"3\n" +
+	"\x17RevokeAppAccessResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess2\x82\a\n" +
+	"\aAccount\x12?\n" +
+	"\n" +
+	"GetProfile\x12\x17.auth.GetProfileRequest\x1a\x18.auth.GetProfileResponse\x12H\n" +
+	"\rUpdateProfile\x12\x1a.auth.UpdateProfileRequest\x1a\x1b.auth.UpdateProfileResponse\x12N\n" +
+	"\x0fChangeEmailInit\x12\x1c.auth.ChangeEmailInitRequest\x1a\x1d.auth.ChangeEmailInitResponse\x12W\n" +
+	"\x12ChangeEmailConfirm\x12\x1f.auth.ChangeEmailConfirmRequest\x1a .auth.ChangeEmailConfirmResponse\x12N\n" +
+	"\x0fChangePhoneInit\x12\x1c.auth.ChangePhoneInitRequest\x1a\x1d.auth.ChangePhoneInitResponse\x12W\n" +
+	"\x12ChangePhoneConfirm\x12\x1f.auth.ChangePhoneConfirmRequest\x1a .auth.ChangePhoneConfirmResponse\x12c\n" +
+	"\x16RequestAccountDeletion\x12#.auth.RequestAccountDeletionRequest\x1a$.auth.RequestAccountDeletionResponse\x12E\n" +
+	"\fExportMyData\x12\x19.auth.ExportMyDataRequest\x1a\x1a.auth.ExportMyDataResponse\x12E\n" +
+	"\fAuthorizeApp\x12\x19.auth.AuthorizeAppRequest\x1a\x1a.auth.AuthorizeAppResponse\x12W\n" +
+	"\x12ListAuthorizedApps\x12\x1f.auth.ListAuthorizedAppsRequest\x1a .auth.ListAuthorizedAppsResponse\x12N\n" +
+	"\x0fRevokeAppAccess\x12\x1c.auth.RevokeAppAccessRequest\x1a\x1d.auth.RevokeAppAccessResponseB$Z\"github.com/Abazin97/sso/gen/go/ssob\x06proto3"
+
+var (
+	file_sso_account_proto_rawDescOnce sync.Once
+	file_sso_account_proto_rawDescData []byte
+)
+
+func file_sso_account_proto_rawDescGZIP() []byte {
+	file_sso_account_proto_rawDescOnce.Do(func() {
+		file_sso_account_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sso_account_proto_rawDesc), len(file_sso_account_proto_rawDesc)))
+	})
+	return file_sso_account_proto_rawDescData
+}
+
+var file_sso_account_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_sso_account_proto_goTypes = []any{
+	(*Profile)(nil),                        // 0: auth.Profile
+	(*GetProfileRequest)(nil),              // 1: auth.GetProfileRequest
+	(*GetProfileResponse)(nil),             // 2: auth.GetProfileResponse
+	(*UpdateProfileRequest)(nil),           // 3: auth.UpdateProfileRequest
+	(*UpdateProfileResponse)(nil),          // 4: auth.UpdateProfileResponse
+	(*ChangeEmailInitRequest)(nil),         // 5: auth.ChangeEmailInitRequest
+	(*ChangeEmailInitResponse)(nil),        // 6: auth.ChangeEmailInitResponse
+	(*ChangeEmailConfirmRequest)(nil),      // 7: auth.ChangeEmailConfirmRequest
+	(*ChangeEmailConfirmResponse)(nil),     // 8: auth.ChangeEmailConfirmResponse
+	(*ChangePhoneInitRequest)(nil),         // 9: auth.ChangePhoneInitRequest
+	(*ChangePhoneInitResponse)(nil),        // 10: auth.ChangePhoneInitResponse
+	(*ChangePhoneConfirmRequest)(nil),      // 11: auth.ChangePhoneConfirmRequest
+	(*ChangePhoneConfirmResponse)(nil),     // 12: auth.ChangePhoneConfirmResponse
+	(*RequestAccountDeletionRequest)(nil),  // 13: auth.RequestAccountDeletionRequest
+	(*RequestAccountDeletionResponse)(nil), // 14: auth.RequestAccountDeletionResponse
+	(*ExportMyDataRequest)(nil),            // 15: auth.ExportMyDataRequest
+	(*ExportMyDataResponse)(nil),           // 16: auth.ExportMyDataResponse
+	(*AppGrant)(nil),                       // 17: auth.AppGrant
+	(*AuthorizeAppRequest)(nil),            // 18: auth.AuthorizeAppRequest
+	(*AuthorizeAppResponse)(nil),           // 19: auth.AuthorizeAppResponse
+	(*ListAuthorizedAppsRequest)(nil),      // 20: auth.ListAuthorizedAppsRequest
+	(*ListAuthorizedAppsResponse)(nil),     // 21: auth.ListAuthorizedAppsResponse
+	(*RevokeAppAccessRequest)(nil),         // 22: auth.RevokeAppAccessRequest
+	(*RevokeAppAccessResponse)(nil),        // 23: auth.RevokeAppAccessResponse
+}
+var file_sso_account_proto_depIdxs = []int32{
+	0,  // 0: auth.GetProfileResponse.profile:type_name -> auth.Profile
+	0,  // 1: auth.UpdateProfileResponse.profile:type_name -> auth.Profile
+	17, // 2: auth.AuthorizeAppResponse.grant:type_name -> auth.AppGrant
+	17, // 3: auth.ListAuthorizedAppsResponse.grants:type_name -> auth.AppGrant
+	1,  // 4: auth.Account.GetProfile:input_type -> auth.GetProfileRequest
+	3,  // 5: auth.Account.UpdateProfile:input_type -> auth.UpdateProfileRequest
+	5,  // 6: auth.Account.ChangeEmailInit:input_type -> auth.ChangeEmailInitRequest
+	7,  // 7: auth.Account.ChangeEmailConfirm:input_type -> auth.ChangeEmailConfirmRequest
+	9,  // 8: auth.Account.ChangePhoneInit:input_type -> auth.ChangePhoneInitRequest
+	11, // 9: auth.Account.ChangePhoneConfirm:input_type -> auth.ChangePhoneConfirmRequest
+	13, // 10: auth.Account.RequestAccountDeletion:input_type -> auth.RequestAccountDeletionRequest
+	15, // 11: auth.Account.ExportMyData:input_type -> auth.ExportMyDataRequest
+	18, // 12: auth.Account.AuthorizeApp:input_type -> auth.AuthorizeAppRequest
+	20, // 13: auth.Account.ListAuthorizedApps:input_type -> auth.ListAuthorizedAppsRequest
+	22, // 14: auth.Account.RevokeAppAccess:input_type -> auth.RevokeAppAccessRequest
+	2,  // 15: auth.Account.GetProfile:output_type -> auth.GetProfileResponse
+	4,  // 16: auth.Account.UpdateProfile:output_type -> auth.UpdateProfileResponse
+	6,  // 17: auth.Account.ChangeEmailInit:output_type -> auth.ChangeEmailInitResponse
+	8,  // 18: auth.Account.ChangeEmailConfirm:output_type -> auth.ChangeEmailConfirmResponse
+	10, // 19: auth.Account.ChangePhoneInit:output_type -> auth.ChangePhoneInitResponse
+	12, // 20: auth.Account.ChangePhoneConfirm:output_type -> auth.ChangePhoneConfirmResponse
+	14, // 21: auth.Account.RequestAccountDeletion:output_type -> auth.RequestAccountDeletionResponse
+	16, // 22: auth.Account.ExportMyData:output_type -> auth.ExportMyDataResponse
+	19, // 23: auth.Account.AuthorizeApp:output_type -> auth.AuthorizeAppResponse
+	21, // 24: auth.Account.ListAuthorizedApps:output_type -> auth.ListAuthorizedAppsResponse
+	23, // 25: auth.Account.RevokeAppAccess:output_type -> auth.RevokeAppAccessResponse
+	15, // [15:26] is the sub-list for method output_type
+	4,  // [4:15] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_sso_account_proto_init() }
+func file_sso_account_proto_init() {
+	if File_sso_account_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sso_account_proto_rawDesc), len(file_sso_account_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sso_account_proto_goTypes,
+		DependencyIndexes: file_sso_account_proto_depIdxs,
+		MessageInfos:      file_sso_account_proto_msgTypes,
+	}.Build()
+	File_sso_account_proto = out.File
+	file_sso_account_proto_goTypes = nil
+	file_sso_account_proto_depIdxs = nil
+}
@@ -0,0 +1,537 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.0
+// source: sso/account.proto
+
+package sso
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Account_GetProfile_FullMethodName             = "/auth.Account/GetProfile"
+	Account_UpdateProfile_FullMethodName          = "/auth.Account/UpdateProfile"
+	Account_ChangeEmailInit_FullMethodName        = "/auth.Account/ChangeEmailInit"
+	Account_ChangeEmailConfirm_FullMethodName     = "/auth.Account/ChangeEmailConfirm"
+	Account_ChangePhoneInit_FullMethodName        = "/auth.Account/ChangePhoneInit"
+	Account_ChangePhoneConfirm_FullMethodName     = "/auth.Account/ChangePhoneConfirm"
+	Account_RequestAccountDeletion_FullMethodName = "/auth.Account/RequestAccountDeletion"
+	Account_ExportMyData_FullMethodName           = "/auth.Account/ExportMyData"
+	Account_AuthorizeApp_FullMethodName           = "/auth.Account/AuthorizeApp"
+	Account_ListAuthorizedApps_FullMethodName     = "/auth.Account/ListAuthorizedApps"
+	Account_RevokeAppAccess_FullMethodName        = "/auth.Account/RevokeAppAccess"
+)
+
+// AccountClient is the client API for Account service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Account is service for users managing their own accounts. Every call requires
+// a token passed as "authorization: Bearer <token>" metadata.
+type AccountClient interface {
+	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*GetProfileResponse, error)
+	// UpdateProfile overwrites profile fields with the non-empty fields of the request.
+	// Every change is recorded in the profile history.
+	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*UpdateProfileResponse, error)
+	// ChangeEmailInit sends a verification code to the new email.
+	ChangeEmailInit(ctx context.Context, in *ChangeEmailInitRequest, opts ...grpc.CallOption) (*ChangeEmailInitResponse, error)
+	// ChangeEmailConfirm replaces the email with the one the code was sent to and revokes all tokens of the user.
+	// The old address gets a notice with a link reverting the change.
+	ChangeEmailConfirm(ctx context.Context, in *ChangeEmailConfirmRequest, opts ...grpc.CallOption) (*ChangeEmailConfirmResponse, error)
+	// ChangePhoneInit sends a verification code to the new phone by SMS.
+	ChangePhoneInit(ctx context.Context, in *ChangePhoneInitRequest, opts ...grpc.CallOption) (*ChangePhoneInitResponse, error)
+	// ChangePhoneConfirm replaces the phone with the one the code was sent to.
+	ChangePhoneConfirm(ctx context.Context, in *ChangePhoneConfirmRequest, opts ...grpc.CallOption) (*ChangePhoneConfirmResponse, error)
+	// RequestAccountDeletion schedules the account to be deleted once the grace period is over
+	// and revokes all tokens of the user. Logging in before that cancels the deletion.
+	RequestAccountDeletion(ctx context.Context, in *RequestAccountDeletionRequest, opts ...grpc.CallOption) (*RequestAccountDeletionResponse, error)
+	// ExportMyData returns everything stored about the user as a JSON document.
+	ExportMyData(ctx context.Context, in *ExportMyDataRequest, opts ...grpc.CallOption) (*ExportMyDataResponse, error)
+	// AuthorizeApp is the consent step of the authorization flow of a third-party app. Users who granted
+	// the requested scopes before skip it, otherwise consent_required is set until the user approves the scopes.
+	// Apps that registered themselves only get tokens of users who granted them the requested scopes.
+	AuthorizeApp(ctx context.Context, in *AuthorizeAppRequest, opts ...grpc.CallOption) (*AuthorizeAppResponse, error)
+	// ListAuthorizedApps returns apps the user granted access to.
+	ListAuthorizedApps(ctx context.Context, in *ListAuthorizedAppsRequest, opts ...grpc.CallOption) (*ListAuthorizedAppsResponse, error)
+	// RevokeAppAccess deletes the user's grant to an app and revokes tokens the app got for the user.
+	RevokeAppAccess(ctx context.Context, in *RevokeAppAccessRequest, opts ...grpc.CallOption) (*RevokeAppAccessResponse, error)
+}
+
+type accountClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccountClient(cc grpc.ClientConnInterface) AccountClient {
+	return &accountClient{cc}
+}
+
+func (c *accountClient) GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*GetProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProfileResponse)
+	err := c.cc.Invoke(ctx, Account_GetProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*UpdateProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateProfileResponse)
+	err := c.cc.Invoke(ctx, Account_UpdateProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ChangeEmailInit(ctx context.Context, in *ChangeEmailInitRequest, opts ...grpc.CallOption) (*ChangeEmailInitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeEmailInitResponse)
+	err := c.cc.Invoke(ctx, Account_ChangeEmailInit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ChangeEmailConfirm(ctx context.Context, in *ChangeEmailConfirmRequest, opts ...grpc.CallOption) (*ChangeEmailConfirmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeEmailConfirmResponse)
+	err := c.cc.Invoke(ctx, Account_ChangeEmailConfirm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ChangePhoneInit(ctx context.Context, in *ChangePhoneInitRequest, opts ...grpc.CallOption) (*ChangePhoneInitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangePhoneInitResponse)
+	err := c.cc.Invoke(ctx, Account_ChangePhoneInit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ChangePhoneConfirm(ctx context.Context, in *ChangePhoneConfirmRequest, opts ...grpc.CallOption) (*ChangePhoneConfirmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangePhoneConfirmResponse)
+	err := c.cc.Invoke(ctx, Account_ChangePhoneConfirm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) RequestAccountDeletion(ctx context.Context, in *RequestAccountDeletionRequest, opts ...grpc.CallOption) (*RequestAccountDeletionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestAccountDeletionResponse)
+	err := c.cc.Invoke(ctx, Account_RequestAccountDeletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ExportMyData(ctx context.Context, in *ExportMyDataRequest, opts ...grpc.CallOption) (*ExportMyDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportMyDataResponse)
+	err := c.cc.Invoke(ctx, Account_ExportMyData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) AuthorizeApp(ctx context.Context, in *AuthorizeAppRequest, opts ...grpc.CallOption) (*AuthorizeAppResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorizeAppResponse)
+	err := c.cc.Invoke(ctx, Account_AuthorizeApp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) ListAuthorizedApps(ctx context.Context, in *ListAuthorizedAppsRequest, opts ...grpc.CallOption) (*ListAuthorizedAppsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuthorizedAppsResponse)
+	err := c.cc.Invoke(ctx, Account_ListAuthorizedApps_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) RevokeAppAccess(ctx context.Context, in *RevokeAppAccessRequest, opts ...grpc.CallOption) (*RevokeAppAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAppAccessResponse)
+	err := c.cc.Invoke(ctx, Account_RevokeAppAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountServer is the server API for Account service.
+// All implementations must embed UnimplementedAccountServer
+// for forward compatibility.
+//
+// Account is service for users managing their own accounts. Every call requires
+// a token passed as "authorization: Bearer <token>" metadata.
+type AccountServer interface {
+	GetProfile(context.Context, *GetProfileRequest) (*GetProfileResponse, error)
+	// UpdateProfile overwrites profile fields with the non-empty fields of the request.
+	// Every change is recorded in the profile history.
+	UpdateProfile(context.Context, *UpdateProfileRequest) (*UpdateProfileResponse, error)
+	// ChangeEmailInit sends a verification code to the new email.
+	ChangeEmailInit(context.Context, *ChangeEmailInitRequest) (*ChangeEmailInitResponse, error)
+	// ChangeEmailConfirm replaces the email with the one the code was sent to and revokes all tokens of the user.
+	// The old address gets a notice with a link reverting the change.
+	ChangeEmailConfirm(context.Context, *ChangeEmailConfirmRequest) (*ChangeEmailConfirmResponse, error)
+	// ChangePhoneInit sends a verification code to the new phone by SMS.
+	ChangePhoneInit(context.Context, *ChangePhoneInitRequest) (*ChangePhoneInitResponse, error)
+	// ChangePhoneConfirm replaces the phone with the one the code was sent to.
+	ChangePhoneConfirm(context.Context, *ChangePhoneConfirmRequest) (*ChangePhoneConfirmResponse, error)
+	// RequestAccountDeletion schedules the account to be deleted once the grace period is over
+	// and revokes all tokens of the user. Logging in before that cancels the deletion.
+	RequestAccountDeletion(context.Context, *RequestAccountDeletionRequest) (*RequestAccountDeletionResponse, error)
+	// ExportMyData returns everything stored about the user as a JSON document.
+	ExportMyData(context.Context, *ExportMyDataRequest) (*ExportMyDataResponse, error)
+	// AuthorizeApp is the consent step of the authorization flow of a third-party app. Users who granted
+	// the requested scopes before skip it, otherwise consent_required is set until the user approves the scopes.
+	// Apps that registered themselves only get tokens of users who granted them the requested scopes.
+	AuthorizeApp(context.Context, *AuthorizeAppRequest) (*AuthorizeAppResponse, error)
+	// ListAuthorizedApps returns apps the user granted access to.
+	ListAuthorizedApps(context.Context, *ListAuthorizedAppsRequest) (*ListAuthorizedAppsResponse, error)
+	// RevokeAppAccess deletes the user's grant to an app and revokes tokens the app got for the user.
+	RevokeAppAccess(context.Context, *RevokeAppAccessRequest) (*RevokeAppAccessResponse, error)
+	mustEmbedUnimplementedAccountServer()
+}
+
+// UnimplementedAccountServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAccountServer struct{}
+
+func (UnimplementedAccountServer) GetProfile(context.Context, *GetProfileRequest) (*GetProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedAccountServer) UpdateProfile(context.Context, *UpdateProfileRequest) (*UpdateProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
+func (UnimplementedAccountServer) ChangeEmailInit(context.Context, *ChangeEmailInitRequest) (*ChangeEmailInitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeEmailInit not implemented")
+}
+func (UnimplementedAccountServer) ChangeEmailConfirm(context.Context, *ChangeEmailConfirmRequest) (*ChangeEmailConfirmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeEmailConfirm not implemented")
+}
+func (UnimplementedAccountServer) ChangePhoneInit(context.Context, *ChangePhoneInitRequest) (*ChangePhoneInitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePhoneInit not implemented")
+}
+func (UnimplementedAccountServer) ChangePhoneConfirm(context.Context, *ChangePhoneConfirmRequest) (*ChangePhoneConfirmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePhoneConfirm not implemented")
+}
+func (UnimplementedAccountServer) RequestAccountDeletion(context.Context, *RequestAccountDeletionRequest) (*RequestAccountDeletionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestAccountDeletion not implemented")
+}
+func (UnimplementedAccountServer) ExportMyData(context.Context, *ExportMyDataRequest) (*ExportMyDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMyData not implemented")
+}
+func (UnimplementedAccountServer) AuthorizeApp(context.Context, *AuthorizeAppRequest) (*AuthorizeAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthorizeApp not implemented")
+}
+func (UnimplementedAccountServer) ListAuthorizedApps(context.Context, *ListAuthorizedAppsRequest) (*ListAuthorizedAppsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthorizedApps not implemented")
+}
+func (UnimplementedAccountServer) RevokeAppAccess(context.Context, *RevokeAppAccessRequest) (*RevokeAppAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAppAccess not implemented")
+}
+func (UnimplementedAccountServer) mustEmbedUnimplementedAccountServer() {}
+func (UnimplementedAccountServer) testEmbeddedByValue()                 {}
+
+// UnsafeAccountServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccountServer will
+// result in compilation errors.
+type UnsafeAccountServer interface {
+	mustEmbedUnimplementedAccountServer()
+}
+
+func RegisterAccountServer(s grpc.ServiceRegistrar, srv AccountServer) {
+	// If the following call pancis, it indicates UnimplementedAccountServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Account_ServiceDesc, srv)
+}
+
+func _Account_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_GetProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetProfile(ctx, req.(*GetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_UpdateProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).UpdateProfile(ctx, req.(*UpdateProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ChangeEmailInit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeEmailInitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ChangeEmailInit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_ChangeEmailInit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ChangeEmailInit(ctx, req.(*ChangeEmailInitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ChangeEmailConfirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeEmailConfirmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ChangeEmailConfirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_ChangeEmailConfirm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ChangeEmailConfirm(ctx, req.(*ChangeEmailConfirmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ChangePhoneInit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePhoneInitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ChangePhoneInit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_ChangePhoneInit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ChangePhoneInit(ctx, req.(*ChangePhoneInitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ChangePhoneConfirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePhoneConfirmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ChangePhoneConfirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_ChangePhoneConfirm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ChangePhoneConfirm(ctx, req.(*ChangePhoneConfirmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_RequestAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestAccountDeletionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).RequestAccountDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_RequestAccountDeletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).RequestAccountDeletion(ctx, req.(*RequestAccountDeletionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ExportMyData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportMyDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ExportMyData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_ExportMyData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ExportMyData(ctx, req.(*ExportMyDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_AuthorizeApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).AuthorizeApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_AuthorizeApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).AuthorizeApp(ctx, req.(*AuthorizeAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_ListAuthorizedApps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuthorizedAppsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).ListAuthorizedApps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_ListAuthorizedApps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).ListAuthorizedApps(ctx, req.(*ListAuthorizedAppsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_RevokeAppAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAppAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).RevokeAppAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Account_RevokeAppAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).RevokeAppAccess(ctx, req.(*RevokeAppAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Account_ServiceDesc is the grpc.ServiceDesc for Account service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Account_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Account",
+	HandlerType: (*AccountServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProfile",
+			Handler:    _Account_GetProfile_Handler,
+		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _Account_UpdateProfile_Handler,
+		},
+		{
+			MethodName: "ChangeEmailInit",
+			Handler:    _Account_ChangeEmailInit_Handler,
+		},
+		{
+			MethodName: "ChangeEmailConfirm",
+			Handler:    _Account_ChangeEmailConfirm_Handler,
+		},
+		{
+			MethodName: "ChangePhoneInit",
+			Handler:    _Account_ChangePhoneInit_Handler,
+		},
+		{
+			MethodName: "ChangePhoneConfirm",
+			Handler:    _Account_ChangePhoneConfirm_Handler,
+		},
+		{
+			MethodName: "RequestAccountDeletion",
+			Handler:    _Account_RequestAccountDeletion_Handler,
+		},
+		{
+			MethodName: "ExportMyData",
+			Handler:    _Account_ExportMyData_Handler,
+		},
+		{
+			MethodName: "AuthorizeApp",
+			Handler:    _Account_AuthorizeApp_Handler,
+		},
+		{
+			MethodName: "ListAuthorizedApps",
+			Handler:    _Account_ListAuthorizedApps_Handler,
+		},
+		{
+			MethodName: "RevokeAppAccess",
+			Handler:    _Account_RevokeAppAccess_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/account.proto",
+}
@@ -0,0 +1,301 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: sso/attributes.proto
+
+package sso
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetAttributesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 0 for the caller.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAttributesRequest) Reset() {
+	*x = GetAttributesRequest{}
+	mi := &file_sso_attributes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAttributesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAttributesRequest) ProtoMessage() {}
+
+func (x *GetAttributesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_attributes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAttributesRequest.ProtoReflect.Descriptor instead.
+func (*GetAttributesRequest) Descriptor() ([]byte, []int) {
+	return file_sso_attributes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetAttributesRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetAttributesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attributes    map[string]string      `protobuf:"bytes,1,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // JSON values by key.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAttributesResponse) Reset() {
+	*x = GetAttributesResponse{}
+	mi := &file_sso_attributes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAttributesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAttributesResponse) ProtoMessage() {}
+
+func (x *GetAttributesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_attributes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAttributesResponse.ProtoReflect.Descriptor instead.
+func (*GetAttributesResponse) Descriptor() ([]byte, []int) {
+	return file_sso_attributes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAttributesResponse) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type SetAttributesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                                    // 0 for the caller.
+	Attributes    map[string]string      `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // JSON values by key, e.g. "\"en\"" or "{\"id\": 42}".
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAttributesRequest) Reset() {
+	*x = SetAttributesRequest{}
+	mi := &file_sso_attributes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAttributesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAttributesRequest) ProtoMessage() {}
+
+func (x *SetAttributesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_attributes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAttributesRequest.ProtoReflect.Descriptor instead.
+func (*SetAttributesRequest) Descriptor() ([]byte, []int) {
+	return file_sso_attributes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetAttributesRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SetAttributesRequest) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type SetAttributesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attributes    map[string]string      `protobuf:"bytes,1,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAttributesResponse) Reset() {
+	*x = SetAttributesResponse{}
+	mi := &file_sso_attributes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAttributesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAttributesResponse) ProtoMessage() {}
+
+func (x *SetAttributesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_attributes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAttributesResponse.ProtoReflect.Descriptor instead.
+func (*SetAttributesResponse) Descriptor() ([]byte, []int) {
+	return file_sso_attributes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetAttributesResponse) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+var File_sso_attributes_proto protoreflect.FileDescriptor
+
+const file_sso_attributes_proto_rawDesc = "" +
+	"\n" +
+	"\x14sso/attributes.proto\x12\x04auth\"/\n" +
+	"\x14GetAttributesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"\xa3\x01\n" +
+	"\x15GetAttributesResponse\x12K\n" +
+	"\n" +
+	"attributes\x18\x01 \x03(\v2+.auth.GetAttributesResponse.AttributesEntryR\n" +
+	"attributes\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xba\x01\n" +
+	"\x14SetAttributesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12J\n" +
+	"\n" +
+	"attributes\x18\x02 \x03(\v2*.auth.SetAttributesRequest.AttributesEntryR\n" +
+	"attributes\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa3\x01\n" +
+	"\x15SetAttributesResponse\x12K\n" +
+	"\n" +
+	"attributes\x18\x01 \x03(\v2+.auth.SetAttributesResponse.AttributesEntryR\n" +
+	"attributes\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xa0\x01\n" +
+	"\n" +
+	"Attributes\x12H\n" +
+	"\rGetAttributes\x12\x1a.auth.GetAttributesRequest\x1a\x1b.auth.GetAttributesResponse\x12H\n" +
+	"\rSetAttributes\x12\x1a.auth.SetAttributesRequest\x1a\x1b.auth.SetAttributesResponseB$Z\"github.com/Abazin97/sso/gen/go/ssob\x06proto3"
+
+var (
+	file_sso_attributes_proto_rawDescOnce sync.Once
+	file_sso_attributes_proto_rawDescData []byte
+)
+
+func file_sso_attributes_proto_rawDescGZIP() []byte {
+	file_sso_attributes_proto_rawDescOnce.Do(func() {
+		file_sso_attributes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sso_attributes_proto_rawDesc), len(file_sso_attributes_proto_rawDesc)))
+	})
+	return file_sso_attributes_proto_rawDescData
+}
+
+var file_sso_attributes_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_sso_attributes_proto_goTypes = []any{
+	(*GetAttributesRequest)(nil),  // 0: auth.GetAttributesRequest
+	(*GetAttributesResponse)(nil), // 1: auth.GetAttributesResponse
+	(*SetAttributesRequest)(nil),  // 2: auth.SetAttributesRequest
+	(*SetAttributesResponse)(nil), // 3: auth.SetAttributesResponse
+	nil,                           // 4: auth.GetAttributesResponse.AttributesEntry
+	nil,                           // 5: auth.SetAttributesRequest.AttributesEntry
+	nil,                           // 6: auth.SetAttributesResponse.AttributesEntry
+}
+var file_sso_attributes_proto_depIdxs = []int32{
+	4, // 0: auth.GetAttributesResponse.attributes:type_name -> auth.GetAttributesResponse.AttributesEntry
+	5, // 1: auth.SetAttributesRequest.attributes:type_name -> auth.SetAttributesRequest.AttributesEntry
+	6, // 2: auth.SetAttributesResponse.attributes:type_name -> auth.SetAttributesResponse.AttributesEntry
+	0, // 3: auth.Attributes.GetAttributes:input_type -> auth.GetAttributesRequest
+	2, // 4: auth.Attributes.SetAttributes:input_type -> auth.SetAttributesRequest
+	1, // 5: auth.Attributes.GetAttributes:output_type -> auth.GetAttributesResponse
+	3, // 6: auth.Attributes.SetAttributes:output_type -> auth.SetAttributesResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_sso_attributes_proto_init() }
+func file_sso_attributes_proto_init() {
+	if File_sso_attributes_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sso_attributes_proto_rawDesc), len(file_sso_attributes_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sso_attributes_proto_goTypes,
+		DependencyIndexes: file_sso_attributes_proto_depIdxs,
+		MessageInfos:      file_sso_attributes_proto_msgTypes,
+	}.Build()
+	File_sso_attributes_proto = out.File
+	file_sso_attributes_proto_goTypes = nil
+	file_sso_attributes_proto_depIdxs = nil
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.0
+// source: sso/attributes.proto
+
+package sso
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Attributes_GetAttributes_FullMethodName = "/auth.Attributes/GetAttributes"
+	Attributes_SetAttributes_FullMethodName = "/auth.Attributes/SetAttributes"
+)
+
+// AttributesClient is the client API for Attributes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Attributes is service for custom data apps attach to their users. Every call requires a token
+// passed as "authorization: Bearer <token>" metadata and works with attributes of the token's app.
+// Users can read their own attributes, holders of the attributes or admin permission in the app,
+// e.g. service accounts of its backend, can read and write anyone's.
+type AttributesClient interface {
+	GetAttributes(ctx context.Context, in *GetAttributesRequest, opts ...grpc.CallOption) (*GetAttributesResponse, error)
+	// SetAttributes merges attributes into the stored ones and returns the result.
+	// Attributes set to JSON null are deleted. If the app has an attributes schema,
+	// the result must match it.
+	SetAttributes(ctx context.Context, in *SetAttributesRequest, opts ...grpc.CallOption) (*SetAttributesResponse, error)
+}
+
+type attributesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAttributesClient(cc grpc.ClientConnInterface) AttributesClient {
+	return &attributesClient{cc}
+}
+
+func (c *attributesClient) GetAttributes(ctx context.Context, in *GetAttributesRequest, opts ...grpc.CallOption) (*GetAttributesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAttributesResponse)
+	err := c.cc.Invoke(ctx, Attributes_GetAttributes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *attributesClient) SetAttributes(ctx context.Context, in *SetAttributesRequest, opts ...grpc.CallOption) (*SetAttributesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetAttributesResponse)
+	err := c.cc.Invoke(ctx, Attributes_SetAttributes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AttributesServer is the server API for Attributes service.
+// All implementations must embed UnimplementedAttributesServer
+// for forward compatibility.
+//
+// Attributes is service for custom data apps attach to their users. Every call requires a token
+// passed as "authorization: Bearer <token>" metadata and works with attributes of the token's app.
+// Users can read their own attributes, holders of the attributes or admin permission in the app,
+// e.g. service accounts of its backend, can read and write anyone's.
+type AttributesServer interface {
+	GetAttributes(context.Context, *GetAttributesRequest) (*GetAttributesResponse, error)
+	// SetAttributes merges attributes into the stored ones and returns the result.
+	// Attributes set to JSON null are deleted. If the app has an attributes schema,
+	// the result must match it.
+	SetAttributes(context.Context, *SetAttributesRequest) (*SetAttributesResponse, error)
+	mustEmbedUnimplementedAttributesServer()
+}
+
+// UnimplementedAttributesServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAttributesServer struct{}
+
+func (UnimplementedAttributesServer) GetAttributes(context.Context, *GetAttributesRequest) (*GetAttributesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttributes not implemented")
+}
+func (UnimplementedAttributesServer) SetAttributes(context.Context, *SetAttributesRequest) (*SetAttributesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAttributes not implemented")
+}
+func (UnimplementedAttributesServer) mustEmbedUnimplementedAttributesServer() {}
+func (UnimplementedAttributesServer) testEmbeddedByValue()                    {}
+
+// UnsafeAttributesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AttributesServer will
+// result in compilation errors.
+type UnsafeAttributesServer interface {
+	mustEmbedUnimplementedAttributesServer()
+}
+
+func RegisterAttributesServer(s grpc.ServiceRegistrar, srv AttributesServer) {
+	// If the following call pancis, it indicates UnimplementedAttributesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Attributes_ServiceDesc, srv)
+}
+
+func _Attributes_GetAttributes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAttributesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttributesServer).GetAttributes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Attributes_GetAttributes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttributesServer).GetAttributes(ctx, req.(*GetAttributesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Attributes_SetAttributes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAttributesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttributesServer).SetAttributes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Attributes_SetAttributes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttributesServer).SetAttributes(ctx, req.(*SetAttributesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Attributes_ServiceDesc is the grpc.ServiceDesc for Attributes service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Attributes_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Attributes",
+	HandlerType: (*AttributesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAttributes",
+			Handler:    _Attributes_GetAttributes_Handler,
+		},
+		{
+			MethodName: "SetAttributes",
+			Handler:    _Attributes_SetAttributes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/attributes.proto",
+}
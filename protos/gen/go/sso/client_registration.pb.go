@@ -0,0 +1,503 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: sso/client_registration.proto
+
+package sso
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ClientMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientName    string                 `protobuf:"bytes,1,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	RedirectUris  []string               `protobuf:"bytes,2,rep,name=redirect_uris,json=redirectUris,proto3" json:"redirect_uris,omitempty"` // Required for the authorization_code grant.
+	GrantTypes    []string               `protobuf:"bytes,3,rep,name=grant_types,json=grantTypes,proto3" json:"grant_types,omitempty"`       // authorization_code, refresh_token or client_credentials; authorization_code if empty.
+	LogoUri       string                 `protobuf:"bytes,4,opt,name=logo_uri,json=logoUri,proto3" json:"logo_uri,omitempty"`
+	Contacts      []string               `protobuf:"bytes,5,rep,name=contacts,proto3" json:"contacts,omitempty"` // Emails of people responsible for the client.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientMetadata) Reset() {
+	*x = ClientMetadata{}
+	mi := &file_sso_client_registration_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientMetadata) ProtoMessage() {}
+
+func (x *ClientMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_client_registration_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientMetadata.ProtoReflect.Descriptor instead.
+func (*ClientMetadata) Descriptor() ([]byte, []int) {
+	return file_sso_client_registration_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClientMetadata) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *ClientMetadata) GetRedirectUris() []string {
+	if x != nil {
+		return x.RedirectUris
+	}
+	return nil
+}
+
+func (x *ClientMetadata) GetGrantTypes() []string {
+	if x != nil {
+		return x.GrantTypes
+	}
+	return nil
+}
+
+func (x *ClientMetadata) GetLogoUri() string {
+	if x != nil {
+		return x.LogoUri
+	}
+	return ""
+}
+
+func (x *ClientMetadata) GetContacts() []string {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+type ClientInformation struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	ClientId                int32                  `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret            string                 `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`                                    // Only returned by RegisterClient.
+	ClientIdIssuedAt        int64                  `protobuf:"varint,3,opt,name=client_id_issued_at,json=clientIdIssuedAt,proto3" json:"client_id_issued_at,omitempty"`                   // Unix time.
+	ClientSecretExpiresAt   int64                  `protobuf:"varint,4,opt,name=client_secret_expires_at,json=clientSecretExpiresAt,proto3" json:"client_secret_expires_at,omitempty"`    // Always 0, secrets don't expire.
+	RegistrationAccessToken string                 `protobuf:"bytes,5,opt,name=registration_access_token,json=registrationAccessToken,proto3" json:"registration_access_token,omitempty"` // Only returned by RegisterClient.
+	Metadata                *ClientMetadata        `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *ClientInformation) Reset() {
+	*x = ClientInformation{}
+	mi := &file_sso_client_registration_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientInformation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientInformation) ProtoMessage() {}
+
+func (x *ClientInformation) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_client_registration_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientInformation.ProtoReflect.Descriptor instead.
+func (*ClientInformation) Descriptor() ([]byte, []int) {
+	return file_sso_client_registration_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ClientInformation) GetClientId() int32 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+func (x *ClientInformation) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *ClientInformation) GetClientIdIssuedAt() int64 {
+	if x != nil {
+		return x.ClientIdIssuedAt
+	}
+	return 0
+}
+
+func (x *ClientInformation) GetClientSecretExpiresAt() int64 {
+	if x != nil {
+		return x.ClientSecretExpiresAt
+	}
+	return 0
+}
+
+func (x *ClientInformation) GetRegistrationAccessToken() string {
+	if x != nil {
+		return x.RegistrationAccessToken
+	}
+	return ""
+}
+
+func (x *ClientInformation) GetMetadata() *ClientMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type RegisterClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *ClientMetadata        `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterClientRequest) Reset() {
+	*x = RegisterClientRequest{}
+	mi := &file_sso_client_registration_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterClientRequest) ProtoMessage() {}
+
+func (x *RegisterClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_client_registration_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterClientRequest.ProtoReflect.Descriptor instead.
+func (*RegisterClientRequest) Descriptor() ([]byte, []int) {
+	return file_sso_client_registration_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisterClientRequest) GetMetadata() *ClientMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type GetClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      int32                  `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClientRequest) Reset() {
+	*x = GetClientRequest{}
+	mi := &file_sso_client_registration_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClientRequest) ProtoMessage() {}
+
+func (x *GetClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_client_registration_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClientRequest.ProtoReflect.Descriptor instead.
+func (*GetClientRequest) Descriptor() ([]byte, []int) {
+	return file_sso_client_registration_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetClientRequest) GetClientId() int32 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+type UpdateClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      int32                  `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Metadata      *ClientMetadata        `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateClientRequest) Reset() {
+	*x = UpdateClientRequest{}
+	mi := &file_sso_client_registration_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateClientRequest) ProtoMessage() {}
+
+func (x *UpdateClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_client_registration_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateClientRequest.ProtoReflect.Descriptor instead.
+func (*UpdateClientRequest) Descriptor() ([]byte, []int) {
+	return file_sso_client_registration_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateClientRequest) GetClientId() int32 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+func (x *UpdateClientRequest) GetMetadata() *ClientMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type DeleteClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      int32                  `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteClientRequest) Reset() {
+	*x = DeleteClientRequest{}
+	mi := &file_sso_client_registration_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteClientRequest) ProtoMessage() {}
+
+func (x *DeleteClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_client_registration_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteClientRequest.ProtoReflect.Descriptor instead.
+func (*DeleteClientRequest) Descriptor() ([]byte, []int) {
+	return file_sso_client_registration_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteClientRequest) GetClientId() int32 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+type DeleteClientResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteClientResponse) Reset() {
+	*x = DeleteClientResponse{}
+	mi := &file_sso_client_registration_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteClientResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteClientResponse) ProtoMessage() {}
+
+func (x *DeleteClientResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_client_registration_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteClientResponse.ProtoReflect.Descriptor instead.
+func (*DeleteClientResponse) Descriptor() ([]byte, []int) {
+	return file_sso_client_registration_proto_rawDescGZIP(), []int{6}
+}
+
+var File_sso_client_registration_proto protoreflect.FileDescriptor
+
+const file_sso_client_registration_proto_rawDesc = "" +
+	"\n" +
+	"\x1dsso/client_registration.proto\x12\x04auth\"\xae\x01\n" +
+	"\x0eClientMetadata\x12\x1f\n" +
+	"\vclient_name\x18\x01 \x01(\tR\n" +
+	"clientName\x12#\n" +
+	"\rredirect_uris\x18\x02 \x03(\tR\fredirectUris\x12\x1f\n" +
+	"\vgrant_types\x18\x03 \x03(\tR\n" +
+	"grantTypes\x12\x19\n" +
+	"\blogo_uri\x18\x04 \x01(\tR\alogoUri\x12\x1a\n" +
+	"\bcontacts\x18\x05 \x03(\tR\bcontacts\"\xab\x02\n" +
+	"\x11ClientInformation\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\x05R\bclientId\x12#\n" +
+	"\rclient_secret\x18\x02 \x01(\tR\fclientSecret\x12-\n" +
+	"\x13client_id_issued_at\x18\x03 \x01(\x03R\x10clientIdIssuedAt\x127\n" +
+	"\x18client_secret_expires_at\x18\x04 \x01(\x03R\x15clientSecretExpiresAt\x12:\n" +
+	"\x19registration_access_token\x18\x05 \x01(\tR\x17registrationAccessToken\x120\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x14.auth.ClientMetadataR\bmetadata\"I\n" +
+	"\x15RegisterClientRequest\x120\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x14.auth.ClientMetadataR\bmetadata\"/\n" +
+	"\x10GetClientRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\x05R\bclientId\"d\n" +
+	"\x13UpdateClientRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\x05R\bclientId\x120\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x14.auth.ClientMetadataR\bmetadata\"2\n" +
+	"\x13DeleteClientRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\x05R\bclientId\"\x16\n" +
+	"\x14DeleteClientResponse2\xa5\x02\n" +
+	"\x12ClientRegistration\x12F\n" +
+	"\x0eRegisterClient\x12\x1b.auth.RegisterClientRequest\x1a\x17.auth.ClientInformation\x12<\n" +
+	"\tGetClient\x12\x16.auth.GetClientRequest\x1a\x17.auth.ClientInformation\x12B\n" +
+	"\fUpdateClient\x12\x19.auth.UpdateClientRequest\x1a\x17.auth.ClientInformation\x12E\n" +
+	"\fDeleteClient\x12\x19.auth.DeleteClientRequest\x1a\x1a.auth.DeleteClientResponseB$Z\"github.com/Abazin97/sso/gen/go/ssob\x06proto3"
+
+var (
+	file_sso_client_registration_proto_rawDescOnce sync.Once
+	file_sso_client_registration_proto_rawDescData []byte
+)
+
+func file_sso_client_registration_proto_rawDescGZIP() []byte {
+	file_sso_client_registration_proto_rawDescOnce.Do(func() {
+		file_sso_client_registration_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sso_client_registration_proto_rawDesc), len(file_sso_client_registration_proto_rawDesc)))
+	})
+	return file_sso_client_registration_proto_rawDescData
+}
+
+var file_sso_client_registration_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_sso_client_registration_proto_goTypes = []any{
+	(*ClientMetadata)(nil),        // 0: auth.ClientMetadata
+	(*ClientInformation)(nil),     // 1: auth.ClientInformation
+	(*RegisterClientRequest)(nil), // 2: auth.RegisterClientRequest
+	(*GetClientRequest)(nil),      // 3: auth.GetClientRequest
+	(*UpdateClientRequest)(nil),   // 4: auth.UpdateClientRequest
+	(*DeleteClientRequest)(nil),   // 5: auth.DeleteClientRequest
+	(*DeleteClientResponse)(nil),  // 6: auth.DeleteClientResponse
+}
+var file_sso_client_registration_proto_depIdxs = []int32{
+	0, // 0: auth.ClientInformation.metadata:type_name -> auth.ClientMetadata
+	0, // 1: auth.RegisterClientRequest.metadata:type_name -> auth.ClientMetadata
+	0, // 2: auth.UpdateClientRequest.metadata:type_name -> auth.ClientMetadata
+	2, // 3: auth.ClientRegistration.RegisterClient:input_type -> auth.RegisterClientRequest
+	3, // 4: auth.ClientRegistration.GetClient:input_type -> auth.GetClientRequest
+	4, // 5: auth.ClientRegistration.UpdateClient:input_type -> auth.UpdateClientRequest
+	5, // 6: auth.ClientRegistration.DeleteClient:input_type -> auth.DeleteClientRequest
+	1, // 7: auth.ClientRegistration.RegisterClient:output_type -> auth.ClientInformation
+	1, // 8: auth.ClientRegistration.GetClient:output_type -> auth.ClientInformation
+	1, // 9: auth.ClientRegistration.UpdateClient:output_type -> auth.ClientInformation
+	6, // 10: auth.ClientRegistration.DeleteClient:output_type -> auth.DeleteClientResponse
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_sso_client_registration_proto_init() }
+func file_sso_client_registration_proto_init() {
+	if File_sso_client_registration_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sso_client_registration_proto_rawDesc), len(file_sso_client_registration_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sso_client_registration_proto_goTypes,
+		DependencyIndexes: file_sso_client_registration_proto_depIdxs,
+		MessageInfos:      file_sso_client_registration_proto_msgTypes,
+	}.Build()
+	File_sso_client_registration_proto = out.File
+	file_sso_client_registration_proto_goTypes = nil
+	file_sso_client_registration_proto_depIdxs = nil
+}
@@ -0,0 +1,1293 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: sso/service_accounts.proto
+
+package sso
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ServiceAccount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	AppId         int32                  `protobuf:"varint,4,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"` // ID of the app owning the account, 0 if an organization owns it.
+	OrgId         int64                  `protobuf:"varint,5,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"` // ID of the organization owning the account, 0 if an app owns it.
+	ClientId      string                 `protobuf:"bytes,6,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Disabled      bool                   `protobuf:"varint,7,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339 time.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceAccount) Reset() {
+	*x = ServiceAccount{}
+	mi := &file_sso_service_accounts_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceAccount) ProtoMessage() {}
+
+func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceAccount.ProtoReflect.Descriptor instead.
+func (*ServiceAccount) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ServiceAccount) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ServiceAccount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *ServiceAccount) GetOrgId() int64 {
+	if x != nil {
+		return x.OrgId
+	}
+	return 0
+}
+
+func (x *ServiceAccount) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ServiceAccount) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *ServiceAccount) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateServiceAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	AppId         int32                  `protobuf:"varint,3,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"` // Exactly one of app_id and org_id must be set.
+	OrgId         int64                  `protobuf:"varint,4,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountRequest) Reset() {
+	*x = CreateServiceAccountRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountRequest) ProtoMessage() {}
+
+func (x *CreateServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateServiceAccountRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountRequest) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *CreateServiceAccountRequest) GetOrgId() int64 {
+	if x != nil {
+		return x.OrgId
+	}
+	return 0
+}
+
+type CreateServiceAccountResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccount *ServiceAccount        `protobuf:"bytes,1,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	ClientSecret   string                 `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountResponse) Reset() {
+	*x = CreateServiceAccountResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountResponse) ProtoMessage() {}
+
+func (x *CreateServiceAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountResponse.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateServiceAccountResponse) GetServiceAccount() *ServiceAccount {
+	if x != nil {
+		return x.ServiceAccount
+	}
+	return nil
+}
+
+func (x *CreateServiceAccountResponse) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+type ListServiceAccountsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServiceAccountsRequest) Reset() {
+	*x = ListServiceAccountsRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceAccountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceAccountsRequest) ProtoMessage() {}
+
+func (x *ListServiceAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceAccountsRequest.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{3}
+}
+
+type ListServiceAccountsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccounts []*ServiceAccount      `protobuf:"bytes,1,rep,name=service_accounts,json=serviceAccounts,proto3" json:"service_accounts,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListServiceAccountsResponse) Reset() {
+	*x = ListServiceAccountsResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceAccountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceAccountsResponse) ProtoMessage() {}
+
+func (x *ListServiceAccountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceAccountsResponse.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountsResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListServiceAccountsResponse) GetServiceAccounts() []*ServiceAccount {
+	if x != nil {
+		return x.ServiceAccounts
+	}
+	return nil
+}
+
+type GetServiceAccountRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetServiceAccountRequest) Reset() {
+	*x = GetServiceAccountRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceAccountRequest) ProtoMessage() {}
+
+func (x *GetServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetServiceAccountRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+type GetServiceAccountResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccount *ServiceAccount        `protobuf:"bytes,1,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetServiceAccountResponse) Reset() {
+	*x = GetServiceAccountResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceAccountResponse) ProtoMessage() {}
+
+func (x *GetServiceAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceAccountResponse.ProtoReflect.Descriptor instead.
+func (*GetServiceAccountResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetServiceAccountResponse) GetServiceAccount() *ServiceAccount {
+	if x != nil {
+		return x.ServiceAccount
+	}
+	return nil
+}
+
+type UpdateServiceAccountRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description      string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Disabled         bool                   `protobuf:"varint,4,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateServiceAccountRequest) Reset() {
+	*x = UpdateServiceAccountRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServiceAccountRequest) ProtoMessage() {}
+
+func (x *UpdateServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*UpdateServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateServiceAccountRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+func (x *UpdateServiceAccountRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateServiceAccountRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateServiceAccountRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type UpdateServiceAccountResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccount *ServiceAccount        `protobuf:"bytes,1,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateServiceAccountResponse) Reset() {
+	*x = UpdateServiceAccountResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServiceAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServiceAccountResponse) ProtoMessage() {}
+
+func (x *UpdateServiceAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServiceAccountResponse.ProtoReflect.Descriptor instead.
+func (*UpdateServiceAccountResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateServiceAccountResponse) GetServiceAccount() *ServiceAccount {
+	if x != nil {
+		return x.ServiceAccount
+	}
+	return nil
+}
+
+type DeleteServiceAccountRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeleteServiceAccountRequest) Reset() {
+	*x = DeleteServiceAccountRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceAccountRequest) ProtoMessage() {}
+
+func (x *DeleteServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteServiceAccountRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+type DeleteServiceAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceAccountResponse) Reset() {
+	*x = DeleteServiceAccountResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceAccountResponse) ProtoMessage() {}
+
+func (x *DeleteServiceAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceAccountResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServiceAccountResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteServiceAccountResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RotateServiceAccountSecretRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RotateServiceAccountSecretRequest) Reset() {
+	*x = RotateServiceAccountSecretRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateServiceAccountSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateServiceAccountSecretRequest) ProtoMessage() {}
+
+func (x *RotateServiceAccountSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateServiceAccountSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateServiceAccountSecretRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RotateServiceAccountSecretRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+type RotateServiceAccountSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientSecret  string                 `protobuf:"bytes,1,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateServiceAccountSecretResponse) Reset() {
+	*x = RotateServiceAccountSecretResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateServiceAccountSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateServiceAccountSecretResponse) ProtoMessage() {}
+
+func (x *RotateServiceAccountSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateServiceAccountSecretResponse.ProtoReflect.Descriptor instead.
+func (*RotateServiceAccountSecretResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RotateServiceAccountSecretResponse) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+type SetServiceAccountRolesRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	AppId            int32                  `protobuf:"varint,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"` // The app must be accessible to the account.
+	Roles            []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SetServiceAccountRolesRequest) Reset() {
+	*x = SetServiceAccountRolesRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetServiceAccountRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetServiceAccountRolesRequest) ProtoMessage() {}
+
+func (x *SetServiceAccountRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetServiceAccountRolesRequest.ProtoReflect.Descriptor instead.
+func (*SetServiceAccountRolesRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SetServiceAccountRolesRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+func (x *SetServiceAccountRolesRequest) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *SetServiceAccountRolesRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type SetServiceAccountRolesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roles         []string               `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetServiceAccountRolesResponse) Reset() {
+	*x = SetServiceAccountRolesResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetServiceAccountRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetServiceAccountRolesResponse) ProtoMessage() {}
+
+func (x *SetServiceAccountRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetServiceAccountRolesResponse.ProtoReflect.Descriptor instead.
+func (*SetServiceAccountRolesResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SetServiceAccountRolesResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type CreateServiceAccountAPIKeyRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	AppId            int32                  `protobuf:"varint,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"` // The app must be accessible to the account.
+	Name             string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes           []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ExpiresIn        int64                  `protobuf:"varint,5,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"` // Lifetime of the key in seconds, the configured default if 0.
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountAPIKeyRequest) Reset() {
+	*x = CreateServiceAccountAPIKeyRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateServiceAccountAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateServiceAccountAPIKeyRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+func (x *CreateServiceAccountAPIKeyRequest) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *CreateServiceAccountAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateServiceAccountAPIKeyRequest) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+type CreateServiceAccountAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountAPIKeyResponse) Reset() {
+	*x = CreateServiceAccountAPIKeyResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateServiceAccountAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateServiceAccountAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateServiceAccountAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListServiceAccountAPIKeysRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListServiceAccountAPIKeysRequest) Reset() {
+	*x = ListServiceAccountAPIKeysRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceAccountAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceAccountAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListServiceAccountAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceAccountAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListServiceAccountAPIKeysRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+type ListServiceAccountAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServiceAccountAPIKeysResponse) Reset() {
+	*x = ListServiceAccountAPIKeysResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServiceAccountAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServiceAccountAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListServiceAccountAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServiceAccountAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListServiceAccountAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListServiceAccountAPIKeysResponse) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeServiceAccountAPIKeyRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceAccountId int64                  `protobuf:"varint,1,opt,name=service_account_id,json=serviceAccountId,proto3" json:"service_account_id,omitempty"`
+	ApiKeyId         int64                  `protobuf:"varint,2,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RevokeServiceAccountAPIKeyRequest) Reset() {
+	*x = RevokeServiceAccountAPIKeyRequest{}
+	mi := &file_sso_service_accounts_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeServiceAccountAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeServiceAccountAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeServiceAccountAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeServiceAccountAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeServiceAccountAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RevokeServiceAccountAPIKeyRequest) GetServiceAccountId() int64 {
+	if x != nil {
+		return x.ServiceAccountId
+	}
+	return 0
+}
+
+func (x *RevokeServiceAccountAPIKeyRequest) GetApiKeyId() int64 {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return 0
+}
+
+type RevokeServiceAccountAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeServiceAccountAPIKeyResponse) Reset() {
+	*x = RevokeServiceAccountAPIKeyResponse{}
+	mi := &file_sso_service_accounts_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeServiceAccountAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeServiceAccountAPIKeyResponse) ProtoMessage() {}
+
+func (x *RevokeServiceAccountAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_service_accounts_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeServiceAccountAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeServiceAccountAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_sso_service_accounts_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RevokeServiceAccountAPIKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+var File_sso_service_accounts_proto protoreflect.FileDescriptor
+
+const file_sso_service_accounts_proto_rawDesc = "" +
+	"\n" +
+	"\x1asso/service_accounts.proto\x12\x04auth\x1a\x12sso/api_keys.proto\"\xdc\x01\n" +
+	"\x0eServiceAccount\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x15\n" +
+	"\x06app_id\x18\x04 \x01(\x05R\x05appId\x12\x15\n" +
+	"\x06org_id\x18\x05 \x01(\x03R\x05orgId\x12\x1b\n" +
+	"\tclient_id\x18\x06 \x01(\tR\bclientId\x12\x1a\n" +
+	"\bdisabled\x18\a \x01(\bR\bdisabled\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\"\x81\x01\n" +
+	"\x1bCreateServiceAccountRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x15\n" +
+	"\x06app_id\x18\x03 \x01(\x05R\x05appId\x12\x15\n" +
+	"\x06org_id\x18\x04 \x01(\x03R\x05orgId\"\x82\x01\n" +
+	"\x1cCreateServiceAccountResponse\x12=\n" +
+	"\x0fservice_account\x18\x01 \x01(\v2\x14.auth.ServiceAccountR\x0eserviceAccount\x12#\n" +
+	"\rclient_secret\x18\x02 \x01(\tR\fclientSecret\"\x1c\n" +
+	"\x1aListServiceAccountsRequest\"^\n" +
+	"\x1bListServiceAccountsResponse\x12?\n" +
+	"\x10service_accounts\x18\x01 \x03(\v2\x14.auth.ServiceAccountR\x0fserviceAccounts\"H\n" +
+	"\x18GetServiceAccountRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\"Z\n" +
+	"\x19GetServiceAccountResponse\x12=\n" +
+	"\x0fservice_account\x18\x01 \x01(\v2\x14.auth.ServiceAccountR\x0eserviceAccount\"\x9d\x01\n" +
+	"\x1bUpdateServiceAccountRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bdisabled\x18\x04 \x01(\bR\bdisabled\"]\n" +
+	"\x1cUpdateServiceAccountResponse\x12=\n" +
+	"\x0fservice_account\x18\x01 \x01(\v2\x14.auth.ServiceAccountR\x0eserviceAccount\"K\n" +
+	"\x1bDeleteServiceAccountRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\"8\n" +
+	"\x1cDeleteServiceAccountResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"Q\n" +
+	"!RotateServiceAccountSecretRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\"I\n" +
+	"\"RotateServiceAccountSecretResponse\x12#\n" +
+	"\rclient_secret\x18\x01 \x01(\tR\fclientSecret\"z\n" +
+	"\x1dSetServiceAccountRolesRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\x05R\x05appId\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\"6\n" +
+	"\x1eSetServiceAccountRolesResponse\x12\x14\n" +
+	"\x05roles\x18\x01 \x03(\tR\x05roles\"\xb3\x01\n" +
+	"!CreateServiceAccountAPIKeyRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\x05R\x05appId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x05 \x01(\x03R\texpiresIn\"]\n" +
+	"\"CreateServiceAccountAPIKeyResponse\x12%\n" +
+	"\aapi_key\x18\x01 \x01(\v2\f.auth.APIKeyR\x06apiKey\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"P\n" +
+	" ListServiceAccountAPIKeysRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\"L\n" +
+	"!ListServiceAccountAPIKeysResponse\x12'\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\f.auth.APIKeyR\aapiKeys\"o\n" +
+	"!RevokeServiceAccountAPIKeyRequest\x12,\n" +
+	"\x12service_account_id\x18\x01 \x01(\x03R\x10serviceAccountId\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x02 \x01(\x03R\bapiKeyId\">\n" +
+	"\"RevokeServiceAccountAPIKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess2\x86\b\n" +
+	"\x0fServiceAccounts\x12]\n" +
+	"\x14CreateServiceAccount\x12!.auth.CreateServiceAccountRequest\x1a\".auth.CreateServiceAccountResponse\x12Z\n" +
+	"\x13ListServiceAccounts\x12 .auth.ListServiceAccountsRequest\x1a!.auth.ListServiceAccountsResponse\x12T\n" +
+	"\x11GetServiceAccount\x12\x1e.auth.GetServiceAccountRequest\x1a\x1f.auth.GetServiceAccountResponse\x12]\n" +
+	"\x14UpdateServiceAccount\x12!.auth.UpdateServiceAccountRequest\x1a\".auth.UpdateServiceAccountResponse\x12]\n" +
+	"\x14DeleteServiceAccount\x12!.auth.DeleteServiceAccountRequest\x1a\".auth.DeleteServiceAccountResponse\x12o\n" +
+	"\x1aRotateServiceAccountSecret\x12'.auth.RotateServiceAccountSecretRequest\x1a(.auth.RotateServiceAccountSecretResponse\x12c\n" +
+	"\x16SetServiceAccountRoles\x12#.auth.SetServiceAccountRolesRequest\x1a$.auth.SetServiceAccountRolesResponse\x12o\n" +
+	"\x1aCreateServiceAccountAPIKey\x12'.auth.CreateServiceAccountAPIKeyRequest\x1a(.auth.CreateServiceAccountAPIKeyResponse\x12l\n" +
+	"\x19ListServiceAccountAPIKeys\x12&.auth.ListServiceAccountAPIKeysRequest\x1a'.auth.ListServiceAccountAPIKeysResponse\x12o\n" +
+	"\x1aRevokeServiceAccountAPIKey\x12'.auth.RevokeServiceAccountAPIKeyRequest\x1a(.auth.RevokeServiceAccountAPIKeyResponseB$Z\"github.com/Abazin97/sso/gen/go/ssob\x06proto3"
+
+var (
+	file_sso_service_accounts_proto_rawDescOnce sync.Once
+	file_sso_service_accounts_proto_rawDescData []byte
+)
+
+func file_sso_service_accounts_proto_rawDescGZIP() []byte {
+	file_sso_service_accounts_proto_rawDescOnce.Do(func() {
+		file_sso_service_accounts_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sso_service_accounts_proto_rawDesc), len(file_sso_service_accounts_proto_rawDesc)))
+	})
+	return file_sso_service_accounts_proto_rawDescData
+}
+
+var file_sso_service_accounts_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_sso_service_accounts_proto_goTypes = []any{
+	(*ServiceAccount)(nil),                     // 0: auth.ServiceAccount
+	(*CreateServiceAccountRequest)(nil),        // 1: auth.CreateServiceAccountRequest
+	(*CreateServiceAccountResponse)(nil),       // 2: auth.CreateServiceAccountResponse
+	(*ListServiceAccountsRequest)(nil),         // 3: auth.ListServiceAccountsRequest
+	(*ListServiceAccountsResponse)(nil),        // 4: auth.ListServiceAccountsResponse
+	(*GetServiceAccountRequest)(nil),           // 5: auth.GetServiceAccountRequest
+	(*GetServiceAccountResponse)(nil),          // 6: auth.GetServiceAccountResponse
+	(*UpdateServiceAccountRequest)(nil),        // 7: auth.UpdateServiceAccountRequest
+	(*UpdateServiceAccountResponse)(nil),       // 8: auth.UpdateServiceAccountResponse
+	(*DeleteServiceAccountRequest)(nil),        // 9: auth.DeleteServiceAccountRequest
+	(*DeleteServiceAccountResponse)(nil),       // 10: auth.DeleteServiceAccountResponse
+	(*RotateServiceAccountSecretRequest)(nil),  // 11: auth.RotateServiceAccountSecretRequest
+	(*RotateServiceAccountSecretResponse)(nil), // 12: auth.RotateServiceAccountSecretResponse
+	(*SetServiceAccountRolesRequest)(nil),      // 13: auth.SetServiceAccountRolesRequest
+	(*SetServiceAccountRolesResponse)(nil),     // 14: auth.SetServiceAccountRolesResponse
+	(*CreateServiceAccountAPIKeyRequest)(nil),  // 15: auth.CreateServiceAccountAPIKeyRequest
+	(*CreateServiceAccountAPIKeyResponse)(nil), // 16: auth.CreateServiceAccountAPIKeyResponse
+	(*ListServiceAccountAPIKeysRequest)(nil),   // 17: auth.ListServiceAccountAPIKeysRequest
+	(*ListServiceAccountAPIKeysResponse)(nil),  // 18: auth.ListServiceAccountAPIKeysResponse
+	(*RevokeServiceAccountAPIKeyRequest)(nil),  // 19: auth.RevokeServiceAccountAPIKeyRequest
+	(*RevokeServiceAccountAPIKeyResponse)(nil), // 20: auth.RevokeServiceAccountAPIKeyResponse
+	(*APIKey)(nil), // 21: auth.APIKey
+}
+var file_sso_service_accounts_proto_depIdxs = []int32{
+	0,  // 0: auth.CreateServiceAccountResponse.service_account:type_name -> auth.ServiceAccount
+	0,  // 1: auth.ListServiceAccountsResponse.service_accounts:type_name -> auth.ServiceAccount
+	0,  // 2: auth.GetServiceAccountResponse.service_account:type_name -> auth.ServiceAccount
+	0,  // 3: auth.UpdateServiceAccountResponse.service_account:type_name -> auth.ServiceAccount
+	21, // 4: auth.CreateServiceAccountAPIKeyResponse.api_key:type_name -> auth.APIKey
+	21, // 5: auth.ListServiceAccountAPIKeysResponse.api_keys:type_name -> auth.APIKey
+	1,  // 6: auth.ServiceAccounts.CreateServiceAccount:input_type -> auth.CreateServiceAccountRequest
+	3,  // 7: auth.ServiceAccounts.ListServiceAccounts:input_type -> auth.ListServiceAccountsRequest
+	5,  // 8: auth.ServiceAccounts.GetServiceAccount:input_type -> auth.GetServiceAccountRequest
+	7,  // 9: auth.ServiceAccounts.UpdateServiceAccount:input_type -> auth.UpdateServiceAccountRequest
+	9,  // 10: auth.ServiceAccounts.DeleteServiceAccount:input_type -> auth.DeleteServiceAccountRequest
+	11, // 11: auth.ServiceAccounts.RotateServiceAccountSecret:input_type -> auth.RotateServiceAccountSecretRequest
+	13, // 12: auth.ServiceAccounts.SetServiceAccountRoles:input_type -> auth.SetServiceAccountRolesRequest
+	15, // 13: auth.ServiceAccounts.CreateServiceAccountAPIKey:input_type -> auth.CreateServiceAccountAPIKeyRequest
+	17, // 14: auth.ServiceAccounts.ListServiceAccountAPIKeys:input_type -> auth.ListServiceAccountAPIKeysRequest
+	19, // 15: auth.ServiceAccounts.RevokeServiceAccountAPIKey:input_type -> auth.RevokeServiceAccountAPIKeyRequest
+	2,  // 16: auth.ServiceAccounts.CreateServiceAccount:output_type -> auth.CreateServiceAccountResponse
+	4,  // 17: auth.ServiceAccounts.ListServiceAccounts:output_type -> auth.ListServiceAccountsResponse
+	6,  // 18: auth.ServiceAccounts.GetServiceAccount:output_type -> auth.GetServiceAccountResponse
+	8,  // 19: auth.ServiceAccounts.UpdateServiceAccount:output_type -> auth.UpdateServiceAccountResponse
+	10, // 20: auth.ServiceAccounts.DeleteServiceAccount:output_type -> auth.DeleteServiceAccountResponse
+	12, // 21: auth.ServiceAccounts.RotateServiceAccountSecret:output_type -> auth.RotateServiceAccountSecretResponse
+	14, // 22: auth.ServiceAccounts.SetServiceAccountRoles:output_type -> auth.SetServiceAccountRolesResponse
+	16, // 23: auth.ServiceAccounts.CreateServiceAccountAPIKey:output_type -> auth.CreateServiceAccountAPIKeyResponse
+	18, // 24: auth.ServiceAccounts.ListServiceAccountAPIKeys:output_type -> auth.ListServiceAccountAPIKeysResponse
+	20, // 25: auth.ServiceAccounts.RevokeServiceAccountAPIKey:output_type -> auth.RevokeServiceAccountAPIKeyResponse
+	16, // [16:26] is the sub-list for method output_type
+	6,  // [6:16] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_sso_service_accounts_proto_init() }
+func file_sso_service_accounts_proto_init() {
+	if File_sso_service_accounts_proto != nil {
+		return
+	}
+	file_sso_api_keys_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sso_service_accounts_proto_rawDesc), len(file_sso_service_accounts_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sso_service_accounts_proto_goTypes,
+		DependencyIndexes: file_sso_service_accounts_proto_depIdxs,
+		MessageInfos:      file_sso_service_accounts_proto_msgTypes,
+	}.Build()
+	File_sso_service_accounts_proto = out.File
+	file_sso_service_accounts_proto_goTypes = nil
+	file_sso_service_accounts_proto_depIdxs = nil
+}
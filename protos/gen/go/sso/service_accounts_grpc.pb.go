@@ -0,0 +1,483 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.0
+// source: sso/service_accounts.proto
+
+package sso
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ServiceAccounts_CreateServiceAccount_FullMethodName       = "/auth.ServiceAccounts/CreateServiceAccount"
+	ServiceAccounts_ListServiceAccounts_FullMethodName        = "/auth.ServiceAccounts/ListServiceAccounts"
+	ServiceAccounts_GetServiceAccount_FullMethodName          = "/auth.ServiceAccounts/GetServiceAccount"
+	ServiceAccounts_UpdateServiceAccount_FullMethodName       = "/auth.ServiceAccounts/UpdateServiceAccount"
+	ServiceAccounts_DeleteServiceAccount_FullMethodName       = "/auth.ServiceAccounts/DeleteServiceAccount"
+	ServiceAccounts_RotateServiceAccountSecret_FullMethodName = "/auth.ServiceAccounts/RotateServiceAccountSecret"
+	ServiceAccounts_SetServiceAccountRoles_FullMethodName     = "/auth.ServiceAccounts/SetServiceAccountRoles"
+	ServiceAccounts_CreateServiceAccountAPIKey_FullMethodName = "/auth.ServiceAccounts/CreateServiceAccountAPIKey"
+	ServiceAccounts_ListServiceAccountAPIKeys_FullMethodName  = "/auth.ServiceAccounts/ListServiceAccountAPIKeys"
+	ServiceAccounts_RevokeServiceAccountAPIKey_FullMethodName = "/auth.ServiceAccounts/RevokeServiceAccountAPIKey"
+)
+
+// ServiceAccountsClient is the client API for ServiceAccounts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ServiceAccounts is service for managing non-human principals. Every call requires
+// a token passed as "authorization: Bearer <token>" metadata with the admin permission.
+type ServiceAccountsClient interface {
+	// CreateServiceAccount registers a service account owned by an app or an organization.
+	// The client secret is not stored and can't be shown again.
+	CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error)
+	ListServiceAccounts(ctx context.Context, in *ListServiceAccountsRequest, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error)
+	GetServiceAccount(ctx context.Context, in *GetServiceAccountRequest, opts ...grpc.CallOption) (*GetServiceAccountResponse, error)
+	// UpdateServiceAccount replaces name, description and disabled flag of a service account.
+	UpdateServiceAccount(ctx context.Context, in *UpdateServiceAccountRequest, opts ...grpc.CallOption) (*UpdateServiceAccountResponse, error)
+	// DeleteServiceAccount deletes a service account along with its roles and API keys.
+	DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error)
+	// RotateServiceAccountSecret replaces the client secret and returns the new one.
+	RotateServiceAccountSecret(ctx context.Context, in *RotateServiceAccountSecretRequest, opts ...grpc.CallOption) (*RotateServiceAccountSecretResponse, error)
+	// SetServiceAccountRoles replaces roles of a service account in an app.
+	SetServiceAccountRoles(ctx context.Context, in *SetServiceAccountRolesRequest, opts ...grpc.CallOption) (*SetServiceAccountRolesResponse, error)
+	// CreateServiceAccountAPIKey issues an API key for a service account. The key can't be shown again.
+	CreateServiceAccountAPIKey(ctx context.Context, in *CreateServiceAccountAPIKeyRequest, opts ...grpc.CallOption) (*CreateServiceAccountAPIKeyResponse, error)
+	ListServiceAccountAPIKeys(ctx context.Context, in *ListServiceAccountAPIKeysRequest, opts ...grpc.CallOption) (*ListServiceAccountAPIKeysResponse, error)
+	RevokeServiceAccountAPIKey(ctx context.Context, in *RevokeServiceAccountAPIKeyRequest, opts ...grpc.CallOption) (*RevokeServiceAccountAPIKeyResponse, error)
+}
+
+type serviceAccountsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServiceAccountsClient(cc grpc.ClientConnInterface) ServiceAccountsClient {
+	return &serviceAccountsClient{cc}
+}
+
+func (c *serviceAccountsClient) CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_CreateServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) ListServiceAccounts(ctx context.Context, in *ListServiceAccountsRequest, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServiceAccountsResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_ListServiceAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) GetServiceAccount(ctx context.Context, in *GetServiceAccountRequest, opts ...grpc.CallOption) (*GetServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_GetServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) UpdateServiceAccount(ctx context.Context, in *UpdateServiceAccountRequest, opts ...grpc.CallOption) (*UpdateServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_UpdateServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_DeleteServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) RotateServiceAccountSecret(ctx context.Context, in *RotateServiceAccountSecretRequest, opts ...grpc.CallOption) (*RotateServiceAccountSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateServiceAccountSecretResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_RotateServiceAccountSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) SetServiceAccountRoles(ctx context.Context, in *SetServiceAccountRolesRequest, opts ...grpc.CallOption) (*SetServiceAccountRolesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetServiceAccountRolesResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_SetServiceAccountRoles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) CreateServiceAccountAPIKey(ctx context.Context, in *CreateServiceAccountAPIKeyRequest, opts ...grpc.CallOption) (*CreateServiceAccountAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateServiceAccountAPIKeyResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_CreateServiceAccountAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) ListServiceAccountAPIKeys(ctx context.Context, in *ListServiceAccountAPIKeysRequest, opts ...grpc.CallOption) (*ListServiceAccountAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServiceAccountAPIKeysResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_ListServiceAccountAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) RevokeServiceAccountAPIKey(ctx context.Context, in *RevokeServiceAccountAPIKeyRequest, opts ...grpc.CallOption) (*RevokeServiceAccountAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeServiceAccountAPIKeyResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_RevokeServiceAccountAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServiceAccountsServer is the server API for ServiceAccounts service.
+// All implementations must embed UnimplementedServiceAccountsServer
+// for forward compatibility.
+//
+// ServiceAccounts is service for managing non-human principals. Every call requires
+// a token passed as "authorization: Bearer <token>" metadata with the admin permission.
+type ServiceAccountsServer interface {
+	// CreateServiceAccount registers a service account owned by an app or an organization.
+	// The client secret is not stored and can't be shown again.
+	CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*CreateServiceAccountResponse, error)
+	ListServiceAccounts(context.Context, *ListServiceAccountsRequest) (*ListServiceAccountsResponse, error)
+	GetServiceAccount(context.Context, *GetServiceAccountRequest) (*GetServiceAccountResponse, error)
+	// UpdateServiceAccount replaces name, description and disabled flag of a service account.
+	UpdateServiceAccount(context.Context, *UpdateServiceAccountRequest) (*UpdateServiceAccountResponse, error)
+	// DeleteServiceAccount deletes a service account along with its roles and API keys.
+	DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error)
+	// RotateServiceAccountSecret replaces the client secret and returns the new one.
+	RotateServiceAccountSecret(context.Context, *RotateServiceAccountSecretRequest) (*RotateServiceAccountSecretResponse, error)
+	// SetServiceAccountRoles replaces roles of a service account in an app.
+	SetServiceAccountRoles(context.Context, *SetServiceAccountRolesRequest) (*SetServiceAccountRolesResponse, error)
+	// CreateServiceAccountAPIKey issues an API key for a service account. The key can't be shown again.
+	CreateServiceAccountAPIKey(context.Context, *CreateServiceAccountAPIKeyRequest) (*CreateServiceAccountAPIKeyResponse, error)
+	ListServiceAccountAPIKeys(context.Context, *ListServiceAccountAPIKeysRequest) (*ListServiceAccountAPIKeysResponse, error)
+	RevokeServiceAccountAPIKey(context.Context, *RevokeServiceAccountAPIKeyRequest) (*RevokeServiceAccountAPIKeyResponse, error)
+	mustEmbedUnimplementedServiceAccountsServer()
+}
+
+// UnimplementedServiceAccountsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedServiceAccountsServer struct{}
+
+func (UnimplementedServiceAccountsServer) CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*CreateServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) ListServiceAccounts(context.Context, *ListServiceAccountsRequest) (*ListServiceAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServiceAccounts not implemented")
+}
+func (UnimplementedServiceAccountsServer) GetServiceAccount(context.Context, *GetServiceAccountRequest) (*GetServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) UpdateServiceAccount(context.Context, *UpdateServiceAccountRequest) (*UpdateServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) RotateServiceAccountSecret(context.Context, *RotateServiceAccountSecretRequest) (*RotateServiceAccountSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateServiceAccountSecret not implemented")
+}
+func (UnimplementedServiceAccountsServer) SetServiceAccountRoles(context.Context, *SetServiceAccountRolesRequest) (*SetServiceAccountRolesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetServiceAccountRoles not implemented")
+}
+func (UnimplementedServiceAccountsServer) CreateServiceAccountAPIKey(context.Context, *CreateServiceAccountAPIKeyRequest) (*CreateServiceAccountAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccountAPIKey not implemented")
+}
+func (UnimplementedServiceAccountsServer) ListServiceAccountAPIKeys(context.Context, *ListServiceAccountAPIKeysRequest) (*ListServiceAccountAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServiceAccountAPIKeys not implemented")
+}
+func (UnimplementedServiceAccountsServer) RevokeServiceAccountAPIKey(context.Context, *RevokeServiceAccountAPIKeyRequest) (*RevokeServiceAccountAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeServiceAccountAPIKey not implemented")
+}
+func (UnimplementedServiceAccountsServer) mustEmbedUnimplementedServiceAccountsServer() {}
+func (UnimplementedServiceAccountsServer) testEmbeddedByValue()                         {}
+
+// UnsafeServiceAccountsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServiceAccountsServer will
+// result in compilation errors.
+type UnsafeServiceAccountsServer interface {
+	mustEmbedUnimplementedServiceAccountsServer()
+}
+
+func RegisterServiceAccountsServer(s grpc.ServiceRegistrar, srv ServiceAccountsServer) {
+	// If the following call pancis, it indicates UnimplementedServiceAccountsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ServiceAccounts_ServiceDesc, srv)
+}
+
+func _ServiceAccounts_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).CreateServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_CreateServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).CreateServiceAccount(ctx, req.(*CreateServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_ListServiceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).ListServiceAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_ListServiceAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).ListServiceAccounts(ctx, req.(*ListServiceAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_GetServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).GetServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_GetServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).GetServiceAccount(ctx, req.(*GetServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_UpdateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).UpdateServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_UpdateServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).UpdateServiceAccount(ctx, req.(*UpdateServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_DeleteServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).DeleteServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_DeleteServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).DeleteServiceAccount(ctx, req.(*DeleteServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_RotateServiceAccountSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateServiceAccountSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).RotateServiceAccountSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_RotateServiceAccountSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).RotateServiceAccountSecret(ctx, req.(*RotateServiceAccountSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_SetServiceAccountRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetServiceAccountRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).SetServiceAccountRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_SetServiceAccountRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).SetServiceAccountRoles(ctx, req.(*SetServiceAccountRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_CreateServiceAccountAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).CreateServiceAccountAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_CreateServiceAccountAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).CreateServiceAccountAPIKey(ctx, req.(*CreateServiceAccountAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_ListServiceAccountAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceAccountAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).ListServiceAccountAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_ListServiceAccountAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).ListServiceAccountAPIKeys(ctx, req.(*ListServiceAccountAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_RevokeServiceAccountAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeServiceAccountAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).RevokeServiceAccountAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_RevokeServiceAccountAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).RevokeServiceAccountAPIKey(ctx, req.(*RevokeServiceAccountAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ServiceAccounts_ServiceDesc is the grpc.ServiceDesc for ServiceAccounts service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServiceAccounts_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.ServiceAccounts",
+	HandlerType: (*ServiceAccountsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateServiceAccount",
+			Handler:    _ServiceAccounts_CreateServiceAccount_Handler,
+		},
+		{
+			MethodName: "ListServiceAccounts",
+			Handler:    _ServiceAccounts_ListServiceAccounts_Handler,
+		},
+		{
+			MethodName: "GetServiceAccount",
+			Handler:    _ServiceAccounts_GetServiceAccount_Handler,
+		},
+		{
+			MethodName: "UpdateServiceAccount",
+			Handler:    _ServiceAccounts_UpdateServiceAccount_Handler,
+		},
+		{
+			MethodName: "DeleteServiceAccount",
+			Handler:    _ServiceAccounts_DeleteServiceAccount_Handler,
+		},
+		{
+			MethodName: "RotateServiceAccountSecret",
+			Handler:    _ServiceAccounts_RotateServiceAccountSecret_Handler,
+		},
+		{
+			MethodName: "SetServiceAccountRoles",
+			Handler:    _ServiceAccounts_SetServiceAccountRoles_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccountAPIKey",
+			Handler:    _ServiceAccounts_CreateServiceAccountAPIKey_Handler,
+		},
+		{
+			MethodName: "ListServiceAccountAPIKeys",
+			Handler:    _ServiceAccounts_ListServiceAccountAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeServiceAccountAPIKey",
+			Handler:    _ServiceAccounts_RevokeServiceAccountAPIKey_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/service_accounts.proto",
+}